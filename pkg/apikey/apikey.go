@@ -0,0 +1,330 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/mystaline/clefinport-be/pkg/db"
+	"github.com/mystaline/clefinport-be/pkg/entity"
+	"github.com/mystaline/clefinport-be/pkg/service"
+	"github.com/mystaline/clefinport-be/pkg/sql_query"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type Scope string
+
+const (
+	ScopeReadOnly          Scope = "read-only"
+	ScopeTransactionsWrite Scope = "transactions:write"
+)
+
+// Scopes lists every scope that can be granted to a key.
+func Scopes() []Scope {
+	return []Scope{ScopeReadOnly, ScopeTransactionsWrite}
+}
+
+const (
+	keyPrefix      = "cfp"
+	lookupIDLength = 8
+	secretLength   = 32
+)
+
+var (
+	ErrInvalidKey   = errors.New("invalid api key")
+	ErrRevokedKey   = errors.New("api key has been revoked")
+	ErrInvalidScope = errors.New("invalid api key scope")
+	ErrScopeNotHeld = errors.New("api key can't grant a scope it doesn't hold")
+)
+
+type APIKey struct {
+	ID         string     `json:"id"         column:"id::text"`
+	UserID     string     `json:"userId"     column:"user_id::text"`
+	Name       string     `json:"name"       column:"name"`
+	LookupID   string     `json:"lookupId"   column:"lookup_id"`
+	KeyHash    string     `json:"-"          column:"key_hash"`
+	Scopes     []string   `json:"scopes"     column:"scopes"`
+	LastUsedAt *time.Time `json:"lastUsedAt" column:"last_used_at"`
+	RevokedAt  *time.Time `json:"revokedAt"  column:"revoked_at"`
+	CreatedAt  time.Time  `json:"createdAt"  column:"created_at"`
+}
+
+// KeyHash must be selected for verification but never serialized, so scan through this row instead of APIKey.
+type apiKeyRow struct {
+	ID         string     `json:"id"         column:"id::text"`
+	UserID     string     `json:"userId"     column:"user_id::text"`
+	Name       string     `json:"name"       column:"name"`
+	LookupID   string     `json:"lookupId"   column:"lookup_id"`
+	KeyHash    string     `json:"keyHash"    column:"key_hash"`
+	Scopes     []string   `json:"scopes"     column:"scopes"`
+	LastUsedAt *time.Time `json:"lastUsedAt" column:"last_used_at"`
+	RevokedAt  *time.Time `json:"revokedAt"  column:"revoked_at"`
+	CreatedAt  time.Time  `json:"createdAt"  column:"created_at"`
+}
+
+type insertAPIKey struct {
+	UserID   string   `json:"userId"   column:"user_id"`
+	Name     string   `json:"name"     column:"name"`
+	LookupID string   `json:"lookupId" column:"lookup_id"`
+	KeyHash  string   `json:"keyHash"  column:"key_hash"`
+	Scopes   []string `json:"scopes"   column:"scopes"`
+}
+
+// HasScope reports whether the key was granted scope.
+// transactions:write implies read-only.
+func (k *APIKey) HasScope(scope Scope) bool {
+	for _, each := range k.Scopes {
+		if Scope(each) == scope {
+			return true
+		}
+		if scope == ScopeReadOnly && Scope(each) == ScopeTransactionsWrite {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CanGrant returns ErrScopeNotHeld unless k holds every scope, a key can't issue a key stronger than itself.
+// A nil key is a caller authenticated another way (e.g. a user session), it may grant any scope.
+func (k *APIKey) CanGrant(scopes []Scope) error {
+	if k == nil {
+		return nil
+	}
+
+	for _, scope := range scopes {
+		if !k.HasScope(scope) {
+			return fmt.Errorf("%w: %s", ErrScopeNotHeld, scope)
+		}
+	}
+
+	return nil
+}
+
+// CreatedKey is only returned on create/rotate, the plaintext key is never stored and can't be shown again.
+type CreatedKey struct {
+	APIKey
+	PlaintextKey string `json:"key"`
+}
+
+type Manager struct {
+	Service service.PostgreSqlService
+}
+
+// MakeManager creates an api key manager backed by the api_keys table of the given service.
+func MakeManager(svc service.PostgreSqlService) *Manager {
+	return &Manager{Service: svc}
+}
+
+// Create issues a new key for userID with the given scopes.
+func (m *Manager) Create(ctx context.Context, userID, name string, scopes []Scope) (*CreatedKey, error) {
+	if len(scopes) == 0 {
+		return nil, ErrInvalidScope
+	}
+
+	normalizedScopes := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if !sql_query.ArrayIncludes(Scopes(), scope) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidScope, scope)
+		}
+		normalizedScopes = append(normalizedScopes, string(scope))
+	}
+
+	lookupID, secret, err := generateKey()
+	if err != nil {
+		return nil, err
+	}
+	plaintext := fmt.Sprintf("%s_%s_%s", keyPrefix, lookupID, secret)
+
+	var created APIKey
+	_, err = m.Service.InsertOneWithData(ctx, db.APIKeyTableName, insertAPIKey{
+		UserID:   userID,
+		Name:     name,
+		LookupID: lookupID,
+		KeyHash:  hashKey(plaintext),
+		Scopes:   normalizedScopes,
+	}, service.ReturningConfig{
+		Column:      sql_query.ExtractJSONTags[APIKey](),
+		Destination: &created,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &CreatedKey{APIKey: created, PlaintextKey: plaintext}, nil
+}
+
+// Rotate revokes keyID and issues a replacement with the same name and scopes, which caller must be able to grant.
+func (m *Manager) Rotate(ctx context.Context, userID, keyID string, caller *APIKey) (*CreatedKey, error) {
+	current, err := m.findOwned(ctx, userID, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := make([]Scope, 0, len(current.Scopes))
+	for _, each := range current.Scopes {
+		scopes = append(scopes, Scope(each))
+	}
+	if err := caller.CanGrant(scopes); err != nil {
+		return nil, err
+	}
+
+	// The manager's service is shared with the authentication of concurrent requests, it stays out of tx
+	return service.UseTransactions(ctx, m.Service.GetPool(), func(tx pgx.Tx) (*CreatedKey, error) {
+		txManager := MakeManager(service.TransactionService(m.Service, tx))
+
+		if err := txManager.Revoke(ctx, userID, keyID); err != nil {
+			return nil, err
+		}
+
+		return txManager.Create(ctx, userID, current.Name, scopes)
+	})
+}
+
+// Revoke marks keyID as revoked. Revoked keys fail authentication immediately.
+func (m *Manager) Revoke(ctx context.Context, userID, keyID string) error {
+	_, err := m.Service.UpdateOneWithData(ctx, db.APIKeyTableName,
+		map[string]sql_query.SQLCondition{
+			"id":         {Operator: sql_query.SQLOperatorEqual, Value: keyID},
+			"user_id":    {Operator: sql_query.SQLOperatorEqual, Value: userID},
+			"revoked_at": {Operator: sql_query.SQLOperatorIsNull},
+		},
+		map[string]any{
+			"revoked_at": sql_query.UpdateRawSQL{Expr: "NOW()"},
+		},
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.NotFound("api key not found")
+	}
+
+	return err
+}
+
+// Authenticate resolves a plaintext key into its record.
+// Keys are looked up by their public lookup id and verified against the stored hash in constant time.
+func (m *Manager) Authenticate(ctx context.Context, plaintext string) (*APIKey, error) {
+	parts := strings.Split(plaintext, "_")
+	if len(parts) != 3 || parts[0] != keyPrefix || len(parts[1]) != lookupIDLength {
+		return nil, ErrInvalidKey
+	}
+
+	query, args, err := sql_query.NewSQLSelectBuilder[apiKeyRow](db.APIKeyTableName).
+		Where(map[string]sql_query.SQLCondition{
+			"lookup_id": {Operator: sql_query.SQLOperatorEqual, Value: parts[1]},
+		}).
+		SetLimit(1).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+
+	var row apiKeyRow
+	err = m.Service.SelectOne(&row, ctx, query, args...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrInvalidKey
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(row.KeyHash), []byte(hashKey(plaintext))) != 1 {
+		return nil, ErrInvalidKey
+	}
+	if row.RevokedAt != nil {
+		return nil, ErrRevokedKey
+	}
+
+	key := APIKey(row)
+	return &key, nil
+}
+
+// TrackUsage stamps last_used_at and records the request into event_logs.
+// It is called asynchronously by the middleware so failures are only logged.
+func (m *Manager) TrackUsage(ctx context.Context, key *APIKey, method, path string, status int) {
+	_, err := m.Service.UpdateOneWithData(ctx, db.APIKeyTableName,
+		map[string]sql_query.SQLCondition{
+			"id": {Operator: sql_query.SQLOperatorEqual, Value: key.ID},
+		},
+		map[string]any{
+			"last_used_at": sql_query.UpdateRawSQL{Expr: "NOW()"},
+		},
+	)
+	if err != nil {
+		log.Printf("api key usage: failed to stamp last_used_at: %v", err)
+	}
+
+	_, err = m.Service.InsertOneWithData(ctx, db.EventLogTableName, usageEvent{
+		EventType: "api_key.used",
+		UserID:    key.UserID,
+		Payload: usagePayload{
+			APIKeyID: key.ID,
+			Method:   method,
+			Path:     path,
+			Status:   status,
+		},
+	})
+	if err != nil {
+		log.Printf("api key usage: failed to write event log: %v", err)
+	}
+}
+
+type usagePayload struct {
+	APIKeyID string `json:"apiKeyId"`
+	Method   string `json:"method"`
+	Path     string `json:"path"`
+	Status   int    `json:"status"`
+}
+
+type usageEvent struct {
+	EventType string       `json:"eventType" column:"event_type"`
+	UserID    string       `json:"userId"    column:"user_id"`
+	Payload   usagePayload `json:"payload"   column:"payload"`
+}
+
+func (m *Manager) findOwned(ctx context.Context, userID, keyID string) (*APIKey, error) {
+	query, args, err := sql_query.NewSQLSelectBuilder[APIKey](db.APIKeyTableName).
+		Where(map[string]sql_query.SQLCondition{
+			"id":         {Operator: sql_query.SQLOperatorEqual, Value: keyID},
+			"user_id":    {Operator: sql_query.SQLOperatorEqual, Value: userID},
+			"revoked_at": {Operator: sql_query.SQLOperatorIsNull},
+		}).
+		SetLimit(1).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+
+	var key APIKey
+	err = m.Service.SelectOne(&key, ctx, query, args...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, entity.NotFound("api key not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+func generateKey() (lookupID string, secret string, err error) {
+	buf := make([]byte, lookupIDLength/2+secretLength/2)
+	if _, err = rand.Read(buf); err != nil {
+		return "", "", err
+	}
+
+	encoded := hex.EncodeToString(buf)
+	return encoded[:lookupIDLength], encoded[lookupIDLength:], nil
+}
+
+func hashKey(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
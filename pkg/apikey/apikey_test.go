@@ -0,0 +1,170 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/mystaline/clefinport-be/pkg/entity"
+	"github.com/mystaline/clefinport-be/pkg/service"
+)
+
+var errConnection = errors.New("connection refused")
+
+func TestAuthenticateErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		selected error
+		want     error
+	}{
+		{name: "unknown key", selected: pgx.ErrNoRows, want: ErrInvalidKey},
+		{name: "query failure", selected: errConnection, want: errConnection},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &service.MockBasePostgreSqlService{}
+			svc.On("SelectOne", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(tt.selected)
+
+			_, err := MakeManager(svc).Authenticate(context.Background(), "cfp_0123abcd_secret")
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Authenticate() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRevokeErrors(t *testing.T) {
+	t.Run("unknown key", func(t *testing.T) {
+		svc := &service.MockBasePostgreSqlService{}
+		svc.On("UpdateOneWithData", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, pgx.ErrNoRows)
+
+		err := MakeManager(svc).Revoke(context.Background(), "42", "7")
+		var httpErr *entity.HttpError
+		if !errors.As(err, &httpErr) || httpErr.Code != fiber.StatusNotFound {
+			t.Fatalf("Revoke() error = %v, want a 404", err)
+		}
+	})
+
+	t.Run("query failure", func(t *testing.T) {
+		svc := &service.MockBasePostgreSqlService{}
+		svc.On("UpdateOneWithData", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errConnection)
+
+		if err := MakeManager(svc).Revoke(context.Background(), "42", "7"); !errors.Is(err, errConnection) {
+			t.Fatalf("Revoke() error = %v, want %v", err, errConnection)
+		}
+	})
+}
+
+func TestCanGrant(t *testing.T) {
+	readOnly := &APIKey{Scopes: []string{string(ScopeReadOnly)}}
+	write := &APIKey{Scopes: []string{string(ScopeTransactionsWrite)}}
+
+	tests := []struct {
+		name   string
+		caller *APIKey
+		scopes []Scope
+		want   error
+	}{
+		{name: "session caller", caller: nil, scopes: []Scope{ScopeTransactionsWrite}},
+		{name: "same scope", caller: readOnly, scopes: []Scope{ScopeReadOnly}},
+		{name: "implied scope", caller: write, scopes: []Scope{ScopeReadOnly, ScopeTransactionsWrite}},
+		{name: "stronger scope", caller: readOnly, scopes: []Scope{ScopeTransactionsWrite}, want: ErrScopeNotHeld},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.caller.CanGrant(tt.scopes); !errors.Is(err, tt.want) {
+				t.Fatalf("CanGrant() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequireOwner(t *testing.T) {
+	tests := []struct {
+		name   string
+		userID string
+		want   int
+	}{
+		{name: "anonymous", userID: "", want: fiber.StatusUnauthorized},
+		{name: "another user", userID: "7", want: fiber.StatusForbidden},
+		{name: "owner", userID: "42", want: fiber.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Use(func(ctx *fiber.Ctx) error {
+				if tt.userID != "" {
+					ctx.Locals(LocalsUserID, tt.userID)
+				}
+				return ctx.Next()
+			})
+			app.Post("/v1/user/:id/api-keys", RequireOwner("id"), func(ctx *fiber.Ctx) error {
+				return ctx.SendStatus(fiber.StatusOK)
+			})
+
+			res, err := app.Test(httptest.NewRequest(fiber.MethodPost, "/v1/user/42/api-keys", nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.StatusCode != tt.want {
+				t.Fatalf("status = %d, want %d", res.StatusCode, tt.want)
+			}
+		})
+	}
+}
+
+func TestRotateRequiresGrantableScopes(t *testing.T) {
+	svc := &service.MockBasePostgreSqlService{}
+	svc.On("SelectOne", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(0).(*APIKey) = APIKey{ID: "7", UserID: "42", Scopes: []string{string(ScopeTransactionsWrite)}}
+	}).Return(nil)
+
+	caller := &APIKey{ID: "8", UserID: "42", Scopes: []string{string(ScopeReadOnly)}}
+	if _, err := MakeManager(svc).Rotate(context.Background(), "42", "7", caller); !errors.Is(err, ErrScopeNotHeld) {
+		t.Fatalf("Rotate() error = %v, want %v", err, ErrScopeNotHeld)
+	}
+	svc.AssertNotCalled(t, "GetPool")
+}
+
+func TestRequireScope(t *testing.T) {
+	tests := []struct {
+		name string
+		key  *APIKey
+		want int
+	}{
+		{name: "without key", key: nil, want: fiber.StatusOK},
+		{name: "read-only key", key: &APIKey{Scopes: []string{string(ScopeReadOnly)}}, want: fiber.StatusForbidden},
+		{name: "write key", key: &APIKey{Scopes: []string{string(ScopeTransactionsWrite)}}, want: fiber.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Use(func(ctx *fiber.Ctx) error {
+				if tt.key != nil {
+					ctx.Locals(LocalsAPIKey, tt.key)
+				}
+				return ctx.Next()
+			})
+			app.Post("/v1/wallet/:id/invite-member", RequireScope(ScopeTransactionsWrite), func(ctx *fiber.Ctx) error {
+				return ctx.SendStatus(fiber.StatusOK)
+			})
+
+			res, err := app.Test(httptest.NewRequest(fiber.MethodPost, "/v1/wallet/7/invite-member", nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.StatusCode != tt.want {
+				t.Fatalf("status = %d, want %d", res.StatusCode, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mystaline/clefinport-be/pkg/entity"
+	"github.com/mystaline/clefinport-be/pkg/service"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/limiter"
+)
+
+const (
+	HeaderAPIKey        = "X-API-Key"
+	authorizationScheme = "ApiKey "
+
+	// Locals keys set by Authenticate for downstream handlers
+	LocalsAPIKey = "apiKey"
+	LocalsUserID = "userId"
+)
+
+// Authenticate resolves the api key sent in X-API-Key (or `Authorization: ApiKey <key>`).
+// It runs parallel to the JWT middleware: requests without a key are passed through untouched
+// so user sessions keep working, while requests carrying a key must present a valid, unrevoked one.
+// Every authenticated request is recorded into event_logs once the handler chain returns.
+func Authenticate(manager *Manager) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		plaintext := extractKey(ctx)
+		if plaintext == "" {
+			return ctx.Next()
+		}
+
+		key, err := manager.Authenticate(ctx.UserContext(), plaintext)
+		if errors.Is(err, ErrInvalidKey) || errors.Is(err, ErrRevokedKey) {
+			return entity.Unauthorized(err.Error()).SendResponse(ctx)
+		}
+		if err != nil {
+			return entity.InternalServerError("failed to authenticate api key").SendResponse(ctx)
+		}
+
+		ctx.Locals(LocalsAPIKey, key)
+		ctx.Locals(LocalsUserID, key.UserID)
+		// Usecases get the user context, the WithData writes stamp created_by/updated_by from it
+		ctx.SetUserContext(service.WithActor(ctx.UserContext(), key.UserID))
+
+		chainErr := ctx.Next()
+
+		// Method and Path point into fiber's request buffer, reused by the next request before the goroutine runs
+		method, path, status := strings.Clone(ctx.Method()), strings.Clone(ctx.Path()), ctx.Response().StatusCode()
+		go func() {
+			usageCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			manager.TrackUsage(usageCtx, key, method, path, status)
+		}()
+
+		return chainErr
+	}
+}
+
+// RequireScope rejects requests authenticated with a key that wasn't granted scope.
+// Requests without a key are left to the session authentication, like RateLimit. Must be registered after Authenticate.
+func RequireScope(scope Scope) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		key := FromContext(ctx)
+		if key == nil {
+			return ctx.Next()
+		}
+		if !key.HasScope(scope) {
+			return entity.Forbidden("api key is missing scope " + string(scope)).SendResponse(ctx)
+		}
+
+		return ctx.Next()
+	}
+}
+
+// RequireOwner rejects requests whose authenticated user isn't the one of the param route parameter,
+// e.g. RequireOwner("id") on /v1/user/:id/api-keys. The user is the LocalsUserID set by Authenticate,
+// or by any other authentication middleware registered before it.
+func RequireOwner(param string) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		userID, _ := ctx.Locals(LocalsUserID).(string)
+		if userID == "" {
+			return entity.Unauthorized("authentication is required").SendResponse(ctx)
+		}
+		if userID != ctx.Params(param) {
+			return entity.Forbidden("not allowed to manage another user").SendResponse(ctx)
+		}
+
+		return ctx.Next()
+	}
+}
+
+// RateLimit limits requests per api key within the given window.
+// Requests without a key are not limited here, they fall under the regular per-IP limits.
+func RateLimit(max int, window time.Duration) fiber.Handler {
+	return limiter.New(limiter.Config{
+		Max:        max,
+		Expiration: window,
+		Next: func(ctx *fiber.Ctx) bool {
+			return FromContext(ctx) == nil
+		},
+		KeyGenerator: func(ctx *fiber.Ctx) string {
+			return FromContext(ctx).ID
+		},
+		LimitReached: func(ctx *fiber.Ctx) error {
+			return entity.TooManyRequests("api key rate limit exceeded").SendResponse(ctx)
+		},
+	})
+}
+
+// DynamicRateLimit is RateLimit with max read on every request (e.g. from runtime settings),
+// a changed max starts a new limiter, the counters of the previous one are dropped. A max below 1 disables the limit.
+func DynamicRateLimit(max func() int, window time.Duration) fiber.Handler {
+	var mu sync.Mutex
+	current := 0
+	var handler fiber.Handler
+
+	return func(ctx *fiber.Ctx) error {
+		limit := max()
+		if limit < 1 {
+			return ctx.Next()
+		}
+
+		mu.Lock()
+		if handler == nil || limit != current {
+			current = limit
+			handler = RateLimit(limit, window)
+		}
+		limiter := handler
+		mu.Unlock()
+
+		return limiter(ctx)
+	}
+}
+
+// FromContext returns the api key resolved by Authenticate, or nil.
+func FromContext(ctx *fiber.Ctx) *APIKey {
+	key, _ := ctx.Locals(LocalsAPIKey).(*APIKey)
+	return key
+}
+
+func extractKey(ctx *fiber.Ctx) string {
+	if key := ctx.Get(HeaderAPIKey); key != "" {
+		return key
+	}
+
+	authorization := ctx.Get(fiber.HeaderAuthorization)
+	if strings.HasPrefix(authorization, authorizationScheme) {
+		return strings.TrimSpace(strings.TrimPrefix(authorization, authorizationScheme))
+	}
+
+	return ""
+}
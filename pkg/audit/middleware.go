@@ -0,0 +1,114 @@
+package audit
+
+import (
+	"context"
+	"strings"
+
+	"github.com/mystaline/clefinport-be/pkg/apikey"
+	"github.com/mystaline/clefinport-be/pkg/grpcauth"
+	"github.com/mystaline/clefinport-be/pkg/privacy"
+
+	"github.com/gofiber/fiber/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// Middleware audits every request of the route it is mounted on as action.
+// Mount it before access guards so denied requests are audited too.
+// The resource is the request path, the actor is resolved from the support requester header,
+// the api key, or the userId local, in that order.
+//
+// Handlers can describe the access further with SetDetail.
+//
+// Example:
+//
+//	support := app.Group("/v1/support", audit.Middleware(writer, "support.access"), privacy.RequireSupportAccess())
+func Middleware(writer *Writer, action string) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		chainErr := ctx.Next()
+
+		actor, actorType := httpActor(ctx)
+		detail, _ := ctx.Locals(localsDetail).(string)
+		// The record is written after the handler returned, Path and headers point into fiber's reused buffers
+		writer.TryWrite(Record{
+			Actor:     actor,
+			ActorType: actorType,
+			Action:    action,
+			Resource:  strings.Clone(ctx.Path()),
+			Result:    httpResult(ctx.Response().StatusCode(), chainErr),
+			Channel:   "http",
+			Detail:    detail,
+		})
+
+		return chainErr
+	}
+}
+
+// UnaryServerInterceptor audits the gRPC methods listed in actions (full method name -> action).
+// Must run after grpcauth's interceptor so the calling service is known.
+func UnaryServerInterceptor(writer *Writer, actions map[string]string) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		action, ok := actions[info.FullMethod]
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		res, err := handler(ctx, req)
+
+		caller, _ := grpcauth.CallerFromContext(ctx)
+		record := Record{
+			Actor:     string(caller),
+			ActorType: "service",
+			Action:    action,
+			Resource:  info.FullMethod,
+			Result:    ResultSuccess,
+			Channel:   "grpc",
+		}
+		if err != nil {
+			record.Result = ResultFailure
+			record.Detail = status.Convert(err).Message()
+		}
+		writer.TryWrite(record)
+
+		return res, err
+	}
+}
+
+const localsDetail = "auditDetail"
+
+// SetDetail sets the Detail of the record Middleware writes for the current request, detail is copied.
+func SetDetail(ctx *fiber.Ctx, detail string) {
+	ctx.Locals(localsDetail, strings.Clone(detail))
+}
+
+func httpActor(ctx *fiber.Ctx) (string, string) {
+	if requester := ctx.Get(privacy.SupportRequesterHeader); requester != "" {
+		return strings.Clone(requester), "support"
+	}
+	if key := apikey.FromContext(ctx); key != nil {
+		return key.ID, "api_key"
+	}
+	if userId, ok := ctx.Locals(apikey.LocalsUserID).(string); ok && userId != "" {
+		return userId, "user"
+	}
+
+	return "anonymous", "anonymous"
+}
+
+func httpResult(statusCode int, err error) Result {
+	switch {
+	case err != nil || statusCode >= fiber.StatusInternalServerError:
+		return ResultFailure
+	case statusCode == fiber.StatusUnauthorized || statusCode == fiber.StatusForbidden:
+		return ResultDenied
+	case statusCode >= fiber.StatusBadRequest:
+		return ResultFailure
+	default:
+		return ResultSuccess
+	}
+}
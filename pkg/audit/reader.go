@@ -0,0 +1,97 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/mystaline/clefinport-be/pkg/db"
+	"github.com/mystaline/clefinport-be/pkg/dto"
+	"github.com/mystaline/clefinport-be/pkg/service"
+	"github.com/mystaline/clefinport-be/pkg/sql_query"
+)
+
+type ListFilter struct {
+	Page   int    `json:"page"   transform:"int"`
+	Limit  int    `json:"limit"  transform:"int"`
+	Actor  string `json:"actor"  transform:"string"`
+	Action string `json:"action" transform:"string"`
+	Result string `json:"result" transform:"string"`
+}
+
+type StoredRecord struct {
+	ID         string `json:"id"         column:"id::text"`
+	Actor      string `json:"actor"      column:"payload->>'actor'"`
+	ActorType  string `json:"actorType"  column:"payload->>'actorType'"`
+	Action     string `json:"action"     column:"payload->>'action'"`
+	Resource   string `json:"resource"   column:"payload->>'resource'"`
+	Result     string `json:"result"     column:"payload->>'result'"`
+	Channel    string `json:"channel"    column:"payload->>'channel'"`
+	Detail     string `json:"detail"     column:"COALESCE(payload->>'detail', '')"`
+	OccurredAt string `json:"occurredAt" column:"payload->>'occurredAt'"`
+}
+
+// ListRecords returns audit records for admin review, newest first.
+func ListRecords(
+	ctx context.Context,
+	svc service.PostgreSqlService,
+	filter ListFilter,
+) (dto.PaginationResult[StoredRecord], error) {
+	pagination := sql_query.Pagination{
+		Page:        filter.Page,
+		Limit:       filter.Limit,
+		DefaultSort: []sql_query.Sort{{SortBy: "id", SortOrder: -1}},
+	}
+	if pagination.Limit <= 0 {
+		pagination.Limit = 50
+	}
+
+	query, args, meta, err := sql_query.NewSQLSelectBuilder[StoredRecord](db.EventLogTableName).
+		Where(recordFilters(filter)).
+		Paginate(pagination).
+		BuildPaginated()
+	if err != nil {
+		return dto.PaginationResult[StoredRecord]{}, err
+	}
+
+	var result []dto.PaginationResult[StoredRecord]
+	if err := svc.SelectMany(&result, ctx, query, args...); err != nil {
+		return dto.PaginationResult[StoredRecord]{}, err
+	}
+
+	return sql_query.FormatPaginationResult(result, meta), nil
+}
+
+// EachRecord calls fn with every audit record matching filter, newest first, without loading them all.
+// Page and Limit of filter are ignored.
+func EachRecord(
+	ctx context.Context,
+	svc service.PostgreSqlService,
+	filter ListFilter,
+	fn func(record StoredRecord) error,
+) error {
+	query, args, err := sql_query.NewSQLSelectBuilder[StoredRecord](db.EventLogTableName).
+		Where(recordFilters(filter)).
+		OrderBy([]string{"id"}, false).
+		Build()
+	if err != nil {
+		return err
+	}
+
+	return service.SelectEach(ctx, svc, query, args, fn)
+}
+
+func recordFilters(filter ListFilter) map[string]sql_query.SQLCondition {
+	filters := map[string]sql_query.SQLCondition{
+		"event_type": {Operator: sql_query.SQLOperatorLike, Value: EventTypePrefix + "%"},
+	}
+	if filter.Actor != "" {
+		filters["payload->>'actor'"] = sql_query.SQLCondition{Operator: sql_query.SQLOperatorEqual, Value: filter.Actor}
+	}
+	if filter.Action != "" {
+		filters["payload->>'action'"] = sql_query.SQLCondition{Operator: sql_query.SQLOperatorEqual, Value: filter.Action}
+	}
+	if filter.Result != "" {
+		filters["payload->>'result'"] = sql_query.SQLCondition{Operator: sql_query.SQLOperatorEqual, Value: filter.Result}
+	}
+
+	return filters
+}
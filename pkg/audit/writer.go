@@ -0,0 +1,207 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mystaline/clefinport-be/pkg/db"
+	"github.com/mystaline/clefinport-be/pkg/service"
+	"github.com/mystaline/clefinport-be/pkg/sql_query"
+)
+
+type Result string
+
+const (
+	ResultSuccess Result = "success"
+	ResultDenied  Result = "denied"
+	ResultFailure Result = "failure"
+)
+
+// Prefix of event_type for every audit record, keeps them apart from other event_logs rows
+const EventTypePrefix = "audit."
+
+var ErrWriterClosed = errors.New("audit writer is closed")
+
+// Record describes one access to a sensitive operation: who did what to which resource and how it ended.
+type Record struct {
+	Actor      string    `json:"actor"`
+	ActorType  string    `json:"actorType"`
+	Action     string    `json:"action"`
+	Resource   string    `json:"resource"`
+	Result     Result    `json:"result"`
+	Channel    string    `json:"channel"` // http | grpc
+	Detail     string    `json:"detail,omitempty"`
+	OccurredAt time.Time `json:"occurredAt"`
+}
+
+type eventLogRow struct {
+	EventType string `json:"eventType" column:"event_type"`
+	Payload   Record `json:"payload"   column:"payload"`
+}
+
+type WriterConfig struct {
+	// Records held in memory before Write starts blocking
+	BufferSize int
+	// Max records inserted per statement
+	BatchSize int
+	// Pending records are flushed at least this often
+	FlushInterval time.Duration
+}
+
+// Writer persists audit records into event_logs asynchronously.
+// Records are buffered and inserted in batches by a single background goroutine.
+// When the buffer is full, Write blocks until there is room or its context is done (backpressure),
+// TryWrite drops the record instead and counts it in Dropped.
+type Writer struct {
+	Service service.PostgreSqlService
+
+	config  WriterConfig
+	records chan Record
+	done    chan struct{}
+	dropped atomic.Int64
+
+	// Held for reading while sending to records and for writing to close it, a send never hits a closed channel.
+	// A Write blocked on a full buffer delays Close until the run goroutine made room.
+	mu     sync.RWMutex
+	closed bool
+}
+
+// MakeWriter starts a writer backed by the event_logs table of the given service.
+// Zero values in config fall back to a buffer of 1024, batches of 100 and a 1 second flush interval.
+func MakeWriter(svc service.PostgreSqlService, config WriterConfig) *Writer {
+	if config.BufferSize <= 0 {
+		config.BufferSize = 1024
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = time.Second
+	}
+
+	w := &Writer{
+		Service: svc,
+		config:  config,
+		records: make(chan Record, config.BufferSize),
+		done:    make(chan struct{}),
+	}
+	go w.run()
+
+	return w
+}
+
+// Write enqueues record, blocking while the buffer is full.
+func (w *Writer) Write(ctx context.Context, record Record) error {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.closed {
+		return ErrWriterClosed
+	}
+	if record.OccurredAt.IsZero() {
+		record.OccurredAt = time.Now()
+	}
+
+	select {
+	case w.records <- record:
+		return nil
+	case <-ctx.Done():
+		w.dropped.Add(1)
+		return ctx.Err()
+	}
+}
+
+// TryWrite enqueues record without blocking. It returns false when the record was dropped.
+func (w *Writer) TryWrite(record Record) bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.closed {
+		return false
+	}
+	if record.OccurredAt.IsZero() {
+		record.OccurredAt = time.Now()
+	}
+
+	select {
+	case w.records <- record:
+		return true
+	default:
+		w.dropped.Add(1)
+		return false
+	}
+}
+
+// Dropped returns how many records never made it into the buffer.
+func (w *Writer) Dropped() int64 {
+	return w.dropped.Load()
+}
+
+// Close stops accepting records and waits until the buffer is flushed or ctx is done, call it on shutdown.
+func (w *Writer) Close(ctx context.Context) error {
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.records)
+	}
+	w.mu.Unlock()
+
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (w *Writer) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.config.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]eventLogRow, 0, w.config.BatchSize)
+	for {
+		select {
+		case record, ok := <-w.records:
+			if !ok {
+				w.flush(batch)
+				return
+			}
+
+			batch = append(batch, eventLogRow{EventType: EventTypePrefix + record.Action, Payload: record})
+			if len(batch) >= w.config.BatchSize {
+				w.flush(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			w.flush(batch)
+			batch = batch[:0]
+		}
+	}
+}
+
+func (w *Writer) flush(batch []eventLogRow) {
+	if len(batch) == 0 {
+		return
+	}
+
+	query, args, err := sql_query.NewSQLInsertBuilder(db.EventLogTableName).
+		Insert(batch).
+		Build()
+	if err != nil {
+		log.Printf("audit: failed to build insert: %v", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := w.Service.InsertMany(ctx, query, args...); err != nil {
+		log.Printf("audit: failed to write %d records: %v", len(batch), err)
+	}
+}
@@ -0,0 +1,54 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/mystaline/clefinport-be/pkg/service"
+)
+
+func TestCloseWhileWriteBlocks(t *testing.T) {
+	release := make(chan struct{})
+	inserting := make(chan struct{}, 10)
+
+	svc := &service.MockBasePostgreSqlService{}
+	svc.On("InsertMany", mock.Anything, mock.Anything, mock.Anything).Run(func(mock.Arguments) {
+		inserting <- struct{}{}
+		<-release
+	}).Return(int64(1), nil)
+
+	writer := MakeWriter(svc, WriterConfig{BufferSize: 1, BatchSize: 1, FlushInterval: time.Hour})
+
+	// The first record stalls the insert, the second fills the buffer, the third blocks
+	writer.TryWrite(Record{Action: "first"})
+	<-inserting
+	writer.TryWrite(Record{Action: "second"})
+
+	written := make(chan error)
+	go func() {
+		written <- writer.Write(context.Background(), Record{Action: "third"})
+	}()
+	closed := make(chan error)
+	go func() {
+		// Let Write block on the full buffer first
+		time.Sleep(20 * time.Millisecond)
+		closed <- writer.Close(context.Background())
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+
+	if err := <-written; err != nil && !errors.Is(err, ErrWriterClosed) {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := <-closed; err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if writer.TryWrite(Record{Action: "late"}) {
+		t.Fatal("TryWrite() after Close() = true, want false")
+	}
+}
@@ -0,0 +1,216 @@
+package categoryseed
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mystaline/clefinport-be/pkg/enum"
+)
+
+// Default category trees created for new users, one name pack per language.
+// The tree (packs/tree.json) is shared by every language, its keys are the identity of a system category:
+// a category seeded in Indonesian and one seeded in English carry the same system_key,
+// so reports group by system_key and stay correct when the user switches display language.
+//
+// Adding a language only needs a packs/<locale>.json with the names, missing names fall back to DefaultLocale.
+
+// DefaultLocale is the language used for unknown locales and for names a pack doesn't translate.
+const DefaultLocale = "en"
+
+//go:embed packs/*.json
+var packFiles embed.FS
+
+// Category is one system category of a pack, parents come before their children.
+type Category struct {
+	Key       string
+	ParentKey string
+	Type      enum.TransactionType
+	Name      string
+}
+
+type Pack struct {
+	Locale     string
+	Categories []Category
+}
+
+// Name is the name of a system category in one language, the rows of the system_category_names mapping table.
+type Name struct {
+	SystemKey string `json:"systemKey" column:"system_key"`
+	Locale    string `json:"locale"    column:"locale"`
+	Name      string `json:"name"      column:"name"`
+}
+
+type treeNode struct {
+	Key      string               `json:"key"`
+	Type     enum.TransactionType `json:"type"`
+	Children []string             `json:"children"`
+}
+
+type catalog struct {
+	tree    []Category
+	names   map[string]map[string]string
+	locales []string
+}
+
+var (
+	loadOnce sync.Once
+	loaded   *catalog
+)
+
+// The packs are embedded, a broken one is a build mistake and fails loudly on first use.
+func load() *catalog {
+	loadOnce.Do(func() {
+		c, err := parsePacks()
+		if err != nil {
+			panic(fmt.Sprintf("categoryseed: %v", err))
+		}
+		loaded = c
+	})
+
+	return loaded
+}
+
+func parsePacks() (*catalog, error) {
+	raw, err := packFiles.ReadFile("packs/tree.json")
+	if err != nil {
+		return nil, err
+	}
+
+	var nodes []treeNode
+	if err := json.Unmarshal(raw, &nodes); err != nil {
+		return nil, fmt.Errorf("tree.json: %w", err)
+	}
+
+	c := &catalog{names: map[string]map[string]string{}}
+	keys := map[string]bool{}
+	addKey := func(category Category) error {
+		if keys[category.Key] {
+			return fmt.Errorf("tree.json: duplicate key %q", category.Key)
+		}
+		keys[category.Key] = true
+		c.tree = append(c.tree, category)
+
+		return nil
+	}
+
+	for _, node := range nodes {
+		if !node.Type.Valid() || node.Type == enum.TransactionTransfer {
+			return nil, fmt.Errorf("tree.json: %q has invalid type %q", node.Key, node.Type)
+		}
+		if err := addKey(Category{Key: node.Key, Type: node.Type}); err != nil {
+			return nil, err
+		}
+	}
+	// Children after every parent, so inserts can resolve parent ids in one pass
+	for _, node := range nodes {
+		for _, child := range node.Children {
+			if err := addKey(Category{Key: child, ParentKey: node.Key, Type: node.Type}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	entries, err := packFiles.ReadDir("packs")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		locale := strings.TrimSuffix(entry.Name(), path.Ext(entry.Name()))
+		if locale == "tree" {
+			continue
+		}
+
+		raw, err := packFiles.ReadFile("packs/" + entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		var names map[string]string
+		if err := json.Unmarshal(raw, &names); err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		for key := range names {
+			if !keys[key] {
+				return nil, fmt.Errorf("%s: %q is not in tree.json", entry.Name(), key)
+			}
+		}
+
+		c.names[locale] = names
+		c.locales = append(c.locales, locale)
+	}
+
+	for key := range keys {
+		if c.names[DefaultLocale][key] == "" {
+			return nil, fmt.Errorf("%s.json: missing name of %q", DefaultLocale, key)
+		}
+	}
+	sort.Strings(c.locales)
+
+	return c, nil
+}
+
+// Locales lists the languages with a pack.
+func Locales() []string {
+	return append([]string(nil), load().locales...)
+}
+
+// ResolveLocale maps a requested locale to the closest pack, "id-ID" and "id_ID" resolve to "id",
+// anything without a pack resolves to DefaultLocale.
+func ResolveLocale(locale string) string {
+	c := load()
+
+	locale = strings.ToLower(strings.TrimSpace(strings.ReplaceAll(locale, "_", "-")))
+	if _, ok := c.names[locale]; ok {
+		return locale
+	}
+
+	if base, _, found := strings.Cut(locale, "-"); found {
+		if _, ok := c.names[base]; ok {
+			return base
+		}
+	}
+
+	return DefaultLocale
+}
+
+// Load returns the category tree named in the closest pack of locale.
+func Load(locale string) Pack {
+	c := load()
+	locale = ResolveLocale(locale)
+
+	pack := Pack{Locale: locale, Categories: make([]Category, len(c.tree))}
+	for i, category := range c.tree {
+		category.Name = c.names[locale][category.Key]
+		if category.Name == "" {
+			category.Name = c.names[DefaultLocale][category.Key]
+		}
+		pack.Categories[i] = category
+	}
+
+	return pack
+}
+
+// Names returns the name of every system category in every language.
+// Languages that don't translate a category get the DefaultLocale name, so a lookup by (system_key, locale) always hits.
+func Names() []Name {
+	c := load()
+
+	names := make([]Name, 0, len(c.tree)*len(c.locales))
+	for _, category := range c.tree {
+		for _, locale := range c.locales {
+			name := c.names[locale][category.Key]
+			if name == "" {
+				name = c.names[DefaultLocale][category.Key]
+			}
+			names = append(names, Name{SystemKey: category.Key, Locale: locale, Name: name})
+		}
+	}
+
+	return names
+}
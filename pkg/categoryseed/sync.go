@@ -0,0 +1,24 @@
+package categoryseed
+
+import (
+	"context"
+
+	"github.com/mystaline/clefinport-be/pkg/db"
+	"github.com/mystaline/clefinport-be/pkg/service"
+	"github.com/mystaline/clefinport-be/pkg/sql_query"
+)
+
+// SyncNames upserts Names into the system_category_names mapping table, run it on startup of the service
+// owning the categories so renamed or newly translated packs reach the database.
+func SyncNames(ctx context.Context, svc service.PostgreSqlService) error {
+	query, args, err := sql_query.NewSQLInsertBuilder(db.SystemCategoryNameTableName).
+		Insert(Names()).
+		ConflictUpdate("(system_key, locale)", []string{"name"}, nil).
+		Build()
+	if err != nil {
+		return err
+	}
+
+	_, err = svc.InsertMany(ctx, query, args...)
+	return err
+}
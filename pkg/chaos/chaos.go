@@ -0,0 +1,195 @@
+package chaos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Fault injection for failure testing: the database services and the gRPC clients are decorated so a
+// configurable share of the calls is delayed, loses its connection or fails to serialize.
+// Nothing is injected unless CHAOS_ENABLED=true, and never when ENV=production.
+//
+//	CHAOS_ENABLED=true
+//	CHAOS_SEED=42                      // optional, replays the same fault sequence
+//	CHAOS_LATENCY=300ms                // added delay
+//	CHAOS_LATENCY_RATE=0.2             // share of the calls delayed
+//	CHAOS_RESET_RATE=0.05              // share of the calls failing with a connection reset
+//	CHAOS_SERIALIZATION_RATE=0.05      // share of the calls failing with SQLSTATE 40001
+
+// Fault is an injected failure kind.
+type Fault string
+
+const (
+	FaultNone          Fault = ""
+	FaultReset         Fault = "reset"
+	FaultSerialization Fault = "serialization"
+)
+
+// ErrConnectionReset is the error of a FaultReset on the database, it matches syscall.ECONNRESET
+// with errors.Is like a reset of the real connection does.
+var ErrConnectionReset = fmt.Errorf("chaos: connection reset by peer: %w", syscall.ECONNRESET)
+
+// SerializationFailure returns the error of a FaultSerialization on the database, the error Postgres
+// raises when a SERIALIZABLE or REPEATABLE READ transaction must be retried.
+func SerializationFailure() error {
+	return &pgconn.PgError{
+		Severity: "ERROR",
+		Code:     "40001",
+		Message:  serializationMessage,
+	}
+}
+
+const serializationMessage = "chaos: could not serialize access due to concurrent update"
+
+// IsInjected reports whether err was produced by an Injector.
+func IsInjected(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "40001" && pgErr.Message == serializationMessage
+	}
+
+	return errors.Is(err, ErrConnectionReset)
+}
+
+type Config struct {
+	Latency           time.Duration
+	LatencyRate       float64
+	ResetRate         float64
+	SerializationRate float64
+	// Seed makes the fault sequence reproducible, 0 seeds from the clock
+	Seed int64
+}
+
+// Enabled reports whether the config injects anything at all.
+func (c Config) Enabled() bool {
+	return (c.Latency > 0 && c.LatencyRate > 0) || c.ResetRate > 0 || c.SerializationRate > 0
+}
+
+// ConfigFromEnv reads the CHAOS_* variables, false when fault injection is not enabled.
+func ConfigFromEnv() (Config, bool) {
+	if os.Getenv("CHAOS_ENABLED") != "true" {
+		return Config{}, false
+	}
+	if os.Getenv("ENV") == "production" {
+		log.Println("⚠️ CHAOS_ENABLED is ignored in production")
+		return Config{}, false
+	}
+
+	config := Config{
+		LatencyRate:       envRate("CHAOS_LATENCY_RATE"),
+		ResetRate:         envRate("CHAOS_RESET_RATE"),
+		SerializationRate: envRate("CHAOS_SERIALIZATION_RATE"),
+	}
+	if latency, err := time.ParseDuration(os.Getenv("CHAOS_LATENCY")); err == nil {
+		config.Latency = latency
+	}
+	if seed, err := strconv.ParseInt(os.Getenv("CHAOS_SEED"), 10, 64); err == nil {
+		config.Seed = seed
+	}
+
+	return config, config.Enabled()
+}
+
+func envRate(name string) float64 {
+	rate, err := strconv.ParseFloat(os.Getenv(name), 64)
+	if err != nil || rate < 0 {
+		return 0
+	}
+
+	return min(rate, 1)
+}
+
+var (
+	envInjector     *Injector
+	envInjectorOnce sync.Once
+)
+
+// FromEnv returns the injector configured by the environment, shared by the whole process, nil when disabled.
+func FromEnv() *Injector {
+	envInjectorOnce.Do(func() {
+		config, ok := ConfigFromEnv()
+		if !ok {
+			return
+		}
+
+		log.Printf("⚠️ Fault injection enabled: %+v\n", config)
+		envInjector = MakeInjector(config)
+	})
+
+	return envInjector
+}
+
+// Injector draws the faults, it is safe for concurrent use.
+type Injector struct {
+	config Config
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+func MakeInjector(config Config) *Injector {
+	seed := config.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	return &Injector{
+		config: config,
+		rand:   rand.New(rand.NewSource(seed)),
+	}
+}
+
+func (i *Injector) Config() Config {
+	return i.config
+}
+
+// Next draws the fault of one call and sleeps the injected latency, if any.
+// The sleep ends early when ctx is done, the fault is then FaultNone and the caller sees ctx.Err() itself.
+func (i *Injector) Next(ctx context.Context) Fault {
+	i.mu.Lock()
+	delay := i.rand.Float64() < i.config.LatencyRate
+	draw := i.rand.Float64()
+	i.mu.Unlock()
+
+	if delay && i.config.Latency > 0 {
+		timer := time.NewTimer(i.config.Latency)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			return FaultNone
+		case <-timer.C:
+		}
+	}
+
+	switch {
+	case draw < i.config.ResetRate:
+		return FaultReset
+	case draw < i.config.ResetRate+i.config.SerializationRate:
+		return FaultSerialization
+	}
+
+	return FaultNone
+}
+
+// databaseError draws the fault of one database call, nil when the call must go through.
+func (i *Injector) databaseError(ctx context.Context) error {
+	switch i.Next(ctx) {
+	case FaultReset:
+		return ErrConnectionReset
+	case FaultSerialization:
+		return SerializationFailure()
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package chaos
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryClientInterceptor injects the faults of injector before each call, the call is then not sent.
+// A reset fails with codes.Unavailable like a dropped connection, a serialization failure with codes.Aborted,
+// the code of a transaction the server had to abort.
+func UnaryClientInterceptor(injector *Injector) grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		switch injector.Next(ctx) {
+		case FaultReset:
+			return status.Error(codes.Unavailable, "chaos: connection reset by peer")
+		case FaultSerialization:
+			return status.Error(codes.Aborted, "chaos: could not serialize access due to concurrent update")
+		}
+
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// DialOptions returns the interceptor option of the environment injector, none when fault injection is disabled.
+//
+// Example:
+//
+//	options = append(options, chaos.DialOptions()...)
+func DialOptions() []grpc.DialOption {
+	injector := FromEnv()
+	if injector == nil {
+		return nil
+	}
+
+	return []grpc.DialOption{grpc.WithChainUnaryInterceptor(UnaryClientInterceptor(injector))}
+}
@@ -0,0 +1,280 @@
+package chaos
+
+import (
+	"context"
+
+	"github.com/mystaline/clefinport-be/pkg/service"
+	"github.com/mystaline/clefinport-be/pkg/sql_query"
+)
+
+// faultyService injects the faults before each call reaching the database, the call is then not made at all.
+// Commits are included, a serialization failure is most often reported by the COMMIT itself.
+type faultyService struct {
+	service.PostgreSqlService
+
+	injector *Injector
+}
+
+// WrapService decorates svc with the faults of injector, svc is returned as is when injector is nil.
+//
+// Example:
+//
+//	svc := chaos.WrapService(service.MakeService(db.WalletServiceDBName), chaos.MakeInjector(chaos.Config{
+//	    SerializationRate: 0.3,
+//	    Seed:              1,
+//	}))
+func WrapService(svc service.PostgreSqlService, injector *Injector) service.PostgreSqlService {
+	if injector == nil {
+		return svc
+	}
+
+	return &faultyService{PostgreSqlService: svc, injector: injector}
+}
+
+func (s *faultyService) CommitTransaction(ctx context.Context) error {
+	if err := s.injector.databaseError(ctx); err != nil {
+		// The transaction is gone on the server, as it would be after a real failure
+		s.PostgreSqlService.RollbackTransaction(ctx)
+		return err
+	}
+
+	return s.PostgreSqlService.CommitTransaction(ctx)
+}
+
+func (s *faultyService) Count(ctx context.Context, queryString string, args ...any) (int, error) {
+	if err := s.injector.databaseError(ctx); err != nil {
+		return 0, err
+	}
+
+	return s.PostgreSqlService.Count(ctx, queryString, args...)
+}
+
+func (s *faultyService) CountWithFilter(ctx context.Context, tableName string, filter map[string]sql_query.SQLCondition) (int, error) {
+	if err := s.injector.databaseError(ctx); err != nil {
+		return 0, err
+	}
+
+	return s.PostgreSqlService.CountWithFilter(ctx, tableName, filter)
+}
+
+func (s *faultyService) Execute(ctx context.Context, queryString string) error {
+	if err := s.injector.databaseError(ctx); err != nil {
+		return err
+	}
+
+	return s.PostgreSqlService.Execute(ctx, queryString)
+}
+
+func (s *faultyService) SelectOne(v any, ctx context.Context, queryString string, args ...any) error {
+	if err := s.injector.databaseError(ctx); err != nil {
+		return err
+	}
+
+	return s.PostgreSqlService.SelectOne(v, ctx, queryString, args...)
+}
+
+func (s *faultyService) SelectMany(v any, ctx context.Context, queryString string, args ...any) error {
+	if err := s.injector.databaseError(ctx); err != nil {
+		return err
+	}
+
+	return s.PostgreSqlService.SelectMany(v, ctx, queryString, args...)
+}
+
+func (s *faultyService) SelectManyCursor(v any, ctx context.Context, cursor sql_query.Cursor, queryString string, args ...any) (string, error) {
+	if err := s.injector.databaseError(ctx); err != nil {
+		return "", err
+	}
+
+	return s.PostgreSqlService.SelectManyCursor(v, ctx, cursor, queryString, args...)
+}
+
+func (s *faultyService) SelectManyByIDs(v any, ctx context.Context, tableName string, ids any, columns ...string) error {
+	if err := s.injector.databaseError(ctx); err != nil {
+		return err
+	}
+
+	return s.PostgreSqlService.SelectManyByIDs(v, ctx, tableName, ids, columns...)
+}
+
+func (s *faultyService) ExecPrepared(v any, ctx context.Context, statement sql_query.PreparedStatement) (int64, error) {
+	if err := s.injector.databaseError(ctx); err != nil {
+		return 0, err
+	}
+
+	return s.PostgreSqlService.ExecPrepared(v, ctx, statement)
+}
+
+func (s *faultyService) Explain(ctx context.Context, builder service.Buildable, analyze bool) (service.ExplainPlan, error) {
+	if err := s.injector.databaseError(ctx); err != nil {
+		return service.ExplainPlan{}, err
+	}
+
+	return s.PostgreSqlService.Explain(ctx, builder, analyze)
+}
+
+func (s *faultyService) InsertOne(ctx context.Context, queryString string, args ...any) (interface{}, error) {
+	if err := s.injector.databaseError(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.PostgreSqlService.InsertOne(ctx, queryString, args...)
+}
+
+func (s *faultyService) InsertOneWithData(
+	ctx context.Context,
+	tableName string,
+	body interface{},
+	returnOption ...service.ReturningConfig,
+) (interface{}, error) {
+	if err := s.injector.databaseError(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.PostgreSqlService.InsertOneWithData(ctx, tableName, body, returnOption...)
+}
+
+func (s *faultyService) InsertMany(ctx context.Context, queryString string, args ...any) (int64, error) {
+	if err := s.injector.databaseError(ctx); err != nil {
+		return 0, err
+	}
+
+	return s.PostgreSqlService.InsertMany(ctx, queryString, args...)
+}
+
+func (s *faultyService) InsertManyWithData(
+	ctx context.Context,
+	tableName string,
+	body interface{},
+	returnOption ...service.ReturningConfig,
+) (interface{}, error) {
+	if err := s.injector.databaseError(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.PostgreSqlService.InsertManyWithData(ctx, tableName, body, returnOption...)
+}
+
+func (s *faultyService) UpdateOne(ctx context.Context, queryString string, args ...any) (interface{}, error) {
+	if err := s.injector.databaseError(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.PostgreSqlService.UpdateOne(ctx, queryString, args...)
+}
+
+func (s *faultyService) UpdateOneWithData(
+	ctx context.Context,
+	tableName string,
+	query map[string]sql_query.SQLCondition,
+	body interface{},
+	returnOption ...service.ReturningConfig,
+) (interface{}, error) {
+	if err := s.injector.databaseError(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.PostgreSqlService.UpdateOneWithData(ctx, tableName, query, body, returnOption...)
+}
+
+func (s *faultyService) UpdateMany(ctx context.Context, queryString string, args ...any) (int64, error) {
+	if err := s.injector.databaseError(ctx); err != nil {
+		return 0, err
+	}
+
+	return s.PostgreSqlService.UpdateMany(ctx, queryString, args...)
+}
+
+func (s *faultyService) UpdateManyWithData(
+	ctx context.Context,
+	tableName string,
+	query map[string]sql_query.SQLCondition,
+	body interface{},
+	returnOption ...service.ReturningConfig,
+) (int64, error) {
+	if err := s.injector.databaseError(ctx); err != nil {
+		return 0, err
+	}
+
+	return s.PostgreSqlService.UpdateManyWithData(ctx, tableName, query, body, returnOption...)
+}
+
+func (s *faultyService) UpdateEachWithData(
+	ctx context.Context,
+	tableName string,
+	rowIdentifier string,
+	query map[string]sql_query.SQLCondition,
+	body interface{},
+) (int64, error) {
+	if err := s.injector.databaseError(ctx); err != nil {
+		return 0, err
+	}
+
+	return s.PostgreSqlService.UpdateEachWithData(ctx, tableName, rowIdentifier, query, body)
+}
+
+func (s *faultyService) SoftDeleteOne(
+	ctx context.Context,
+	tableName string,
+	filter map[string]sql_query.SQLCondition,
+	returnOption ...service.ReturningConfig,
+) (interface{}, error) {
+	if err := s.injector.databaseError(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.PostgreSqlService.SoftDeleteOne(ctx, tableName, filter, returnOption...)
+}
+
+func (s *faultyService) SoftDeleteMany(
+	ctx context.Context,
+	tableName string,
+	filter map[string]sql_query.SQLCondition,
+	returnOption ...service.ReturningConfig,
+) (int64, error) {
+	if err := s.injector.databaseError(ctx); err != nil {
+		return 0, err
+	}
+
+	return s.PostgreSqlService.SoftDeleteMany(ctx, tableName, filter, returnOption...)
+}
+
+func (s *faultyService) DeleteOne(ctx context.Context, queryString string, args ...any) (interface{}, error) {
+	if err := s.injector.databaseError(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.PostgreSqlService.DeleteOne(ctx, queryString, args...)
+}
+
+func (s *faultyService) DeleteOneWithFilter(
+	ctx context.Context,
+	tableName string,
+	filter map[string]sql_query.SQLCondition,
+) (interface{}, error) {
+	if err := s.injector.databaseError(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.PostgreSqlService.DeleteOneWithFilter(ctx, tableName, filter)
+}
+
+func (s *faultyService) DeleteMany(ctx context.Context, queryString string, args ...any) (int64, error) {
+	if err := s.injector.databaseError(ctx); err != nil {
+		return 0, err
+	}
+
+	return s.PostgreSqlService.DeleteMany(ctx, queryString, args...)
+}
+
+func (s *faultyService) DeleteManyWithFilter(
+	ctx context.Context,
+	tableName string,
+	filter map[string]sql_query.SQLCondition,
+) (int64, error) {
+	if err := s.injector.databaseError(ctx); err != nil {
+		return 0, err
+	}
+
+	return s.PostgreSqlService.DeleteManyWithFilter(ctx, tableName, filter)
+}
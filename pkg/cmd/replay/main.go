@@ -0,0 +1,138 @@
+// replay re-executes the mutating requests captured with REPLAY_CAPTURE=true (see pkg/replay), in capture order,
+// against a staging deployment, to reproduce incidents such as a balance discrepancy reported by a user.
+// Credentials are never captured, every request is sent with the -authorization header instead.
+//
+// Usage, with the database environment of the log service exported:
+//
+//	go run github.com/mystaline/clefinport-be/pkg/cmd/replay -target=https://staging.example.com \
+//	    -service=wallet_service -actor=user:42 -since=2026-10-01T00:00:00Z -authorization="Bearer ..."
+//
+// Flags:
+//   - target: base URL of the staging service, required
+//   - service: service whose requests are replayed, every service when empty
+//   - actor: only the requests of this caller, e.g. user:<id> or api_key:<id>
+//   - since, until: RFC 3339 capture time window
+//   - authorization: Authorization header sent with every request
+//   - dry-run: print the requests without sending them
+//   - stop-on-mismatch: stop at the first status differing from the captured one
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mystaline/clefinport-be/pkg/db"
+	"github.com/mystaline/clefinport-be/pkg/replay"
+	"github.com/mystaline/clefinport-be/pkg/service"
+)
+
+var errMismatch = errors.New("status mismatch")
+
+func main() {
+	target := flag.String("target", "", "base URL of the staging service")
+	serviceName := flag.String("service", "", "service whose requests are replayed")
+	actor := flag.String("actor", "", "only the requests of this caller")
+	since := flag.String("since", "", "RFC 3339 start of the capture window")
+	until := flag.String("until", "", "RFC 3339 end of the capture window")
+	authorization := flag.String("authorization", "", "Authorization header sent with every request")
+	dryRun := flag.Bool("dry-run", false, "print the requests without sending them")
+	stopOnMismatch := flag.Bool("stop-on-mismatch", false, "stop at the first status mismatch")
+	flag.Parse()
+
+	if *target == "" && !*dryRun {
+		log.Fatal("replay: -target is required")
+	}
+
+	filter := replay.Filter{Service: *serviceName, Actor: *actor}
+	filter.Since = mustParseTime("since", *since)
+	filter.Until = mustParseTime("until", *until)
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	var sent, skipped, mismatched int
+
+	err := replay.EachEnvelope(
+		context.Background(),
+		service.MakeService(db.LogServiceDBName),
+		filter,
+		func(stored replay.StoredEnvelope) error {
+			envelope := stored.Envelope
+			url := strings.TrimRight(*target, "/") + envelope.Path
+			if envelope.Query != "" {
+				url += "?" + envelope.Query
+			}
+
+			if envelope.BodyOmitted {
+				skipped++
+				log.Printf("replay: #%d %s %s skipped, its body was not captured", stored.ID, envelope.Method, url)
+				return nil
+			}
+			if *dryRun {
+				fmt.Printf("#%d %s %s %s\n", stored.ID, envelope.Method, url, envelope.Body)
+				return nil
+			}
+
+			status, err := send(client, url, envelope, *authorization)
+			if err != nil {
+				return fmt.Errorf("#%d: %w", stored.ID, err)
+			}
+			sent++
+
+			if status != envelope.Status {
+				mismatched++
+				log.Printf("replay: #%d %s %s returned %d, captured %d", stored.ID, envelope.Method, url, status, envelope.Status)
+				if *stopOnMismatch {
+					return errMismatch
+				}
+			}
+
+			return nil
+		},
+	)
+	log.Printf("replay: %d sent, %d skipped, %d status mismatches", sent, skipped, mismatched)
+	if err != nil {
+		log.Fatalf("replay: %v", err)
+	}
+}
+
+func send(client *http.Client, url string, envelope replay.Envelope, authorization string) (int, error) {
+	request, err := http.NewRequest(envelope.Method, url, bytes.NewReader(envelope.Body))
+	if err != nil {
+		return 0, err
+	}
+	for name, value := range envelope.Headers {
+		request.Header.Set(name, value)
+	}
+	if authorization != "" {
+		request.Header.Set("Authorization", authorization)
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, response.Body)
+
+	return response.StatusCode, nil
+}
+
+func mustParseTime(name, value string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		log.Fatalf("replay: -%s: %v", name, err)
+	}
+
+	return parsed
+}
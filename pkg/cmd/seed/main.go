@@ -0,0 +1,52 @@
+// seed fills the local user and wallet databases with realistic users, wallets and transactions
+// built by pkg/factory, for trying endpoints and load tests against something else than empty tables.
+//
+// Usage, with the database environment of the services exported:
+//
+//	go run github.com/mystaline/clefinport-be/pkg/cmd/seed -users=20 -wallets=2 -transactions=100
+//
+// Flags:
+//   - users: users to create (default 10)
+//   - wallets: wallets per user (default 1)
+//   - transactions: transactions per wallet (default 50)
+//   - seed: random seed, the same seed generates the same names and amounts (default time based)
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+
+	"github.com/mystaline/clefinport-be/pkg/db"
+	"github.com/mystaline/clefinport-be/pkg/factory"
+	"github.com/mystaline/clefinport-be/pkg/service"
+)
+
+func main() {
+	users := flag.Int("users", 10, "users to create")
+	wallets := flag.Int("wallets", 1, "wallets per user")
+	transactions := flag.Int("transactions", 50, "transactions per wallet")
+	seed := flag.Int64("seed", 0, "random seed, 0 for time based")
+	flag.Parse()
+
+	ctx := context.Background()
+	userService := service.MakeService(db.UserServiceDBName)
+	walletService := service.MakeService(db.WalletServiceDBName)
+
+	for i := range *users {
+		user := factory.User().
+			WithWallet(*wallets).
+			WithTransactions(*transactions).
+			WalletsIn(walletService)
+		if *seed != 0 {
+			user.Seed(*seed + int64(i))
+		}
+
+		built, err := user.Build(ctx, userService)
+		if err != nil {
+			log.Fatalf("seed: user %d: %v", i+1, err)
+		}
+
+		log.Printf("seed: user %s (%s) with %d wallets", built.ID, built.Email, len(built.Wallets))
+	}
+}
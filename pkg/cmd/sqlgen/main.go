@@ -0,0 +1,367 @@
+// sqlgen emits reflection-free ScanRow / InsertColumns / BindInsert methods for DTO structs,
+// picked up automatically by sql_query (see sql_query.RowScanner and sql_query.InsertBinder),
+// and typed column names for sql_query.Columns.
+//
+// Usage, next to the DTO declaration:
+//
+//	//go:generate go run github.com/mystaline/clefinport-be/pkg/cmd/sqlgen -type=GetWalletInfoData -mode=scan
+//
+// Flags:
+//   - type: comma separated struct names
+//   - mode: comma separated scan, insert, columns (default scan,insert)
+//   - output: output file name (default <source>_sqlgen.go)
+//
+// Regenerate whenever the tags of a listed struct change, stale methods silently drop new columns.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mystaline/clefinport-be/pkg/sql_query"
+)
+
+type field struct {
+	Name       string
+	TypeExpr   string
+	IsPointer  bool
+	Alias      string // scan: column alias matched against the result set
+	Column     string // insert: target column, empty when not inserted
+	ColumnExpr string // columns: column referenced by Where / OrderBy, empty when not selected
+	ImportRefs []string
+}
+
+type target struct {
+	Name   string
+	Fields []field
+}
+
+func main() {
+	typeNames := flag.String("type", "", "comma separated struct names")
+	mode := flag.String("mode", "scan,insert", "comma separated scan, insert, columns")
+	output := flag.String("output", "", "output file name")
+	flag.Parse()
+
+	if *typeNames == "" {
+		log.Fatal("sqlgen: -type is required")
+	}
+
+	source := os.Getenv("GOFILE")
+	if source == "" {
+		log.Fatal("sqlgen: must be run through go generate")
+	}
+	if *output == "" {
+		*output = strings.TrimSuffix(source, ".go") + "_sqlgen.go"
+	}
+
+	withScan := strings.Contains(*mode, "scan")
+	withInsert := strings.Contains(*mode, "insert")
+	withColumns := strings.Contains(*mode, "columns")
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, source, nil, parser.ParseComments)
+	if err != nil {
+		log.Fatalf("sqlgen: %v", err)
+	}
+
+	imports := map[string]string{}
+	for _, spec := range file.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		if spec.Name != nil {
+			imports[spec.Name.Name] = spec.Name.Name + " " + spec.Path.Value
+			continue
+		}
+		imports[filepath.Base(path)] = spec.Path.Value
+	}
+
+	var targets []target
+	for _, name := range strings.Split(*typeNames, ",") {
+		t, err := collect(fset, file, strings.TrimSpace(name))
+		if err != nil {
+			log.Fatalf("sqlgen: %v", err)
+		}
+		targets = append(targets, t)
+	}
+
+	code, err := render(file.Name.Name, targets, imports, withScan, withInsert, withColumns)
+	if err != nil {
+		log.Fatalf("sqlgen: %v", err)
+	}
+
+	if err := os.WriteFile(*output, code, 0o644); err != nil {
+		log.Fatalf("sqlgen: %v", err)
+	}
+}
+
+func collect(fset *token.FileSet, file *ast.File, name string) (target, error) {
+	var structType *ast.StructType
+	ast.Inspect(file, func(n ast.Node) bool {
+		spec, ok := n.(*ast.TypeSpec)
+		if !ok || spec.Name.Name != name {
+			return true
+		}
+		structType, _ = spec.Type.(*ast.StructType)
+		return false
+	})
+	if structType == nil {
+		return target{}, fmt.Errorf("struct %s not found", name)
+	}
+
+	t := target{Name: name}
+	for _, each := range structType.Fields.List {
+		// Embedded and unexported fields are skipped by the reflection path as well
+		if len(each.Names) == 0 || !each.Names[0].IsExported() {
+			continue
+		}
+
+		tag := reflect.StructTag("")
+		if each.Tag != nil {
+			raw, _ := strconv.Unquote(each.Tag.Value)
+			tag = reflect.StructTag(raw)
+		}
+		jsonTag := strings.Split(tag.Get("json"), ",")[0]
+		columnTag := tag.Get("column")
+
+		var typeBuf bytes.Buffer
+		if err := printer.Fprint(&typeBuf, fset, each.Type); err != nil {
+			return target{}, err
+		}
+
+		f := field{
+			Name:       each.Names[0].Name,
+			TypeExpr:   typeBuf.String(),
+			ImportRefs: selectorPackages(each.Type),
+		}
+		_, f.IsPointer = each.Type.(*ast.StarExpr)
+
+		// Mirrors buildColumnsFromMeta: the json tag is the alias of the selected column
+		if jsonTag != "" && jsonTag != "-" && columnTag != "-" {
+			f.Alias = jsonTag
+		}
+		f.Column = insertColumn(jsonTag, columnTag, tag.Get("special"))
+		if f.Alias != "" {
+			f.ColumnExpr = referencedColumn(jsonTag, columnTag)
+		}
+
+		t.Fields = append(t.Fields, f)
+	}
+
+	return t, nil
+}
+
+// Mirrors the template building of cachedInsertSingle.
+func insertColumn(jsonTag, columnTag, specialTag string) string {
+	if specialTag == "generated" {
+		return ""
+	}
+	if jsonTag == "_id" || jsonTag == "id" || columnTag == "id" {
+		return ""
+	}
+	if (jsonTag == "" || jsonTag == "-") && (columnTag == "" || columnTag == "-") {
+		return ""
+	}
+
+	column := sql_query.CamelToSnake(jsonTag)
+	if columnTag != "" {
+		column = columnTag[strings.Index(columnTag, ".")+1:]
+	}
+	if column == "updated_at" || column == "created_at" {
+		return ""
+	}
+
+	return column
+}
+
+// Mirrors buildColumnsFromMeta without the cast of the column tag, e.g. "id::text" is referenced as "id"
+// so filters and sorts keep using the column's index.
+func referencedColumn(jsonTag, columnTag string) string {
+	if columnTag == "" {
+		return sql_query.CamelToSnake(jsonTag)
+	}
+
+	return strings.TrimSpace(strings.Split(columnTag, "::")[0])
+}
+
+func selectorPackages(expr ast.Expr) []string {
+	var packages []string
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if selector, ok := n.(*ast.SelectorExpr); ok {
+			if ident, ok := selector.X.(*ast.Ident); ok {
+				packages = append(packages, ident.Name)
+			}
+		}
+		return true
+	})
+
+	return packages
+}
+
+func render(
+	packageName string,
+	targets []target,
+	imports map[string]string,
+	withScan bool,
+	withInsert bool,
+	withColumns bool,
+) ([]byte, error) {
+	var body bytes.Buffer
+	usedImports := map[string]bool{}
+
+	for _, t := range targets {
+		if withScan {
+			renderScan(&body, t, imports, usedImports)
+		}
+		if withInsert {
+			renderInsert(&body, t, imports, usedImports)
+		}
+		if withColumns {
+			renderColumns(&body, t)
+		}
+	}
+
+	var out bytes.Buffer
+	out.WriteString("// Code generated by sqlgen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&out, "package %s\n\n", packageName)
+
+	// Standard library first, then everything else, like goimports
+	var stdImports, otherImports []string
+	for each := range usedImports {
+		path := each[strings.Index(each, `"`)+1:]
+		if strings.Contains(strings.Split(path, "/")[0], ".") {
+			otherImports = append(otherImports, each)
+		} else {
+			stdImports = append(stdImports, each)
+		}
+	}
+	sort.Strings(stdImports)
+	sort.Strings(otherImports)
+	if len(usedImports) > 0 {
+		out.WriteString("import (\n")
+		for _, each := range stdImports {
+			fmt.Fprintf(&out, "\t%s\n", each)
+		}
+		if len(stdImports) > 0 && len(otherImports) > 0 {
+			out.WriteString("\n")
+		}
+		for _, each := range otherImports {
+			fmt.Fprintf(&out, "\t%s\n", each)
+		}
+		out.WriteString(")\n\n")
+	}
+
+	out.Write(body.Bytes())
+
+	return format.Source(out.Bytes())
+}
+
+func renderScan(body *bytes.Buffer, t target, imports map[string]string, usedImports map[string]bool) {
+	usedImports[`"github.com/jackc/pgx/v5"`] = true
+
+	fmt.Fprintf(body, "// ScanRow implements sql_query.RowScanner for %s.\n", t.Name)
+	fmt.Fprintf(body, "func (d *%s) ScanRow(rows pgx.Rows) error {\n", t.Name)
+
+	// Scan into pointers first so NULL columns leave the zero value, same as the JSON path
+	body.WriteString("var (\n")
+	for i, f := range t.Fields {
+		if f.Alias == "" {
+			continue
+		}
+		markImports(f, imports, usedImports)
+		if f.IsPointer {
+			fmt.Fprintf(body, "f%d %s\n", i, f.TypeExpr)
+		} else {
+			fmt.Fprintf(body, "f%d *%s\n", i, f.TypeExpr)
+		}
+	}
+	body.WriteString(")\n\n")
+
+	body.WriteString("fields := rows.FieldDescriptions()\n")
+	body.WriteString("dest := make([]any, len(fields))\n")
+	body.WriteString("for i, field := range fields {\n")
+	body.WriteString("switch field.Name {\n")
+	for i, f := range t.Fields {
+		if f.Alias == "" {
+			continue
+		}
+		fmt.Fprintf(body, "case %q:\ndest[i] = &f%d\n", f.Alias, i)
+	}
+	body.WriteString("default:\ndest[i] = new(any)\n")
+	body.WriteString("}\n}\n\n")
+
+	body.WriteString("if err := rows.Scan(dest...); err != nil {\nreturn err\n}\n\n")
+	for i, f := range t.Fields {
+		if f.Alias == "" {
+			continue
+		}
+		if f.IsPointer {
+			fmt.Fprintf(body, "d.%s = f%d\n", f.Name, i)
+			continue
+		}
+		fmt.Fprintf(body, "if f%d != nil {\nd.%s = *f%d\n}\n", i, f.Name, i)
+	}
+	body.WriteString("\nreturn nil\n}\n\n")
+}
+
+func renderInsert(body *bytes.Buffer, t target, imports map[string]string, usedImports map[string]bool) {
+	var columns []string
+	var values []string
+	for _, f := range t.Fields {
+		if f.Column == "" {
+			continue
+		}
+		columns = append(columns, "`\""+f.Column+"\"`")
+		values = append(values, "d."+f.Name)
+	}
+
+	fmt.Fprintf(body, "// InsertColumns implements sql_query.InsertBinder for %s.\n", t.Name)
+	fmt.Fprintf(body, "func (d %s) InsertColumns() []string {\n", t.Name)
+	fmt.Fprintf(body, "return []string{%s}\n}\n\n", strings.Join(columns, ", "))
+
+	fmt.Fprintf(body, "// BindInsert implements sql_query.InsertBinder for %s.\n", t.Name)
+	fmt.Fprintf(body, "func (d %s) BindInsert() []any {\n", t.Name)
+	fmt.Fprintf(body, "return []any{%s}\n}\n\n", strings.Join(values, ", "))
+}
+
+func renderColumns(body *bytes.Buffer, t target) {
+	fmt.Fprintf(body, "// %sColumns holds the column of every selected field of %s.\n", t.Name, t.Name)
+	fmt.Fprintf(body, "type %sColumns struct {\n", t.Name)
+	for _, f := range t.Fields {
+		if f.ColumnExpr == "" {
+			continue
+		}
+		fmt.Fprintf(body, "%s string\n", f.Name)
+	}
+	body.WriteString("}\n\n")
+
+	fmt.Fprintf(body, "// Columns implements sql_query.ColumnSet for %s.\n", t.Name)
+	fmt.Fprintf(body, "func (d %s) Columns() %sColumns {\n", t.Name, t.Name)
+	fmt.Fprintf(body, "return %sColumns{\n", t.Name)
+	for _, f := range t.Fields {
+		if f.ColumnExpr == "" {
+			continue
+		}
+		fmt.Fprintf(body, "%s: %q,\n", f.Name, f.ColumnExpr)
+	}
+	body.WriteString("}\n}\n\n")
+}
+
+func markImports(f field, imports map[string]string, usedImports map[string]bool) {
+	for _, each := range f.ImportRefs {
+		if spec, ok := imports[each]; ok {
+			usedImports[spec] = true
+		}
+	}
+}
@@ -0,0 +1,344 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/mystaline/clefinport-be/pkg/db"
+	"github.com/mystaline/clefinport-be/pkg/service"
+	"github.com/mystaline/clefinport-be/pkg/sql_query"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Runtime settings: operational values (rate limits, pagination caps, feature toggles) stored in the settings
+// table of a service's database, so they can be tuned through the admin API without a redeploy.
+// Every instance keeps them in memory: a change is announced with NOTIFY and each listening instance reloads,
+// a periodic reload covers the notifications missed while its connection was down.
+// Readers pass their default, a setting missing or of the wrong type falls back to it.
+//
+// Expected table:
+//
+//	CREATE TABLE settings (
+//	    id         bigint      PRIMARY KEY,
+//	    key        text        NOT NULL UNIQUE,
+//	    value      jsonb       NOT NULL,
+//	    updated_by text        NOT NULL,
+//	    created_at timestamptz NOT NULL,
+//	    updated_at timestamptz NOT NULL
+//	);
+
+// NotifyChannel is the LISTEN/NOTIFY channel of setting changes, the payload is the changed key.
+const NotifyChannel = "settings_changed"
+
+var (
+	ErrInvalidKey      = errors.New("setting key must be lower case letters, digits, dots and underscores")
+	ErrSettingNotFound = errors.New("setting not found")
+)
+
+var keyRegexp = regexp.MustCompile(`^[a-z0-9_]+(\.[a-z0-9_]+)*$`)
+
+type Setting struct {
+	Key       string          `json:"key"       column:"key"`
+	Value     json.RawMessage `json:"value"     column:"value"`
+	UpdatedBy string          `json:"updatedBy" column:"updated_by"`
+	UpdatedAt time.Time       `json:"updatedAt" column:"updated_at"`
+}
+
+type insertSetting struct {
+	Key       string          `json:"key"       column:"key"`
+	Value     json.RawMessage `json:"value"     column:"value"`
+	UpdatedBy string          `json:"updatedBy" column:"updated_by"`
+}
+
+type Settings struct {
+	Service service.PostgreSqlService
+
+	mu     sync.RWMutex
+	values map[string]Setting
+}
+
+func MakeSettings(svc service.PostgreSqlService) *Settings {
+	return &Settings{
+		Service: svc,
+		values:  map[string]Setting{},
+	}
+}
+
+// Int returns the setting of key as an int, fallback when it is missing or not a number.
+func (s *Settings) Int(key string, fallback int) int {
+	var value int
+	if !s.Decode(key, &value) {
+		return fallback
+	}
+
+	return value
+}
+
+// Bool returns the setting of key as a bool, fallback when it is missing or not a boolean.
+func (s *Settings) Bool(key string, fallback bool) bool {
+	var value bool
+	if !s.Decode(key, &value) {
+		return fallback
+	}
+
+	return value
+}
+
+// String returns the setting of key as a string, fallback when it is missing or not a string.
+func (s *Settings) String(key string, fallback string) string {
+	var value string
+	if !s.Decode(key, &value) {
+		return fallback
+	}
+
+	return value
+}
+
+// Duration returns the setting of key stored as a duration string such as "15s",
+// fallback when it is missing or not a duration.
+func (s *Settings) Duration(key string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(s.String(key, ""))
+	if err != nil {
+		return fallback
+	}
+
+	return value
+}
+
+// Decode unmarshals the setting of key into v, false when it is missing or doesn't fit v.
+func (s *Settings) Decode(key string, v any) bool {
+	s.mu.RLock()
+	setting, ok := s.values[key]
+	s.mu.RUnlock()
+	if !ok {
+		return false
+	}
+
+	return json.Unmarshal(setting.Value, v) == nil
+}
+
+// List returns the stored settings by key.
+func (s *Settings) List(ctx context.Context) ([]Setting, error) {
+	query, args, err := sql_query.NewSQLSelectBuilder[Setting](db.SettingTableName).
+		OrderBy([]string{"key"}, true).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+
+	settings := []Setting{}
+	if err := s.Service.SelectMany(&settings, ctx, query, args...); err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+// get reads key on svc, the service of the settings or the one of a transaction.
+func get(ctx context.Context, svc service.PostgreSqlService, key string) (Setting, error) {
+	query, args, err := sql_query.NewSQLSelectBuilder[Setting](db.SettingTableName).
+		Where(map[string]sql_query.SQLCondition{
+			"key": {Operator: sql_query.SQLOperatorEqual, Value: key},
+		}).
+		SetLimit(1).
+		Build()
+	if err != nil {
+		return Setting{}, err
+	}
+
+	var setting Setting
+	if err := svc.SelectOne(&setting, ctx, query, args...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Setting{}, ErrSettingNotFound
+		}
+		return Setting{}, err
+	}
+
+	return setting, nil
+}
+
+// Set stores value under key and notifies every listening instance, this one is updated right away.
+func (s *Settings) Set(ctx context.Context, key string, value any, actor string) (*Setting, error) {
+	if !keyRegexp.MatchString(key) {
+		return nil, ErrInvalidKey
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	setting, err := s.notifying(ctx, key, func(txService service.PostgreSqlService) (Setting, error) {
+		query, args, err := sql_query.NewSQLInsertBuilder(db.SettingTableName).
+			UpsertMany(
+				[]insertSetting{{Key: key, Value: encoded, UpdatedBy: actor}},
+				[]string{"key"},
+				[]string{"value", "updated_by"},
+			).
+			Build()
+		if err != nil {
+			return Setting{}, err
+		}
+		if _, err := txService.GetTransaction().Exec(ctx, query, args...); err != nil {
+			return Setting{}, err
+		}
+
+		return get(ctx, txService, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.values[key] = setting
+	s.mu.Unlock()
+
+	return &setting, nil
+}
+
+// Delete removes the setting of key, readers fall back to their default again.
+func (s *Settings) Delete(ctx context.Context, key string) error {
+	_, err := s.notifying(ctx, key, func(txService service.PostgreSqlService) (Setting, error) {
+		deleted, err := txService.DeleteManyWithFilter(ctx, db.SettingTableName, map[string]sql_query.SQLCondition{
+			"key": {Operator: sql_query.SQLOperatorEqual, Value: key},
+		})
+		if err == nil && deleted == 0 {
+			err = ErrSettingNotFound
+		}
+		return Setting{}, err
+	})
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	delete(s.values, key)
+	s.mu.Unlock()
+
+	return nil
+}
+
+// notifying runs write and the NOTIFY of key in one transaction, listeners are only told once it is committed.
+// write gets a service of the transaction, the one of the settings is shared with Watch and concurrent requests.
+func (s *Settings) notifying(ctx context.Context, key string, write func(txService service.PostgreSqlService) (Setting, error)) (Setting, error) {
+	return service.UseTransactions(ctx, s.Service.GetPool(), func(tx pgx.Tx) (Setting, error) {
+		setting, err := write(service.TransactionService(s.Service, tx))
+		if err != nil {
+			return Setting{}, err
+		}
+
+		_, err = tx.Exec(ctx, "SELECT pg_notify($1, $2)", NotifyChannel, key)
+		return setting, err
+	})
+}
+
+// Reload replaces the settings in memory with the stored ones.
+func (s *Settings) Reload(ctx context.Context) error {
+	settings, err := s.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	values := make(map[string]Setting, len(settings))
+	for _, setting := range settings {
+		values[setting.Key] = setting
+	}
+
+	s.mu.Lock()
+	s.values = values
+	s.mu.Unlock()
+
+	return nil
+}
+
+// Watch loads the settings, then keeps them up to date in the background until ctx is done:
+// it reloads on every notification and at least every interval.
+// Without a LISTEN capable pool (e.g. a mock) it only reloads every interval.
+func (s *Settings) Watch(ctx context.Context, interval time.Duration) {
+	if err := s.Reload(ctx); err != nil {
+		log.Printf("settings: initial load failed, defaults apply until the next reload: %v", err)
+	}
+
+	go func() {
+		for {
+			err := s.listen(ctx, interval)
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("settings: listening stopped, retrying: %v", err)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(interval):
+			}
+		}
+	}()
+}
+
+// listen holds a connection subscribed to NotifyChannel and reloads on each notification or interval.
+func (s *Settings) listen(ctx context.Context, interval time.Duration) error {
+	pool, ok := s.Service.GetPool().(*pgxpool.Pool)
+	if !ok {
+		return s.poll(ctx, interval)
+	}
+
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+NotifyChannel); err != nil {
+		return err
+	}
+	// Changes made while not listening were never notified to this instance
+	if err := s.Reload(ctx); err != nil {
+		return err
+	}
+
+	for {
+		waitCtx, cancel := context.WithTimeout(ctx, interval)
+		_, err := conn.Conn().WaitForNotification(waitCtx)
+		cancel()
+
+		switch {
+		case ctx.Err() != nil:
+			return ctx.Err()
+		case errors.Is(err, context.DeadlineExceeded):
+			// Periodic reload, nothing was notified
+		case err != nil:
+			// The connection may be broken, never hand it back to the pool
+			conn.Hijack().Close(context.Background())
+			return fmt.Errorf("wait for notification: %w", err)
+		}
+
+		if err := s.Reload(ctx); err != nil {
+			log.Printf("settings: reload failed: %v", err)
+		}
+	}
+}
+
+func (s *Settings) poll(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := s.Reload(ctx); err != nil {
+				log.Printf("settings: reload failed: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/mystaline/clefinport-be/pkg/service"
+)
+
+func TestDeleteRunsOnItsTransaction(t *testing.T) {
+	tx := &service.MockPgxTx{}
+	tx.On("Exec", mock.Anything, mock.MatchedBy(func(sql string) bool { return sql != "SELECT pg_notify($1, $2)" }), mock.Anything).
+		Return(pgconn.NewCommandTag("DELETE 1"), nil).Once()
+	tx.On("Exec", mock.Anything, "SELECT pg_notify($1, $2)", []any{NotifyChannel, "apikey.rate_limit_per_minute"}).
+		Return(pgconn.NewCommandTag("SELECT 1"), nil).Once()
+	tx.On("Commit", mock.Anything).Return(nil)
+	tx.On("Rollback", mock.Anything).Return(nil).Maybe()
+
+	pool := &service.MockPgxPool{}
+	pool.On("Begin", mock.Anything).Return(tx, nil)
+
+	// Without a SetTransaction expectation, the shared service panics if the write moves it into tx
+	svc := &service.MockBasePostgreSqlService{}
+	svc.On("GetPool").Return(pool)
+
+	if err := MakeSettings(svc).Delete(context.Background(), "apikey.rate_limit_per_minute"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	tx.AssertExpectations(t)
+}
@@ -6,6 +6,7 @@ import (
 	"log"
 	"net"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -17,6 +18,8 @@ import (
 
 var (
 	pools         = make(map[string]*pgxpool.Pool)
+	poolsMu       sync.RWMutex // Guards pools and sshClients, never held across a network call
+	createMu      sync.Mutex   // One pool creation at a time, the others wait for it and take its pool
 	snowflakeOnce sync.Once
 	Node          *snowflake.Node
 	sshClients    = make(map[string]*ssh.Client)
@@ -38,18 +41,24 @@ func ConnectPostgres(dbName DBName) *pgxpool.Pool {
 	key := string(dbName)
 
 	// 1. Check for an existing, healthy pool.
-	if pool, ok := pools[key]; ok && pool != nil {
-		if err := pool.Ping(context.Background()); err == nil {
+	// Runs on every MakeService, the ping is bounded so a hung database only slows its own requests.
+	if pool := storedPool(key); pool != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+		err := pool.Ping(ctx)
+		cancel()
+		if err == nil {
 			return pool // Pool is healthy, reuse it.
 		}
 		// Pool is unhealthy. Close it and its associated SSH client before creating a new one.
 		log.Printf("Unhealthy pool for '%s' detected, closing old resources.", key)
-		pool.Close()
-		if client, ok := sshClients[key]; ok && client != nil {
-			client.Close()
-		}
-		delete(pools, key)
-		delete(sshClients, key)
+		dropPool(key, pool)
+	}
+
+	// Warmup runs in the background while requests may already arrive, only one of them creates the pool
+	createMu.Lock()
+	defer createMu.Unlock()
+	if pool := storedPool(key); pool != nil {
+		return pool
 	}
 
 	// 2. Gather all configuration details first.
@@ -109,6 +118,10 @@ func ConnectPostgres(dbName DBName) *pgxpool.Pool {
 	config.MaxConnIdleTime = 5 * time.Minute
 	config.MaxConnLifetime = 2 * time.Hour
 	config.HealthCheckPeriod = 1 * time.Minute
+	if minConns, err := strconv.Atoi(os.Getenv("DB_MIN_CONNS")); err == nil && minConns > 0 {
+		config.MinConns = int32(minConns)
+	}
+	config.AfterConnect = afterConnect(dbName)
 
 	// 5. Now, create the pool using the fully prepared config.
 	pool, err := pgxpool.NewWithConfig(context.Background(), config)
@@ -127,11 +140,41 @@ func ConnectPostgres(dbName DBName) *pgxpool.Pool {
 	}
 
 	// 6. Store the new pool and its SSH client for future use.
+	poolsMu.Lock()
 	pools[key] = pool
 	if sshClient != nil {
 		sshClients[key] = sshClient
 	}
+	poolsMu.Unlock()
 
 	log.Printf("Connected to PostgreSQL database: %s\n", dbName)
 	return pool
 }
+
+// pingTimeout bounds the health check of a stored pool
+const pingTimeout = 2 * time.Second
+
+func storedPool(key string) *pgxpool.Pool {
+	poolsMu.RLock()
+	defer poolsMu.RUnlock()
+
+	return pools[key]
+}
+
+// dropPool closes pool and its SSH client, unless a concurrent caller already replaced it.
+func dropPool(key string, pool *pgxpool.Pool) {
+	poolsMu.Lock()
+	if pools[key] != pool {
+		poolsMu.Unlock()
+		return
+	}
+	client := sshClients[key]
+	delete(pools, key)
+	delete(sshClients, key)
+	poolsMu.Unlock()
+
+	pool.Close()
+	if client != nil {
+		client.Close()
+	}
+}
@@ -0,0 +1,116 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// SessionTimeouts are server side guards set on every pooled connection, zero keeps the server default.
+// IdleInTransaction makes Postgres end sessions that sit in an open transaction, e.g. a holdCommit
+// transaction that is never committed, so its locks don't outlive the mistake.
+type SessionTimeouts struct {
+	Statement         time.Duration
+	Lock              time.Duration
+	IdleInTransaction time.Duration
+}
+
+// DefaultSessionTimeouts apply to databases without SetSessionTimeouts.
+var DefaultSessionTimeouts = SessionTimeouts{
+	Statement:         30 * time.Second,
+	Lock:              5 * time.Second,
+	IdleInTransaction: time.Minute,
+}
+
+var (
+	sessionTimeouts   = make(map[DBName]SessionTimeouts)
+	sessionTimeoutsMu sync.RWMutex
+)
+
+// SetSessionTimeouts registers the defaults of a database, call it before the first ConnectPostgres.
+// DB_STATEMENT_TIMEOUT, DB_LOCK_TIMEOUT and DB_IDLE_IN_TRANSACTION_TIMEOUT (e.g. "15s") still override them.
+func SetSessionTimeouts(dbName DBName, timeouts SessionTimeouts) {
+	sessionTimeoutsMu.Lock()
+	defer sessionTimeoutsMu.Unlock()
+
+	sessionTimeouts[dbName] = timeouts
+}
+
+// SessionTimeoutsFor returns the timeouts applied to new connections of dbName.
+func SessionTimeoutsFor(dbName DBName) SessionTimeouts {
+	sessionTimeoutsMu.RLock()
+	timeouts, ok := sessionTimeouts[dbName]
+	sessionTimeoutsMu.RUnlock()
+	if !ok {
+		timeouts = DefaultSessionTimeouts
+	}
+
+	for env, target := range map[string]*time.Duration{
+		"DB_STATEMENT_TIMEOUT":           &timeouts.Statement,
+		"DB_LOCK_TIMEOUT":                &timeouts.Lock,
+		"DB_IDLE_IN_TRANSACTION_TIMEOUT": &timeouts.IdleInTransaction,
+	} {
+		value := os.Getenv(env)
+		if value == "" {
+			continue
+		}
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			log.Printf("ignoring invalid %s %q: %v", env, value, err)
+			continue
+		}
+		*target = duration
+	}
+
+	return timeouts
+}
+
+type execer interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+// Apply sets the non-zero timeouts on conn (a *pgx.Conn or pgx.Tx),
+// local limits them to the current transaction like SET LOCAL.
+func (t SessionTimeouts) Apply(ctx context.Context, conn execer, local bool) error {
+	var settings []string
+	var args []any
+	for _, each := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"statement_timeout", t.Statement},
+		{"lock_timeout", t.Lock},
+		{"idle_in_transaction_session_timeout", t.IdleInTransaction},
+	} {
+		if each.value <= 0 {
+			continue
+		}
+		args = append(args, fmt.Sprintf("%dms", each.value.Milliseconds()))
+		settings = append(settings, fmt.Sprintf("set_config('%s', $%d, %t)", each.name, len(args), local))
+	}
+	if len(settings) == 0 {
+		return nil
+	}
+
+	_, err := conn.Exec(ctx, "SELECT "+strings.Join(settings, ", "), args...)
+	return err
+}
+
+// afterConnect applies the session timeouts of dbName to every new pool connection.
+func afterConnect(dbName DBName) func(ctx context.Context, conn *pgx.Conn) error {
+	timeouts := SessionTimeoutsFor(dbName)
+
+	return func(ctx context.Context, conn *pgx.Conn) error {
+		if err := timeouts.Apply(ctx, conn, false); err != nil {
+			return fmt.Errorf("failed to set session timeouts: %w", err)
+		}
+		return nil
+	}
+}
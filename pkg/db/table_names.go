@@ -1,16 +1,25 @@
 package db
 
 const (
-	CategoryTableName       = "categories"
-	ChangeLogTableName      = "change_logs"
-	EventLogTableName       = "event_logs"
-	LogOutboxTableName      = "log_outboxes"
-	ProfileSettingTableName = "profile_settings"
-	SessionLogTableName     = "session_logs"
-	TransactionTableName    = "transactions"
-	UserTableName           = "users"
-	UserOutboxTableName     = "user_outboxes"
-	UserWalletTableName     = "user_wallets"
-	WalletTableName         = "wallets"
-	WalletOutboxTableName   = "wallet_outboxes"
+	APIKeyTableName             = "api_keys"
+	CategoryTableName           = "categories"
+	ChangeLogTableName          = "change_logs"
+	EventLogTableName           = "event_logs"
+	ImportProfileTableName      = "import_mapping_profiles"
+	JobQueueTableName           = "job_queue"
+	LogOutboxTableName          = "log_outboxes"
+	PIITokenTableName           = "pii_tokens"
+	ProfileSettingTableName     = "profile_settings"
+	SessionLogTableName         = "session_logs"
+	SettingTableName            = "settings"
+	SystemCategoryNameTableName = "system_category_names"
+	TableMetricTableName        = "table_metrics"
+	TransactionTableName        = "transactions"
+	UserTableName               = "users"
+	UserOutboxTableName         = "user_outboxes"
+	UserWalletTableName         = "user_wallets"
+	WalletMemberTableName       = "wallet_members"
+	WalletEventTableName        = "wallet_events"
+	WalletTableName             = "wallets"
+	WalletOutboxTableName       = "wallet_outboxes"
 )
@@ -0,0 +1,61 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type WarmupConfig struct {
+	// Run `SELECT 1` on every warmed connection before reporting success
+	Canary bool
+	// Upper bound for the whole warmup of one database
+	Timeout time.Duration
+}
+
+// WarmupPool connects to dbName and pre-establishes MinConns connections (DB_MIN_CONNS, at least 1),
+// so the first requests don't pay for TCP/TLS/auth handshakes.
+func WarmupPool(ctx context.Context, dbName DBName, config WarmupConfig) error {
+	if config.Timeout <= 0 {
+		config.Timeout = 30 * time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, config.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	pool := ConnectPostgres(dbName)
+
+	target := int(pool.Config().MinConns)
+	if target < 1 {
+		target = 1
+	}
+
+	// Hold all connections at once, otherwise the pool keeps handing back the same one
+	conns := make([]*pgxpool.Conn, 0, target)
+	defer func() {
+		for _, conn := range conns {
+			conn.Release()
+		}
+	}()
+
+	for i := 0; i < target; i++ {
+		conn, err := pool.Acquire(ctx)
+		if err != nil {
+			return fmt.Errorf("warmup %s: failed to acquire connection %d/%d: %w", dbName, i+1, target, err)
+		}
+		conns = append(conns, conn)
+
+		if config.Canary {
+			if _, err := conn.Exec(ctx, "SELECT 1"); err != nil {
+				return fmt.Errorf("warmup %s: canary query failed: %w", dbName, err)
+			}
+		}
+	}
+
+	log.Printf("Warmed up %d connection(s) for %s in %s", target, dbName, time.Since(start))
+	return nil
+}
@@ -0,0 +1,31 @@
+package delivery
+
+import (
+	"log"
+	"os"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/compress"
+)
+
+// Compression compresses responses with br, gzip or deflate, whichever the client prefers in Accept-Encoding.
+// HTTP_COMPRESSION picks the level: off, speed, default (when unset) or best.
+// Streamed responses (StreamJSONArray) are compressed on the fly as well.
+func Compression() fiber.Handler {
+	level := compress.LevelDefault
+
+	switch value := strings.ToLower(os.Getenv("HTTP_COMPRESSION")); value {
+	case "", "default":
+	case "off":
+		level = compress.LevelDisabled
+	case "speed":
+		level = compress.LevelBestSpeed
+	case "best":
+		level = compress.LevelBestCompression
+	default:
+		log.Printf("unknown HTTP_COMPRESSION %q, using default", value)
+	}
+
+	return compress.New(compress.Config{Level: level})
+}
@@ -0,0 +1,25 @@
+package delivery
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// DryRunQuery is the query parameter asking a write endpoint to only simulate, e.g. POST /v1/wallet/:id/transfer?dryRun=true
+	DryRunQuery = "dryRun"
+	// DryRunHeader is set on responses of simulated writes, nothing of their result was stored
+	DryRunHeader = "X-Dry-Run"
+)
+
+// IsDryRun reads DryRunQuery and flags the response with DryRunHeader when set.
+// Pass the result to service.WithDryRun and service.TxOptions.DryRun.
+func IsDryRun(ctx *fiber.Ctx) bool {
+	dryRun, _ := strconv.ParseBool(ctx.Query(DryRunQuery))
+	if dryRun {
+		ctx.Set(DryRunHeader, "true")
+	}
+
+	return dryRun
+}
@@ -0,0 +1,37 @@
+package delivery
+
+import (
+	"sync/atomic"
+
+	"github.com/mystaline/clefinport-be/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Readiness tracks whether the service finished its startup work (pool warmup, cache priming).
+// /health only reports the process is alive, /ready returns 503 until MarkReady is called.
+type Readiness struct {
+	ready atomic.Bool
+}
+
+func (r *Readiness) MarkReady() {
+	r.ready.Store(true)
+}
+
+func (r *Readiness) IsReady() bool {
+	return r.ready.Load()
+}
+
+// Register mounts GET /health and GET /ready on app.
+func (r *Readiness) Register(app *fiber.App) {
+	app.Get("/health", func(ctx *fiber.Ctx) error {
+		return response.Success(ctx, "ok", nil)
+	})
+	app.Get("/ready", func(ctx *fiber.Ctx) error {
+		if !r.IsReady() {
+			return response.SendResponse(ctx, fiber.StatusServiceUnavailable, nil, "warming up")
+		}
+
+		return response.Success(ctx, "ready", nil)
+	})
+}
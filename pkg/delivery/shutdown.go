@@ -0,0 +1,52 @@
+package delivery
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// ShutdownTimeout bounds the stop of Serve, and then its cleanups.
+const ShutdownTimeout = 15 * time.Second
+
+// Serve runs serve until it returns or the process gets SIGINT or SIGTERM, which calls stop to end serve gracefully.
+// The cleanups run in order once serve is done either way, e.g. to flush the buffers of background writers.
+//
+// Example:
+//
+//	delivery.Serve(
+//	    func() error { return app.Listen(":8080") },
+//	    app.ShutdownWithContext,
+//	    auditWriter.Close,
+//	)
+func Serve(serve func() error, stop func(ctx context.Context) error, cleanups ...func(ctx context.Context) error) error {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	served := make(chan error, 1)
+	go func() {
+		served <- serve()
+	}()
+
+	var errs []error
+	select {
+	case err := <-served:
+		errs = append(errs, err)
+	case <-signals:
+		ctx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout)
+		errs = append(errs, stop(ctx), <-served)
+		cancel()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout)
+	defer cancel()
+	for _, cleanup := range cleanups {
+		errs = append(errs, cleanup(ctx))
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,48 @@
+package delivery
+
+import (
+	"context"
+	"errors"
+	"syscall"
+	"testing"
+)
+
+func TestServe(t *testing.T) {
+	t.Run("serve fails", func(t *testing.T) {
+		errListen := errors.New("address already in use")
+		cleaned := false
+
+		err := Serve(
+			func() error { return errListen },
+			func(ctx context.Context) error { t.Fatal("stop called without signal"); return nil },
+			func(ctx context.Context) error { cleaned = true; return nil },
+		)
+		if !errors.Is(err, errListen) || !cleaned {
+			t.Fatalf("Serve() error = %v, cleaned = %v, want %v and cleaned", err, cleaned, errListen)
+		}
+	})
+
+	t.Run("signal", func(t *testing.T) {
+		stopped := make(chan struct{})
+		var order []string
+
+		err := Serve(
+			func() error {
+				if err := syscall.Kill(syscall.Getpid(), syscall.SIGTERM); err != nil {
+					return err
+				}
+				<-stopped
+				order = append(order, "served")
+				return nil
+			},
+			func(ctx context.Context) error { close(stopped); return nil },
+			func(ctx context.Context) error { order = append(order, "cleanup"); return nil },
+		)
+		if err != nil {
+			t.Fatalf("Serve() error = %v", err)
+		}
+		if len(order) != 2 || order[0] != "served" || order[1] != "cleanup" {
+			t.Fatalf("order = %v, want [served cleanup]", order)
+		}
+	})
+}
@@ -0,0 +1,133 @@
+package delivery
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/mystaline/clefinport-be/pkg/entity"
+	"github.com/mystaline/clefinport-be/pkg/response"
+)
+
+const (
+	// Encoded elements waiting to be written, bounds memory when the client reads slower than the database
+	streamBuffer = 64
+	// Elements written between flushes to the client
+	streamFlushEvery = 100
+)
+
+// EmitFunc writes one element of a streamed array, it fails once the client is gone or the timeout hit.
+type EmitFunc[T any] func(element T) error
+
+// StreamJSONArray sends the same envelope as RunHTTPWithTimeout with data written as a JSON array
+// element by element while produce emits them (e.g. from service.SelectEach), instead of building the whole slice first.
+//
+// The response only starts with the first element: an error returned before it is sent as a regular error response.
+// Once streaming, the status is already sent, so a later error is appended as the envelope's error field:
+//
+//	{"status":200,"message":"...","data":[{...},{...}],"error":"Timeout"}
+//
+// Example:
+//
+//	return delivery.StreamJSONArray(ctx, 5*time.Minute, func(ctx context.Context, emit delivery.EmitFunc[Record]) error {
+//	    return service.SelectEach(ctx, svc, query, args, func(record Record) error { return emit(record) })
+//	}, "Successfully export records")
+func StreamJSONArray[T any](
+	ctx *fiber.Ctx,
+	timeout time.Duration,
+	produce func(ctx context.Context, emit EmitFunc[T]) error,
+	successMessage string,
+) error {
+	// Outlives the handler, the body is written after it returns
+	streamCtx, cancel := context.WithTimeout(context.Background(), timeout)
+
+	items := make(chan []byte, streamBuffer)
+	done := make(chan error, 1)
+
+	go func() {
+		defer close(items)
+
+		done <- produce(streamCtx, func(element T) error {
+			encoded, err := json.Marshal(element)
+			if err != nil {
+				return err
+			}
+
+			select {
+			case items <- encoded:
+				return nil
+			case <-streamCtx.Done():
+				return streamCtx.Err()
+			}
+		})
+	}()
+
+	var first []byte
+	select {
+	case encoded, ok := <-items:
+		if !ok {
+			err := <-done
+			cancel()
+
+			if err != nil {
+				e := entity.ToHttpError(err)
+				return response.SendResponse(ctx, e.Code, e.Data, e.Message)
+			}
+			return response.SendResponse(ctx, fiber.StatusOK, []T{}, successMessage)
+		}
+		first = encoded
+	case <-streamCtx.Done():
+		cancel()
+		return response.SendResponse(ctx, fiber.StatusRequestTimeout, nil, "Timeout")
+	}
+
+	message, _ := json.Marshal(successMessage)
+
+	ctx.Status(fiber.StatusOK)
+	ctx.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	ctx.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer cancel()
+
+		w.WriteString(`{"status":` + strconv.Itoa(fiber.StatusOK) + `,"message":`)
+		w.Write(message)
+		w.WriteString(`,"data":[`)
+		w.Write(first)
+
+		written := 1
+		for encoded := range items {
+			w.WriteByte(',')
+			w.Write(encoded)
+
+			written++
+			if written%streamFlushEvery == 0 {
+				if err := w.Flush(); err != nil {
+					// Client is gone, stop the producer and let it drain
+					cancel()
+					for range items {
+					}
+					return
+				}
+			}
+		}
+		w.WriteByte(']')
+
+		if err := <-done; err != nil {
+			failure := "Timeout"
+			if streamCtx.Err() == nil {
+				failure = entity.ToHttpError(err).Message
+			}
+			encodedErr, _ := json.Marshal(failure)
+			w.WriteString(`,"error":`)
+			w.Write(encodedErr)
+		}
+
+		w.WriteByte('}')
+		w.Flush()
+	})
+
+	return nil
+}
@@ -1,8 +1,10 @@
 package dto
 
 type PaginationResult[T any] struct {
-	TotalRecords int `json:"totalRecords" bson:"totalRecords"`
-	Data         []T `json:"data"         bson:"data"`
+	TotalRecords int  `json:"totalRecords" bson:"totalRecords"`
+	TotalPages   int  `json:"totalPages"   bson:"totalPages"`
+	HasNextPage  bool `json:"hasNextPage"  bson:"hasNextPage"`
+	Data         []T  `json:"data"         bson:"data"`
 }
 
 type SetSoftDelete struct {
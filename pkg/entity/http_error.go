@@ -17,6 +17,12 @@ func (e *HttpError) Error() string {
 	return e.Message
 }
 
+// Unwrap returns Err when it is an error, so errors.Is and gRPC status conversion see through.
+func (e *HttpError) Unwrap() error {
+	err, _ := e.Err.(error)
+	return err
+}
+
 func (e *HttpError) SendResponse(ctx *fiber.Ctx) error {
 	return response.SendResponse(ctx, e.Code, nil, e.Message)
 }
@@ -67,10 +73,21 @@ func Conflict(message string) *HttpError {
 	}
 }
 
+func TooManyRequests(message string) *HttpError {
+	return &HttpError{
+		Code:    fiber.StatusTooManyRequests,
+		Message: message,
+	}
+}
+
 func ToHttpError(err error) *HttpError {
 	if httpErr, ok := err.(*HttpError); ok {
 		return httpErr
 	}
+	// Domain errors knowing their status, e.g. walleterror.Error
+	if mapped, ok := err.(interface{ HttpError() *HttpError }); ok {
+		return mapped.HttpError()
+	}
 
 	return InternalServerError(err.Error())
 }
@@ -0,0 +1,118 @@
+package enum
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Small framework for string-backed enums: a Set lists the values of a type once and derives
+// validation and the Postgres DDL (enum type or CHECK constraint) from that single list.
+
+var ErrInvalidValue = errors.New("invalid enum value")
+
+// Set is the list of valid values of a string-backed enum type T, created with Define.
+type Set[T ~string] struct {
+	name   string
+	values []T
+}
+
+// Define creates the Set of T, name is also the Postgres enum type name.
+//
+// Example:
+//
+//	type Color string
+//	var Colors = enum.Define("color", Color("red"), Color("green"))
+func Define[T ~string](name string, values ...T) Set[T] {
+	return Set[T]{name: name, values: values}
+}
+
+func (s Set[T]) Name() string {
+	return s.name
+}
+
+// Values returns a copy of the valid values in definition order.
+func (s Set[T]) Values() []T {
+	return append([]T(nil), s.values...)
+}
+
+func (s Set[T]) Strings() []string {
+	result := make([]string, len(s.values))
+	for i, each := range s.values {
+		result[i] = string(each)
+	}
+
+	return result
+}
+
+func (s Set[T]) Valid(value T) bool {
+	for _, each := range s.values {
+		if each == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Parse converts raw into T, the error lists the valid values so it can be shown to clients.
+func (s Set[T]) Parse(raw string) (T, error) {
+	value := T(raw)
+	if !s.Valid(value) {
+		return "", fmt.Errorf("%w: %s must be one of %s, got %q", ErrInvalidValue, s.name, strings.Join(s.Strings(), ", "), raw)
+	}
+
+	return value, nil
+}
+
+// CreateTypeSQL creates the Postgres enum type, it's a no-op when the type already exists.
+//
+// Generates:
+//
+//	DO $$ BEGIN CREATE TYPE transaction_type AS ENUM ('income', 'expense', 'transfer');
+//	EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+func (s Set[T]) CreateTypeSQL() string {
+	return fmt.Sprintf(
+		"DO $$ BEGIN CREATE TYPE %s AS ENUM (%s);\nEXCEPTION WHEN duplicate_object THEN NULL; END $$;",
+		s.name, s.quotedValues(),
+	)
+}
+
+// AddValuesSQL adds values defined after the type was created, one statement per value
+// because ALTER TYPE ... ADD VALUE can't be combined.
+func (s Set[T]) AddValuesSQL() []string {
+	statements := make([]string, len(s.values))
+	for i, each := range s.values {
+		statements[i] = fmt.Sprintf("ALTER TYPE %s ADD VALUE IF NOT EXISTS %s;", s.name, quote(string(each)))
+	}
+
+	return statements
+}
+
+// CheckConstraintSQL (re)creates a CHECK constraint for text columns that don't use the enum type.
+//
+// Generates:
+//
+//	ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_type_check;
+//	ALTER TABLE transactions ADD CONSTRAINT transactions_type_check CHECK ("type" IN ('income', 'expense', 'transfer'));
+func (s Set[T]) CheckConstraintSQL(table string, column string) string {
+	constraint := fmt.Sprintf("%s_%s_check", table, column)
+
+	return fmt.Sprintf(
+		"ALTER TABLE %[1]s DROP CONSTRAINT IF EXISTS %[2]s;\nALTER TABLE %[1]s ADD CONSTRAINT %[2]s CHECK (\"%[3]s\" IN (%[4]s));",
+		table, constraint, column, s.quotedValues(),
+	)
+}
+
+func (s Set[T]) quotedValues() string {
+	quoted := make([]string, len(s.values))
+	for i, each := range s.values {
+		quoted[i] = quote(string(each))
+	}
+
+	return strings.Join(quoted, ", ")
+}
+
+func quote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
@@ -0,0 +1,79 @@
+package enum
+
+import (
+	"database/sql/driver"
+)
+
+// The enum types below validate on JSON decoding and before being sent as query arguments,
+// so builder conditions can take them directly:
+//
+//	{"type": {Operator: sql_query.SQLOperatorIn, Value: []enum.TransactionType{enum.TransactionIncome, enum.TransactionExpense}}}
+
+type TransactionType string
+
+const (
+	TransactionIncome   TransactionType = "income"
+	TransactionExpense  TransactionType = "expense"
+	TransactionTransfer TransactionType = "transfer"
+)
+
+var TransactionTypes = Define("transaction_type", TransactionIncome, TransactionExpense, TransactionTransfer)
+
+func (t TransactionType) Valid() bool {
+	return TransactionTypes.Valid(t)
+}
+
+func (t *TransactionType) UnmarshalText(text []byte) error {
+	value, err := TransactionTypes.Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*t = value
+
+	return nil
+}
+
+func (t TransactionType) Value() (driver.Value, error) {
+	if _, err := TransactionTypes.Parse(string(t)); err != nil {
+		return nil, err
+	}
+
+	return string(t), nil
+}
+
+type WalletRole string
+
+const (
+	WalletRoleOwner  WalletRole = "owner"
+	WalletRoleEditor WalletRole = "editor"
+	WalletRoleViewer WalletRole = "viewer"
+)
+
+var WalletRoles = Define("wallet_role", WalletRoleOwner, WalletRoleEditor, WalletRoleViewer)
+
+func (r WalletRole) Valid() bool {
+	return WalletRoles.Valid(r)
+}
+
+func (r *WalletRole) UnmarshalText(text []byte) error {
+	value, err := WalletRoles.Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*r = value
+
+	return nil
+}
+
+func (r WalletRole) Value() (driver.Value, error) {
+	if _, err := WalletRoles.Parse(string(r)); err != nil {
+		return nil, err
+	}
+
+	return string(r), nil
+}
+
+// CanWrite reports whether the role may change wallet data.
+func (r WalletRole) CanWrite() bool {
+	return r == WalletRoleOwner || r == WalletRoleEditor
+}
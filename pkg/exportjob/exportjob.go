@@ -0,0 +1,383 @@
+package exportjob
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/mystaline/clefinport-be/pkg/db"
+	"github.com/mystaline/clefinport-be/pkg/jobqueue"
+	"github.com/mystaline/clefinport-be/pkg/service"
+	"github.com/mystaline/clefinport-be/pkg/sql_query"
+	"github.com/mystaline/clefinport-be/pkg/storage"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Asynchronous exports: large files don't fit in a request timeout, so the request only creates a job of the queue
+// (see pkg/jobqueue), a worker writes the file to storage (see pkg/storage) and the caller polls the job until it
+// can download the file.
+//
+// Creating a job is idempotent: the same request (kind, requester and params, or the same idempotency key)
+// returns the job already created within DedupeWindow unless it failed. Each requester may have MaxActive
+// pending or running jobs of a kind, a kind being one export endpoint.
+
+var (
+	ErrUnknownKind  = errors.New("unknown export kind")
+	ErrTooManyJobs  = errors.New("too many exports in progress")
+	ErrJobNotFound  = errors.New("export job not found")
+	ErrFileNotReady = errors.New("export file is not ready")
+)
+
+// Producer writes the export file of params to w.
+type Producer func(ctx context.Context, params json.RawMessage, w io.Writer) error
+
+type Config struct {
+	// Pending or running jobs of one kind per requester, 2 by default
+	MaxActive int
+	// Identical requests within the window get the same job, 24 hours by default
+	DedupeWindow time.Duration
+	// DownloadPath formats the download URL of a done job from its id, e.g. "/api/v1/admin/export-jobs/%s/download"
+	DownloadPath string
+}
+
+// Payload is the job payload.
+type Payload struct {
+	Kind           string          `json:"kind"`
+	Requester      string          `json:"requester"`
+	IdempotencyKey string          `json:"idempotencyKey"`
+	Params         json.RawMessage `json:"params"`
+}
+
+// Progress is stored on the job once its file is written.
+type Progress struct {
+	File  string `json:"file"`
+	Bytes int64  `json:"bytes"`
+}
+
+// Status is a job as seen by its requester, Progress is set once the job is done.
+type Status struct {
+	ID       string          `json:"id"`
+	Kind     string          `json:"kind"`
+	Status   jobqueue.Status `json:"status"`
+	Progress *Progress       `json:"progress"`
+	// Set once the file is ready
+	DownloadURL *string   `json:"downloadUrl"`
+	LastError   *string   `json:"lastError"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+
+	requester string
+}
+
+type storedJob struct {
+	ID        string          `json:"id"        column:"id::text"`
+	Status    jobqueue.Status `json:"status"    column:"status"`
+	Payload   json.RawMessage `json:"payload"   column:"payload"`
+	Progress  json.RawMessage `json:"progress"  column:"progress"`
+	LastError *string         `json:"lastError" column:"last_error"`
+	CreatedAt time.Time       `json:"createdAt" column:"created_at"`
+	UpdatedAt time.Time       `json:"updatedAt" column:"updated_at"`
+}
+
+func (j storedJob) status(downloadPath string) (*Status, error) {
+	var payload Payload
+	if err := json.Unmarshal(j.Payload, &payload); err != nil {
+		return nil, err
+	}
+
+	status := &Status{
+		ID:        j.ID,
+		Kind:      payload.Kind,
+		Status:    j.Status,
+		LastError: j.LastError,
+		CreatedAt: j.CreatedAt,
+		UpdatedAt: j.UpdatedAt,
+		requester: payload.Requester,
+	}
+	if len(j.Progress) > 0 && string(j.Progress) != "null" {
+		status.Progress = &Progress{}
+		if err := json.Unmarshal(j.Progress, status.Progress); err != nil {
+			return nil, err
+		}
+	}
+	if status.Status == jobqueue.StatusDone && status.Progress != nil && downloadPath != "" {
+		url := fmt.Sprintf(downloadPath, j.ID)
+		status.DownloadURL = &url
+	}
+
+	return status, nil
+}
+
+type Manager struct {
+	Queue   *jobqueue.Queue
+	Storage storage.Storage
+
+	config    Config
+	producers map[string]Producer
+}
+
+// MakeManager creates exports on queue, their files are written to store.
+func MakeManager(queue *jobqueue.Queue, store storage.Storage, config Config) *Manager {
+	if config.MaxActive <= 0 {
+		config.MaxActive = 2
+	}
+	if config.DedupeWindow <= 0 {
+		config.DedupeWindow = 24 * time.Hour
+	}
+
+	return &Manager{
+		Queue:     queue,
+		Storage:   store,
+		config:    config,
+		producers: map[string]Producer{},
+	}
+}
+
+// Register sets the producer of kind, call it before Create and Work.
+func (m *Manager) Register(kind string, producer Producer) {
+	m.producers[kind] = producer
+}
+
+// Create returns the job exporting params as kind for requester, and whether it was created by this call.
+// Without idempotencyKey, the request itself is the key.
+func (m *Manager) Create(
+	ctx context.Context,
+	kind string,
+	requester string,
+	idempotencyKey string,
+	params any,
+) (*Status, bool, error) {
+	if _, ok := m.producers[kind]; !ok {
+		return nil, false, fmt.Errorf("%w: %s", ErrUnknownKind, kind)
+	}
+
+	encoded, err := json.Marshal(params)
+	if err != nil {
+		return nil, false, err
+	}
+	if idempotencyKey == "" {
+		sum := sha256.Sum256([]byte(kind + "\x00" + requester + "\x00" + string(encoded)))
+		idempotencyKey = hex.EncodeToString(sum[:])
+	}
+
+	type created struct {
+		status  *Status
+		created bool
+	}
+	result, err := service.UseTransactions(ctx, m.Queue.Service.GetPool(), func(tx pgx.Tx) (created, error) {
+		// The queue's service is shared with Work and the status handlers, it stays out of tx
+		txQueue := *m.Queue
+		txQueue.Service = service.TransactionService(m.Queue.Service, tx)
+		txManager := *m
+		txManager.Queue = &txQueue
+
+		// Serializes the creates of a requester, the lookups and the count below stay true until commit
+		if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock(hashtextextended($1, 0))", "export-job:"+m.Queue.Name+":"+requester); err != nil {
+			return created{}, err
+		}
+
+		existing, err := txManager.findByKey(ctx, idempotencyKey)
+		if err != nil || existing != nil {
+			return created{status: existing}, err
+		}
+
+		active, err := txManager.activeJobs(ctx, kind, requester)
+		if err != nil {
+			return created{}, err
+		}
+		if active >= m.config.MaxActive {
+			return created{}, fmt.Errorf("%w: %d of %d", ErrTooManyJobs, active, m.config.MaxActive)
+		}
+
+		id, err := txQueue.Enqueue(ctx, Payload{
+			Kind:           kind,
+			Requester:      requester,
+			IdempotencyKey: idempotencyKey,
+			Params:         encoded,
+		})
+		if err != nil {
+			return created{}, err
+		}
+
+		status, err := txManager.get(ctx, id)
+		return created{status: status, created: true}, err
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return result.status, result.created, nil
+}
+
+// Get returns the job of requester, ErrJobNotFound for a job of someone else.
+func (m *Manager) Get(ctx context.Context, id string, requester string) (*Status, error) {
+	status, err := m.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if status.requester != requester {
+		return nil, ErrJobNotFound
+	}
+
+	return status, nil
+}
+
+// Open returns the file of a done job of requester, ErrFileNotReady until then.
+func (m *Manager) Open(ctx context.Context, id string, requester string) (io.ReadCloser, *Status, error) {
+	status, err := m.Get(ctx, id, requester)
+	if err != nil {
+		return nil, nil, err
+	}
+	if status.Status != jobqueue.StatusDone || status.Progress == nil {
+		return nil, nil, ErrFileNotReady
+	}
+
+	file, err := m.Storage.Open(ctx, status.Progress.File)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return file, status, nil
+}
+
+// Handle is the queue handler, run it with Queue.Work.
+func (m *Manager) Handle(ctx context.Context, job jobqueue.Job) error {
+	var payload Payload
+	if err := job.Decode(&payload); err != nil {
+		return err
+	}
+
+	producer, ok := m.producers[payload.Kind]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownKind, payload.Kind)
+	}
+
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(producer(ctx, payload.Params, writer))
+	}()
+
+	key := fmt.Sprintf("exports/%s/%s.json", payload.Kind, job.ID)
+	written, err := m.Storage.Put(ctx, key, reader)
+	// Unblocks the producer when Put gave up first
+	reader.CloseWithError(err)
+	if err != nil {
+		return err
+	}
+
+	return m.Queue.SetProgress(ctx, job, Progress{File: key, Bytes: written})
+}
+
+var storedJobColumns = strings.Join(sql_query.ExtractJSONTags[storedJob](), ", ")
+
+func (m *Manager) get(ctx context.Context, id string) (*Status, error) {
+	query, args, err := sql_query.NewSQLSelectBuilder[storedJob](db.JobQueueTableName).
+		Where(map[string]sql_query.SQLCondition{
+			"id":    {Operator: sql_query.SQLOperatorEqual, Value: id},
+			"queue": {Operator: sql_query.SQLOperatorEqual, Value: m.Queue.Name},
+		}).
+		SetLimit(1).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+
+	var job storedJob
+	if err := m.Queue.Service.SelectOne(&job, ctx, query, args...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrJobNotFound
+		}
+		return nil, err
+	}
+
+	return job.status(m.config.DownloadPath)
+}
+
+// findByKey returns the latest job of idempotencyKey created within the window that didn't fail, nil when none.
+func (m *Manager) findByKey(ctx context.Context, idempotencyKey string) (*Status, error) {
+	query := fmt.Sprintf(`
+SELECT %s
+FROM %s
+WHERE queue = $1
+AND payload->>'idempotencyKey' = $2
+AND status <> $3
+AND created_at > NOW() - make_interval(secs => $4)
+ORDER BY created_at DESC
+LIMIT 1`,
+		storedJobColumns,
+		db.JobQueueTableName,
+	)
+
+	var job storedJob
+	err := m.Queue.Service.SelectOne(&job, ctx, query, m.Queue.Name, idempotencyKey, jobqueue.StatusFailed, m.config.DedupeWindow.Seconds())
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return job.status(m.config.DownloadPath)
+}
+
+func (m *Manager) activeJobs(ctx context.Context, kind string, requester string) (int, error) {
+	query := fmt.Sprintf(`
+SELECT COUNT(*)
+FROM %s
+WHERE queue = $1
+AND payload->>'kind' = $2
+AND payload->>'requester' = $3
+AND status IN ($4, $5)`,
+		db.JobQueueTableName,
+	)
+
+	return m.Queue.Service.Count(ctx, query, m.Queue.Name, kind, requester, jobqueue.StatusPending, jobqueue.StatusRunning)
+}
+
+// JSONArray writes every element produce emits to w as one JSON array, the usual shape of a Producer's file.
+//
+// Example:
+//
+//	return exportjob.JSONArray(w, func(emit func(record audit.StoredRecord) error) error {
+//	    return audit.EachRecord(ctx, svc, filter, emit)
+//	})
+func JSONArray[T any](w io.Writer, produce func(emit func(element T) error) error) error {
+	buffered := bufio.NewWriter(w)
+	if _, err := buffered.WriteString("["); err != nil {
+		return err
+	}
+
+	first := true
+	err := produce(func(element T) error {
+		encoded, err := json.Marshal(element)
+		if err != nil {
+			return err
+		}
+		if !first {
+			if err := buffered.WriteByte(','); err != nil {
+				return err
+			}
+		}
+		first = false
+
+		_, err = buffered.Write(encoded)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := buffered.WriteString("]"); err != nil {
+		return err
+	}
+
+	return buffered.Flush()
+}
@@ -0,0 +1,49 @@
+package exportjob
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/mystaline/clefinport-be/pkg/jobqueue"
+	"github.com/mystaline/clefinport-be/pkg/service"
+)
+
+var errConnection = errors.New("connection refused")
+
+// failedRows stands for the rows of a failed query, which are never read
+type failedRows struct{ pgx.Rows }
+
+func TestCreateRunsOnItsTransaction(t *testing.T) {
+	tx := &service.MockPgxTx{}
+	tx.On("Exec", mock.Anything, "SELECT pg_advisory_xact_lock(hashtextextended($1, 0))", mock.Anything).
+		Return(pgconn.NewCommandTag("SELECT 1"), nil)
+	// The idempotency lookup fails, it must have been sent on tx
+	tx.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(failedRows{}, errConnection).Once()
+	tx.On("Rollback", mock.Anything).Return(nil)
+
+	pool := &service.MockPgxPool{}
+	pool.On("Begin", mock.Anything).Return(tx, nil)
+
+	// Without a SetTransaction expectation, the shared service panics if Create moves it into tx
+	svc := &service.MockBasePostgreSqlService{}
+	svc.On("GetPool").Return(pool)
+
+	queue, err := jobqueue.MakeQueue(svc, "exports", jobqueue.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager := MakeManager(queue, nil, Config{})
+	manager.Register("audit_logs", func(ctx context.Context, params json.RawMessage, w io.Writer) error { return nil })
+
+	if _, _, err := manager.Create(context.Background(), "audit_logs", "support:1", "", nil); !errors.Is(err, errConnection) {
+		t.Fatalf("Create() error = %v, want %v", err, errConnection)
+	}
+	tx.AssertExpectations(t)
+}
@@ -0,0 +1,226 @@
+package factory
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/mystaline/clefinport-be/pkg/db"
+	"github.com/mystaline/clefinport-be/pkg/enum"
+	"github.com/mystaline/clefinport-be/pkg/service"
+)
+
+type insertUser struct {
+	FullName string `json:"fullName" column:"full_name"`
+	Email    string `json:"email"    column:"email"`
+}
+
+type insertWallet struct {
+	FullName string `json:"fullName" column:"full_name"`
+}
+
+type insertUserWallet struct {
+	UserID   string          `json:"userId"   column:"user_id"`
+	WalletID string          `json:"walletId" column:"wallet_id"`
+	Role     enum.WalletRole `json:"role"     column:"role"`
+	Balance  int64           `json:"balance"  column:"balance"`
+}
+
+type insertTransaction struct {
+	WalletID string               `json:"walletId" column:"wallet_id"`
+	UserID   string               `json:"userId"   column:"user_id"`
+	Type     enum.TransactionType `json:"type"     column:"type"`
+	Amount   int64                `json:"amount"   column:"amount"`
+	Note     string               `json:"note"     column:"note"`
+	Date     time.Time            `json:"date"     column:"date"`
+}
+
+// BuiltUser is the inserted user with its wallets.
+type BuiltUser struct {
+	ID       string
+	FullName string
+	Email    string
+	Wallets  []BuiltWallet
+}
+
+// BuiltWallet is an inserted wallet, Balance is the sum of its transactions and stored on user_wallets.
+type BuiltWallet struct {
+	ID           string
+	Name         string
+	Balance      int64
+	Transactions int
+}
+
+type UserFactory struct {
+	generator generator
+
+	fullName     string
+	email        string
+	wallets      int
+	transactions int
+	historyDays  int
+
+	walletService service.PostgreSqlService
+}
+
+// User starts a user factory, without options it inserts a single user with a random name and no wallet.
+func User() *UserFactory {
+	return &UserFactory{generator: newGenerator(), historyDays: 90}
+}
+
+// Seed makes the generated names, amounts and dates reproducible.
+func (f *UserFactory) Seed(seed int64) *UserFactory {
+	f.generator = generator{rand: rand.New(rand.NewSource(seed))}
+	return f
+}
+
+func (f *UserFactory) WithName(fullName string) *UserFactory {
+	f.fullName = fullName
+	return f
+}
+
+func (f *UserFactory) WithEmail(email string) *UserFactory {
+	f.email = email
+	return f
+}
+
+// WithWallet gives the user count wallets, owned by the user.
+func (f *UserFactory) WithWallet(count int) *UserFactory {
+	f.wallets = count
+	return f
+}
+
+// WithTransactions inserts count transactions in every wallet, mostly expenses with a few incomes.
+func (f *UserFactory) WithTransactions(count int) *UserFactory {
+	f.transactions = count
+	return f
+}
+
+// WithHistory spreads the transactions over the last days, 90 by default.
+func (f *UserFactory) WithHistory(days int) *UserFactory {
+	if days > 0 {
+		f.historyDays = days
+	}
+	return f
+}
+
+// WalletsIn inserts the wallets and transactions with svc instead of the service given to Build,
+// for setups where the wallet tables live in their own database.
+func (f *UserFactory) WalletsIn(svc service.PostgreSqlService) *UserFactory {
+	f.walletService = svc
+	return f
+}
+
+// Build inserts the user with svc, then its wallets and transactions in one transaction.
+func (f *UserFactory) Build(ctx context.Context, svc service.PostgreSqlService) (*BuiltUser, error) {
+	fullName := f.fullName
+	if fullName == "" {
+		fullName = f.generator.fullName()
+	}
+	email := f.email
+	if email == "" {
+		email = f.generator.email(fullName)
+	}
+
+	id, err := svc.InsertOneWithData(ctx, db.UserTableName, insertUser{FullName: fullName, Email: email})
+	if err != nil {
+		return nil, fmt.Errorf("factory: insert user: %w", err)
+	}
+
+	user := &BuiltUser{ID: fmt.Sprint(id), FullName: fullName, Email: email}
+	if f.wallets <= 0 {
+		return user, nil
+	}
+
+	walletService := f.walletService
+	if walletService == nil {
+		walletService = svc
+	}
+
+	wallets, err := service.UseTransactions(ctx, walletService.GetPool(), func(tx pgx.Tx) ([]BuiltWallet, error) {
+		walletService.SetTransaction(tx)
+		defer walletService.SetTransaction(nil)
+
+		wallets := make([]BuiltWallet, 0, f.wallets)
+		for range f.wallets {
+			wallet, err := f.buildWallet(ctx, walletService, user.ID)
+			if err != nil {
+				return nil, err
+			}
+			wallets = append(wallets, wallet)
+		}
+
+		return wallets, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	user.Wallets = wallets
+	return user, nil
+}
+
+// buildWallet inserts one wallet, its transactions and the user's membership holding the resulting balance,
+// so ledger.Check finds nothing to repair.
+func (f *UserFactory) buildWallet(ctx context.Context, svc service.PostgreSqlService, userID string) (BuiltWallet, error) {
+	name := f.generator.pick(walletNames)
+
+	id, err := svc.InsertOneWithData(ctx, db.WalletTableName, insertWallet{FullName: name})
+	if err != nil {
+		return BuiltWallet{}, fmt.Errorf("factory: insert wallet: %w", err)
+	}
+	wallet := BuiltWallet{ID: fmt.Sprint(id), Name: name, Transactions: f.transactions}
+
+	if f.transactions > 0 {
+		transactions := make([]insertTransaction, 0, f.transactions)
+		for range f.transactions {
+			transaction := f.transaction(wallet.ID, userID)
+			if transaction.Type == enum.TransactionIncome {
+				wallet.Balance += transaction.Amount
+			} else {
+				wallet.Balance -= transaction.Amount
+			}
+			transactions = append(transactions, transaction)
+		}
+
+		if _, err := svc.InsertManyWithData(ctx, db.TransactionTableName, transactions); err != nil {
+			return BuiltWallet{}, fmt.Errorf("factory: insert transactions: %w", err)
+		}
+	}
+
+	_, err = svc.InsertOneWithData(ctx, db.UserWalletTableName, insertUserWallet{
+		UserID:   userID,
+		WalletID: wallet.ID,
+		Role:     enum.WalletRoleOwner,
+		Balance:  wallet.Balance,
+	})
+	if err != nil {
+		return BuiltWallet{}, fmt.Errorf("factory: insert user wallet: %w", err)
+	}
+
+	return wallet, nil
+}
+
+// transaction generates one in five as an income, real histories are mostly small expenses.
+func (f *UserFactory) transaction(walletID, userID string) insertTransaction {
+	transaction := insertTransaction{
+		WalletID: walletID,
+		UserID:   userID,
+		Date:     f.generator.date(f.historyDays),
+	}
+
+	if f.generator.rand.Intn(5) == 0 {
+		transaction.Type = enum.TransactionIncome
+		transaction.Amount = f.generator.amount(500_000, 10_000_000)
+		transaction.Note = f.generator.pick(incomeNotes)
+	} else {
+		transaction.Type = enum.TransactionExpense
+		transaction.Amount = f.generator.amount(5_000, 500_000)
+		transaction.Note = f.generator.pick(expenseNotes)
+	}
+
+	return transaction
+}
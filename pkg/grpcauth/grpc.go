@@ -0,0 +1,155 @@
+package grpcauth
+
+import (
+	"context"
+	"log"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// MethodPolicy maps a full gRPC method name to the services allowed to call it.
+// Methods missing from the policy are denied.
+//
+// Example:
+//
+//	grpcauth.MethodPolicy{
+//	    pb_wallet.WalletService_GetTotalBalanceByUserId_FullMethodName: {grpcauth.UserServiceName},
+//	}
+type MethodPolicy map[string][]ServiceName
+
+// callers lists every service the policy allows to call any method.
+func (p MethodPolicy) callers() []ServiceName {
+	seen := map[ServiceName]bool{}
+	var callers []ServiceName
+	for _, services := range p {
+		for _, service := range services {
+			if !seen[service] {
+				seen[service] = true
+				callers = append(callers, service)
+			}
+		}
+	}
+
+	return callers
+}
+
+func (p MethodPolicy) allows(method string, caller ServiceName) bool {
+	for _, each := range p[method] {
+		if each == caller {
+			return true
+		}
+	}
+
+	return false
+}
+
+type callerKey struct{}
+
+// CallerFromContext returns the authenticated calling service set by the server interceptor.
+func CallerFromContext(ctx context.Context) (ServiceName, bool) {
+	caller, ok := ctx.Value(callerKey{}).(ServiceName)
+	return caller, ok
+}
+
+// DialOptions builds the transport and per-RPC credentials for a client acting as service.
+// mTLS is used when GRPC_TLS_* files are configured, every call additionally carries a signed service token.
+func DialOptions(service ServiceName) ([]grpc.DialOption, error) {
+	signer, err := MakeSigner(service)
+	if err != nil {
+		return nil, err
+	}
+
+	files, useTLS := TLSFilesFromEnv()
+	transport := insecure.NewCredentials()
+	if useTLS {
+		tlsConfig, err := ClientTLSConfig(files)
+		if err != nil {
+			return nil, err
+		}
+		transport = credentials.NewTLS(tlsConfig)
+	}
+
+	return []grpc.DialOption{
+		grpc.WithTransportCredentials(transport),
+		grpc.WithPerRPCCredentials(serviceTokenCredentials{
+			signer:     signer,
+			requireTLS: useTLS,
+		}),
+	}, nil
+}
+
+// ServerOptions builds the transport credentials and the authorization interceptor for a server.
+// Only the secrets of the services named in policy are loaded, see MakeVerifier.
+func ServerOptions(policy MethodPolicy) ([]grpc.ServerOption, error) {
+	verifier, err := MakeVerifier(policy.callers()...)
+	if err != nil {
+		return nil, err
+	}
+
+	options := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(UnaryServerInterceptor(verifier, policy)),
+	}
+
+	if files, useTLS := TLSFilesFromEnv(); useTLS {
+		tlsConfig, err := ServerTLSConfig(files)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+
+	return options, nil
+}
+
+// UnaryServerInterceptor authenticates the calling service from its signed token and enforces policy.
+// When the connection is mTLS, the client certificate CN must match the service named in the token.
+func UnaryServerInterceptor(verifier *Verifier, policy MethodPolicy) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		caller, err := authenticate(ctx, verifier)
+		if err != nil {
+			log.Printf("grpc auth: rejected %s: %v", info.FullMethod, err)
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+
+		if !policy.allows(info.FullMethod, caller) {
+			log.Printf("grpc auth: %s is not allowed to call %s", caller, info.FullMethod)
+			return nil, status.Errorf(codes.PermissionDenied, "%s is not allowed to call %s", caller, info.FullMethod)
+		}
+
+		return handler(context.WithValue(ctx, callerKey{}, caller), req)
+	}
+}
+
+func authenticate(ctx context.Context, verifier *Verifier) (ServiceName, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	tokens := md.Get(TokenMetadataKey)
+	if len(tokens) == 0 {
+		return "", ErrMissingToken
+	}
+
+	caller, err := verifier.Verify(tokens[0])
+	if err != nil {
+		return "", err
+	}
+
+	if p, ok := peer.FromContext(ctx); ok {
+		if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(tlsInfo.State.PeerCertificates) > 0 {
+			if tlsInfo.State.PeerCertificates[0].Subject.CommonName != string(caller) {
+				return "", ErrInvalidToken
+			}
+		}
+	}
+
+	return caller, nil
+}
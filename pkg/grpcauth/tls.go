@@ -0,0 +1,143 @@
+package grpcauth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// TLSFiles points to the PEM files used for mTLS between services.
+type TLSFiles struct {
+	CertFile string
+	KeyFile  string
+	CAFile   string
+}
+
+// TLSFilesFromEnv reads GRPC_TLS_CERT_FILE, GRPC_TLS_KEY_FILE and GRPC_TLS_CA_FILE.
+// ok is false when any of them is empty, meaning mTLS is disabled and callers fall back to plaintext.
+func TLSFilesFromEnv() (files TLSFiles, ok bool) {
+	files = TLSFiles{
+		CertFile: os.Getenv("GRPC_TLS_CERT_FILE"),
+		KeyFile:  os.Getenv("GRPC_TLS_KEY_FILE"),
+		CAFile:   os.Getenv("GRPC_TLS_CA_FILE"),
+	}
+
+	return files, files.CertFile != "" && files.KeyFile != "" && files.CAFile != ""
+}
+
+// certReloader serves the key pair from disk and reloads it whenever the cert file changes,
+// so rotated certificates are picked up on the next handshake without a restart.
+type certReloader struct {
+	certFile string
+	keyFile  string
+
+	mu      sync.RWMutex
+	cert    *tls.Certificate
+	modTime time.Time
+}
+
+func makeCertReloader(certFile, keyFile string) (*certReloader, error) {
+	r := &certReloader{certFile: certFile, keyFile: keyFile}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *certReloader) reload() error {
+	info, err := os.Stat(r.certFile)
+	if err != nil {
+		return err
+	}
+
+	r.mu.RLock()
+	unchanged := r.cert != nil && info.ModTime().Equal(r.modTime)
+	r.mu.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load key pair: %w", err)
+	}
+
+	r.mu.Lock()
+	r.cert = &cert
+	r.modTime = info.ModTime()
+	r.mu.Unlock()
+
+	return nil
+}
+
+func (r *certReloader) current() *tls.Certificate {
+	// Keep serving the previous certificate while a rotation is half written
+	_ = r.reload()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.cert
+}
+
+func loadCAPool(caFile string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, errors.New("no certificate found in CA file")
+	}
+
+	return pool, nil
+}
+
+// ServerTLSConfig requires and verifies client certificates signed by the shared CA.
+func ServerTLSConfig(files TLSFiles) (*tls.Config, error) {
+	reloader, err := makeCertReloader(files.CertFile, files.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	caPool, err := loadCAPool(files.CAFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  caPool,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return reloader.current(), nil
+		},
+	}, nil
+}
+
+// ClientTLSConfig presents the service certificate and verifies the server against the shared CA.
+func ClientTLSConfig(files TLSFiles) (*tls.Config, error) {
+	reloader, err := makeCertReloader(files.CertFile, files.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	caPool, err := loadCAPool(files.CAFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		RootCAs:    caPool,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return reloader.current(), nil
+		},
+	}, nil
+}
@@ -0,0 +1,55 @@
+package grpcauth
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestVerifyChecksTheSecretOfTheNamedService(t *testing.T) {
+	verifier := &Verifier{
+		secrets: map[ServiceName][]byte{
+			UserServiceName:   []byte("user-secret"),
+			WalletServiceName: []byte("wallet-secret"),
+		},
+		now: time.Now,
+	}
+	signer := func(service ServiceName, secret string) *Signer {
+		return &Signer{service: service, secret: []byte(secret), now: time.Now}
+	}
+
+	tests := []struct {
+		name    string
+		signer  *Signer
+		want    ServiceName
+		wantErr error
+	}{
+		{name: "own secret", signer: signer(UserServiceName, "user-secret"), want: UserServiceName},
+		{name: "secret of another service", signer: signer(UserServiceName, "wallet-secret"), wantErr: ErrInvalidToken},
+		{name: "unknown service", signer: signer(LogServiceName, "user-secret"), wantErr: ErrInvalidToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			caller, err := verifier.Verify(tt.signer.Sign())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+			if caller != tt.want {
+				t.Fatalf("Verify() = %q, want %q", caller, tt.want)
+			}
+		})
+	}
+}
+
+func TestMakeVerifierRequiresEveryCallerSecret(t *testing.T) {
+	t.Setenv("SERVICE_TOKEN_SECRET_USER_SERVICE", "user-secret")
+	t.Setenv("SERVICE_TOKEN_SECRET_LOG_SERVICE", "")
+
+	if _, err := MakeVerifier(UserServiceName); err != nil {
+		t.Fatalf("MakeVerifier(user_service) error = %v", err)
+	}
+	if _, err := MakeVerifier(UserServiceName, LogServiceName); err == nil {
+		t.Fatal("MakeVerifier(user_service, log_service) error = nil, want the missing secret")
+	}
+}
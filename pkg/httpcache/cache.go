@@ -0,0 +1,170 @@
+package httpcache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Response cache for read heavy GET endpoints (category tree, reference lists).
+// Entries are tagged with surrogate keys, e.g. "categories:<userId>", and write usecases invalidate the tags
+// they touch, so a cached read never outlives the write that changed it. The TTL only bounds how long
+// a missed invalidation can serve stale data.
+
+const (
+	// CacheHeader tells whether the response came from the cache (HIT) or was computed (MISS)
+	CacheHeader = "X-Cache"
+	// SurrogateKeyHeader lists the tags of the response, CDNs supporting surrogate keys can purge by them too
+	SurrogateKeyHeader = "Surrogate-Key"
+)
+
+type Config struct {
+	// DefaultTTL of routes without their own TTL, 1 minute by default
+	DefaultTTL time.Duration
+	// MaxTTL caps route TTLs, 10 minutes by default
+	MaxTTL time.Duration
+}
+
+func (c Config) withDefaults() Config {
+	if c.DefaultTTL <= 0 {
+		c.DefaultTTL = time.Minute
+	}
+	if c.MaxTTL <= 0 {
+		c.MaxTTL = 10 * time.Minute
+	}
+
+	return c
+}
+
+type RouteOptions struct {
+	// TTL of the route's entries, Config.DefaultTTL when zero
+	TTL time.Duration
+	// Tags returns the surrogate keys of the response, usually built with Tag from route params
+	Tags func(ctx *fiber.Ctx) []string
+	// Vary lists request headers that change the response (e.g. Accept-Language), they become part of the key
+	Vary []string
+}
+
+type Cache struct {
+	store  Store
+	config Config
+}
+
+func MakeCache(store Store, config Config) *Cache {
+	return &Cache{store: store, config: config.withDefaults()}
+}
+
+// Tag builds a surrogate key from a resource and the ids scoping it.
+//
+// Example:
+//
+//	httpcache.Tag("categories", userID) // "categories:42"
+func Tag(resource string, ids ...string) string {
+	return strings.Join(append([]string{resource}, ids...), ":")
+}
+
+// Middleware serves cached 200 responses of GET requests and caches the ones computed by the next handlers.
+// The key covers the path, the sorted query, the Vary headers and the caller's Authorization,
+// so users never see each other's responses. Streamed responses are never cached.
+//
+// Example:
+//
+//	wallet.Get("/categories", cache.Middleware(httpcache.RouteOptions{
+//	    Tags: func(ctx *fiber.Ctx) []string { return []string{httpcache.Tag("categories", ctx.Query("userId"))} },
+//	    Vary: []string{fiber.HeaderAcceptLanguage},
+//	}), walletController.ListCategories)
+func (c *Cache) Middleware(options RouteOptions) fiber.Handler {
+	ttl := options.TTL
+	if ttl <= 0 {
+		ttl = c.config.DefaultTTL
+	}
+	if ttl > c.config.MaxTTL {
+		ttl = c.config.MaxTTL
+	}
+
+	return func(ctx *fiber.Ctx) error {
+		if ctx.Method() != fiber.MethodGet {
+			return ctx.Next()
+		}
+
+		key := c.key(ctx, options.Vary)
+		reqCtx := ctx.UserContext()
+
+		entry, found, err := c.store.Get(reqCtx, key)
+		if err != nil {
+			log.Printf("http cache: get failed: %v", err)
+		}
+		if found {
+			ctx.Set(CacheHeader, "HIT")
+			ctx.Set(fiber.HeaderContentType, entry.ContentType)
+			return ctx.Status(entry.Status).Send(entry.Body)
+		}
+
+		ctx.Set(CacheHeader, "MISS")
+		if err := ctx.Next(); err != nil {
+			return err
+		}
+
+		response := ctx.Response()
+		if response.StatusCode() != fiber.StatusOK || response.IsBodyStream() {
+			return nil
+		}
+
+		var tags []string
+		if options.Tags != nil {
+			tags = options.Tags(ctx)
+			ctx.Set(SurrogateKeyHeader, strings.Join(tags, " "))
+		}
+
+		entry = Entry{
+			Status:      response.StatusCode(),
+			ContentType: string(response.Header.ContentType()),
+			// The body buffer is reused by fasthttp once the response is sent
+			Body: append([]byte(nil), response.Body()...),
+		}
+		if err := c.store.Set(reqCtx, key, entry, ttl, tags); err != nil {
+			log.Printf("http cache: set failed: %v", err)
+		}
+
+		return nil
+	}
+}
+
+// Invalidate drops every cached response tagged with one of tags, call it after the write committed.
+// A failing store only logs, entries then expire with their TTL.
+func (c *Cache) Invalidate(ctx context.Context, tags ...string) {
+	if c == nil || len(tags) == 0 {
+		return
+	}
+
+	if err := c.store.Invalidate(ctx, tags...); err != nil {
+		log.Printf("http cache: invalidate %v failed: %v", tags, err)
+	}
+}
+
+func (c *Cache) key(ctx *fiber.Ctx, vary []string) string {
+	queries := ctx.Queries()
+	names := make([]string, 0, len(queries))
+	for name := range queries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	hash := sha256.New()
+	hash.Write([]byte(ctx.Path()))
+	for _, name := range names {
+		hash.Write([]byte("\x00" + name + "=" + queries[name]))
+	}
+	for _, header := range vary {
+		hash.Write([]byte("\x00" + header + ":" + ctx.Get(header)))
+	}
+	hash.Write([]byte("\x00" + ctx.Get(fiber.HeaderAuthorization)))
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
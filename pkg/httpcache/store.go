@@ -0,0 +1,140 @@
+package httpcache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Entry is a cached response.
+type Entry struct {
+	Status      int    `json:"status"`
+	ContentType string `json:"contentType"`
+	Body        []byte `json:"body"`
+}
+
+// Store keeps cached responses and the surrogate keys (tags) pointing to them.
+// MemoryStore serves a single instance, a shared store (e.g. Redis with a set per tag) implements the same interface
+// so invalidations reach every instance.
+type Store interface {
+	Get(ctx context.Context, key string) (Entry, bool, error)
+	Set(ctx context.Context, key string, entry Entry, ttl time.Duration, tags []string) error
+	// Invalidate drops every entry stored with one of tags.
+	Invalidate(ctx context.Context, tags ...string) error
+}
+
+type memoryEntry struct {
+	Entry
+	expiresAt time.Time
+	tags      []string
+}
+
+type MemoryStore struct {
+	maxEntries int
+	now        func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*memoryEntry
+	tags    map[string]map[string]struct{}
+}
+
+// MakeMemoryStore creates an in-process store holding at most maxEntries responses (10000 when <= 0).
+func MakeMemoryStore(maxEntries int) *MemoryStore {
+	if maxEntries <= 0 {
+		maxEntries = 10000
+	}
+
+	return &MemoryStore{
+		maxEntries: maxEntries,
+		now:        time.Now,
+		entries:    map[string]*memoryEntry{},
+		tags:       map[string]map[string]struct{}{},
+	}
+}
+
+func (m *MemoryStore) Get(_ context.Context, key string) (Entry, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.entries[key]
+	if !ok {
+		return Entry{}, false, nil
+	}
+	if !m.now().Before(entry.expiresAt) {
+		m.remove(key)
+		return Entry{}, false, nil
+	}
+
+	return entry.Entry, true, nil
+}
+
+func (m *MemoryStore) Set(_ context.Context, key string, entry Entry, ttl time.Duration, tags []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.remove(key)
+	if len(m.entries) >= m.maxEntries {
+		m.evict()
+	}
+
+	m.entries[key] = &memoryEntry{Entry: entry, expiresAt: m.now().Add(ttl), tags: tags}
+	for _, tag := range tags {
+		if m.tags[tag] == nil {
+			m.tags[tag] = map[string]struct{}{}
+		}
+		m.tags[tag][key] = struct{}{}
+	}
+
+	return nil
+}
+
+func (m *MemoryStore) Invalidate(_ context.Context, tags ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, tag := range tags {
+		for key := range m.tags[tag] {
+			m.remove(key)
+		}
+		delete(m.tags, tag)
+	}
+
+	return nil
+}
+
+// remove drops key and its tag references, mu must be held.
+func (m *MemoryStore) remove(key string) {
+	entry, ok := m.entries[key]
+	if !ok {
+		return
+	}
+
+	delete(m.entries, key)
+	for _, tag := range entry.tags {
+		delete(m.tags[tag], key)
+		if len(m.tags[tag]) == 0 {
+			delete(m.tags, tag)
+		}
+	}
+}
+
+// evict makes room for one entry, expired entries first, otherwise the one closest to expiring. mu must be held.
+func (m *MemoryStore) evict() {
+	now := m.now()
+
+	var oldestKey string
+	var oldest time.Time
+	for key, entry := range m.entries {
+		if !now.Before(entry.expiresAt) {
+			m.remove(key)
+			continue
+		}
+		if oldestKey == "" || entry.expiresAt.Before(oldest) {
+			oldestKey, oldest = key, entry.expiresAt
+		}
+	}
+
+	if len(m.entries) >= m.maxEntries && oldestKey != "" {
+		m.remove(oldestKey)
+	}
+}
@@ -0,0 +1,313 @@
+package indexadvisor
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mystaline/clefinport-be/pkg/sql_query"
+)
+
+// Index advisor for dev runs: it collects the shapes of built queries through sql_query.SetQueryHook
+// and suggests the btree index each shape wants, equality columns first, then the ORDER BY columns
+// of paginated queries (the pagination path must not sort the whole table) or else the first range column.
+//
+// Usage, in the TestMain of an integration test package or behind a flag of a local run:
+//
+//	recorder := indexadvisor.Install()
+//	code := m.Run()
+//	existing, _ := indexadvisor.LoadIndexes(ctx, svc)
+//	indexadvisor.WriteReport(os.Stdout, recorder.Suggest(existing...))
+//
+// Only clauses on the main table are analyzed, OR groups, negations and expressions are skipped.
+
+// Index is a btree index, Columns in index order, a column may carry its direction, e.g. "created_at DESC".
+type Index struct {
+	Table   string   `json:"table"`
+	Columns []string `json:"columns"`
+	// Where is the predicate of a partial index, e.g. soft delete filters: "deleted_at IS NULL"
+	Where string `json:"where"`
+}
+
+// Suggestion is an index wanted by the recorded queries and missing from the existing ones.
+type Suggestion struct {
+	Index
+	// Queries is the number of recorded builds served by the index
+	Queries int
+	// Paginated is true when at least one of them was a LIMIT / cursor page
+	Paginated bool
+	// Example is the first recorded query of the shape
+	Example string
+}
+
+type Recorder struct {
+	mu          sync.Mutex
+	shapes      int
+	suggestions map[string]*Suggestion
+}
+
+func MakeRecorder() *Recorder {
+	return &Recorder{suggestions: map[string]*Suggestion{}}
+}
+
+// Install creates a recorder and makes it the sql_query hook, replacing any previous hook.
+func Install() *Recorder {
+	recorder := MakeRecorder()
+	sql_query.SetQueryHook(recorder.Record)
+
+	return recorder
+}
+
+// Record analyzes one built query, it is safe for concurrent use.
+func (r *Recorder) Record(shape sql_query.QueryShape) {
+	if shape.Mode == sql_query.SQLInsert {
+		return
+	}
+
+	index, ok := wantedIndex(shape)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.shapes++
+	if !ok {
+		return
+	}
+
+	key := indexKey(index)
+	suggestion, found := r.suggestions[key]
+	if !found {
+		suggestion = &Suggestion{Index: index, Example: shape.Query}
+		r.suggestions[key] = suggestion
+	}
+	suggestion.Queries++
+	suggestion.Paginated = suggestion.Paginated || shape.Paginated
+}
+
+// Shapes returns the number of recorded SELECT, UPDATE and DELETE builds.
+func (r *Recorder) Shapes() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.shapes
+}
+
+// Suggest returns the wanted indexes not covered by existing, paginated ones first, then by number of queries.
+// An index is covered by another of the same table starting with its columns, so shorter wanted indexes
+// are folded into the longer ones they prefix.
+func (r *Recorder) Suggest(existing ...Index) []Suggestion {
+	r.mu.Lock()
+	wanted := make([]Suggestion, 0, len(r.suggestions))
+	for _, suggestion := range r.suggestions {
+		wanted = append(wanted, *suggestion)
+	}
+	r.mu.Unlock()
+
+	// Longest first, so prefixes find the index folding them
+	sort.Slice(wanted, func(i, j int) bool {
+		if len(wanted[i].Columns) != len(wanted[j].Columns) {
+			return len(wanted[i].Columns) > len(wanted[j].Columns)
+		}
+		return indexKey(wanted[i].Index) < indexKey(wanted[j].Index)
+	})
+
+	suggestions := []Suggestion{}
+	for _, candidate := range wanted {
+		if coveredBy(candidate.Index, existing) {
+			continue
+		}
+
+		folded := false
+		for i := range suggestions {
+			if covers(suggestions[i].Index, candidate.Index) {
+				suggestions[i].Queries += candidate.Queries
+				suggestions[i].Paginated = suggestions[i].Paginated || candidate.Paginated
+				folded = true
+				break
+			}
+		}
+		if !folded {
+			suggestions = append(suggestions, candidate)
+		}
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].Paginated != suggestions[j].Paginated {
+			return suggestions[i].Paginated
+		}
+		return suggestions[i].Queries > suggestions[j].Queries
+	})
+
+	return suggestions
+}
+
+var (
+	// "wallet_id" = $1, "t"."created_at" >= to_timestamp($2), "id" IN ($3, $4), "deleted_at" IS NULL
+	filterRegexp = regexp.MustCompile(`(?i)^((?:"?\w+"?\.)?"?\w+"?)\s*(=|>=|<=|>|<|IN\b|BETWEEN\b|LIKE\b|ILIKE\b|IS NULL\b)`)
+	// "created_at" DESC NULLS LAST
+	sortRegexp = regexp.MustCompile(`(?i)^((?:"?\w+"?\.)?"?\w+"?)(?:\s+(ASC|DESC))?(?:\s+NULLS\s+(?:FIRST|LAST))?$`)
+)
+
+// wantedIndex returns the index serving shape, false when no clause of the main table is indexable.
+func wantedIndex(shape sql_query.QueryShape) (Index, bool) {
+	table, alias := splitTable(shape.Table)
+	if table == "" {
+		return Index{}, false
+	}
+
+	var equalities, ranges, nulls []string
+	for _, filter := range shape.Filters {
+		filter = strings.TrimSpace(filter)
+		if strings.HasPrefix(filter, "(") || strings.Contains(strings.ToUpper(filter), " OR ") {
+			continue
+		}
+
+		match := filterRegexp.FindStringSubmatch(filter)
+		if match == nil {
+			continue
+		}
+
+		column, ok := ownColumn(match[1], table, alias)
+		if !ok {
+			continue
+		}
+
+		switch strings.ToUpper(match[2]) {
+		case "IS NULL":
+			nulls = appendUnique(nulls, column)
+		case "=", "IN":
+			equalities = appendUnique(equalities, column)
+		default:
+			ranges = appendUnique(ranges, column)
+		}
+	}
+
+	var sorts []string
+	for _, sortBy := range shape.SortBy {
+		match := sortRegexp.FindStringSubmatch(strings.TrimSpace(sortBy))
+		if match == nil {
+			sorts = nil
+			break
+		}
+
+		column, ok := ownColumn(match[1], table, alias)
+		if !ok {
+			sorts = nil
+			break
+		}
+		if strings.EqualFold(match[2], "DESC") {
+			column += " DESC"
+		}
+		sorts = append(sorts, column)
+	}
+
+	// The order of equality columns doesn't matter, sorting them merges shapes filtering in another order
+	sort.Strings(equalities)
+	columns := equalities
+
+	switch {
+	case shape.Paginated && len(sorts) > 0:
+		for _, column := range sorts {
+			columns = appendUnique(columns, column)
+		}
+	case len(ranges) > 0:
+		columns = appendUnique(columns, ranges[0])
+	case len(sorts) > 0:
+		for _, column := range sorts {
+			columns = appendUnique(columns, column)
+		}
+	}
+
+	if len(columns) == 0 {
+		return Index{}, false
+	}
+
+	// IS NULL filters (soft deletes mostly) select most rows, they make a partial index rather than a column
+	sort.Strings(nulls)
+	for i := range nulls {
+		nulls[i] += " IS NULL"
+	}
+
+	return Index{Table: table, Columns: columns, Where: strings.Join(nulls, " AND ")}, true
+}
+
+// splitTable splits "transactions t" or "transactions AS t" into the table and its alias.
+func splitTable(table string) (string, string) {
+	fields := strings.Fields(table)
+	if len(fields) == 0 || strings.HasPrefix(fields[0], "(") {
+		return "", ""
+	}
+
+	name := unquote(fields[0])
+	if len(fields) == 1 {
+		return name, ""
+	}
+
+	return name, unquote(fields[len(fields)-1])
+}
+
+// ownColumn returns the bare column of identifier when it belongs to the main table.
+func ownColumn(identifier, table, alias string) (string, bool) {
+	parts := strings.Split(identifier, ".")
+	column := unquote(parts[len(parts)-1])
+	if len(parts) == 1 {
+		return column, true
+	}
+
+	qualifier := unquote(parts[0])
+	return column, qualifier == table || (alias != "" && qualifier == alias)
+}
+
+func unquote(identifier string) string {
+	return strings.Trim(identifier, `"`)
+}
+
+func appendUnique(columns []string, column string) []string {
+	for _, existing := range columns {
+		if columnName(existing) == columnName(column) {
+			return columns
+		}
+	}
+
+	return append(columns, column)
+}
+
+// columnName strips the direction of an index column.
+func columnName(column string) string {
+	return strings.Fields(column)[0]
+}
+
+func indexKey(index Index) string {
+	return index.Table + "(" + strings.Join(index.Columns, ", ") + ")" + index.Where
+}
+
+// covers reports whether index starts with the columns of wanted. Directions are ignored,
+// a btree is scanned backwards just as well, only mixed directions would need a closer look.
+// A partial index only covers queries with the same predicate, a full index covers them all.
+func covers(index, wanted Index) bool {
+	if index.Table != wanted.Table || len(index.Columns) < len(wanted.Columns) {
+		return false
+	}
+	if index.Where != "" && index.Where != wanted.Where {
+		return false
+	}
+
+	for i, column := range wanted.Columns {
+		if columnName(index.Columns[i]) != columnName(column) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func coveredBy(wanted Index, existing []Index) bool {
+	for _, index := range existing {
+		if covers(index, wanted) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,87 @@
+package indexadvisor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mystaline/clefinport-be/pkg/service"
+)
+
+// existingIndexes lists the column indexes of the current schema with their partial predicate,
+// expression columns are left out.
+const existingIndexes = `
+SELECT t.relname::text AS "table", array_agg(a.attname::text ORDER BY k.ord) AS "columns",
+	COALESCE(pg_get_expr(i.indpred, i.indrelid), '') AS "where"
+FROM pg_index i
+JOIN pg_class t ON t.oid = i.indrelid
+JOIN pg_namespace n ON n.oid = t.relnamespace
+CROSS JOIN LATERAL unnest(i.indkey::int2[]) WITH ORDINALITY AS k(attnum, ord)
+JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = k.attnum
+WHERE n.nspname = current_schema()
+GROUP BY i.indexrelid, i.indpred, i.indrelid, t.relname`
+
+// LoadIndexes returns the indexes of the database of svc, to leave the covered suggestions out.
+func LoadIndexes(ctx context.Context, svc service.PostgreSqlService) ([]Index, error) {
+	indexes := []Index{}
+	if err := svc.SelectMany(&indexes, ctx, existingIndexes); err != nil {
+		return nil, err
+	}
+
+	// Postgres prints predicates parenthesized, "(deleted_at IS NULL)", suggestions don't
+	for i := range indexes {
+		if strings.HasPrefix(indexes[i].Where, "(") && strings.HasSuffix(indexes[i].Where, ")") {
+			indexes[i].Where = indexes[i].Where[1 : len(indexes[i].Where)-1]
+		}
+	}
+
+	return indexes, nil
+}
+
+// WriteReport writes suggestions as a markdown report, the pagination path first.
+//
+// Example output:
+//
+//	## Pagination path
+//
+//	- `CREATE INDEX ON transactions (wallet_id, created_at DESC) WHERE deleted_at IS NULL;` builds: 12
+//	  SELECT ... FROM transactions WHERE "deleted_at" IS NULL AND "wallet_id" = $1 ORDER BY "created_at" DESC LIMIT 20
+func WriteReport(w io.Writer, suggestions []Suggestion) error {
+	var sb strings.Builder
+
+	sb.WriteString("# Index advisor report\n\n")
+	if len(suggestions) == 0 {
+		sb.WriteString("Every recorded query shape is covered by an existing index.\n")
+	}
+
+	writeSection := func(title string, paginated bool) {
+		written := false
+		for _, suggestion := range suggestions {
+			if suggestion.Paginated != paginated {
+				continue
+			}
+			if !written {
+				sb.WriteString("## " + title + "\n\n")
+				written = true
+			}
+
+			var where string
+			if suggestion.Where != "" {
+				where = " WHERE " + suggestion.Where
+			}
+			sb.WriteString(fmt.Sprintf("- `CREATE INDEX ON %s (%s)%s;` builds: %d\n",
+				suggestion.Table, strings.Join(suggestion.Columns, ", "), where, suggestion.Queries))
+			sb.WriteString("  " + strings.Join(strings.Fields(suggestion.Example), " ") + "\n")
+		}
+		if written {
+			sb.WriteByte('\n')
+		}
+	}
+
+	writeSection("Pagination path", true)
+	writeSection("Filters", false)
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
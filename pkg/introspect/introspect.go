@@ -0,0 +1,238 @@
+package introspect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mystaline/clefinport-be/pkg/service"
+	"github.com/mystaline/clefinport-be/pkg/sql_query"
+)
+
+// Table and column metadata of the current schema read from information_schema, for generic admin views
+// (data browser) and for validating user supplied column names (import mappings).
+// Metadata is cached per table, migrations run before deploys so a short TTL is enough to pick them up.
+
+var ErrTableNotFound = errors.New("table not found")
+
+type ForeignKey struct {
+	Constraint string `json:"constraint"`
+	Table      string `json:"table"`
+	Column     string `json:"column"`
+}
+
+type Column struct {
+	Name string `json:"name"`
+	// DataType as information_schema reports it, e.g. "bigint", "timestamp with time zone", "ARRAY"
+	DataType string `json:"dataType"`
+	// UDTName is the underlying type, e.g. "_text" for text[] or the name of an enum
+	UDTName    string      `json:"udtName"`
+	Nullable   bool        `json:"nullable"`
+	Default    *string     `json:"default"`
+	PrimaryKey bool        `json:"primaryKey"`
+	ForeignKey *ForeignKey `json:"foreignKey"`
+}
+
+type Table struct {
+	Name string `json:"name"`
+	// Columns in table order
+	Columns []Column `json:"columns"`
+}
+
+// Column returns the column named name.
+func (t Table) Column(name string) (Column, bool) {
+	for _, column := range t.Columns {
+		if column.Name == name {
+			return column, true
+		}
+	}
+
+	return Column{}, false
+}
+
+type columnRow struct {
+	Name     string  `json:"name"     column:"column_name"`
+	DataType string  `json:"dataType" column:"data_type"`
+	UDTName  string  `json:"udtName"  column:"udt_name"`
+	Nullable string  `json:"nullable" column:"is_nullable"`
+	Default  *string `json:"default"  column:"column_default"`
+}
+
+type constraintRow struct {
+	Constraint    string  `json:"constraint"`
+	Type          string  `json:"type"`
+	Column        string  `json:"column"`
+	ForeignTable  *string `json:"foreignTable"`
+	ForeignColumn *string `json:"foreignColumn"`
+}
+
+// Primary (p) and foreign (f) key columns of table $1. pg_constraint pairs the columns of composite foreign keys
+// with their referenced column, information_schema can't without joining on positions.
+const constraintsQuery = `
+SELECT c.conname::text AS "constraint", c.contype::text AS "type", a.attname::text AS "column",
+	ft.relname::text AS "foreignTable", fa.attname::text AS "foreignColumn"
+FROM pg_constraint c
+JOIN pg_class t ON t.oid = c.conrelid
+JOIN pg_namespace n ON n.oid = t.relnamespace
+CROSS JOIN LATERAL unnest(c.conkey, c.confkey) AS k(attnum, fattnum)
+JOIN pg_attribute a ON a.attrelid = c.conrelid AND a.attnum = k.attnum
+LEFT JOIN pg_class ft ON ft.oid = c.confrelid
+LEFT JOIN pg_attribute fa ON fa.attrelid = c.confrelid AND fa.attnum = k.fattnum
+WHERE n.nspname = current_schema() AND t.relname = $1 AND c.contype IN ('p', 'f')`
+
+type cachedTable struct {
+	table     Table
+	expiresAt time.Time
+}
+
+type Inspector struct {
+	service service.PostgreSqlService
+	ttl     time.Duration
+	now     func() time.Time
+
+	mu     sync.Mutex
+	tables map[string]cachedTable
+}
+
+// MakeInspector reads the schema of svc's database, caching every table for ttl (5 minutes when <= 0).
+func MakeInspector(svc service.PostgreSqlService, ttl time.Duration) *Inspector {
+	if ttl <= 0 {
+		ttl = 5 * time.Minute
+	}
+
+	return &Inspector{
+		service: svc,
+		ttl:     ttl,
+		now:     time.Now,
+		tables:  map[string]cachedTable{},
+	}
+}
+
+// TableNames returns the base tables of the current schema, sorted. Not cached, it is one cheap query.
+func (i *Inspector) TableNames(ctx context.Context) ([]string, error) {
+	type tableRow struct {
+		Name string `json:"name" column:"table_name"`
+	}
+
+	query, args, err := sql_query.NewSQLSelectBuilder[tableRow]("information_schema.tables").
+		Where(map[string]sql_query.SQLCondition{
+			"table_schema": {Operator: sql_query.SQLOperatorEqual, Value: "current_schema()", IsRef: true},
+			"table_type":   {Operator: sql_query.SQLOperatorEqual, Value: "BASE TABLE"},
+		}).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []tableRow{}
+	if err := i.service.SelectMany(&rows, ctx, query, args...); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(rows))
+	for index, row := range rows {
+		names[index] = row.Name
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// Table returns the metadata of name, ErrTableNotFound when the current schema has no such table.
+func (i *Inspector) Table(ctx context.Context, name string) (Table, error) {
+	i.mu.Lock()
+	cached, ok := i.tables[name]
+	i.mu.Unlock()
+	if ok && i.now().Before(cached.expiresAt) {
+		return cached.table, nil
+	}
+
+	table, err := i.load(ctx, name)
+	if err != nil {
+		return Table{}, err
+	}
+
+	i.mu.Lock()
+	i.tables[name] = cachedTable{table: table, expiresAt: i.now().Add(i.ttl)}
+	i.mu.Unlock()
+
+	return table, nil
+}
+
+// Invalidate drops the cached metadata of tables, all of them when none is given. Call it after running migrations.
+func (i *Inspector) Invalidate(tables ...string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if len(tables) == 0 {
+		i.tables = map[string]cachedTable{}
+		return
+	}
+	for _, table := range tables {
+		delete(i.tables, table)
+	}
+}
+
+func (i *Inspector) load(ctx context.Context, name string) (Table, error) {
+	query, args, err := sql_query.NewSQLSelectBuilder[columnRow]("information_schema.columns").
+		Where(map[string]sql_query.SQLCondition{
+			"table_schema": {Operator: sql_query.SQLOperatorEqual, Value: "current_schema()", IsRef: true},
+			"table_name":   {Operator: sql_query.SQLOperatorEqual, Value: name},
+		}).
+		OrderBy([]string{"ordinal_position"}, true).
+		Build()
+	if err != nil {
+		return Table{}, err
+	}
+
+	columns := []columnRow{}
+	if err := i.service.SelectMany(&columns, ctx, query, args...); err != nil {
+		return Table{}, err
+	}
+	if len(columns) == 0 {
+		return Table{}, fmt.Errorf("%w: %s", ErrTableNotFound, name)
+	}
+
+	constraints := []constraintRow{}
+	if err := i.service.SelectMany(&constraints, ctx, constraintsQuery, name); err != nil {
+		return Table{}, err
+	}
+
+	table := Table{Name: name, Columns: make([]Column, len(columns))}
+	for index, each := range columns {
+		table.Columns[index] = Column{
+			Name:     each.Name,
+			DataType: each.DataType,
+			UDTName:  each.UDTName,
+			Nullable: each.Nullable == "YES",
+			Default:  each.Default,
+		}
+	}
+
+	for _, constraint := range constraints {
+		for index := range table.Columns {
+			column := &table.Columns[index]
+			if column.Name != constraint.Column {
+				continue
+			}
+
+			switch constraint.Type {
+			case "p":
+				column.PrimaryKey = true
+			case "f":
+				if constraint.ForeignTable != nil && constraint.ForeignColumn != nil {
+					column.ForeignKey = &ForeignKey{
+						Constraint: constraint.Constraint,
+						Table:      *constraint.ForeignTable,
+						Column:     *constraint.ForeignColumn,
+					}
+				}
+			}
+		}
+	}
+
+	return table, nil
+}
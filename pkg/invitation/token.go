@@ -0,0 +1,121 @@
+package invitation
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"time"
+)
+
+// Signed, expiring tokens for wallet invitation links.
+// A token is `<base64url(json claims)>.<base64url(hmac-sha256)>`, it carries no secret itself,
+// the pending wallet_members row it points to stays the source of truth.
+
+// DefaultTTL is how long an invitation link stays valid when WALLET_INVITE_TTL is not set.
+const DefaultTTL = 7 * 24 * time.Hour
+
+var (
+	ErrInvalidToken = errors.New("invalid invitation token")
+	ErrExpiredToken = errors.New("expired invitation token")
+)
+
+// Claims identifies the pending invitation a token was issued for.
+type Claims struct {
+	MemberID  string `json:"mid"`
+	WalletID  string `json:"wid"`
+	Email     string `json:"email"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// Signer mints and verifies invitation tokens with a secret only the wallet service knows.
+type Signer struct {
+	secret []byte
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+// MakeSigner reads the secret from WALLET_INVITE_SECRET and the optional TTL (e.g. 72h) from WALLET_INVITE_TTL.
+func MakeSigner() (*Signer, error) {
+	secret := os.Getenv("WALLET_INVITE_SECRET")
+	if secret == "" {
+		return nil, errors.New("WALLET_INVITE_SECRET is not set")
+	}
+
+	ttl := DefaultTTL
+	if raw := os.Getenv("WALLET_INVITE_TTL"); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil || parsed <= 0 {
+			return nil, errors.New("WALLET_INVITE_TTL must be a positive duration")
+		}
+		ttl = parsed
+	}
+
+	return &Signer{secret: []byte(secret), ttl: ttl, now: time.Now}, nil
+}
+
+// Sign returns a token for the invitation, expiring after the signer's TTL.
+func (s *Signer) Sign(memberID string, walletID string, email string) (string, time.Time) {
+	expiresAt := s.now().Add(s.ttl)
+
+	// Marshalling a struct of strings and an int can't fail
+	raw, _ := json.Marshal(Claims{
+		MemberID:  memberID,
+		WalletID:  walletID,
+		Email:     strings.ToLower(email),
+		ExpiresAt: expiresAt.Unix(),
+	})
+	payload := base64.RawURLEncoding.EncodeToString(raw)
+
+	return payload + "." + s.signature(payload), expiresAt
+}
+
+// Verify validates the signature and expiry of token and returns its claims.
+func (s *Signer) Verify(token string) (Claims, error) {
+	payload, signature, found := strings.Cut(token, ".")
+	if !found || !hmac.Equal([]byte(signature), []byte(s.signature(payload))) {
+		return Claims{}, ErrInvalidToken
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return Claims{}, ErrInvalidToken
+	}
+
+	var claims Claims
+	if err := json.Unmarshal(raw, &claims); err != nil || claims.MemberID == "" {
+		return Claims{}, ErrInvalidToken
+	}
+
+	if s.now().After(time.Unix(claims.ExpiresAt, 0)) {
+		return Claims{}, ErrExpiredToken
+	}
+
+	return claims, nil
+}
+
+// Link embeds token in the invitation URL from WALLET_INVITE_URL, e.g. https://app.clefinport.com/invite.
+// Without WALLET_INVITE_URL the bare token is returned.
+func Link(token string) string {
+	base := os.Getenv("WALLET_INVITE_URL")
+	if base == "" {
+		return token
+	}
+
+	separator := "?"
+	if strings.Contains(base, "?") {
+		separator = "&"
+	}
+
+	return base + separator + "token=" + token
+}
+
+func (s *Signer) signature(payload string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(payload))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,345 @@
+package jobqueue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/mystaline/clefinport-be/pkg/db"
+	"github.com/mystaline/clefinport-be/pkg/service"
+	"github.com/mystaline/clefinport-be/pkg/sql_query"
+)
+
+// Postgres backed job queue for small deployments (webhooks, notifications, exports), no broker needed.
+// Jobs of every queue live in the job_queue table:
+//
+//	id bigint, queue text, payload jsonb, status text, attempts int, max_attempts int,
+//	run_at timestamptz, locked_at timestamptz, last_error text, progress jsonb, created_at, updated_at
+
+type Status string
+
+const (
+	StatusPending Status = "pending"
+	StatusRunning Status = "running"
+	StatusDone    Status = "done"
+	StatusFailed  Status = "failed" // attempts exhausted, kept for inspection
+)
+
+var (
+	ErrEmptyQueueName = errors.New("job queue name is required")
+	ErrJobNotFound    = errors.New("job not found")
+	// The job was claimed again after its lock timed out, or was already completed or failed
+	ErrClaimLost = errors.New("job claim lost")
+)
+
+type Job struct {
+	ID          string          `json:"id"          column:"id::text"`
+	Queue       string          `json:"queue"       column:"queue"`
+	Payload     json.RawMessage `json:"payload"     column:"payload"`
+	Attempts    int             `json:"attempts"    column:"attempts"`
+	MaxAttempts int             `json:"maxAttempts" column:"max_attempts"`
+	RunAt       time.Time       `json:"runAt"       column:"run_at"`
+}
+
+// Decode unmarshals the job payload into v.
+func (j Job) Decode(v any) error {
+	return json.Unmarshal(j.Payload, v)
+}
+
+// JobStatus is a job as seen by status endpoints, Progress is whatever the handler last reported.
+type JobStatus struct {
+	ID          string          `json:"id"          column:"id::text"`
+	Queue       string          `json:"queue"       column:"queue"`
+	Status      Status          `json:"status"      column:"status"`
+	Attempts    int             `json:"attempts"    column:"attempts"`
+	MaxAttempts int             `json:"maxAttempts" column:"max_attempts"`
+	Progress    json.RawMessage `json:"progress"    column:"progress"`
+	LastError   *string         `json:"lastError"   column:"last_error"`
+	RunAt       time.Time       `json:"runAt"       column:"run_at"`
+	CreatedAt   time.Time       `json:"createdAt"   column:"created_at"`
+	UpdatedAt   time.Time       `json:"updatedAt"   column:"updated_at"`
+}
+
+type Config struct {
+	MaxAttempts int
+	// Retry n waits BaseBackoff * 2^(n-1), capped at MaxBackoff
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// Running jobs locked for longer than LockTimeout are considered abandoned and claimed again,
+	// or failed when they have no attempts left
+	LockTimeout time.Duration
+}
+
+func (c Config) withDefaults() Config {
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = 5
+	}
+	if c.BaseBackoff <= 0 {
+		c.BaseBackoff = 10 * time.Second
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = time.Hour
+	}
+	if c.LockTimeout <= 0 {
+		c.LockTimeout = 5 * time.Minute
+	}
+
+	return c
+}
+
+type Queue struct {
+	Service service.PostgreSqlService
+	Name    string
+	Config  Config
+}
+
+// MakeQueue creates a named queue on the job_queue table of the given service.
+func MakeQueue(svc service.PostgreSqlService, name string, config Config) (*Queue, error) {
+	if name == "" {
+		return nil, ErrEmptyQueueName
+	}
+
+	return &Queue{Service: svc, Name: name, Config: config.withDefaults()}, nil
+}
+
+type insertJob struct {
+	Queue       string    `json:"queue"       column:"queue"`
+	Payload     any       `json:"payload"     column:"payload"`
+	Status      Status    `json:"status"      column:"status"`
+	Attempts    int       `json:"attempts"    column:"attempts"`
+	MaxAttempts int       `json:"maxAttempts" column:"max_attempts"`
+	RunAt       time.Time `json:"runAt"       column:"run_at"`
+}
+
+// Enqueue adds a job that is ready to run immediately and returns its id.
+func (q *Queue) Enqueue(ctx context.Context, payload any) (string, error) {
+	return q.EnqueueAt(ctx, payload, time.Now())
+}
+
+// EnqueueAt adds a job that won't be claimed before runAt and returns its id.
+func (q *Queue) EnqueueAt(ctx context.Context, payload any, runAt time.Time) (string, error) {
+	id, err := q.Service.InsertOneWithData(ctx, db.JobQueueTableName, insertJob{
+		Queue:       q.Name,
+		Payload:     payload,
+		Status:      StatusPending,
+		MaxAttempts: q.Config.MaxAttempts,
+		RunAt:       runAt,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprint(id), nil
+}
+
+// Claim locks up to limit due jobs for this worker and marks them running.
+// FOR UPDATE SKIP LOCKED lets concurrent workers claim disjoint batches without waiting on each other.
+// Abandoned jobs without attempts left are failed instead of claimed.
+func (q *Queue) Claim(ctx context.Context, limit int) ([]Job, error) {
+	if limit <= 0 {
+		limit = 1
+	}
+
+	query := fmt.Sprintf(`
+WITH exhausted AS (
+	UPDATE %[1]s
+	SET status = '%[5]s', locked_at = NULL, last_error = 'lock timed out on the last attempt', updated_at = NOW()
+	WHERE queue = $1
+	AND status = '%[2]s' AND locked_at < NOW() - make_interval(secs => $2)
+	AND attempts >= max_attempts
+)
+UPDATE %[1]s
+SET status = '%[2]s', locked_at = NOW(), attempts = attempts + 1, updated_at = NOW()
+WHERE id IN (
+	SELECT id
+	FROM %[1]s
+	WHERE queue = $1
+	AND (
+		(status = '%[3]s' AND run_at <= NOW())
+		OR (status = '%[2]s' AND locked_at < NOW() - make_interval(secs => $2) AND attempts < max_attempts)
+	)
+	ORDER BY run_at
+	LIMIT $3
+	FOR UPDATE SKIP LOCKED
+)
+RETURNING %[4]s`,
+		db.JobQueueTableName,
+		StatusRunning,
+		StatusPending,
+		strings.Join(sql_query.ExtractJSONTags[Job](), ", "),
+		StatusFailed,
+	)
+
+	var jobs []Job
+	if err := q.Service.SelectMany(&jobs, ctx, query, q.Name, q.Config.LockTimeout.Seconds(), limit); err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// Get returns the status of a job of this queue, ErrJobNotFound when it doesn't exist or belongs to another queue.
+func (q *Queue) Get(ctx context.Context, id string) (*JobStatus, error) {
+	query, args, err := sql_query.NewSQLSelectBuilder[JobStatus](db.JobQueueTableName).
+		Where(map[string]sql_query.SQLCondition{
+			"id":    {Operator: sql_query.SQLOperatorEqual, Value: id},
+			"queue": {Operator: sql_query.SQLOperatorEqual, Value: q.Name},
+		}).
+		SetLimit(1).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+
+	var status JobStatus
+	if err := q.Service.SelectOne(&status, ctx, query, args...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrJobNotFound
+		}
+		return nil, err
+	}
+
+	return &status, nil
+}
+
+// SetProgress stores progress (marshalled to JSON) on a claimed job so status endpoints can show it while it runs.
+// It is kept once the job completes or fails, a retried job overwrites it.
+// ErrClaimLost tells the handler another worker took the job over, so it can stop.
+func (q *Queue) SetProgress(ctx context.Context, job Job, progress any) error {
+	return q.updateClaimed(ctx, job, map[string]any{
+		"progress": progress,
+	})
+}
+
+// Complete marks a claimed job as done, ErrClaimLost when another worker claimed it since.
+func (q *Queue) Complete(ctx context.Context, job Job) error {
+	return q.updateClaimed(ctx, job, map[string]any{
+		"status":     StatusDone,
+		"locked_at":  nil,
+		"last_error": nil,
+	})
+}
+
+// Fail reschedules a claimed job with exponential backoff,
+// or marks it failed for good once it used all of its attempts, ErrClaimLost when another worker claimed it since.
+func (q *Queue) Fail(ctx context.Context, job Job, cause error) error {
+	body := map[string]any{
+		"locked_at":  nil,
+		"last_error": cause.Error(),
+	}
+
+	if job.Attempts >= job.MaxAttempts {
+		body["status"] = StatusFailed
+	} else {
+		body["status"] = StatusPending
+		body["run_at"] = sql_query.UpdateRawSQL{
+			Expr: "NOW() + make_interval(secs => ?)",
+			Args: []any{q.backoff(job.Attempts).Seconds()},
+		}
+	}
+
+	return q.updateClaimed(ctx, job, body)
+}
+
+// updateClaimed updates job only while it is still held by this claim, Claim bumps attempts on every claim
+// so a stale worker whose lock timed out can't overwrite the run of the worker that claimed the job again.
+func (q *Queue) updateClaimed(ctx context.Context, job Job, body map[string]any) error {
+	_, err := q.Service.UpdateOneWithData(ctx, db.JobQueueTableName,
+		map[string]sql_query.SQLCondition{
+			"id":       {Operator: sql_query.SQLOperatorEqual, Value: job.ID},
+			"status":   {Operator: sql_query.SQLOperatorEqual, Value: StatusRunning},
+			"attempts": {Operator: sql_query.SQLOperatorEqual, Value: job.Attempts},
+		},
+		body,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrClaimLost
+	}
+
+	return err
+}
+
+// Prune deletes the done jobs of every queue finished more than olderThan ago and returns how many.
+// Failed jobs are kept for inspection.
+func Prune(ctx context.Context, svc service.PostgreSqlService, olderThan time.Duration) (int64, error) {
+	return svc.DeleteManyWithFilter(ctx, db.JobQueueTableName, map[string]sql_query.SQLCondition{
+		"status":     {Operator: sql_query.SQLOperatorEqual, Value: StatusDone},
+		"updated_at": {Operator: sql_query.SQLOperatorLessThan, Value: time.Now().Add(-olderThan)},
+	})
+}
+
+func (q *Queue) backoff(attempts int) time.Duration {
+	backoff := float64(q.Config.BaseBackoff) * math.Pow(2, float64(attempts-1))
+	if backoff > float64(q.Config.MaxBackoff) {
+		return q.Config.MaxBackoff
+	}
+
+	return time.Duration(backoff)
+}
+
+// Work claims and handles jobs until ctx is cancelled, polling every interval when the queue is empty.
+// A handler error fails the job (retry or give up), a nil error completes it.
+// Handlers may return the ErrClaimLost of SetProgress to stop working on a job another worker took over.
+//
+// Example:
+//
+//	go queue.Work(ctx, 10, 5*time.Second, func(ctx context.Context, job jobqueue.Job) error {
+//	    var webhook WebhookPayload
+//	    if err := job.Decode(&webhook); err != nil {
+//	        return err
+//	    }
+//	    return deliver(ctx, webhook)
+//	})
+func (q *Queue) Work(ctx context.Context, batchSize int, interval time.Duration, handler func(ctx context.Context, job Job) error) {
+	if batchSize <= 0 {
+		batchSize = 1
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		jobs, err := q.Claim(ctx, batchSize)
+		if err != nil {
+			log.Printf("job queue %s: claim failed: %v", q.Name, err)
+		}
+
+		for _, job := range jobs {
+			if handlerErr := handler(ctx, job); handlerErr != nil {
+				if errors.Is(handlerErr, ErrClaimLost) {
+					log.Printf("job queue %s: job %s was claimed again by another worker", q.Name, job.ID)
+					continue
+				}
+				if err := q.Fail(ctx, job, handlerErr); err != nil {
+					log.Printf("job queue %s: failed to reschedule job %s: %v", q.Name, job.ID, err)
+				}
+				continue
+			}
+
+			if err := q.Complete(ctx, job); err != nil {
+				log.Printf("job queue %s: failed to complete job %s: %v", q.Name, job.ID, err)
+			}
+		}
+
+		// Keep draining while batches come back full
+		if len(jobs) == batchSize {
+			if ctx.Err() != nil {
+				return
+			}
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
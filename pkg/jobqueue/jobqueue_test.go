@@ -0,0 +1,51 @@
+package jobqueue
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/mystaline/clefinport-be/pkg/db"
+	"github.com/mystaline/clefinport-be/pkg/service"
+	"github.com/mystaline/clefinport-be/pkg/sql_query"
+)
+
+func TestUpdatesAreFencedOnTheClaim(t *testing.T) {
+	job := Job{ID: "7", Attempts: 2, MaxAttempts: 5}
+	claimed := map[string]sql_query.SQLCondition{
+		"id":       {Operator: sql_query.SQLOperatorEqual, Value: "7"},
+		"status":   {Operator: sql_query.SQLOperatorEqual, Value: StatusRunning},
+		"attempts": {Operator: sql_query.SQLOperatorEqual, Value: 2},
+	}
+
+	tests := []struct {
+		name   string
+		update func(q *Queue) error
+	}{
+		{name: "complete", update: func(q *Queue) error { return q.Complete(context.Background(), job) }},
+		{name: "fail", update: func(q *Queue) error { return q.Fail(context.Background(), job, errors.New("boom")) }},
+		{name: "progress", update: func(q *Queue) error { return q.SetProgress(context.Background(), job, map[string]int{"done": 1}) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := new(service.MockBasePostgreSqlService)
+			// Another worker claimed the job again after its lock timed out, so attempts moved on
+			svc.On("UpdateOneWithData", mock.Anything, db.JobQueueTableName, claimed, mock.Anything).
+				Return(nil, pgx.ErrNoRows)
+
+			q, err := MakeQueue(svc, "exports", Config{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := tt.update(q); !errors.Is(err, ErrClaimLost) {
+				t.Fatalf("error = %v, want ErrClaimLost", err)
+			}
+			svc.AssertExpectations(t)
+		})
+	}
+}
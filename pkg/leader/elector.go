@@ -0,0 +1,174 @@
+package leader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/mystaline/clefinport-be/pkg/service"
+)
+
+// Leader election between the replicas of a service, so scheduled jobs run on exactly one of them.
+// The leader holds a session advisory lock on a connection of its own: the lock goes away with the session,
+// so when the leader crashes or loses the database the next follower to retry takes over.
+//
+// Leadership is checked every RetryInterval, a leader cut off from the database keeps believing it leads
+// until its next check while another replica may already have taken over. Jobs must tolerate that overlap.
+
+var ErrNoPool = errors.New("leader election needs a *pgxpool.Pool")
+
+type Config struct {
+	// RetryInterval between lock attempts of a follower and between liveness checks of the leader, 10 seconds by default
+	RetryInterval time.Duration
+	// OnChange is called with true when this replica becomes leader and with false when it stops leading.
+	// Changes are logged either way.
+	OnChange func(leading bool)
+}
+
+type Elector struct {
+	name    string
+	service service.PostgreSqlService
+	config  Config
+
+	mu        sync.Mutex
+	leading   bool
+	leaderCtx context.Context
+	cancel    context.CancelFunc
+	acquired  int64
+	lost      int64
+}
+
+// MakeElector creates the elector of the named election on svc's database, Start runs it.
+// Replicas electing under the same name compete for the same lock.
+func MakeElector(name string, svc service.PostgreSqlService, config Config) *Elector {
+	if config.RetryInterval <= 0 {
+		config.RetryInterval = 10 * time.Second
+	}
+
+	return &Elector{
+		name:    name,
+		service: svc,
+		config:  config,
+	}
+}
+
+// Start campaigns for leadership until ctx is cancelled, the lock is released on cancellation.
+func (e *Elector) Start(ctx context.Context) {
+	go func() {
+		for {
+			if err := e.campaign(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("leader %s: %v", e.name, err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(e.config.RetryInterval):
+			}
+		}
+	}()
+}
+
+// IsLeader reports whether this replica currently holds the lock.
+func (e *Elector) IsLeader() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.leading
+}
+
+// LeaderContext returns a context cancelled as soon as this replica stops leading, nil while it follows.
+func (e *Elector) LeaderContext() context.Context {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.leaderCtx
+}
+
+// campaign holds a dedicated connection, tries the lock until it gets it and then checks the connection
+// is still alive. It returns when ctx is cancelled or the connection fails, having given up leadership.
+func (e *Elector) campaign(ctx context.Context) error {
+	pool, ok := e.service.GetPool().(*pgxpool.Pool)
+	if !ok {
+		return ErrNoPool
+	}
+
+	pooled, err := pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	// Closing the session is what releases the lock, the connection never goes back to the pool
+	conn := pooled.Hijack()
+	defer func() {
+		e.setLeading(false)
+		closeCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		conn.Close(closeCtx)
+	}()
+
+	ticker := time.NewTicker(e.config.RetryInterval)
+	defer ticker.Stop()
+
+	for {
+		if e.IsLeader() {
+			if err := ping(ctx, conn, e.config.RetryInterval); err != nil {
+				return fmt.Errorf("lost connection while leading: %w", err)
+			}
+		} else {
+			var locked bool
+			if err := conn.QueryRow(ctx, "SELECT pg_try_advisory_lock(hashtextextended($1, 0))", "leader:"+e.name).Scan(&locked); err != nil {
+				return err
+			}
+			if locked {
+				e.setLeading(true)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func ping(ctx context.Context, conn *pgx.Conn, timeout time.Duration) error {
+	pingCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	return conn.Ping(pingCtx)
+}
+
+func (e *Elector) setLeading(leading bool) {
+	e.mu.Lock()
+	if e.leading == leading {
+		e.mu.Unlock()
+		return
+	}
+
+	e.leading = leading
+	if leading {
+		e.acquired++
+		e.leaderCtx, e.cancel = context.WithCancel(context.Background())
+	} else {
+		e.lost++
+		e.cancel()
+		e.leaderCtx, e.cancel = nil, nil
+	}
+	e.mu.Unlock()
+
+	if leading {
+		log.Printf("leader %s: this replica is now leading", e.name)
+	} else {
+		log.Printf("leader %s: this replica stopped leading", e.name)
+	}
+	if e.config.OnChange != nil {
+		e.config.OnChange(leading)
+	}
+}
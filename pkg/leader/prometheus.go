@@ -0,0 +1,78 @@
+package leader
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Handler serves the leadership and task stats of runners in the Prometheus text format,
+// mount it next to the other metrics, e.g. on GET /metrics/maintenance.
+//
+// Example output:
+//
+//	# HELP clefinport_leader 1 while this replica leads the election.
+//	# TYPE clefinport_leader gauge
+//	clefinport_leader{election="log-maintenance"} 1
+//	# HELP clefinport_leader_changes_total Leadership changes of this replica.
+//	# TYPE clefinport_leader_changes_total counter
+//	clefinport_leader_changes_total{election="log-maintenance",change="acquired"} 1
+func Handler(runners ...*Runner) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var leading, changes, runs, durations strings.Builder
+
+		for _, runner := range runners {
+			elector := runner.elector
+
+			elector.mu.Lock()
+			election := fmt.Sprintf(`election="%s"`, escapeLabel(elector.name))
+			value := 0
+			if elector.leading {
+				value = 1
+			}
+			fmt.Fprintf(&leading, "clefinport_leader{%s} %d\n", election, value)
+			fmt.Fprintf(&changes, "clefinport_leader_changes_total{%s,change=\"acquired\"} %d\n", election, elector.acquired)
+			fmt.Fprintf(&changes, "clefinport_leader_changes_total{%s,change=\"lost\"} %d\n", election, elector.lost)
+			elector.mu.Unlock()
+
+			stats := runner.Stats()
+			names := make([]string, 0, len(stats))
+			for name := range stats {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			for _, name := range names {
+				taskStats := stats[name]
+				labels := fmt.Sprintf(`%s,task="%s"`, election, escapeLabel(name))
+				fmt.Fprintf(&runs, "clefinport_maintenance_task_runs_total{%s,result=\"succeeded\"} %d\n", labels, taskStats.Succeeded)
+				fmt.Fprintf(&runs, "clefinport_maintenance_task_runs_total{%s,result=\"failed\"} %d\n", labels, taskStats.Failed)
+				fmt.Fprintf(&runs, "clefinport_maintenance_task_runs_total{%s,result=\"timed_out\"} %d\n", labels, taskStats.TimedOut)
+				fmt.Fprintf(&durations, "clefinport_maintenance_task_last_duration_seconds{%s} %g\n", labels, taskStats.LastDuration.Seconds())
+			}
+		}
+
+		var sb strings.Builder
+		sb.WriteString("# HELP clefinport_leader 1 while this replica leads the election.\n")
+		sb.WriteString("# TYPE clefinport_leader gauge\n")
+		sb.WriteString(leading.String())
+		sb.WriteString("# HELP clefinport_leader_changes_total Leadership changes of this replica.\n")
+		sb.WriteString("# TYPE clefinport_leader_changes_total counter\n")
+		sb.WriteString(changes.String())
+		sb.WriteString("# HELP clefinport_maintenance_task_runs_total Runs of the maintenance task by this replica, by result.\n")
+		sb.WriteString("# TYPE clefinport_maintenance_task_runs_total counter\n")
+		sb.WriteString(runs.String())
+		sb.WriteString("# HELP clefinport_maintenance_task_last_duration_seconds Duration of the last run of the maintenance task by this replica.\n")
+		sb.WriteString("# TYPE clefinport_maintenance_task_last_duration_seconds gauge\n")
+		sb.WriteString(durations.String())
+
+		ctx.Set(fiber.HeaderContentType, "text/plain; version=0.0.4; charset=utf-8")
+		return ctx.SendString(sb.String())
+	}
+}
+
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
@@ -0,0 +1,145 @@
+package leader
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+// Task is a maintenance job run by the leader every Interval (retention, outbox relay, reconciliation).
+type Task struct {
+	Name     string
+	Interval time.Duration
+	// Timeout of a run, Interval by default. The run context is also cancelled when leadership is lost.
+	Timeout time.Duration
+	Run     func(ctx context.Context) error
+}
+
+// TaskStats are the outcomes of the runs of a task on this replica.
+type TaskStats struct {
+	Succeeded    int64
+	Failed       int64
+	TimedOut     int64
+	LastRunAt    time.Time
+	LastDuration time.Duration
+}
+
+type Runner struct {
+	elector *Elector
+	tasks   []Task
+
+	mu    sync.Mutex
+	stats map[string]*TaskStats
+}
+
+// MakeRunner creates the runner of tasks on elector's leader, Start runs both.
+//
+// Example:
+//
+//	runner := leader.MakeRunner(leader.MakeElector("log-maintenance", svc, leader.Config{}), leader.Task{
+//	    Name:     "job-queue-retention",
+//	    Interval: time.Hour,
+//	    Timeout:  5 * time.Minute,
+//	    Run: func(ctx context.Context) error {
+//	        _, err := jobqueue.Prune(ctx, svc, 30*24*time.Hour)
+//	        return err
+//	    },
+//	})
+//	runner.Start(ctx)
+func MakeRunner(elector *Elector, tasks ...Task) *Runner {
+	stats := make(map[string]*TaskStats, len(tasks))
+	for i := range tasks {
+		if tasks[i].Timeout <= 0 {
+			tasks[i].Timeout = tasks[i].Interval
+		}
+		stats[tasks[i].Name] = &TaskStats{}
+	}
+
+	return &Runner{
+		elector: elector,
+		tasks:   tasks,
+		stats:   stats,
+	}
+}
+
+// Start runs the election and ticks every task until ctx is cancelled. Followers skip their ticks,
+// a new leader runs each task on its first tick rather than waiting for a full interval.
+func (r *Runner) Start(ctx context.Context) {
+	r.elector.Start(ctx)
+
+	for _, task := range r.tasks {
+		go r.schedule(ctx, task)
+	}
+}
+
+// Stats returns a copy of the stats of every task by name.
+func (r *Runner) Stats() map[string]TaskStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats := make(map[string]TaskStats, len(r.stats))
+	for name, taskStats := range r.stats {
+		stats[name] = *taskStats
+	}
+
+	return stats
+}
+
+func (r *Runner) schedule(ctx context.Context, task Task) {
+	// Polls at the retry interval so a takeover doesn't wait for a long task interval
+	ticker := time.NewTicker(min(task.Interval, r.elector.config.RetryInterval))
+	defer ticker.Stop()
+
+	var lastRun time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		leaderCtx := r.elector.LeaderContext()
+		if leaderCtx == nil {
+			lastRun = time.Time{}
+			continue
+		}
+		if time.Since(lastRun) < task.Interval {
+			continue
+		}
+
+		lastRun = time.Now()
+		r.run(ctx, leaderCtx, task)
+	}
+}
+
+// run is one time-boxed run of task, cancelled by ctx, leaderCtx or the task timeout.
+func (r *Runner) run(ctx, leaderCtx context.Context, task Task) {
+	runCtx, cancel := context.WithTimeout(leaderCtx, task.Timeout)
+	defer cancel()
+	stop := context.AfterFunc(ctx, cancel)
+	defer stop()
+
+	startedAt := time.Now()
+	err := task.Run(runCtx)
+	duration := time.Since(startedAt)
+
+	r.mu.Lock()
+	stats := r.stats[task.Name]
+	stats.LastRunAt = startedAt
+	stats.LastDuration = duration
+	switch {
+	case err == nil:
+		stats.Succeeded++
+	case errors.Is(err, context.DeadlineExceeded):
+		stats.TimedOut++
+	default:
+		stats.Failed++
+	}
+	r.mu.Unlock()
+
+	if err != nil {
+		log.Printf("leader %s: task %s failed after %s: %v", r.elector.name, task.Name, duration.Round(time.Millisecond), err)
+	}
+}
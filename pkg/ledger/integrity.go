@@ -0,0 +1,74 @@
+package ledger
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mystaline/clefinport-be/pkg/db"
+	"github.com/mystaline/clefinport-be/pkg/enum"
+	"github.com/mystaline/clefinport-be/pkg/service"
+)
+
+// Integrity checker of the wallet ledger: the stored balance of a wallet must equal the sum of its transactions.
+// Income adds its amount, expense subtracts it and transfer rows carry a signed amount
+// (negative on the sending wallet, positive on the receiving one). Soft deleted transactions don't count.
+//
+// Balances are stored on every user_wallets row of the wallet, so members of a shared wallet see the same balance.
+
+// Mismatch is a wallet whose stored balance drifted from its transactions.
+type Mismatch struct {
+	WalletID string `json:"walletId"`
+	Stored   int64  `json:"stored"`
+	Expected int64  `json:"expected"`
+}
+
+// expectedBalances computes the balance of every wallet id in $1 (text[]), wallets without transactions get 0.
+var expectedBalances = fmt.Sprintf(`
+SELECT w.wallet_id, COALESCE(SUM(
+	CASE t.type
+		WHEN '%[2]s' THEN t.amount
+		WHEN '%[3]s' THEN -t.amount
+		WHEN '%[4]s' THEN t.amount
+		ELSE 0
+	END
+), 0)::bigint AS expected
+FROM (SELECT unnest($1::text[])::bigint AS wallet_id) w
+LEFT JOIN %[1]s t ON t.wallet_id = w.wallet_id AND t.deleted_at IS NULL
+GROUP BY w.wallet_id`,
+	db.TransactionTableName,
+	enum.TransactionIncome,
+	enum.TransactionExpense,
+	enum.TransactionTransfer,
+)
+
+// Check returns the wallets of walletIDs whose stored balance differs from their transactions, nothing is changed.
+func Check(ctx context.Context, svc service.PostgreSqlService, walletIDs []string) ([]Mismatch, error) {
+	query := fmt.Sprintf(`
+WITH expected AS (%s)
+SELECT DISTINCT uw.wallet_id::text AS "walletId", uw.balance::bigint AS "stored", e.expected AS "expected"
+FROM %s uw
+JOIN expected e ON e.wallet_id = uw.wallet_id
+WHERE uw.balance IS DISTINCT FROM e.expected
+ORDER BY 1`, expectedBalances, db.UserWalletTableName)
+
+	mismatches := []Mismatch{}
+	if err := svc.SelectMany(&mismatches, ctx, query, walletIDs); err != nil {
+		return nil, err
+	}
+
+	return mismatches, nil
+}
+
+// Repair overwrites the stored balance of walletIDs with the one computed from their transactions
+// and returns the number of user_wallets rows that changed. Rows already correct are left untouched.
+func Repair(ctx context.Context, svc service.PostgreSqlService, walletIDs []string) (int64, error) {
+	query := fmt.Sprintf(`
+WITH expected AS (%s)
+UPDATE %s uw
+SET balance = e.expected, updated_at = NOW()
+FROM expected e
+WHERE e.wallet_id = uw.wallet_id
+AND uw.balance IS DISTINCT FROM e.expected`, expectedBalances, db.UserWalletTableName)
+
+	return svc.UpdateMany(ctx, query, walletIDs)
+}
@@ -0,0 +1,106 @@
+package parser
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// DefaultAmountField receives the signed amount when a profile splits debit and credit into two columns.
+const DefaultAmountField = "amount"
+
+func (p MappingProfile) amountFields() []string {
+	fields := append([]string{}, p.AmountFields...)
+	if len(fields) == 0 {
+		fields = append(fields, DefaultAmountField)
+	}
+	for _, each := range []string{p.DebitField, p.CreditField} {
+		if each != "" {
+			fields = append(fields, each)
+		}
+	}
+
+	return fields
+}
+
+// applyAmounts parses the amount fields of a row with the profile's number format
+// and merges debit/credit into DefaultAmountField as credit - debit.
+func (p MappingProfile) applyAmounts(row map[string]interface{}, raw map[string]string) {
+	for _, field := range p.amountFields() {
+		value, ok := raw[field]
+		if !ok {
+			continue
+		}
+		if amount, ok := parseAmount(value, p.DecimalSeparator); ok {
+			row[field] = amount
+		}
+	}
+
+	if p.DebitField == "" && p.CreditField == "" {
+		return
+	}
+
+	debit, hasDebit := row[p.DebitField].(float64)
+	credit, hasCredit := row[p.CreditField].(float64)
+	if !hasDebit && !hasCredit {
+		return
+	}
+
+	delete(row, p.DebitField)
+	delete(row, p.CreditField)
+	// Banks export both columns unsigned, the column decides the sign
+	row[DefaultAmountField] = math.Abs(credit) - math.Abs(debit)
+}
+
+// parseAmount reads money values like "1.234,56", "(12.50)", "€ 1 234,56", "-Rp 10.000" or "12.50-".
+// decimalSeparator is "." or ",", "." when empty. The other separator is treated as a thousands separator.
+func parseAmount(value string, decimalSeparator string) (float64, bool) {
+	value = strings.TrimSpace(value)
+
+	negative := false
+	if strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")") {
+		negative = true
+		value = value[1 : len(value)-1]
+	}
+
+	thousandsSeparator := ','
+	decimal := '.'
+	if decimalSeparator == "," {
+		thousandsSeparator = '.'
+		decimal = ','
+	}
+
+	var number strings.Builder
+	hasDigit := false
+	for _, r := range value {
+		switch {
+		case unicode.IsDigit(r):
+			number.WriteRune(r)
+			hasDigit = true
+		case r == decimal:
+			number.WriteRune('.')
+		case r == '-':
+			// Leading or trailing minus, currency symbols may sit in between
+			negative = true
+		case r == thousandsSeparator:
+		case unicode.IsLetter(r) || unicode.IsSpace(r) || unicode.IsSymbol(r) || r == '\'':
+			// Currency symbols/codes, spaces and Swiss apostrophes
+		default:
+			return 0, false
+		}
+	}
+	if !hasDigit {
+		return 0, false
+	}
+
+	amount, err := strconv.ParseFloat(number.String(), 64)
+	if err != nil {
+		return 0, false
+	}
+	if negative {
+		amount = -amount
+	}
+
+	return amount, true
+}
@@ -0,0 +1,116 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"mime/multipart"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// ExcelOptions picks the sheets to parse and unlocks password-protected workbooks.
+type ExcelOptions struct {
+	Password string
+	// Sheet picks a sheet by name, it wins over SheetIndex.
+	Sheet string
+	// SheetIndex picks a sheet by 0-based position, the first sheet by default.
+	SheetIndex int
+	// AllSheets parses every sheet, Sheet and SheetIndex are ignored.
+	AllSheets bool
+}
+
+// SheetResult is the parse result of one sheet.
+type SheetResult struct {
+	Sheet string                   `json:"sheet"`
+	Rows  []map[string]interface{} `json:"rows"`
+}
+
+var ErrWorkbookPassword = errors.New("workbook password is missing or incorrect")
+
+// ParseXlsxSheets is ParseXlsxToJson for the sheets selected by options, each sheet looks up its own header.
+func (p *DefaultParser) ParseXlsxSheets(
+	file *multipart.FileHeader,
+	columns []string,
+	options ExcelOptions,
+) ([]SheetResult, error) {
+	sheets, err := getExcelSheets(file, options)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]SheetResult, 0, len(sheets))
+	for _, sheet := range sheets {
+		result := SheetResult{Sheet: sheet.name, Rows: []map[string]interface{}{}}
+
+		header, headerRowIndex := findHeader(sheet.rows, columns)
+		if headerRowIndex != -1 {
+			if rows := parseRows(sheet.rows, header, headerRowIndex, columns); rows != nil {
+				result.Rows = rows
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+type excelSheet struct {
+	name string
+	rows [][]string
+}
+
+// getExcelSheets membuka file (dengan password jika ada) dan membaca baris dari sheet yang dipilih
+func getExcelSheets(file *multipart.FileHeader, options ExcelOptions) ([]excelSheet, error) {
+	fileContent, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %v", err)
+	}
+	defer fileContent.Close()
+
+	f, err := excelize.OpenReader(fileContent, excelize.Options{Password: options.Password})
+	if errors.Is(err, excelize.ErrWorkbookPassword) {
+		return nil, ErrWorkbookPassword
+	}
+	if err != nil {
+		if options.Password == "" {
+			// Encrypted workbooks aren't zip files, opening them without password fails on the format
+			return nil, fmt.Errorf("failed to read Excel file, it may be password protected: %v", err)
+		}
+		return nil, fmt.Errorf("failed to read Excel file: %v", err)
+	}
+	defer f.Close()
+
+	sheetNames := f.GetSheetList()
+	if len(sheetNames) == 0 {
+		return nil, errors.New("no sheets found in Excel file")
+	}
+
+	var selected []string
+	switch {
+	case options.AllSheets:
+		selected = sheetNames
+	case options.Sheet != "":
+		index, err := f.GetSheetIndex(options.Sheet)
+		if err != nil || index == -1 {
+			return nil, fmt.Errorf("sheet %s not found in Excel file", options.Sheet)
+		}
+		selected = []string{options.Sheet}
+	default:
+		if options.SheetIndex < 0 || options.SheetIndex >= len(sheetNames) {
+			return nil, fmt.Errorf("sheet index %d out of range, workbook has %d sheets", options.SheetIndex, len(sheetNames))
+		}
+		selected = []string{sheetNames[options.SheetIndex]}
+	}
+
+	sheets := make([]excelSheet, 0, len(selected))
+	for _, name := range selected {
+		rows, err := f.GetRows(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get rows from sheet %s: %v", name, err)
+		}
+		sheets = append(sheets, excelSheet{name: name, rows: rows})
+	}
+
+	return sheets, nil
+}
@@ -0,0 +1,153 @@
+package parser
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// MappingProfile is a saved import column mapping, e.g. "My Bank CSV": date -> A, amount -> C, note -> E.
+type MappingProfile struct {
+	ID     string `json:"id"        column:"id::text"`
+	UserID string `json:"userId"    column:"user_id::text"`
+	Name   string `json:"name"      column:"name"`
+	// HeaderRow is the 1-based row holding the column titles, data starts below it. 0 means no header row.
+	HeaderRow int `json:"headerRow" column:"header_row"`
+	// Columns maps a target field to a column letter ("C") or a header title ("Amount").
+	Columns map[string]string `json:"columns" column:"columns"`
+	// DecimalSeparator is "." (1,234.56) or "," (1.234,56), "." when empty.
+	DecimalSeparator string `json:"decimalSeparator" column:"decimal_separator"`
+	// AmountFields are parsed with the number format instead of parseValue, ["amount"] when empty.
+	AmountFields []string `json:"amountFields" column:"amount_fields"`
+	// DebitField and CreditField name targets holding unsigned amounts, merged into "amount".
+	DebitField  string    `json:"debitField"  column:"debit_field"`
+	CreditField string    `json:"creditField" column:"credit_field"`
+	CreatedAt   time.Time `json:"createdAt"   column:"created_at"`
+}
+
+var ErrEmptyMapping = errors.New("mapping profile has no columns")
+
+// ParseWithProfile reads a .csv or Excel upload and returns one map per data row keyed by the profile's target fields.
+// A positive limit stops after that many rows, useful for previews.
+func (p *DefaultParser) ParseWithProfile(
+	file *multipart.FileHeader,
+	profile MappingProfile,
+	limit int,
+) ([]map[string]interface{}, error) {
+	if len(profile.Columns) == 0 {
+		return nil, ErrEmptyMapping
+	}
+
+	var rows [][]string
+	var err error
+	if strings.EqualFold(filepath.Ext(file.Filename), ".csv") {
+		rows, err = getCSVRows(file)
+	} else {
+		rows, err = getExcelRows(file)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var header []string
+	if profile.HeaderRow > 0 && profile.HeaderRow <= len(rows) {
+		header = rows[profile.HeaderRow-1]
+	}
+
+	indexes, err := resolveColumns(profile.Columns, header)
+	if err != nil {
+		return nil, err
+	}
+
+	dataStart := profile.HeaderRow
+	if dataStart > len(rows) {
+		dataStart = len(rows)
+	}
+
+	result := []map[string]interface{}{}
+	for _, row := range rows[dataStart:] {
+		rowData := make(map[string]interface{}, len(indexes))
+		raw := make(map[string]string, len(indexes))
+		for target, index := range indexes {
+			if index < len(row) && strings.TrimSpace(row[index]) != "" {
+				raw[target] = strings.TrimSpace(row[index])
+				rowData[target] = parseValue(raw[target])
+			}
+		}
+		if len(rowData) == 0 {
+			continue
+		}
+		profile.applyAmounts(rowData, raw)
+
+		result = append(result, rowData)
+		if limit > 0 && len(result) == limit {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// resolveColumns turns column letters and header titles into 0-based indexes.
+// Header titles win over letters, so a column titled "A" still maps by title.
+func resolveColumns(columns map[string]string, header []string) (map[string]int, error) {
+	indexes := make(map[string]int, len(columns))
+
+	for target, source := range columns {
+		source = strings.TrimSpace(source)
+
+		index := -1
+		for i, title := range header {
+			if strings.EqualFold(strings.TrimSpace(title), source) {
+				index = i
+				break
+			}
+		}
+
+		if index == -1 {
+			number, err := excelize.ColumnNameToNumber(source)
+			if err != nil {
+				return nil, fmt.Errorf("column %q of %s is neither a header title nor a column letter", source, target)
+			}
+			index = number - 1
+		}
+
+		indexes[target] = index
+	}
+
+	return indexes, nil
+}
+
+// getCSVRows reads all records of a csv upload, rows may have different lengths.
+func getCSVRows(file *multipart.FileHeader) ([][]string, error) {
+	fileContent, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %v", err)
+	}
+	defer fileContent.Close()
+
+	reader := csv.NewReader(fileContent)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	var rows [][]string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read csv file: %v", err)
+		}
+		rows = append(rows, record)
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,35 @@
+package privacy
+
+import (
+	"crypto/subtle"
+	"os"
+
+	"github.com/mystaline/clefinport-be/pkg/entity"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	SupportTokenHeader     = "X-Support-Token"
+	SupportRequesterHeader = "X-Support-Requester"
+)
+
+// RequireSupportAccess guards support-only routes (e.g. detokenization).
+// Requests must send SUPPORT_ACCESS_TOKEN in X-Support-Token and identify themselves in X-Support-Requester.
+// When SUPPORT_ACCESS_TOKEN is not configured every request is rejected.
+func RequireSupportAccess() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		expected := os.Getenv("SUPPORT_ACCESS_TOKEN")
+		given := ctx.Get(SupportTokenHeader)
+
+		if expected == "" || subtle.ConstantTimeCompare([]byte(expected), []byte(given)) != 1 {
+			return entity.Forbidden("support access required").SendResponse(ctx)
+		}
+
+		if ctx.Get(SupportRequesterHeader) == "" {
+			return entity.Forbidden("support requester is required").SendResponse(ctx)
+		}
+
+		return ctx.Next()
+	}
+}
@@ -0,0 +1,220 @@
+package privacy
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/mystaline/clefinport-be/pkg/db"
+	"github.com/mystaline/clefinport-be/pkg/entity"
+	"github.com/mystaline/clefinport-be/pkg/service"
+	"github.com/mystaline/clefinport-be/pkg/sql_query"
+)
+
+// TokenNamespace separates pseudonyms of different identifier kinds,
+// so the same raw value never produces the same token for e.g. a user and a wallet.
+type TokenNamespace string
+
+const (
+	NamespaceUser   TokenNamespace = "usr"
+	NamespaceWallet TokenNamespace = "wlt"
+)
+
+// Tag used on DTO fields that must be pseudonymized before leaving the service boundary.
+//
+// Example:
+//
+//	type TransactionExportRow struct {
+//	    UserID string `json:"userId" pii:"usr"`
+//	    Amount int    `json:"amount"`
+//	}
+const PIITag = "pii"
+
+var ErrTokenNotFound = errors.New("pseudonym token not found")
+
+// Tokenizer replaces identifiers with stable pseudonyms in data leaving the service (e.g. audit log export jobs).
+// Detokenization is only meant for support workflows and is guarded by DetokenizeRequest.
+type Tokenizer interface {
+	// Tokenize returns the stable pseudonym of subjectID inside the given namespace.
+	// The same (namespace, subjectID) pair always yields the same token.
+	Tokenize(ctx context.Context, namespace TokenNamespace, subjectID string) (string, error)
+	// Detokenize resolves a pseudonym back to its raw identifier.
+	// The request must carry a requester and a reason, both are written to the service log.
+	Detokenize(ctx context.Context, request DetokenizeRequest) (string, error)
+}
+
+type DetokenizeRequest struct {
+	Token       string `json:"token"`
+	RequesterID string `json:"requesterId"`
+	Reason      string `json:"reason"`
+}
+
+type piiTokenRow struct {
+	Token     string `json:"token"     column:"token"`
+	Namespace string `json:"namespace" column:"namespace"`
+	SubjectID string `json:"subjectId" column:"subject_id"`
+}
+
+type HMACTokenizer struct {
+	Service service.PostgreSqlService
+
+	secret []byte
+	// token -> already persisted in vault, prevents re-inserting hot identifiers on every export
+	persisted sync.Map
+}
+
+// MakeTokenizer creates a Tokenizer backed by the pii_tokens vault table of the given service.
+// The HMAC secret is read from PII_TOKEN_SECRET and must stay identical across deployments,
+// otherwise previously exported pseudonyms can no longer be joined.
+func MakeTokenizer(svc service.PostgreSqlService) (*HMACTokenizer, error) {
+	secret := os.Getenv("PII_TOKEN_SECRET")
+	if secret == "" {
+		return nil, errors.New("PII_TOKEN_SECRET is not set")
+	}
+
+	return &HMACTokenizer{Service: svc, secret: []byte(secret)}, nil
+}
+
+func (t *HMACTokenizer) Tokenize(
+	ctx context.Context,
+	namespace TokenNamespace,
+	subjectID string,
+) (string, error) {
+	if subjectID == "" {
+		return "", nil
+	}
+
+	mac := hmac.New(sha256.New, t.secret)
+	mac.Write([]byte(string(namespace) + ":" + subjectID))
+	token := fmt.Sprintf("%s_%s", namespace, base64.RawURLEncoding.EncodeToString(mac.Sum(nil))[:22])
+
+	if _, ok := t.persisted.Load(token); ok {
+		return token, nil
+	}
+
+	query, args, err := sql_query.NewSQLInsertBuilder(db.PIITokenTableName).
+		Insert(piiTokenRow{Token: token, Namespace: string(namespace), SubjectID: subjectID}).
+		Conflict("(token)", "NOTHING").
+		Build()
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := t.Service.InsertMany(ctx, query, args...); err != nil {
+		return "", err
+	}
+	t.persisted.Store(token, struct{}{})
+
+	return token, nil
+}
+
+func (t *HMACTokenizer) Detokenize(ctx context.Context, request DetokenizeRequest) (string, error) {
+	if request.Token == "" {
+		return "", entity.BadRequest("token is required")
+	}
+	if request.RequesterID == "" || strings.TrimSpace(request.Reason) == "" {
+		return "", entity.Forbidden("detokenization requires requester and reason")
+	}
+
+	query, args, err := sql_query.NewSQLSelectBuilder[piiTokenRow](db.PIITokenTableName).
+		Where(map[string]sql_query.SQLCondition{
+			"token": {Operator: sql_query.SQLOperatorEqual, Value: request.Token},
+		}).
+		SetLimit(1).
+		Build()
+	if err != nil {
+		return "", err
+	}
+
+	var row piiTokenRow
+	err = t.Service.SelectOne(&row, ctx, query, args...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrTokenNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+
+	log.Printf("detokenize: requester=%s token=%s reason=%q", request.RequesterID, request.Token, request.Reason)
+
+	return row.SubjectID, nil
+}
+
+// PseudonymizeRows replaces every string field tagged with `pii:"<namespace>"` in place.
+// rows must be a pointer to a struct or a pointer to a slice of structs.
+//
+// Example:
+//
+//	rows := []TransactionExportRow{...}
+//	err := privacy.PseudonymizeRows(ctx, tokenizer, &rows)
+func PseudonymizeRows(ctx context.Context, tokenizer Tokenizer, rows any) error {
+	v := reflect.ValueOf(rows)
+	if v.Kind() != reflect.Ptr {
+		return errors.New("PseudonymizeRows: rows must be a pointer")
+	}
+	v = v.Elem()
+
+	switch v.Kind() {
+	case reflect.Struct:
+		return pseudonymizeStruct(ctx, tokenizer, v)
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			elem := v.Index(i)
+			if elem.Kind() == reflect.Ptr {
+				elem = elem.Elem()
+			}
+			if err := pseudonymizeStruct(ctx, tokenizer, elem); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return errors.New("PseudonymizeRows: rows must point to a struct or slice of struct")
+	}
+}
+
+func pseudonymizeStruct(ctx context.Context, tokenizer Tokenizer, v reflect.Value) error {
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		namespace := t.Field(i).Tag.Get(PIITag)
+		field := v.Field(i)
+
+		if namespace == "" || !field.CanSet() {
+			continue
+		}
+
+		switch field.Kind() {
+		case reflect.String:
+			token, err := tokenizer.Tokenize(ctx, TokenNamespace(namespace), field.String())
+			if err != nil {
+				return err
+			}
+			field.SetString(token)
+		case reflect.Ptr:
+			if field.IsNil() || field.Elem().Kind() != reflect.String {
+				continue
+			}
+			token, err := tokenizer.Tokenize(ctx, TokenNamespace(namespace), field.Elem().String())
+			if err != nil {
+				return err
+			}
+			field.Elem().SetString(token)
+		}
+	}
+
+	return nil
+}
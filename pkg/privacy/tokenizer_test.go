@@ -0,0 +1,62 @@
+package privacy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/mystaline/clefinport-be/pkg/service"
+)
+
+func TestDetokenize(t *testing.T) {
+	t.Setenv("PII_TOKEN_SECRET", "secret")
+	connectionErr := errors.New("connection refused")
+
+	tests := []struct {
+		name     string
+		selected error
+		want     error
+	}{
+		{name: "unknown token", selected: pgx.ErrNoRows, want: ErrTokenNotFound},
+		{name: "query failure", selected: connectionErr, want: connectionErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &service.MockBasePostgreSqlService{}
+			svc.On("SelectOne", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(tt.selected)
+
+			tokenizer, err := MakeTokenizer(svc)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = tokenizer.Detokenize(context.Background(), DetokenizeRequest{Token: "usr_x", RequesterID: "support", Reason: "ticket 12"})
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Detokenize() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenizePersistsOnce(t *testing.T) {
+	t.Setenv("PII_TOKEN_SECRET", "secret")
+
+	svc := &service.MockBasePostgreSqlService{}
+	svc.On("InsertMany", mock.Anything, mock.Anything, mock.Anything).Return(int64(1), nil)
+
+	tokenizer, err := MakeTokenizer(svc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for range 3 {
+		if _, err := tokenizer.Tokenize(context.Background(), NamespaceUser, "42"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	svc.AssertNumberOfCalls(t, "InsertMany", 1)
+}
@@ -1,6 +1,7 @@
 package provider
 
 import (
+	"github.com/mystaline/clefinport-be/pkg/chaos"
 	"github.com/mystaline/clefinport-be/pkg/db"
 	"github.com/mystaline/clefinport-be/pkg/service"
 
@@ -13,8 +14,9 @@ type IServiceProvider interface {
 
 type ServiceProvider struct{}
 
+// MakeService returns the service of dbName, decorated with fault injection when CHAOS_ENABLED is set (see pkg/chaos).
 func (m *ServiceProvider) MakeService(dbName db.DBName) service.PostgreSqlService {
-	return service.MakeService(dbName)
+	return chaos.WrapService(service.MakeService(dbName), chaos.FromEnv())
 }
 
 type MockServiceProvider struct {
@@ -23,5 +25,5 @@ type MockServiceProvider struct {
 
 func (m *MockServiceProvider) MakeService(dbName db.DBName) service.PostgreSqlService {
 	args := m.Called(dbName)
-	return args.Error(0).(service.PostgreSqlService)
+	return args.Get(0).(service.PostgreSqlService)
 }
@@ -0,0 +1,38 @@
+package quota
+
+import (
+	"context"
+	"time"
+
+	"github.com/mystaline/clefinport-be/pkg/service"
+	"github.com/mystaline/clefinport-be/pkg/sql_query"
+)
+
+// CountRows counts the rows of table whose userColumn is the user, for capacity resources.
+//
+// Example:
+//
+//	quota.Config{Counters: map[quota.Resource]quota.Counter{
+//	    quota.ResourceWallets: quota.CountRows(walletService, db.UserWalletTableName, "user_id"),
+//	}}
+func CountRows(svc service.PostgreSqlService, table string, userColumn string) Counter {
+	return func(ctx context.Context, userID string, _ time.Time) (int64, error) {
+		count, err := svc.CountWithFilter(ctx, table, map[string]sql_query.SQLCondition{
+			userColumn: {Operator: sql_query.SQLOperatorEqual, Value: userID},
+		})
+
+		return int64(count), err
+	}
+}
+
+// CountRowsSince is CountRows limited to rows whose timeColumn is within the current window, for daily resources.
+func CountRowsSince(svc service.PostgreSqlService, table string, userColumn string, timeColumn string) Counter {
+	return func(ctx context.Context, userID string, since time.Time) (int64, error) {
+		count, err := svc.CountWithFilter(ctx, table, map[string]sql_query.SQLCondition{
+			userColumn: {Operator: sql_query.SQLOperatorEqual, Value: userID},
+			timeColumn: {Operator: sql_query.SQLOperatorGTE, Value: since},
+		})
+
+		return int64(count), err
+	}
+}
@@ -0,0 +1,304 @@
+package quota
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/mystaline/clefinport-be/pkg/entity"
+)
+
+// Soft per-plan limits checked by usecases before doing the work.
+// Usage is cached in memory per instance and recounted from the database (Counters) once it gets stale,
+// so limits may be overshot by a few operations across instances, which is fine for quotas that only nudge upgrades.
+
+type Plan string
+
+const (
+	PlanFree    Plan = "free"
+	PlanPremium Plan = "premium"
+)
+
+type Resource string
+
+const (
+	// Wallets a user owns at once
+	ResourceWallets Resource = "wallets"
+	// Imports started per UTC day
+	ResourceImportsPerDay Resource = "imports_per_day"
+	// Size of a single import file in bytes
+	ResourceImportBytes Resource = "import_bytes"
+	// Total size of stored attachments in bytes
+	ResourceAttachmentBytes Resource = "attachment_bytes"
+)
+
+type kind int
+
+const (
+	// Usage accumulates until released, exceeding answers 402
+	kindCapacity kind = iota
+	// Usage resets at UTC midnight, exceeding answers 429
+	kindDaily
+	// Only the requested amount is compared, nothing is tracked
+	kindPerRequest
+)
+
+var resourceKinds = map[Resource]kind{
+	ResourceWallets:         kindCapacity,
+	ResourceImportsPerDay:   kindDaily,
+	ResourceImportBytes:     kindPerRequest,
+	ResourceAttachmentBytes: kindCapacity,
+}
+
+// Unlimited disables the check of a resource, so do missing resources in Limits.
+const Unlimited int64 = -1
+
+type Limits map[Resource]int64
+
+var DefaultPlans = map[Plan]Limits{
+	PlanFree: {
+		ResourceWallets:         3,
+		ResourceImportsPerDay:   5,
+		ResourceImportBytes:     5 << 20,
+		ResourceAttachmentBytes: 100 << 20,
+	},
+	PlanPremium: {
+		ResourceWallets:         50,
+		ResourceImportsPerDay:   100,
+		ResourceImportBytes:     50 << 20,
+		ResourceAttachmentBytes: 5 << 30,
+	},
+}
+
+// Counter returns the real usage of a user, since is the start of the current day for daily resources
+// and the zero time otherwise.
+type Counter func(ctx context.Context, userID string, since time.Time) (int64, error)
+
+type Config struct {
+	// Plans defaults to DefaultPlans
+	Plans map[Plan]Limits
+	// PlanOf resolves the plan of a user, everyone is on PlanFree when nil
+	PlanOf func(ctx context.Context, userID string) (Plan, error)
+	// Counters reconcile cached usage, resources without one trust the cache
+	Counters map[Resource]Counter
+	// ReconcileAfter is the age at which cached usage is recounted, 5 minutes by default
+	ReconcileAfter time.Duration
+}
+
+func (c Config) withDefaults() Config {
+	if c.Plans == nil {
+		c.Plans = DefaultPlans
+	}
+	if c.ReconcileAfter <= 0 {
+		c.ReconcileAfter = 5 * time.Minute
+	}
+
+	return c
+}
+
+// Exceeded is the data of the error returned when a quota is hit, clients use it to show upgrade hints.
+type Exceeded struct {
+	Resource  Resource   `json:"resource"`
+	Plan      Plan       `json:"plan"`
+	Limit     int64      `json:"limit"`
+	Used      int64      `json:"used"`
+	Requested int64      `json:"requested"`
+	ResetAt   *time.Time `json:"resetAt,omitempty"`
+}
+
+type usageKey struct {
+	userID   string
+	resource Resource
+}
+
+type usage struct {
+	value int64
+	// Start of the day the value belongs to, zero for capacity resources
+	window       time.Time
+	reconciledAt time.Time
+}
+
+type Manager struct {
+	config Config
+	now    func() time.Time
+
+	mu    sync.Mutex
+	usage map[usageKey]*usage
+}
+
+func MakeManager(config Config) *Manager {
+	return &Manager{
+		config: config.withDefaults(),
+		now:    time.Now,
+		usage:  map[usageKey]*usage{},
+	}
+}
+
+// Check returns an *entity.HttpError with Exceeded data when amount more of resource would go over the user's limit.
+func (m *Manager) Check(ctx context.Context, userID string, resource Resource, amount int64) error {
+	return m.evaluate(ctx, userID, resource, amount, false)
+}
+
+// Consume is Check followed by Add when within the limit, in one step so concurrent requests can't both pass.
+func (m *Manager) Consume(ctx context.Context, userID string, resource Resource, amount int64) error {
+	return m.evaluate(ctx, userID, resource, amount, true)
+}
+
+// Add records amount of resource as used, a negative amount releases it (e.g. a deleted wallet).
+func (m *Manager) Add(userID string, resource Resource, amount int64) {
+	if resourceKinds[resource] == kindPerRequest {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Unknown usage is counted on the next Check
+	if current, ok := m.usage[usageKey{userID, resource}]; ok && current.window.Equal(m.window(resource)) {
+		current.value += amount
+	}
+}
+
+func (m *Manager) evaluate(ctx context.Context, userID string, resource Resource, amount int64, consume bool) error {
+	plan := PlanFree
+	if m.config.PlanOf != nil {
+		resolved, err := m.config.PlanOf(ctx, userID)
+		if err != nil {
+			return err
+		}
+		plan = resolved
+	}
+
+	limit, ok := m.config.Plans[plan][resource]
+	if !ok || limit == Unlimited {
+		return nil
+	}
+
+	if resourceKinds[resource] == kindPerRequest {
+		if amount > limit {
+			return m.exceeded(Exceeded{Resource: resource, Plan: plan, Limit: limit, Requested: amount})
+		}
+		return nil
+	}
+
+	current, err := m.lockCurrent(ctx, userID, resource)
+	defer m.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if current.value+amount > limit {
+		return m.exceeded(Exceeded{Resource: resource, Plan: plan, Limit: limit, Used: current.value, Requested: amount})
+	}
+
+	if consume {
+		current.value += amount
+	}
+
+	return nil
+}
+
+// lockCurrent returns the cached usage, recounting it when missing, stale or from a previous day.
+// It always returns with mu held, the count query itself runs unlocked.
+func (m *Manager) lockCurrent(ctx context.Context, userID string, resource Resource) (*usage, error) {
+	key := usageKey{userID, resource}
+	window := m.window(resource)
+	now := m.now()
+
+	m.mu.Lock()
+	current, ok := m.usage[key]
+	if ok && current.window.Equal(window) && now.Sub(current.reconciledAt) < m.config.ReconcileAfter {
+		return current, nil
+	}
+
+	counter, hasCounter := m.config.Counters[resource]
+	if !hasCounter {
+		// The cache is the only source, keep it within the window
+		if !ok || !current.window.Equal(window) {
+			current = &usage{window: window}
+			m.usage[key] = current
+		}
+		current.reconciledAt = now
+		return current, nil
+	}
+
+	m.mu.Unlock()
+	value, err := counter(ctx, userID, window)
+	m.mu.Lock()
+	if err != nil {
+		return nil, fmt.Errorf("count %s usage: %w", resource, err)
+	}
+
+	current = &usage{value: value, window: window, reconciledAt: now}
+	m.usage[key] = current
+
+	return current, nil
+}
+
+// window is the start of the current UTC day for daily resources, zero otherwise.
+func (m *Manager) window(resource Resource) time.Time {
+	if resourceKinds[resource] != kindDaily {
+		return time.Time{}
+	}
+
+	return m.now().UTC().Truncate(24 * time.Hour)
+}
+
+func (m *Manager) exceeded(data Exceeded) *entity.HttpError {
+	code := fiber.StatusPaymentRequired
+	message := fmt.Sprintf("%s limit of the %s plan reached", data.Resource, data.Plan)
+
+	if resourceKinds[data.Resource] == kindDaily {
+		resetAt := m.window(data.Resource).Add(24 * time.Hour)
+		data.ResetAt = &resetAt
+		code = fiber.StatusTooManyRequests
+		message = fmt.Sprintf("daily %s limit of the %s plan reached", data.Resource, data.Plan)
+	}
+
+	return &entity.HttpError{Code: code, Message: message, Data: data}
+}
+
+// StartReconciler drops cached usage that hasn't been reconciled for two ReconcileAfter periods,
+// so idle users don't pin memory, until ctx is cancelled. Active users are recounted lazily by Check.
+// Usage of resources without a Counter is only dropped once its window has passed, the cache is its only record.
+func (m *Manager) StartReconciler(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if dropped := m.dropIdle(); dropped > 0 {
+					log.Printf("quota: dropped %d idle usage entries", dropped)
+				}
+			}
+		}
+	}()
+}
+
+func (m *Manager) dropIdle() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cutoff := m.now().Add(-2 * m.config.ReconcileAfter)
+
+	dropped := 0
+	for key, current := range m.usage {
+		if _, hasCounter := m.config.Counters[key.resource]; !hasCounter && current.window.Equal(m.window(key.resource)) {
+			continue
+		}
+		if current.reconciledAt.Before(cutoff) {
+			delete(m.usage, key)
+			dropped++
+		}
+	}
+
+	return dropped
+}
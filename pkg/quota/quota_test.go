@@ -0,0 +1,62 @@
+package quota
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDropIdleKeepsUncountedUsageWithinItsWindow(t *testing.T) {
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	m := MakeManager(Config{})
+	m.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		if err := m.Consume(ctx, "user", ResourceImportsPerDay, 1); err != nil {
+			t.Fatalf("Consume() #%d error = %v", i+1, err)
+		}
+	}
+
+	now = now.Add(time.Hour)
+	if dropped := m.dropIdle(); dropped != 0 {
+		t.Fatalf("dropIdle() = %d within the window, want 0", dropped)
+	}
+	if err := m.Consume(ctx, "user", ResourceImportsPerDay, 1); err == nil {
+		t.Fatal("Consume() after an idle hour = nil, want the daily limit error")
+	}
+
+	now = now.Add(24 * time.Hour)
+	if dropped := m.dropIdle(); dropped != 1 {
+		t.Fatalf("dropIdle() = %d after the window, want 1", dropped)
+	}
+}
+
+func TestDropIdleDropsCountedUsage(t *testing.T) {
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	m := MakeManager(Config{Counters: map[Resource]Counter{
+		ResourceImportsPerDay: func(context.Context, string, time.Time) (int64, error) { return 0, nil },
+	}})
+	m.now = func() time.Time { return now }
+
+	if err := m.Consume(context.Background(), "user", ResourceImportsPerDay, 1); err != nil {
+		t.Fatalf("Consume() error = %v", err)
+	}
+
+	now = now.Add(time.Hour)
+	if dropped := m.dropIdle(); dropped != 1 {
+		t.Fatalf("dropIdle() = %d, want 1", dropped)
+	}
+}
+
+func TestAddReleasesConsumedUsage(t *testing.T) {
+	m := MakeManager(Config{})
+
+	ctx := context.Background()
+	for i := 0; i < 10; i++ {
+		if err := m.Consume(ctx, "user", ResourceImportsPerDay, 1); err != nil {
+			t.Fatalf("Consume() #%d error = %v", i+1, err)
+		}
+		m.Add("user", ResourceImportsPerDay, -1)
+	}
+}
@@ -0,0 +1,213 @@
+package replay
+
+import (
+	"context"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mystaline/clefinport-be/pkg/apikey"
+	"github.com/mystaline/clefinport-be/pkg/db"
+	"github.com/mystaline/clefinport-be/pkg/provider"
+	"github.com/mystaline/clefinport-be/pkg/service"
+	"github.com/mystaline/clefinport-be/pkg/sql_query"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type eventLogRow struct {
+	EventType string   `json:"eventType" column:"event_type"`
+	Payload   Envelope `json:"payload"   column:"payload"`
+}
+
+// Recorder persists envelopes into event_logs asynchronously, in batches flushed every second.
+// Capturing never slows a request down, envelopes are dropped when the buffer is full.
+type Recorder struct {
+	Service service.PostgreSqlService
+
+	service   string
+	envelopes chan Envelope
+	done      chan struct{}
+	dropped   atomic.Int64
+
+	// Held for reading while sending to envelopes and for writing to close it, a send never hits a closed channel
+	mu     sync.RWMutex
+	closed bool
+}
+
+// MakeRecorder starts a recorder storing the envelopes of serviceName into the event_logs of svc.
+func MakeRecorder(svc service.PostgreSqlService, serviceName string) *Recorder {
+	r := &Recorder{
+		Service:   svc,
+		service:   serviceName,
+		envelopes: make(chan Envelope, 1024),
+		done:      make(chan struct{}),
+	}
+	go r.run()
+
+	return r
+}
+
+// CaptureFromEnv returns the capture middleware when REPLAY_CAPTURE=true, a pass-through handler otherwise.
+// Envelopes go to the event_logs of the log service database.
+//
+// Example:
+//
+//	app.Use(replay.CaptureFromEnv(serviceProvider, "wallet_service"))
+func CaptureFromEnv(serviceProvider provider.IServiceProvider, serviceName string) fiber.Handler {
+	if os.Getenv("REPLAY_CAPTURE") != "true" {
+		return func(ctx *fiber.Ctx) error {
+			return ctx.Next()
+		}
+	}
+
+	log.Printf("⚠️ Replay capture enabled for %s\n", serviceName)
+	return Capture(MakeRecorder(serviceProvider.MakeService(db.LogServiceDBName), serviceName))
+}
+
+// Capture records the mutating requests of the routes it is mounted on, once they are handled.
+// Mount it after the authentication middleware so the actor is known.
+func Capture(recorder *Recorder) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		if !IsMutating(ctx.Method()) {
+			return ctx.Next()
+		}
+
+		// Copied before the chain, handlers may reuse the request buffers
+		envelope := Envelope{
+			Service:    recorder.service,
+			Method:     strings.Clone(ctx.Method()),
+			Path:       strings.Clone(ctx.Path()),
+			Query:      string(ctx.Request().URI().QueryString()),
+			Headers:    SanitizeHeaders(ctx.GetReqHeaders()),
+			OccurredAt: time.Now(),
+		}
+		if strings.HasPrefix(ctx.Get(fiber.HeaderContentType), fiber.MIMEApplicationJSON) || len(ctx.Body()) == 0 {
+			envelope.Body, envelope.BodyOmitted = sanitizeOrOmit(ctx.Body())
+		} else {
+			envelope.BodyOmitted = true
+		}
+
+		chainErr := ctx.Next()
+
+		envelope.Actor = actor(ctx)
+		envelope.Status = ctx.Response().StatusCode()
+		if chainErr != nil {
+			if fiberErr, ok := chainErr.(*fiber.Error); ok {
+				envelope.Status = fiberErr.Code
+			} else {
+				envelope.Status = fiber.StatusInternalServerError
+			}
+		}
+		recorder.TryRecord(envelope)
+
+		return chainErr
+	}
+}
+
+func sanitizeOrOmit(body []byte) ([]byte, bool) {
+	sanitized, ok := SanitizeBody(body)
+	return sanitized, !ok
+}
+
+func actor(ctx *fiber.Ctx) string {
+	if key := apikey.FromContext(ctx); key != nil {
+		return "api_key:" + key.ID
+	}
+	if userId, ok := ctx.Locals(apikey.LocalsUserID).(string); ok && userId != "" {
+		return "user:" + userId
+	}
+
+	return ""
+}
+
+// TryRecord enqueues envelope without blocking. It returns false when the envelope was dropped.
+func (r *Recorder) TryRecord(envelope Envelope) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.closed {
+		return false
+	}
+
+	select {
+	case r.envelopes <- envelope:
+		return true
+	default:
+		r.dropped.Add(1)
+		return false
+	}
+}
+
+// Dropped returns how many envelopes never made it into the buffer, a replay missing some is not faithful.
+func (r *Recorder) Dropped() int64 {
+	return r.dropped.Load()
+}
+
+// Close stops accepting envelopes and waits until the buffer is flushed or ctx is done.
+func (r *Recorder) Close(ctx context.Context) error {
+	r.mu.Lock()
+	if !r.closed {
+		r.closed = true
+		close(r.envelopes)
+	}
+	r.mu.Unlock()
+
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *Recorder) run() {
+	defer close(r.done)
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	batch := make([]eventLogRow, 0, 100)
+	for {
+		select {
+		case envelope, ok := <-r.envelopes:
+			if !ok {
+				r.flush(batch)
+				return
+			}
+
+			batch = append(batch, eventLogRow{EventType: EventTypePrefix + envelope.Service, Payload: envelope})
+			if len(batch) == cap(batch) {
+				r.flush(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			r.flush(batch)
+			batch = batch[:0]
+		}
+	}
+}
+
+func (r *Recorder) flush(batch []eventLogRow) {
+	if len(batch) == 0 {
+		return
+	}
+
+	query, args, err := sql_query.NewSQLInsertBuilder(db.EventLogTableName).
+		Insert(batch).
+		Build()
+	if err != nil {
+		log.Printf("replay: failed to build insert: %v", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := r.Service.InsertMany(ctx, query, args...); err != nil {
+		log.Printf("replay: failed to write %d envelopes: %v", len(batch), err)
+	}
+}
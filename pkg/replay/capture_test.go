@@ -0,0 +1,44 @@
+package replay
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/mystaline/clefinport-be/pkg/service"
+)
+
+func TestTryRecordWhileClosing(t *testing.T) {
+	svc := &service.MockBasePostgreSqlService{}
+	svc.On("InsertMany", mock.Anything, mock.Anything, mock.Anything).Return(int64(0), nil)
+
+	for range 10 {
+		recorder := MakeRecorder(svc, "wallet_service")
+
+		// Recording keeps going while Close runs, a send must never hit the closed buffer
+		var wg, started sync.WaitGroup
+		for range 8 {
+			wg.Add(1)
+			started.Add(1)
+			go func() {
+				defer wg.Done()
+				started.Done()
+				for range 5000 {
+					recorder.TryRecord(Envelope{Method: "POST", Path: "/v1/wallet"})
+				}
+			}()
+		}
+
+		started.Wait()
+		if err := recorder.Close(context.Background()); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+		wg.Wait()
+
+		if recorder.TryRecord(Envelope{}) {
+			t.Fatal("TryRecord() after Close() = true, want false")
+		}
+	}
+}
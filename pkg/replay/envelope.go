@@ -0,0 +1,137 @@
+package replay
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Replay log for reproducing production incidents (balance discrepancies mostly): the mutating requests
+// of a service are captured, sanitized, into event_logs, and cmd/replay re-executes them in order
+// against a staging deployment. Capture is opt-in, see CaptureFromEnv.
+//
+// Sanitizing drops every header but HeadersKept, so credentials never reach the log, and replaces the
+// values of SensitiveKeys in JSON bodies. Other bodies (multipart uploads) are not stored, their envelope
+// is kept with BodyOmitted so the replay knows a request is missing.
+
+// Prefix of event_type for every replay envelope, keeps them apart from other event_logs rows
+const EventTypePrefix = "replay."
+
+// Value replacing sensitive JSON values
+const Redacted = "[redacted]"
+
+// Bodies larger than MaxBodyBytes are not stored
+const MaxBodyBytes = 64 << 10
+
+// HeadersKept are the request headers stored with an envelope, matched case-insensitively.
+var HeadersKept = []string{
+	"Content-Type",
+	"Accept",
+	"Accept-Language",
+	"Idempotency-Key",
+	"X-Request-Id",
+}
+
+// SensitiveKeys are the JSON keys whose values are redacted, matched case-insensitively at any depth.
+var SensitiveKeys = []string{
+	"password",
+	"token",
+	"secret",
+	"apiKey",
+	"pin",
+	"otp",
+	"accountNumber",
+}
+
+// Envelope is one captured request.
+type Envelope struct {
+	Service     string            `json:"service"`
+	Method      string            `json:"method"`
+	Path        string            `json:"path"`
+	Query       string            `json:"query,omitempty"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	Body        json.RawMessage   `json:"body,omitempty"`
+	BodyOmitted bool              `json:"bodyOmitted,omitempty"`
+	// Actor is the authenticated caller, credentials are not kept so the replay authenticates on its own
+	Actor string `json:"actor,omitempty"`
+	// Status is the response status seen in production, the replay compares against it
+	Status     int       `json:"status"`
+	OccurredAt time.Time `json:"occurredAt"`
+}
+
+// IsMutating reports whether requests of method change state and are captured.
+func IsMutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+
+	return false
+}
+
+// SanitizeHeaders returns the HeadersKept found in headers, values are copied.
+func SanitizeHeaders(headers map[string][]string) map[string]string {
+	kept := map[string]string{}
+	for name, values := range headers {
+		for _, keep := range HeadersKept {
+			if strings.EqualFold(name, keep) {
+				kept[keep] = strings.Clone(strings.Join(values, ", "))
+				break
+			}
+		}
+	}
+
+	return kept
+}
+
+// SanitizeBody redacts SensitiveKeys in a JSON body, false when body is not JSON or too large to store.
+func SanitizeBody(body []byte) (json.RawMessage, bool) {
+	if len(body) == 0 {
+		return nil, true
+	}
+	if len(body) > MaxBodyBytes {
+		return nil, false
+	}
+
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return nil, false
+	}
+
+	sanitized, err := json.Marshal(redact(value))
+	if err != nil {
+		return nil, false
+	}
+
+	return sanitized, true
+}
+
+func redact(value any) any {
+	switch typed := value.(type) {
+	case map[string]any:
+		for key, nested := range typed {
+			if isSensitive(key) {
+				typed[key] = Redacted
+				continue
+			}
+			typed[key] = redact(nested)
+		}
+	case []any:
+		for i, nested := range typed {
+			typed[i] = redact(nested)
+		}
+	}
+
+	return value
+}
+
+func isSensitive(key string) bool {
+	for _, sensitive := range SensitiveKeys {
+		if strings.EqualFold(key, sensitive) {
+			return true
+		}
+	}
+
+	return false
+}
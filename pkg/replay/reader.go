@@ -0,0 +1,81 @@
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/mystaline/clefinport-be/pkg/db"
+	"github.com/mystaline/clefinport-be/pkg/service"
+	"github.com/mystaline/clefinport-be/pkg/sql_query"
+)
+
+type Filter struct {
+	// Service of the envelopes, every service when empty
+	Service string
+	// Actor narrows the replay to one caller, e.g. "user:<id>" for a user reporting a wrong balance
+	Actor string
+	Since time.Time
+	Until time.Time
+}
+
+// StoredEnvelope is an envelope with its event_logs id, ids give the capture order.
+type StoredEnvelope struct {
+	ID       int64 `json:"id"`
+	Envelope Envelope
+}
+
+type storedRow struct {
+	ID      int64           `json:"id"      column:"id"`
+	Payload json.RawMessage `json:"payload" column:"payload"`
+}
+
+// EachEnvelope calls fn with every envelope matching filter in capture order, without loading them all.
+func EachEnvelope(
+	ctx context.Context,
+	svc service.PostgreSqlService,
+	filter Filter,
+	fn func(stored StoredEnvelope) error,
+) error {
+	builder := sql_query.NewSQLSelectBuilder[storedRow](db.EventLogTableName).
+		Where(envelopeFilters(filter))
+	if !filter.Until.IsZero() {
+		builder = builder.Where(map[string]sql_query.SQLCondition{
+			occurredAt: {Operator: sql_query.SQLOperatorLessThan, Value: filter.Until},
+		})
+	}
+
+	query, args, err := builder.OrderBy([]string{"id"}, true).Build()
+	if err != nil {
+		return err
+	}
+
+	return service.SelectEach(ctx, svc, query, args, func(row storedRow) error {
+		stored := StoredEnvelope{ID: row.ID}
+		if err := json.Unmarshal(row.Payload, &stored.Envelope); err != nil {
+			return err
+		}
+
+		return fn(stored)
+	})
+}
+
+// Capture time of an envelope
+const occurredAt = "(payload->>'occurredAt')::timestamptz"
+
+func envelopeFilters(filter Filter) map[string]sql_query.SQLCondition {
+	filters := map[string]sql_query.SQLCondition{
+		"event_type": {Operator: sql_query.SQLOperatorLike, Value: EventTypePrefix + "%"},
+	}
+	if filter.Service != "" {
+		filters["event_type"] = sql_query.SQLCondition{Operator: sql_query.SQLOperatorEqual, Value: EventTypePrefix + filter.Service}
+	}
+	if filter.Actor != "" {
+		filters["payload->>'actor'"] = sql_query.SQLCondition{Operator: sql_query.SQLOperatorEqual, Value: filter.Actor}
+	}
+	if !filter.Since.IsZero() {
+		filters[occurredAt] = sql_query.SQLCondition{Operator: sql_query.SQLOperatorGTE, Value: filter.Since}
+	}
+
+	return filters
+}
@@ -0,0 +1,276 @@
+package schemacheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/mystaline/clefinport-be/pkg/service"
+	"github.com/mystaline/clefinport-be/pkg/sql_query"
+)
+
+// Startup self-check that the column tags of DTOs still match the database schema,
+// so a deploy where migrations and code diverge fails fast instead of on the first request.
+
+// Target is a DTO and the tables its column tags refer to, built with For.
+type Target struct {
+	Type reflect.Type
+	// Tables[0] owns unqualified columns, qualified columns (users.full_name) must use one of the table names.
+	Tables []string
+}
+
+// For registers T against tables, the first table owns unqualified column tags.
+//
+// Example:
+//
+//	schemacheck.For[dto.GetUserInfoData](db.UserTableName, db.ProfileSettingTableName)
+func For[T any](tables ...string) Target {
+	return Target{Type: reflect.TypeOf((*T)(nil)).Elem(), Tables: tables}
+}
+
+// Mismatch describes one column tag that doesn't fit the schema.
+type Mismatch struct {
+	DTO    string
+	Field  string
+	Table  string
+	Column string
+	Reason string
+}
+
+func (m Mismatch) String() string {
+	return fmt.Sprintf("%s.%s -> %s.%s: %s", m.DTO, m.Field, m.Table, m.Column, m.Reason)
+}
+
+type columnInfo struct {
+	TableName  string `json:"tableName"  column:"table_name"`
+	ColumnName string `json:"columnName" column:"column_name"`
+	DataType   string `json:"dataType"   column:"data_type"`
+}
+
+// Verify introspects information_schema for every table of targets and returns an error listing all mismatches.
+func Verify(ctx context.Context, svc service.PostgreSqlService, targets ...Target) error {
+	mismatches, err := Check(ctx, svc, targets...)
+	if err != nil {
+		return err
+	}
+	if len(mismatches) == 0 {
+		return nil
+	}
+
+	lines := make([]string, len(mismatches))
+	for i, each := range mismatches {
+		lines[i] = each.String()
+	}
+
+	return fmt.Errorf("schema check failed:\n  %s", strings.Join(lines, "\n  "))
+}
+
+// Check is Verify returning the mismatches instead of an error.
+func Check(ctx context.Context, svc service.PostgreSqlService, targets ...Target) ([]Mismatch, error) {
+	var tables []string
+	for _, target := range targets {
+		if len(target.Tables) == 0 {
+			return nil, errors.New("schema check: target without table")
+		}
+		for _, table := range target.Tables {
+			if !sql_query.ArrayIncludes(tables, table) {
+				tables = append(tables, table)
+			}
+		}
+	}
+
+	query, args, err := sql_query.NewSQLSelectBuilder[columnInfo]("information_schema.columns").
+		Where(map[string]sql_query.SQLCondition{
+			"table_schema": {Operator: sql_query.SQLOperatorEqual, Value: "current_schema()", IsRef: true},
+			"table_name":   {Operator: sql_query.SQLOperatorIn, Value: tables},
+		}).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+
+	var columns []columnInfo
+	if err := svc.SelectMany(&columns, ctx, query, args...); err != nil {
+		return nil, err
+	}
+
+	// table -> column -> data type
+	schema := make(map[string]map[string]string, len(tables))
+	for _, each := range columns {
+		if schema[each.TableName] == nil {
+			schema[each.TableName] = map[string]string{}
+		}
+		schema[each.TableName][each.ColumnName] = each.DataType
+	}
+
+	var mismatches []Mismatch
+	for _, target := range targets {
+		for _, table := range target.Tables {
+			if _, ok := schema[table]; !ok {
+				mismatches = append(mismatches, Mismatch{DTO: target.Type.Name(), Table: table, Reason: "table does not exist"})
+			}
+		}
+
+		fields := sql_query.ExtractFromType(target.Type)
+		mismatches = append(mismatches, checkFields(target, fields, schema, false)...)
+	}
+
+	return mismatches, nil
+}
+
+func checkFields(target Target, fields []sql_query.FieldMeta, schema map[string]map[string]string, nested bool) []Mismatch {
+	var mismatches []Mismatch
+
+	for _, field := range fields {
+		if len(field.NestedFields) > 0 && field.ColumnTag == "" {
+			mismatches = append(mismatches, checkFields(target, field.NestedFields, schema, true)...)
+			continue
+		}
+
+		columnExpr := field.ColumnTag
+		if columnExpr == "" {
+			// Nested JSON keys are not columns
+			if nested || field.JSONTag == "" || field.JSONTag == "-" {
+				continue
+			}
+			columnExpr = sql_query.CamelToSnake(field.JSONTag)
+		}
+		if columnExpr == "-" || field.IsGenerated {
+			continue
+		}
+
+		qualifier, column, resultType, ok := parseColumnExpr(columnExpr)
+		if !ok {
+			// Computed expressions can't be verified against a single column
+			continue
+		}
+
+		table := target.Tables[0]
+		if qualifier != "" {
+			if !sql_query.ArrayIncludes(target.Tables, qualifier) {
+				mismatches = append(mismatches, Mismatch{
+					DTO: target.Type.Name(), Field: field.Name, Table: qualifier, Column: column,
+					Reason: "qualifier is not one of the registered tables",
+				})
+				continue
+			}
+			table = qualifier
+		}
+
+		tableColumns, ok := schema[table]
+		if !ok {
+			// Already reported as missing table
+			continue
+		}
+
+		dataType, ok := tableColumns[column]
+		if !ok {
+			mismatches = append(mismatches, Mismatch{
+				DTO: target.Type.Name(), Field: field.Name, Table: table, Column: column,
+				Reason: "column does not exist",
+			})
+			continue
+		}
+
+		if resultType == "" {
+			resultType = dataType
+		}
+		if !compatible(field, resultType) {
+			mismatches = append(mismatches, Mismatch{
+				DTO: target.Type.Name(), Field: field.Name, Table: table, Column: column,
+				Reason: fmt.Sprintf("%s can't be scanned into %s", resultType, fieldTypeName(field)),
+			})
+		}
+	}
+
+	return mismatches
+}
+
+// parseColumnExpr splits `users.id::text` into users, id and the cast type text.
+// `payload->>'actor'` resolves to payload with a text result. ok is false for computed expressions.
+func parseColumnExpr(expr string) (qualifier string, column string, resultType string, ok bool) {
+	expr = strings.TrimSpace(expr)
+	if strings.ContainsAny(expr, "( ") {
+		return "", "", "", false
+	}
+
+	if before, cast, found := strings.Cut(expr, "::"); found {
+		expr = before
+		resultType = strings.ToLower(cast)
+	}
+	if before, _, found := strings.Cut(expr, "->>"); found {
+		expr = before
+		resultType = "text"
+	} else if before, _, found := strings.Cut(expr, "->"); found {
+		expr = before
+		resultType = "jsonb"
+	}
+
+	expr = strings.ReplaceAll(expr, `"`, "")
+	if dot := strings.LastIndex(expr, "."); dot >= 0 {
+		return expr[:dot], expr[dot+1:], resultType, true
+	}
+
+	return "", expr, resultType, true
+}
+
+// compatible mirrors what the JSON round trip of ScanRowObject/ScanRowsArray can decode.
+func compatible(field sql_query.FieldMeta, dataType string) bool {
+	dataType = strings.ToLower(dataType)
+	if dataType == "json" || dataType == "jsonb" {
+		return true
+	}
+
+	if field.IsSlice {
+		return dataType == "array" || strings.HasSuffix(dataType, "[]")
+	}
+	if field.IsTime {
+		return strings.HasPrefix(dataType, "timestamp") || dataType == "date" || dataType == "timestamptz"
+	}
+
+	switch field.Type.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.String:
+		return isTextType(dataType)
+	case reflect.Bool:
+		return dataType == "boolean" || dataType == "bool"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return isIntegerType(dataType) || dataType == "numeric"
+	case reflect.Float32, reflect.Float64:
+		return isIntegerType(dataType) || dataType == "numeric" || dataType == "real" || dataType == "double precision"
+	case reflect.Struct, reflect.Map:
+		return false
+	}
+
+	return true
+}
+
+func isTextType(dataType string) bool {
+	switch dataType {
+	case "text", "character varying", "varchar", "character", "char", "uuid", "citext", "inet", "user-defined":
+		return true
+	}
+
+	return false
+}
+
+func isIntegerType(dataType string) bool {
+	switch dataType {
+	case "smallint", "integer", "bigint", "int", "int2", "int4", "int8":
+		return true
+	}
+
+	return false
+}
+
+func fieldTypeName(field sql_query.FieldMeta) string {
+	if field.IsSlice {
+		return "[]" + field.Type.String()
+	}
+
+	return field.Type.String()
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/mystaline/clefinport-be/pkg/sql_query"
+)
+
+// The WithData methods stamp the audit columns (created_by/updated_by) of the rows they write
+// with the user acting in the request context, on the tables having those columns.
+// Whether a table has them is read once from information_schema, restart the service after adding them.
+
+type actorKey struct{}
+
+// ActorColumns names the audit columns, an empty name is never stamped.
+type ActorColumns struct {
+	CreatedBy string
+	UpdatedBy string
+}
+
+var actorColumns atomic.Pointer[ActorColumns]
+
+func init() {
+	actorColumns.Store(&ActorColumns{CreatedBy: "created_by", UpdatedBy: "updated_by"})
+}
+
+// SetActorColumns renames the stamped audit columns, pass ActorColumns{} to disable stamping.
+func SetActorColumns(columns ActorColumns) {
+	actorColumns.Store(&columns)
+}
+
+// DefaultActorColumns returns the columns set by SetActorColumns, created_by and updated_by unless changed.
+func DefaultActorColumns() ActorColumns {
+	return *actorColumns.Load()
+}
+
+// WithActor marks ctx as acting on behalf of userID.
+func WithActor(ctx context.Context, userID string) context.Context {
+	if userID == "" {
+		return ctx
+	}
+
+	return context.WithValue(ctx, actorKey{}, userID)
+}
+
+// ActorFromContext returns the user set by WithActor.
+func ActorFromContext(ctx context.Context) (string, bool) {
+	userID, ok := ctx.Value(actorKey{}).(string)
+	return userID, ok
+}
+
+type tableColumnsKey struct {
+	pool  PgxPoolInterface
+	table string
+}
+
+// Audit columns of each table by pool, tables without any are cached too
+var tableActorColumns sync.Map
+
+// actorStamp returns the stamp of writes to tableName, disabled without actor or audit columns.
+func (s *BasePostgreSqlService) actorStamp(ctx context.Context, tableName string) (sql_query.ActorStamp, error) {
+	actor, ok := ActorFromContext(ctx)
+	if !ok {
+		return sql_query.ActorStamp{}, nil
+	}
+
+	columns := DefaultActorColumns()
+	if columns.CreatedBy == "" && columns.UpdatedBy == "" {
+		return sql_query.ActorStamp{}, nil
+	}
+
+	existing, err := s.auditColumns(ctx, tableName, columns)
+	if err != nil {
+		return sql_query.ActorStamp{}, err
+	}
+
+	stamp := sql_query.ActorStamp{Actor: actor}
+	for _, column := range existing {
+		switch column {
+		case columns.CreatedBy:
+			stamp.CreatedBy = column
+		case columns.UpdatedBy:
+			stamp.UpdatedBy = column
+		}
+	}
+
+	return stamp, nil
+}
+
+// auditColumns returns which of columns exist in tableName. The lookup goes through the pool,
+// a failing query inside the caller's transaction would abort it.
+func (s *BasePostgreSqlService) auditColumns(ctx context.Context, tableName string, columns ActorColumns) ([]string, error) {
+	// Table names may carry an alias, e.g. "wallets w"
+	table := strings.Fields(tableName)[0]
+	key := tableColumnsKey{pool: s.Pool, table: table}
+	if cached, ok := tableActorColumns.Load(key); ok {
+		return cached.([]string), nil
+	}
+
+	rows, err := s.Pool.Query(ctx, `
+SELECT column_name::text
+FROM information_schema.columns
+WHERE table_schema = current_schema() AND table_name = $1 AND column_name = ANY($2)`,
+		table, []string{columns.CreatedBy, columns.UpdatedBy},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, err
+	}
+
+	tableActorColumns.Store(key, existing)
+	return existing, nil
+}
@@ -0,0 +1,24 @@
+package service
+
+import "context"
+
+// Dry runs execute the whole validation and builder pipeline of a write inside a transaction
+// that is rolled back (TxOptions.DryRun), so clients can preview the effects of an operation.
+// The context carries the flag to code that reaches outside of the transaction.
+
+type dryRunKey struct{}
+
+// WithDryRun marks ctx as belonging to a dry run.
+func WithDryRun(ctx context.Context, dryRun bool) context.Context {
+	if !dryRun {
+		return ctx
+	}
+
+	return context.WithValue(ctx, dryRunKey{}, true)
+}
+
+// IsDryRun reports whether ctx was marked by WithDryRun.
+func IsDryRun(ctx context.Context) bool {
+	dryRun, _ := ctx.Value(dryRunKey{}).(bool)
+	return dryRun
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Buildable is any chain builder of sql_query, e.g. sql_query.SQLSelectChainBuilder.
+type Buildable interface {
+	Build() (string, []interface{}, error)
+}
+
+// ExplainPlan is the output of EXPLAIN (FORMAT JSON), the times are only set by EXPLAIN ANALYZE, in milliseconds.
+type ExplainPlan struct {
+	Plan          PlanNode `json:"Plan"`
+	PlanningTime  float64  `json:"Planning Time"`
+	ExecutionTime float64  `json:"Execution Time"`
+}
+
+// PlanNode is one node of an ExplainPlan, the Actual fields are only set by EXPLAIN ANALYZE.
+type PlanNode struct {
+	NodeType        string     `json:"Node Type"`
+	RelationName    string     `json:"Relation Name"`
+	Alias           string     `json:"Alias"`
+	IndexName       string     `json:"Index Name"`
+	IndexCond       string     `json:"Index Cond"`
+	Filter          string     `json:"Filter"`
+	StartupCost     float64    `json:"Startup Cost"`
+	TotalCost       float64    `json:"Total Cost"`
+	PlanRows        float64    `json:"Plan Rows"`
+	PlanWidth       int        `json:"Plan Width"`
+	ActualTotalTime float64    `json:"Actual Total Time"`
+	ActualRows      float64    `json:"Actual Rows"`
+	ActualLoops     float64    `json:"Actual Loops"`
+	Plans           []PlanNode `json:"Plans"`
+}
+
+// Nodes returns every node of the plan, depth first.
+func (p ExplainPlan) Nodes() []PlanNode {
+	var nodes []PlanNode
+	var walk func(node PlanNode)
+	walk = func(node PlanNode) {
+		nodes = append(nodes, node)
+		for _, child := range node.Plans {
+			walk(child)
+		}
+	}
+	walk(p.Plan)
+
+	return nodes
+}
+
+// UsesIndex reports whether a node of the plan scans index.
+func (p ExplainPlan) UsesIndex(index string) bool {
+	for _, node := range p.Nodes() {
+		if node.IndexName == index {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SeqScans returns the tables read by a sequential scan.
+func (p ExplainPlan) SeqScans() []string {
+	var tables []string
+	for _, node := range p.Nodes() {
+		if node.NodeType == "Seq Scan" {
+			tables = append(tables, node.RelationName)
+		}
+	}
+
+	return tables
+}
+
+func (s *BasePostgreSqlService) Explain(ctx context.Context, builder Buildable, analyze bool) (ExplainPlan, error) {
+	query, args, err := builder.Build()
+	if err != nil {
+		return ExplainPlan{}, builderError(err)
+	}
+
+	options := "FORMAT JSON"
+	if analyze {
+		options += ", ANALYZE"
+	}
+	query = fmt.Sprintf("EXPLAIN (%s) %s", options, query)
+	shouldShowQuery(s.debugLevel, query, args...)
+
+	var output []byte
+	if !analyze {
+		if s.Transaction != nil {
+			err = s.Transaction.QueryRow(ctx, query, args...).Scan(&output)
+		} else {
+			err = s.Pool.QueryRow(ctx, query, args...).Scan(&output)
+		}
+	} else {
+		var tx pgx.Tx
+		if s.Transaction != nil {
+			tx, err = s.Transaction.Begin(ctx)
+		} else {
+			tx, err = s.Pool.Begin(ctx)
+		}
+		if err != nil {
+			return ExplainPlan{}, err
+		}
+		defer tx.Rollback(ctx)
+
+		err = tx.QueryRow(ctx, query, args...).Scan(&output)
+	}
+	if err != nil {
+		return ExplainPlan{}, err
+	}
+
+	var plans []ExplainPlan
+	if err := json.Unmarshal(output, &plans); err != nil {
+		return ExplainPlan{}, err
+	}
+	if len(plans) == 0 {
+		return ExplainPlan{}, errors.New("EXPLAIN returned no plan")
+	}
+
+	return plans[0], nil
+}
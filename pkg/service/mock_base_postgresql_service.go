@@ -85,6 +85,42 @@ func (m *MockBasePostgreSqlService) SelectMany(
 	return arg.Error(0)
 }
 
+func (m *MockBasePostgreSqlService) SelectManyCursor(
+	v any,
+	ctx context.Context,
+	cursor sql_query.Cursor,
+	queryString string,
+	args ...any,
+) (string, error) {
+	arg := m.Called(v, ctx, cursor, queryString, args)
+	return arg.String(0), arg.Error(1)
+}
+
+func (m *MockBasePostgreSqlService) SelectManyByIDs(
+	v any,
+	ctx context.Context,
+	tableName string,
+	ids any,
+	columns ...string,
+) error {
+	arg := m.Called(v, ctx, tableName, ids, columns)
+	return arg.Error(0)
+}
+
+func (m *MockBasePostgreSqlService) ExecPrepared(
+	v any,
+	ctx context.Context,
+	statement sql_query.PreparedStatement,
+) (int64, error) {
+	arg := m.Called(v, ctx, statement)
+	return arg.Get(0).(int64), arg.Error(1)
+}
+
+func (m *MockBasePostgreSqlService) Explain(ctx context.Context, builder Buildable, analyze bool) (ExplainPlan, error) {
+	arg := m.Called(ctx, builder, analyze)
+	return arg.Get(0).(ExplainPlan), arg.Error(1)
+}
+
 func (m *MockBasePostgreSqlService) InsertOne(
 	ctx context.Context,
 	queryString string,
@@ -136,6 +172,17 @@ func (m *MockBasePostgreSqlService) InsertManyWithData(
 	return arg.Get(0), arg.Error(1)
 }
 
+func (m *MockBasePostgreSqlService) UpsertOneWithData(
+	ctx context.Context,
+	tableName string,
+	conflictColumns []string,
+	updateColumns []string,
+	body interface{},
+) (UpsertResult, error) {
+	arg := m.Called(ctx, tableName, conflictColumns, updateColumns, body)
+	return arg.Get(0).(UpsertResult), arg.Error(1)
+}
+
 func (m *MockBasePostgreSqlService) UpdateOne(
 	ctx context.Context,
 	queryString string,
@@ -306,6 +353,12 @@ func (m *MockPgxPool) Begin(ctx context.Context) (pgx.Tx, error) {
 	return called.Get(0).(pgx.Tx), called.Error(1)
 }
 
+// Mock BeginTx(ctx, txOptions)
+func (m *MockPgxPool) BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error) {
+	called := m.Called(ctx, txOptions)
+	return called.Get(0).(pgx.Tx), called.Error(1)
+}
+
 // Mock Close()
 func (m *MockPgxPool) Close() {
 	m.Called()
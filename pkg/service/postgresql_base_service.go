@@ -10,6 +10,7 @@ import (
 
 	"github.com/mystaline/clefinport-be/pkg/db"
 	"github.com/mystaline/clefinport-be/pkg/dto"
+	"github.com/mystaline/clefinport-be/pkg/entity"
 	"github.com/mystaline/clefinport-be/pkg/sql_query"
 	"github.com/mystaline/clefinport-be/pkg/sql_query/common_builders"
 
@@ -22,6 +23,12 @@ type ReturningConfig struct {
 	Destination any
 }
 
+// UpsertResult is the row written by UpsertOneWithData, Inserted is false when the conflicting row was updated.
+type UpsertResult struct {
+	ID       int64 `json:"id"`
+	Inserted bool  `json:"inserted"`
+}
+
 // Base Service PostgreSQL
 type PostgreSqlService interface {
 	// Debug sets the debug level for printing executed SQL queries.
@@ -65,6 +72,33 @@ type PostgreSqlService interface {
 	// and scans the results into the provided slice pointer v
 	// (e.g., *[]dto.GetCustomFieldsResponse).
 	SelectMany(v any, ctx context.Context, queryString string, args ...any) error
+	// SelectManyCursor executes a query built with PaginateCursor,
+	// scans at most cursor.Limit rows into the provided slice pointer v
+	// and returns the cursor of the next page (empty on the last page).
+	SelectManyCursor(v any, ctx context.Context, cursor sql_query.Cursor, queryString string, args ...any) (string, error)
+	// SelectManyByIDs scans the rows of tableName whose id is in ids (a slice) into the provided slice pointer v,
+	// in the order of ids, e.g. to hydrate a cached list of ids. Missing ids are skipped.
+	// Columns default to the json tags of v's element, ids are queried by chunks of 1000 with = ANY.
+	//
+	// Example:
+	//
+	//	var wallets []dto.GetWalletInfoData
+	//	err := svc.SelectManyByIDs(&wallets, ctx, db.WalletTableName, []string{"42", "7"})
+	SelectManyByIDs(v any, ctx context.Context, tableName string, ids any, columns ...string) error
+	// ExecPrepared runs statement (see the builders' Prepare) as a named prepared statement,
+	// prepared once per connection so hot queries are not parsed and planned on every call.
+	// Rows are scanned into v like SelectMany for a slice pointer, like SelectOne otherwise, v may be nil for writes.
+	// Returns the number of rows affected or returned.
+	ExecPrepared(v any, ctx context.Context, statement sql_query.PreparedStatement) (int64, error)
+	// Explain returns the plan of the query of builder, e.g. to assert index usage. With analyze the query
+	// is executed inside a transaction (a savepoint of the service's one) that is always rolled back,
+	// so explaining a write changes nothing.
+	//
+	// Example:
+	//
+	//	plan, err := svc.Explain(ctx, builder, true)
+	//	if !plan.UsesIndex("transactions_wallet_id_date_idx") { ... }
+	Explain(ctx context.Context, builder Buildable, analyze bool) (ExplainPlan, error)
 
 	// InsertOne executes an INSERT ... RETURNING id query
 	// and returns the inserted row ID.
@@ -126,6 +160,19 @@ type PostgreSqlService interface {
 		body interface{},
 		returnOption ...ReturningConfig,
 	) (interface{}, error)
+	// UpsertOneWithData inserts body (a struct), or updates updateColumns of the row conflicting on conflictColumns.
+	// With no updateColumns, every inserted column is updated but id, created_at and the conflict columns.
+	//
+	// Returns:
+	//   - UpsertResult → The row's id, and whether it was inserted or the existing one was updated.
+	//   - error        → Any error encountered during query building, execution, or scanning.
+	UpsertOneWithData(
+		ctx context.Context,
+		tableName string,
+		conflictColumns []string,
+		updateColumns []string,
+		body interface{},
+	) (UpsertResult, error)
 
 	// UpdateOne executes an UPDATE ... RETURNING id query
 	// and returns the updated row ID.
@@ -148,6 +195,7 @@ type PostgreSqlService interface {
 	//   - If returnOption is provided but Destination is nil → still executes UPDATE and returns the ID.
 	//   - If returnOption is provided with Destination → scans the updated row into Destination
 	//     and returns nil as the first return value (caller uses Destination for data).
+	//   - updated_at is set to NOW() unless ctx was marked by WithoutTouch.
 	//
 	// Returns:
 	//   - interface{}: The updated row's ID (string) if no Destination is provided.
@@ -246,6 +294,8 @@ type PostgreSqlService interface {
 type PgxPoolInterface interface {
 	// Begin starts a new database transaction and returns a pgx.Tx.
 	Begin(ctx context.Context) (pgx.Tx, error)
+	// BeginTx starts a new database transaction with the given isolation and access mode.
+	BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error)
 
 	// Query executes a SQL query and returns pgx.Rows for multiple row results.
 	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
@@ -370,7 +420,10 @@ func (s *BasePostgreSqlService) CountWithFilter(
 	tableName string,
 	filter map[string]sql_query.SQLCondition,
 ) (int, error) {
-	queryString, args := common_builders.CountBuilder(tableName, filter)
+	queryString, args, err := common_builders.CountBuilder(tableName, filter)
+	if err != nil {
+		return 0, builderError(err)
+	}
 
 	return s.Count(ctx, queryString, args...)
 }
@@ -442,6 +495,43 @@ func (s *BasePostgreSqlService) SelectMany(
 	return nil
 }
 
+func (s *BasePostgreSqlService) SelectManyCursor(
+	v any,
+	ctx context.Context,
+	cursor sql_query.Cursor,
+	queryString string,
+	args ...any,
+) (string, error) {
+	shouldShowQuery(s.debugLevel, queryString, args...)
+
+	var rows pgx.Rows
+	var err error
+
+	if s.Transaction != nil {
+		rows, err = s.Transaction.Query(ctx, queryString, args...)
+	} else {
+		rows, err = s.Pool.Query(ctx, queryString, args...)
+	}
+
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	limit := cursor.Limit
+	if limit <= 0 {
+		limit = sql_query.DefaultCursorLimit
+	}
+
+	next, err := sql_query.ScanRowsCursor(v, rows, limit)
+	if err != nil {
+		log.Println(err)
+		return "", err
+	}
+
+	return next, nil
+}
+
 func (s *BasePostgreSqlService) InsertOne(
 	ctx context.Context,
 	queryString string,
@@ -476,7 +566,14 @@ func (s *BasePostgreSqlService) InsertOneWithData(
 	if len(returnOption) > 0 {
 		returnColumn = append(returnColumn, returnOption[0].Column...)
 	}
-	queryString, args := common_builders.InsertBuilder(tableName, body, returnColumn...)
+	stamp, err := s.actorStamp(ctx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	queryString, args, err := common_builders.InsertBuilder(tableName, stamp, body, returnColumn...)
+	if err != nil {
+		return nil, builderError(err)
+	}
 
 	if len(returnOption) > 0 && returnOption[0].Destination != nil {
 		return nil, s.SelectOne(returnOption[0].Destination, ctx, queryString, args...)
@@ -518,7 +615,14 @@ func (s *BasePostgreSqlService) InsertManyWithData(
 	if len(returnOption) > 0 {
 		returnColumn = append(returnColumn, returnOption[0].Column...)
 	}
-	queryString, args := common_builders.InsertBuilder(tableName, body, returnColumn...)
+	stamp, err := s.actorStamp(ctx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	queryString, args, err := common_builders.InsertBuilder(tableName, stamp, body, returnColumn...)
+	if err != nil {
+		return nil, builderError(err)
+	}
 
 	if len(returnOption) > 0 && returnOption[0].Destination != nil {
 		err := s.SelectMany(returnOption[0].Destination, ctx, queryString, args...)
@@ -529,6 +633,27 @@ func (s *BasePostgreSqlService) InsertManyWithData(
 	return s.InsertMany(ctx, queryString, args...)
 }
 
+func (s *BasePostgreSqlService) UpsertOneWithData(
+	ctx context.Context,
+	tableName string,
+	conflictColumns []string,
+	updateColumns []string,
+	body interface{},
+) (UpsertResult, error) {
+	stamp, err := s.actorStamp(ctx, tableName)
+	if err != nil {
+		return UpsertResult{}, err
+	}
+	queryString, args, err := common_builders.UpsertBuilder(tableName, stamp, conflictColumns, updateColumns, body)
+	if err != nil {
+		return UpsertResult{}, builderError(err)
+	}
+
+	var result UpsertResult
+	err = s.SelectOne(&result, ctx, queryString, args...)
+	return result, err
+}
+
 // Still in experimental stage, recommended to use InsertManyWithData until this function stable
 func (s *BasePostgreSqlService) InsertBatch(
 	ctx context.Context,
@@ -623,16 +748,34 @@ func (s *BasePostgreSqlService) UpdateOneWithData(
 	if len(returnOption) > 0 {
 		returnColumn = append(returnColumn, returnOption[0].Column...)
 	}
-	queryString, args := common_builders.UpdateBuilder(tableName,
+	stamp, err := s.actorStamp(ctx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	queryString, args, err := common_builders.UpdateBuilder(tableName,
+		stamp,
+		touches(ctx),
 		query,
 		body,
 		returnColumn...,
 	)
+	if err != nil {
+		return nil, builderError(err)
+	}
 
+	var result interface{}
 	if len(returnOption) > 0 && returnOption[0].Destination != nil {
-		return nil, s.SelectOne(returnOption[0].Destination, ctx, queryString, args...)
+		err = s.SelectOne(returnOption[0].Destination, ctx, queryString, args...)
+	} else {
+		result, err = s.UpdateOne(ctx, queryString, args...)
 	}
-	return s.UpdateOne(ctx, queryString, args...)
+
+	// A versioned body matching no row was read before a concurrent update (optimistic locking)
+	if column, version, ok := sql_query.VersionOf(body); ok && errors.Is(err, pgx.ErrNoRows) {
+		return nil, &sql_query.StaleVersionError{Table: tableName, Column: column, Version: version}
+	}
+
+	return result, err
 }
 
 func (s *BasePostgreSqlService) UpdateMany(
@@ -670,11 +813,20 @@ func (s *BasePostgreSqlService) UpdateManyWithData(
 	if len(returnOption) > 0 {
 		returnColumn = append(returnColumn, returnOption[0].Column...)
 	}
-	queryString, args := common_builders.UpdateBuilder(tableName,
+	stamp, err := s.actorStamp(ctx, tableName)
+	if err != nil {
+		return 0, err
+	}
+	queryString, args, err := common_builders.UpdateBuilder(tableName,
+		stamp,
+		touches(ctx),
 		query,
 		body,
 		returnColumn...,
 	)
+	if err != nil {
+		return 0, builderError(err)
+	}
 
 	if len(returnOption) > 0 && returnOption[0].Destination != nil {
 		err := s.SelectMany(returnOption[0].Destination, ctx, queryString, args...)
@@ -692,11 +844,20 @@ func (s *BasePostgreSqlService) UpdateEachWithData(
 	query map[string]sql_query.SQLCondition,
 	body interface{},
 ) (int64, error) {
-	queryString, args := common_builders.UpdateEachBuilder(tableName,
+	stamp, err := s.actorStamp(ctx, tableName)
+	if err != nil {
+		return 0, err
+	}
+	queryString, args, err := common_builders.UpdateEachBuilder(tableName,
+		stamp,
+		touches(ctx),
 		rowIdentifier,
 		query,
 		body,
 	)
+	if err != nil {
+		return 0, builderError(err)
+	}
 
 	return s.UpdateMany(ctx, queryString, args...)
 }
@@ -712,10 +873,17 @@ func (s *BasePostgreSqlService) SoftDeleteOne(
 	if len(returnOption) > 0 {
 		returnColumn = append(returnColumn, returnOption[0].Column...)
 	}
-	queryString, args := common_builders.UpdateBuilder(tableName, filter, dto.SetSoftDelete{
+	stamp, err := s.actorStamp(ctx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	queryString, args, err := common_builders.UpdateBuilder(tableName, stamp, touches(ctx), filter, dto.SetSoftDelete{
 		IsDeleted: true,
 		DeletedAt: "NOW()",
 	}, returnColumn...)
+	if err != nil {
+		return nil, builderError(err)
+	}
 	shouldShowQuery(s.debugLevel, queryString, args...)
 
 	if len(returnOption) > 0 && returnOption[0].Destination != nil {
@@ -735,7 +903,13 @@ func (s *BasePostgreSqlService) SoftDeleteMany(
 	if len(returnOption) > 0 {
 		returnColumn = append(returnColumn, returnOption[0].Column...)
 	}
-	queryString, args := common_builders.UpdateBuilder(tableName,
+	stamp, err := s.actorStamp(ctx, tableName)
+	if err != nil {
+		return 0, err
+	}
+	queryString, args, err := common_builders.UpdateBuilder(tableName,
+		stamp,
+		touches(ctx),
 		filter,
 		dto.SetSoftDelete{
 			IsDeleted: true,
@@ -743,6 +917,9 @@ func (s *BasePostgreSqlService) SoftDeleteMany(
 		},
 		returnColumn...,
 	)
+	if err != nil {
+		return 0, builderError(err)
+	}
 	shouldShowQuery(s.debugLevel, queryString, args...)
 
 	if len(returnOption) > 0 && returnOption[0].Destination != nil {
@@ -782,7 +959,10 @@ func (s *BasePostgreSqlService) DeleteOneWithFilter(
 	tableName string,
 	filter map[string]sql_query.SQLCondition,
 ) (interface{}, error) {
-	queryString, args := common_builders.DeleteBuilder(tableName, filter)
+	queryString, args, err := common_builders.DeleteBuilder(tableName, filter)
+	if err != nil {
+		return nil, builderError(err)
+	}
 
 	return s.DeleteOne(ctx, queryString, args...)
 }
@@ -815,11 +995,28 @@ func (s *BasePostgreSqlService) DeleteManyWithFilter(
 	tableName string,
 	filter map[string]sql_query.SQLCondition,
 ) (int64, error) {
-	queryString, args := common_builders.DeleteBuilder(tableName, filter)
+	queryString, args, err := common_builders.DeleteManyBuilder(tableName, filter)
+	if err != nil {
+		return 0, builderError(err)
+	}
 
 	return s.DeleteMany(ctx, queryString, args...)
 }
 
+// TransactionService returns a service running its queries in tx on the pool of svc.
+// Use it instead of svc.SetTransaction when svc is shared, e.g. by a middleware, concurrent requests
+// would otherwise run their queries in tx too.
+//
+// Example:
+//
+//	service.UseTransactions(ctx, svc.GetPool(), func(tx pgx.Tx) (T, error) {
+//	    txService := service.TransactionService(svc, tx)
+//	    ...
+//	})
+func TransactionService(svc PostgreSqlService, tx pgx.Tx) PostgreSqlService {
+	return &BasePostgreSqlService{Pool: svc.GetPool(), Transaction: tx}
+}
+
 // UseTransactions executes fn within a transaction.
 // If fn returns an error, the transaction is rolled back.
 // If fn succeeds, the transaction is committed.
@@ -835,21 +1032,69 @@ func (s *BasePostgreSqlService) DeleteManyWithFilter(
 //
 // Although pgx will automatically rollback when the connection is returned
 // to the pool, you must NOT rely on this. Always explicitly close the tx.
+//
+// StartTxWatchdog reports held transactions that stay open for too long.
+// As a last resort the connection's idle_in_transaction_session_timeout (see db.SessionTimeouts)
+// makes Postgres terminate a session left idle inside a transaction.
 func UseTransactions[T any](
 	ctx context.Context,
 	pool PgxPoolInterface,
 	fn func(tx pgx.Tx) (T, error),
 	holdCommit ...bool,
 ) (result T, err error) {
-	tx, err := pool.Begin(ctx)
+	return UseTransactionsWithOptions(ctx, pool, TxOptions{}, fn, holdCommit...)
+}
+
+// TxOptions configures a transaction of UseTransactionsWithOptions.
+type TxOptions struct {
+	pgx.TxOptions
+	// Timeouts override the connection's session timeouts for this transaction only (SET LOCAL),
+	// zero fields keep the connection's value.
+	Timeouts db.SessionTimeouts
+	// DryRun rolls back once fn succeeded and returns its result as if committed, holdCommit is ignored.
+	// Side effects outside of the transaction (gRPC calls, notifications) must check IsDryRun themselves.
+	DryRun bool
+}
+
+// UseTransactionsWithOptions is UseTransactions with an isolation level, access mode and per transaction timeouts.
+//
+// Example:
+//
+//	service.UseTransactionsWithOptions(ctx, pool, service.TxOptions{
+//	    TxOptions: pgx.TxOptions{IsoLevel: pgx.Serializable},
+//	    Timeouts:  db.SessionTimeouts{Lock: 2 * time.Second},
+//	}, func(tx pgx.Tx) (T, error) { ... })
+func UseTransactionsWithOptions[T any](
+	ctx context.Context,
+	pool PgxPoolInterface,
+	options TxOptions,
+	fn func(tx pgx.Tx) (T, error),
+	holdCommit ...bool,
+) (result T, err error) {
+	var tx pgx.Tx
+	if options.TxOptions == (pgx.TxOptions{}) {
+		tx, err = pool.Begin(ctx)
+	} else {
+		tx, err = pool.BeginTx(ctx, options.TxOptions)
+	}
 	if err != nil {
 		log.Printf("can't start transactions: %v", err)
 		err = errors.New("something went wrong")
 		return
 	}
 
+	if timeoutErr := options.Timeouts.Apply(ctx, tx, true); timeoutErr != nil {
+		log.Printf("can't set transaction timeouts: %v", timeoutErr)
+		_ = tx.Rollback(ctx)
+		err = errors.New("something went wrong")
+		return
+	}
+
 	// When holdCommit is true, caller is responsible for calling Rollback or Commit. Transaction is returned unclosed.
-	if len(holdCommit) > 0 && holdCommit[0] {
+	if len(holdCommit) > 0 && holdCommit[0] && !options.DryRun {
+		// Tracked until Commit/Rollback, see StartTxWatchdog
+		tx = trackHeldTx(tx)
+
 		defer func() {
 			if r := recover(); r != nil {
 				log.Printf("transaction panicked: %v\n%s", r, debug.Stack())
@@ -882,6 +1127,11 @@ func UseTransactions[T any](
 		return
 	}
 
+	// Deferred rollback discards everything fn wrote
+	if options.DryRun {
+		return result, nil
+	}
+
 	if commitErr := tx.Commit(ctx); commitErr != nil {
 		log.Printf("failed to commit: %v", commitErr)
 		err = errors.New("something went wrong")
@@ -891,11 +1141,28 @@ func UseTransactions[T any](
 	return result, nil
 }
 
+// builderError reports a query the builder refused to build, nothing is sent to Postgres.
+// Rejected values come from the caller's body or filter (400), anything else is a bug in the query (500).
+func builderError(err error) error {
+	if errors.Is(err, sql_query.ErrInvalidValues) {
+		return entity.BadRequest(err.Error())
+	}
+
+	return entity.InternalServerError(err.Error())
+}
+
 func shouldShowQuery(level int, query string, args ...any) {
 	switch level {
 	case 1:
 		log.Println("query", query)
 	case 2:
 		log.Println("query and args", query, args)
+	case 3:
+		interpolated, err := sql_query.InterpolateArgs(query, args)
+		if err != nil {
+			log.Println("query and args", query, args)
+			return
+		}
+		log.Println("query", interpolated)
 	}
 }
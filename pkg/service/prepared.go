@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/mystaline/clefinport-be/pkg/sql_query"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// connAcquirer is implemented by *pgxpool.Pool, statements are prepared on the acquired connection.
+type connAcquirer interface {
+	Acquire(ctx context.Context) (*pgxpool.Conn, error)
+}
+
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+func (s *BasePostgreSqlService) ExecPrepared(
+	v any,
+	ctx context.Context,
+	statement sql_query.PreparedStatement,
+) (int64, error) {
+	shouldShowQuery(s.debugLevel, statement.SQL, statement.Args...)
+
+	// Preparing an already prepared name and SQL is a no-op, each connection prepares it once
+	if s.Transaction != nil {
+		if _, err := s.Transaction.Prepare(ctx, statement.Name, statement.SQL); err != nil {
+			return 0, err
+		}
+		return queryPrepared(ctx, s.Transaction, v, statement.Name, statement.Args)
+	}
+
+	acquirer, ok := s.Pool.(connAcquirer)
+	if !ok {
+		return queryPrepared(ctx, s.Pool, v, statement.SQL, statement.Args)
+	}
+
+	conn, err := acquirer.Acquire(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Release()
+
+	if _, err := conn.Conn().Prepare(ctx, statement.Name, statement.SQL); err != nil {
+		return 0, err
+	}
+	return queryPrepared(ctx, conn, v, statement.Name, statement.Args)
+}
+
+// queryPrepared runs sql, a prepared statement name or a query, and scans its rows into v when not nil.
+func queryPrepared(ctx context.Context, q querier, v any, sql string, args []any) (int64, error) {
+	rows, err := q.Query(ctx, sql, args...)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	if v != nil {
+		if reflect.TypeOf(v).Elem().Kind() == reflect.Slice {
+			err = sql_query.ScanRowsArray(v, rows)
+		} else {
+			err = sql_query.ScanRowObject(v, rows)
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	return rows.CommandTag().RowsAffected(), nil
+}
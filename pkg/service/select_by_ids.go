@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/mystaline/clefinport-be/pkg/sql_query"
+)
+
+// IDs bound per query by SelectManyByIDs, bounding the size of one result
+const selectByIDsChunkSize = 1000
+
+func (s *BasePostgreSqlService) SelectManyByIDs(
+	v any,
+	ctx context.Context,
+	tableName string,
+	ids any,
+	columns ...string,
+) error {
+	dest := reflect.ValueOf(v)
+	if dest.Kind() != reflect.Ptr || dest.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("SelectManyByIDs: v must be a pointer to a slice, got %T", v)
+	}
+	idList := reflect.ValueOf(ids)
+	if idList.Kind() != reflect.Slice {
+		return fmt.Errorf("SelectManyByIDs: ids must be a slice, got %T", ids)
+	}
+
+	if len(columns) == 0 {
+		columns = sql_query.ExtractJSONTags[any](dest.Elem().Type().Elem())
+	}
+
+	rows := reflect.MakeSlice(dest.Elem().Type(), 0, idList.Len())
+	for start := 0; start < idList.Len(); start += selectByIDsChunkSize {
+		chunk := idList.Slice(start, min(start+selectByIDsChunkSize, idList.Len())).Interface()
+
+		// Rows come back in the order of the ids, the array is bound once for both
+		query, args, err := sql_query.NewSQLSelectBuilder[any](tableName).
+			Select(columns...).
+			Where(map[string]sql_query.SQLCondition{
+				"id": {Operator: sql_query.SQLOperatorAny, Value: chunk},
+			}).
+			OrderByAdvanced([]sql_query.OrderRule{{Column: `array_position($1, "id")`}}).
+			Build()
+		if err != nil {
+			return builderError(err)
+		}
+
+		chunkRows := reflect.New(dest.Elem().Type())
+		if err := s.SelectMany(chunkRows.Interface(), ctx, query, args...); err != nil {
+			return err
+		}
+		rows = reflect.AppendSlice(rows, chunkRows.Elem())
+	}
+
+	dest.Elem().Set(rows)
+	return nil
+}
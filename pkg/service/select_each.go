@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/mystaline/clefinport-be/pkg/sql_query"
+)
+
+// SelectEach runs a SELECT query and calls fn with every row scanned into a T as soon as it is read,
+// so large results (exports, streamed lists) never sit in memory as a whole like with SelectMany.
+// Rows are read from the service's transaction when one is set. An error from fn stops reading and is returned.
+//
+// Example:
+//
+//	err := service.SelectEach(ctx, svc, query, args, func(record audit.StoredRecord) error {
+//	    return write(record)
+//	})
+func SelectEach[T any](
+	ctx context.Context,
+	svc PostgreSqlService,
+	queryString string,
+	args []any,
+	fn func(row T) error,
+) error {
+	var rows pgx.Rows
+	var err error
+
+	if tx := svc.GetTransaction(); tx != nil {
+		rows, err = tx.Query(ctx, queryString, args...)
+	} else {
+		rows, err = svc.GetPool().Query(ctx, queryString, args...)
+	}
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row T
+		if err := sql_query.ScanCurrentRow(&row, rows); err != nil {
+			return err
+		}
+
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
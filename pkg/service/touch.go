@@ -0,0 +1,20 @@
+package service
+
+import "context"
+
+// Writes made without touching updated_at, e.g. maintenance migrations and replicated writes of the change-feed
+// sync, which would otherwise be seen as fresh changes and synced back endlessly.
+// The *WithData update methods read the flag from the context.
+
+type withoutTouchKey struct{}
+
+// WithoutTouch marks ctx so updates made with it leave updated_at as is.
+func WithoutTouch(ctx context.Context) context.Context {
+	return context.WithValue(ctx, withoutTouchKey{}, true)
+}
+
+// touches reports whether updates made with ctx set updated_at = NOW().
+func touches(ctx context.Context) bool {
+	withoutTouch, _ := ctx.Value(withoutTouchKey{}).(bool)
+	return !withoutTouch
+}
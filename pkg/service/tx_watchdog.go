@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"log"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Transactions handed out by UseTransactions with holdCommit are tracked until their Commit or Rollback,
+// so a forgotten one shows up in the logs instead of silently exhausting the pool.
+
+// HeldTx describes a holdCommit transaction that is still open.
+type HeldTx struct {
+	ID        uint64
+	Caller    string
+	StartedAt time.Time
+	Age       time.Duration
+}
+
+type heldTxEntry struct {
+	tx        pgx.Tx
+	caller    string
+	startedAt time.Time
+	reported  bool
+}
+
+var (
+	heldTxs   = make(map[uint64]*heldTxEntry)
+	heldTxsMu sync.Mutex
+	heldTxSeq atomic.Uint64
+)
+
+// trackedTx unregisters itself once the caller commits or rolls back.
+type trackedTx struct {
+	pgx.Tx
+	id uint64
+}
+
+func (t *trackedTx) Commit(ctx context.Context) error {
+	releaseHeldTx(t.id)
+	return t.Tx.Commit(ctx)
+}
+
+func (t *trackedTx) Rollback(ctx context.Context) error {
+	releaseHeldTx(t.id)
+	return t.Tx.Rollback(ctx)
+}
+
+func trackHeldTx(tx pgx.Tx) pgx.Tx {
+	id := heldTxSeq.Add(1)
+
+	heldTxsMu.Lock()
+	heldTxs[id] = &heldTxEntry{tx: tx, caller: externalCaller(), startedAt: time.Now()}
+	heldTxsMu.Unlock()
+
+	return &trackedTx{Tx: tx, id: id}
+}
+
+func releaseHeldTx(id uint64) {
+	heldTxsMu.Lock()
+	delete(heldTxs, id)
+	heldTxsMu.Unlock()
+}
+
+// externalCaller returns file:line of the first frame outside this package.
+func externalCaller() string {
+	pcs := make([]uintptr, 16)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	for {
+		frame, more := frames.Next()
+		if !strings.Contains(frame.Function, "clefinport-be/pkg/service.") {
+			return frame.File + ":" + strconv.Itoa(frame.Line)
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
+
+// HeldTransactions lists the open holdCommit transactions, oldest first.
+func HeldTransactions() []HeldTx {
+	now := time.Now()
+
+	heldTxsMu.Lock()
+	result := make([]HeldTx, 0, len(heldTxs))
+	for id, entry := range heldTxs {
+		result = append(result, HeldTx{ID: id, Caller: entry.caller, StartedAt: entry.startedAt, Age: now.Sub(entry.startedAt)})
+	}
+	heldTxsMu.Unlock()
+
+	sort.Slice(result, func(i, j int) bool { return result[i].StartedAt.Before(result[j].StartedAt) })
+
+	return result
+}
+
+type TxWatchdogConfig struct {
+	// Transactions open for longer than Threshold are reported, 30s by default.
+	Threshold time.Duration
+	// Interval between checks, Threshold/3 by default.
+	Interval time.Duration
+	// ForceRollback rolls leaked transactions back to give their connection back to the pool.
+	// pgx connections aren't safe for concurrent use, only enable it when leaks are abandoned for sure.
+	ForceRollback bool
+	// OnLeak is called once per leaked transaction, e.g. to raise an alert. It's logged either way.
+	OnLeak func(leak HeldTx)
+}
+
+// StartTxWatchdog checks the open holdCommit transactions until ctx is cancelled.
+func StartTxWatchdog(ctx context.Context, config TxWatchdogConfig) {
+	if config.Threshold <= 0 {
+		config.Threshold = 30 * time.Second
+	}
+	if config.Interval <= 0 {
+		config.Interval = config.Threshold / 3
+	}
+
+	go func() {
+		ticker := time.NewTicker(config.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				checkHeldTransactions(config)
+			}
+		}
+	}()
+}
+
+func checkHeldTransactions(config TxWatchdogConfig) {
+	now := time.Now()
+
+	var leaks []HeldTx
+	var rollbacks []pgx.Tx
+
+	heldTxsMu.Lock()
+	for id, entry := range heldTxs {
+		age := now.Sub(entry.startedAt)
+		if age < config.Threshold {
+			continue
+		}
+
+		if !entry.reported {
+			entry.reported = true
+			leaks = append(leaks, HeldTx{ID: id, Caller: entry.caller, StartedAt: entry.startedAt, Age: age})
+		}
+		if config.ForceRollback {
+			rollbacks = append(rollbacks, entry.tx)
+			delete(heldTxs, id)
+		}
+	}
+	heldTxsMu.Unlock()
+
+	for _, leak := range leaks {
+		log.Printf("⚠️ holdCommit transaction %d from %s open for %s without Commit/Rollback", leak.ID, leak.Caller, leak.Age.Round(time.Second))
+		if config.OnLeak != nil {
+			config.OnLeak(leak)
+		}
+	}
+
+	for _, tx := range rollbacks {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := tx.Rollback(ctx); err != nil {
+			log.Printf("failed to roll back leaked transaction: %v", err)
+		}
+		cancel()
+	}
+}
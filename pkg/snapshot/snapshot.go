@@ -0,0 +1,174 @@
+package snapshot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Snapshot tests of response envelopes: a response is normalized (ids, timestamps, key order)
+// and compared with the one stored in testdata/snapshots, so an accidental change of a response shape
+// fails the test that produced it. Run the tests with UPDATE_SNAPSHOTS=true to accept the new shapes.
+//
+// Example, against the app built by the service's App.Setup so the whole middleware chain runs:
+//
+//	func TestGetWalletInfo(t *testing.T) {
+//	    server := app.MakeApp().Setup(serviceProvider)
+//	    snapshot.MatchResponse(t, server, httptest.NewRequest("GET", "/v1/wallet/info?walletId=1", nil), "get_wallet_info")
+//	}
+
+const (
+	// UpdateEnv rewrites every compared snapshot when set to true
+	UpdateEnv = "UPDATE_SNAPSHOTS"
+	// Dir holds the snapshots, relative to the package of the test
+	Dir = "testdata/snapshots"
+
+	idPlaceholder        = "<id>"
+	timestampPlaceholder = "<timestamp>"
+)
+
+var numericIDRegexp = regexp.MustCompile(`^\d{6,}$`)
+
+// Normalize rewrites body into indented JSON with sorted keys, id values (keys named id or ending with Id/ID
+// holding a number or numeric string) and RFC 3339 timestamps replaced by placeholders.
+func Normalize(body []byte) ([]byte, error) {
+	var value any
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("snapshot: response is not JSON: %w", err)
+	}
+
+	// Placeholders stay readable, the encoder would escape their brackets otherwise
+	var normalized bytes.Buffer
+	encoder := json.NewEncoder(&normalized)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(normalize("", value)); err != nil {
+		return nil, err
+	}
+
+	return normalized.Bytes(), nil
+}
+
+func normalize(key string, value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for childKey, child := range v {
+			v[childKey] = normalize(childKey, child)
+		}
+		return v
+	case []any:
+		for i, child := range v {
+			v[i] = normalize(key, child)
+		}
+		return v
+	case json.Number:
+		if isIDKey(key) {
+			return idPlaceholder
+		}
+		return v
+	case string:
+		if isIDKey(key) && numericIDRegexp.MatchString(v) {
+			return idPlaceholder
+		}
+		if _, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			return timestampPlaceholder
+		}
+		return v
+	}
+
+	return value
+}
+
+// isIDKey matches id, walletId, userIDs, but not e.g. "paid" or "valid".
+func isIDKey(key string) bool {
+	return key == "id" || strings.HasSuffix(key, "Id") || strings.HasSuffix(key, "ID") ||
+		strings.HasSuffix(key, "Ids") || strings.HasSuffix(key, "IDs")
+}
+
+// Match compares the normalized body with the snapshot name, writing it when missing or UpdateEnv is set.
+func Match(t testing.TB, name string, body []byte) {
+	t.Helper()
+
+	actual, err := Normalize(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(Dir, name+".json")
+	expected, err := os.ReadFile(path)
+	if os.IsNotExist(err) || os.Getenv(UpdateEnv) == "true" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, actual, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		t.Logf("snapshot: wrote %s", path)
+		return
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(expected, actual) {
+		t.Errorf("snapshot %s changed, rerun with %s=true if intended:\n%s", name, UpdateEnv, diff(string(expected), string(actual)))
+	}
+}
+
+// MatchResponse sends req through app and matches its status code and normalized body with the snapshot name.
+func MatchResponse(t testing.TB, app *fiber.App, req *http.Request, name string) {
+	t.Helper()
+
+	res, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		body = []byte("null")
+	}
+
+	// The status code is part of the shape, the envelope's status field may be missing on errors
+	envelope := fmt.Sprintf(`{"httpStatus":%d,"body":%s}`, res.StatusCode, body)
+	Match(t, name, []byte(envelope))
+}
+
+// diff lists the lines that differ, prefixed with - for expected and + for actual.
+func diff(expected, actual string) string {
+	expectedLines := strings.Split(expected, "\n")
+	actualLines := strings.Split(actual, "\n")
+
+	var sb strings.Builder
+	for i := 0; i < max(len(expectedLines), len(actualLines)); i++ {
+		var want, got string
+		if i < len(expectedLines) {
+			want = expectedLines[i]
+		}
+		if i < len(actualLines) {
+			got = actualLines[i]
+		}
+		if want != got {
+			fmt.Fprintf(&sb, "line %d:\n- %s\n+ %s\n", i+1, want, got)
+		}
+	}
+
+	return sb.String()
+}
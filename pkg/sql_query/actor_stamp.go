@@ -0,0 +1,110 @@
+package sql_query
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ActorStamp fills the audit columns of inserted and updated rows with the acting user.
+// Columns left empty are not stamped, so tables having only one of them can be stamped too,
+// and columns already set by the values (or the struct) are never overwritten.
+type ActorStamp struct {
+	// Actor is bound as a single argument shared by every row, nil disables the stamp
+	Actor any
+	// CreatedBy is set on insert
+	CreatedBy string
+	// UpdatedBy is set on insert and update
+	UpdatedBy string
+}
+
+func (a ActorStamp) enabled() bool {
+	return a.Actor != nil && (a.CreatedBy != "" || a.UpdatedBy != "")
+}
+
+func (s *InsertBuilder) StampActor(stamp ActorStamp) SQLInsertInitBuilder {
+	s.actorStamp = stamp
+	return s
+}
+
+func (s *UpdateBuilder) StampActor(stamp ActorStamp) SQLUpdateInitBuilder {
+	s.actorStamp = stamp
+	return s
+}
+
+// stampInsertColumns adds the audit columns missing from columns to every row of valuePlaceholders.
+// The columns may come from a cached template, they are copied before appending.
+func (s *SQLEloquentQuery) stampInsertColumns(columns, valuePlaceholders []string) ([]string, []string) {
+	if !s.actorStamp.enabled() {
+		return columns, valuePlaceholders
+	}
+
+	var missing []string
+	for _, column := range []string{s.actorStamp.CreatedBy, s.actorStamp.UpdatedBy} {
+		if column != "" && !slices.ContainsFunc(columns, func(existing string) bool {
+			return strings.Trim(existing, `"`) == column
+		}) {
+			missing = append(missing, column)
+		}
+	}
+	if len(missing) == 0 {
+		return columns, valuePlaceholders
+	}
+
+	s.Args = append(s.Args, s.actorStamp.Actor)
+	placeholder := "$" + strconv.Itoa(len(s.Args))
+
+	stamped := slices.Clip(columns)
+	for _, column := range missing {
+		stamped = append(stamped, `"`+column+`"`)
+	}
+
+	rows := make([]string, len(valuePlaceholders))
+	for i, values := range valuePlaceholders {
+		rows[i] = appendToRows(values, strings.Repeat(","+placeholder, len(missing)))
+	}
+
+	return stamped, rows
+}
+
+// stampUpdateClauses adds `"updated_by" = $n` unless setClauses already set it.
+func (s *SQLEloquentQuery) stampUpdateClauses(setClauses []string) []string {
+	column := s.actorStamp.UpdatedBy
+	if !s.actorStamp.enabled() || column == "" {
+		return setClauses
+	}
+
+	prefix := fmt.Sprintf(`"%s" `, column)
+	for _, clause := range setClauses {
+		if strings.HasPrefix(clause, prefix) || strings.HasPrefix(clause, column+" ") {
+			return setClauses
+		}
+	}
+
+	s.Args = append(s.Args, s.actorStamp.Actor)
+	return append(setClauses, fmt.Sprintf(`"%s" = $%d`, column, len(s.Args)))
+}
+
+// appendToRows writes extra before the closing parenthesis of every row of a VALUES list, e.g.
+// `($1,NOW()),($2,NOW())` becomes `($1,NOW(),$3),($2,NOW(),$3)`. Calls like NOW() are skipped by tracking the depth.
+func appendToRows(values, extra string) string {
+	var sb strings.Builder
+	sb.Grow(len(values) + len(extra)*4)
+
+	depth := 0
+	for _, char := range values {
+		switch char {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				sb.WriteString(extra)
+			}
+		}
+		sb.WriteRune(char)
+	}
+
+	return sb.String()
+}
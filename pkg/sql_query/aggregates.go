@@ -0,0 +1,95 @@
+package sql_query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+func (s *SelectBuilder) SelectSum(expr, alias, filterCondition string, args ...interface{}) SQLSelectChainBuilder {
+	return s.selectAggregate("SUM", expr, alias, filterCondition, args)
+}
+
+func (s *SelectBuilder) SelectAvg(expr, alias, filterCondition string, args ...interface{}) SQLSelectChainBuilder {
+	return s.selectAggregate("AVG", expr, alias, filterCondition, args)
+}
+
+func (s *SelectBuilder) SelectCountDistinct(expr, alias string) SQLSelectChainBuilder {
+	return s.selectAggregate("COUNT", "DISTINCT "+expr, alias, "", nil)
+}
+
+func (s *SelectBuilder) SelectPercentile(alias string, fraction float64, orderExpr string) SQLSelectChainBuilder {
+	orderExpr = strings.TrimSpace(orderExpr)
+	// Written so that NaN fails too
+	if !(fraction >= 0 && fraction <= 1) {
+		s.LastError = fmt.Errorf("%w: percentile fraction must be between 0 and 1, got %v", ErrInvalidValues, fraction)
+		return s
+	}
+	if orderExpr == "" || alias == "" {
+		s.LastError = fmt.Errorf("%w: percentile needs an alias and an order expression, got %q and %q", ErrInvalidValues, alias, orderExpr)
+		return s
+	}
+
+	column := fmt.Sprintf(`percentile_cont(%s) WITHIN GROUP (ORDER BY %s) AS "%s"`,
+		strconv.FormatFloat(fraction, 'f', -1, 64), orderExpr, alias)
+	s.selectAliased(column, alias, nil)
+	return s
+}
+
+func (s *SelectBuilder) SelectMedian(alias string, orderExpr string) SQLSelectChainBuilder {
+	return s.SelectPercentile(alias, 0.5, orderExpr)
+}
+
+// selectAggregate selects function(expr) with an optional FILTER, replacing the column of the same alias.
+// The ? of expr and filterCondition are bound to args, see bindQuestionMarks.
+func (s *SelectBuilder) selectAggregate(function, expr, alias, filterCondition string, args []interface{}) SQLSelectChainBuilder {
+	expr, filterCondition = strings.TrimSpace(expr), strings.TrimSpace(filterCondition)
+	if expr == "" || strings.TrimSpace(strings.TrimPrefix(expr, "DISTINCT ")) == "" {
+		s.LastError = fmt.Errorf("%w: %s without expression", ErrInvalidValues, function)
+		return s
+	}
+	if alias == "" {
+		s.LastError = fmt.Errorf("%w: %s(%s) without alias", ErrInvalidValues, function, expr)
+		return s
+	}
+
+	aggregate := fmt.Sprintf("%s(%s)", function, expr)
+	if filterCondition != "" {
+		aggregate = fmt.Sprintf("%s FILTER (WHERE %s)", aggregate, filterCondition)
+	}
+	// A sum over no row is NULL, reports expect 0
+	if function == "SUM" {
+		aggregate = fmt.Sprintf("COALESCE(%s, 0)", aggregate)
+	}
+
+	s.selectAliased(fmt.Sprintf(`%s AS "%s"`, aggregate, alias), alias, args)
+	return s
+}
+
+// selectAliased replaces the column of the same alias with column, or appends it, binding the ? of column to args.
+func (s *SelectBuilder) selectAliased(column, alias string, args []interface{}) {
+	index := -1
+	for i, existing := range s.Columns {
+		if extracted := extractAlias(existing); extracted != "" && extracted == strings.ToLower(alias) {
+			// Its arguments are bound already, they would be left without placeholder
+			if highestPlaceholder(existing) > 0 {
+				s.LastError = fmt.Errorf("%w: %q is already selected with arguments", ErrInvalidValues, alias)
+				return
+			}
+			index = i
+			break
+		}
+	}
+
+	bound, err := s.bindQuestionMarks(column, args)
+	if err != nil {
+		s.LastError = err
+		return
+	}
+
+	if index >= 0 {
+		s.Columns[index] = bound // Overwrite
+		return
+	}
+	s.Columns = append(s.Columns, bound)
+}
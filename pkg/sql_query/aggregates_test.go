@@ -0,0 +1,52 @@
+package sql_query
+
+import (
+	"errors"
+	"testing"
+
+	sqltesting "github.com/mystaline/clefinport-be/pkg/sql_query/testing"
+)
+
+func TestSelectAggregates(t *testing.T) {
+	builder := NewSQLSelectBuilder[any]("transactions t").
+		Where(map[string]SQLCondition{"t.user_id": {Operator: SQLOperatorEqual, Value: "42"}}).
+		SelectSum("t.amount", "totalExpense", "t.type = ?", "expense").
+		SelectAvg("t.amount * ?", "averageIncome", "t.type = ?", 100, "income").
+		SelectCountDistinct("t.category_id", "categories")
+
+	sqltesting.AssertSQL(t, builder, `
+		SELECT
+			COALESCE(SUM(t.amount) FILTER (WHERE t.type = $2), 0) AS "totalExpense",
+			AVG(t.amount * $3) FILTER (WHERE t.type = $4) AS "averageIncome",
+			COUNT(DISTINCT t.category_id) AS "categories"
+		FROM transactions t
+		WHERE "t"."user_id" = $1`,
+		[]any{"42", "expense", 100, "income"},
+	)
+}
+
+func TestSelectAggregateReplacesAlias(t *testing.T) {
+	builder := NewSQLSelectBuilder[any]("transactions t").
+		SelectCountDistinct("t.category_id", "total").
+		SelectSum("t.amount", "total", "t.type = ?", "expense")
+
+	sqltesting.AssertSQL(t, builder, `
+		SELECT COALESCE(SUM(t.amount) FILTER (WHERE t.type = $1), 0) AS "total"
+		FROM transactions t`,
+		[]any{"expense"},
+	)
+
+	_, _, err := builder.SelectAvg("t.amount", "total", "").Build()
+	if !errors.Is(err, ErrInvalidValues) {
+		t.Fatalf("Build() error = %v, want ErrInvalidValues for an alias selected with arguments", err)
+	}
+}
+
+func TestSelectAggregateArgsMismatch(t *testing.T) {
+	_, _, err := NewSQLSelectBuilder[any]("transactions t").
+		SelectSum("t.amount", "total", "t.type = ?", "expense", "income").
+		Build()
+	if !errors.Is(err, ErrInvalidValues) {
+		t.Fatalf("Build() error = %v, want ErrInvalidValues", err)
+	}
+}
@@ -0,0 +1,25 @@
+package sql_query
+
+// Args of sub-builders (CTEs, lateral joins, UNION ALL) are merged into the parent by value.
+// Build hands out a capped slice, so appending to the returned args always reallocates
+// instead of writing into the spare capacity still owned by the builder.
+
+// appendArgs appends src to dst, growing dst at most once to fit all of src.
+func appendArgs(dst []interface{}, src []interface{}) []interface{} {
+	if len(src) == 0 {
+		return dst
+	}
+
+	if cap(dst)-len(dst) < len(src) {
+		grown := make([]interface{}, len(dst), 2*len(dst)+len(src))
+		copy(grown, dst)
+		dst = grown
+	}
+
+	return append(dst, src...)
+}
+
+// sealArgs caps args at their length, making the returned slice copy-on-append.
+func sealArgs(args []interface{}) []interface{} {
+	return args[:len(args):len(args)]
+}
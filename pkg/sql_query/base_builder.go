@@ -1,109 +1,198 @@
-package sql_query
-
-import (
-	"errors"
-)
-
-type ArrayAggConfig struct {
-	Expr      string
-	SortBy    string
-	SortOrder int
-}
-
-type SQLMode string
-
-const (
-	SQLDelete SQLMode = "delete"
-	SQLInsert SQLMode = "insert"
-	SQLSelect SQLMode = "select"
-	SQLUpdate SQLMode = "update"
-)
-
-type QueryBuilder interface {
-	buildSelectQuery() (string, []interface{}, error)
-	buildInsertQuery() (string, []interface{}, error)
-	buildUpdateQuery() (string, []interface{}, error)
-	buildDeleteQuery() (string, []interface{}, error)
-}
-
-type SQLFilter map[string]SQLCondition
-
-type SQLCondition struct {
-	Operator      SQLOperators  // e.g., '=', '>', '<=', 'LIKE', 'IN', 'IS NULL'
-	Key           string        // used for array of object json pointing to the key of the object. This option should only be used with IsArray
-	Value         interface{}   // could be a single value, slice, or nil.
-	IsRef         bool          // to determine whether WHERE is targeting literal value or reference, e.g. `"column_a" = value` vs `"column_a" = $2` based on given boolean.
-	SourceIsValue bool          // to determine whether WHERE is sourcing from literal value or reference, e.g. `"column_a" = value` vs `$2 = value` based on given boolean.
-	IsSubQuery    bool          // to determine whether WHERE is sourcing from a query e.g. WHERE category_id IN (SELECT id FROM category_tree).
-	IsEpochTime   bool          // assign this to true if value contains epoch/unix time in milliseconds.
-	IsArray       bool          // to determine whether WHERE is targeting an array of object json. This option should only be used with Key
-	ExtraArgs     []interface{} // for Operator `SQLOperatorRaw`
-}
-
-type UpdateCaseParam struct {
-	conditions []string
-	value      string
-	isElse     bool
-}
-
-// type UpdateCaseParam struct {
-// 	column         string
-// 	UpdateCaseExpr []UpdateCaseExpr
-// }
-
-type MultiFilterCondition struct {
-	And map[string]SQLCondition
-	Or  []map[string]SQLCondition
-}
-
-// Base struct that contains params used by methods
-type SQLEloquentQuery struct {
-	NestedAggregation []string
-	WrapAggregation   bool
-
-	ConflictClause  string
-	WithClauses     []string
-	Table           string
-	Filters         []string
-	OtherTables     []string
-	UnionAllQueries []string
-	Columns         []string
-	DistinctBy      []string
-	DistinctAlias   string
-	Offset          int
-	Limit           int
-	SortBy          []string
-	Grouping        []string
-	HavingClauses   []string
-
-	CustomQuery       string
-	UpdateCaseClauses map[string][]UpdateCaseParam
-
-	Args          []interface{}
-	UsePagination bool
-	Mode          SQLMode
-	LastError     error
-
-	currentUpdateCaseKey string
-	useWithRecursive     bool
-	useUnionAll          bool
-	useHaving            bool
-	excludeEmptyValue    bool
-	isSubQuery           bool
-}
-
-// Run respective build method based on given mode
-func (s *SQLEloquentQuery) Build() (string, []interface{}, error) {
-	switch s.Mode {
-	case SQLDelete:
-		return s.buildDeleteQuery()
-	case SQLInsert:
-		return s.buildInsertQuery()
-	case SQLUpdate:
-		return s.buildUpdateQuery()
-	case SQLSelect:
-		return s.buildSelectQuery()
-	default:
-		return "", nil, errors.New("unsupported query mode")
-	}
-}
+package sql_query
+
+import (
+	"errors"
+	"reflect"
+)
+
+// ErrInvalidValues wraps builder failures caused by the values given to Insert, Update or UpdateEach,
+// or by a WHERE whose conditions were all skipped as empty. Services report them as client errors.
+var ErrInvalidValues = errors.New("invalid values")
+
+type ArrayAggConfig struct {
+	Expr      string
+	SortBy    string
+	SortOrder int
+}
+
+type SQLMode string
+
+const (
+	SQLDelete SQLMode = "delete"
+	SQLInsert SQLMode = "insert"
+	SQLSelect SQLMode = "select"
+	SQLUpdate SQLMode = "update"
+)
+
+type QueryBuilder interface {
+	buildSelectQuery() (string, []interface{}, error)
+	buildInsertQuery() (string, []interface{}, error)
+	buildUpdateQuery() (string, []interface{}, error)
+	buildDeleteQuery() (string, []interface{}, error)
+}
+
+type SQLFilter map[string]SQLCondition
+
+type SQLCondition struct {
+	Operator      SQLOperators  // e.g., '=', '>', '<=', 'LIKE', 'IN', 'IS NULL'
+	Key           string        // used for array of object json pointing to the key of the object. This option should only be used with IsArray
+	Value         interface{}   // could be a single value, slice, or nil.
+	IsRef         bool          // to determine whether WHERE is targeting literal value or reference, e.g. `"column_a" = value` vs `"column_a" = $2` based on given boolean.
+	SourceIsValue bool          // to determine whether WHERE is sourcing from literal value or reference, e.g. `"column_a" = value` vs `$2 = value` based on given boolean.
+	IsSubQuery    bool          // to determine whether WHERE is sourcing from a query e.g. WHERE category_id IN (SELECT id FROM category_tree).
+	IsEpochTime   bool          // assign this to true if value contains epoch/unix time in milliseconds.
+	Timezone      string        // IANA zone of an IsEpochTime value, e.g. "Asia/Jakarta", widens it to its whole day in that zone. Exact instant when empty.
+	IsArray       bool          // to determine whether WHERE is targeting an array of object json. This option should only be used with Key
+	ExtraArgs     []interface{} // for Operator `SQLOperatorRaw`
+}
+
+type UpdateCaseParam struct {
+	conditions []string
+	value      string
+	isElse     bool
+}
+
+// UpdateCaseClause is the CASE expression of one column, kept in AddCase order so the SET clause is stable.
+type UpdateCaseClause struct {
+	Column string
+	Params []UpdateCaseParam
+}
+
+// type UpdateCaseParam struct {
+// 	column         string
+// 	UpdateCaseExpr []UpdateCaseExpr
+// }
+
+type MultiFilterCondition struct {
+	And map[string]SQLCondition
+	Or  []map[string]SQLCondition
+}
+
+// Base struct that contains params used by methods
+type SQLEloquentQuery struct {
+	NestedAggregation []string
+	WrapAggregation   bool
+
+	ConflictClause  string
+	WithClauses     []string
+	Table           string
+	Filters         []string
+	OtherTables     []string
+	UnionAllQueries []string
+	Columns         []string
+	DistinctBy      []string
+	DistinctAlias   string
+	Offset          int
+	Limit           int
+	SortBy          []string
+	Grouping        []string
+	HavingClauses   []string
+
+	CustomQuery       string
+	UpdateCaseClauses []UpdateCaseClause
+
+	Args          []interface{}
+	UsePagination bool
+	Mode          SQLMode
+	LastError     error
+	// Dialect of the built query, DefaultDialect() when empty
+	Dialect Dialect
+
+	currentUpdateCase int
+	cursorColumns     []string
+	cursorLimit       int
+	// Acquired from the builder pool, Release ignores the others
+	pooled            bool
+	lockClause        string
+	tableSample       string
+	useWithRecursive  bool
+	useSetOperation   bool
+	setOperators      []string
+	excludeEmptyValue bool
+	isSubQuery        bool
+	paddedArgs        bool
+
+	strictIdentifiers  bool
+	allowedIdentifiers []string
+	selectedColumns    []string
+	// Built by the builder itself (e.g. GroupByDateTrunc), StrictIdentifiers only checks their columns
+	trustedExpressions []string
+	dateTruncColumns   []string
+
+	// StrictFilterKeys: keys of Where, WhereOr and WhereGroup checked against the DTO (dtoType) columns and known keys
+	strictFilterKeys bool
+	knownFilterKeys  []string
+	filterKeys       []string
+	dtoType          reflect.Type
+
+	// ORDER BY terms written as given, skipping the alias rewrite (e.g. OrderByCase)
+	sortExpressions []string
+	// SET list of the UPDATE, one `"column" = expr` per column, CustomQuery is rendered from it by buildUpdateQuery
+	setClauses []string
+	// VALUES list UpdateEach reads its rows from, rendered after setClauses
+	updateValues string
+	// Current jsonb_set expression per column, so further UpdateJSONBField calls nest into it
+	jsonbSetClauses map[string]string
+	// Step expression of each column of Increment / Decrement, e.g. "balance": `"balance" - $1`, for GuardMin
+	steps map[string]string
+	// Builders of WithCTEBuilder by lower cased name, for duplicate detection and GetCTE
+	ctes map[string]*SQLEloquentQuery
+	// Columns of the INSERT column list, unquoted, for UpsertMany
+	insertColumns []string
+	// Columns set by ConflictUpdate from EXCLUDED, for OnlyIfChanged
+	conflictUpdateColumns []string
+	// Alias of the derived table of FromSubquery, Table then holds the whole subquery
+	fromAlias string
+	// PreserveTimestamps: created_at/updated_at values of each inserted row, bound instead of NOW() when set
+	preserveTimestamps bool
+	rowTimestamps      []map[string]any
+	// WithoutTouch: updates leave updated_at as is
+	withoutTouch bool
+
+	timezone string
+
+	actorStamp ActorStamp
+
+	// Tables of joins and composed sub-builders, for BuildWithMeta
+	tables []string
+}
+
+// Run respective build method based on given mode, placeholders follow the builder's Dialect
+func (s *SQLEloquentQuery) Build() (string, []interface{}, error) {
+	query, args, err := s.build()
+	// Sub-query builders number their placeholders after their parent's args
+	if err == nil && !s.isSubQuery && !s.paddedArgs {
+		err = auditPlaceholders(query, args)
+	}
+	if err == nil {
+		s.runQueryHook(query)
+	}
+	if err != nil || !s.dialect().usesPositionalPlaceholders() {
+		return query, args, err
+	}
+
+	query, args, err = rebindPlaceholders(query, args)
+	return query, sealArgs(args), err
+}
+
+// build returns the query with Postgres placeholders, used to compose sub-builders whatever their Dialect.
+func (s *SQLEloquentQuery) build() (string, []interface{}, error) {
+	var query string
+	var args []interface{}
+	var err error
+
+	switch s.Mode {
+	case SQLDelete:
+		query, args, err = s.buildDeleteQuery()
+	case SQLInsert:
+		query, args, err = s.buildInsertQuery()
+	case SQLUpdate:
+		query, args, err = s.buildUpdateQuery()
+	case SQLSelect:
+		query, args, err = s.buildSelectQuery()
+	default:
+		return "", nil, errors.New("unsupported query mode")
+	}
+
+	return query, sealArgs(args), err
+}
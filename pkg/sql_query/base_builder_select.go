@@ -1,1107 +1,1957 @@
-package sql_query
-
-import (
-	"errors"
-	"fmt"
-	"regexp"
-	"strconv"
-	"strings"
-)
-
-type SQLSelectChainBuilder interface {
-	// GetCurrentArgIndex returns the current number of arguments in the query.
-	// Useful for calculating placeholder positions when building parameterized SQL.
-	GetCurrentArgIndex() int
-	// AddArgs appends one or more arguments to the query's argument list.
-	// Can be used to manually manage parameter placeholders.
-	AddArgs(arg ...interface{}) SQLSelectChainBuilder
-	// StartPlaceholderFrom set starting point of next generated placeholders.
-	// Useful for creating sub query from this builder for another main query.
-	// Placeholders for this sub query will be started from this given value.
-	StartPlaceholderFrom(index int) SQLSelectChainBuilder
-
-	// Distinct implements SQLSelectChainBuilder.
-	// Distinct defines one or more columns for the DISTINCT ON(...) statement.
-	//
-	// Example:
-	//
-	//	builder.Distinct("distinct", "u.id", "u.name")
-	Distinct(alias string, columns ...string) SQLSelectChainBuilder
-
-	// Select implements SQLSelectChainBuilder.
-	// Select defines one or more columns for the SELECT statement.
-	// If a column alias already exists, its expression will be overwritten with the new one.
-	//
-	// Example:
-	//
-	//	builder.Select("u.id AS user_id", "u.name")
-	Select(columns ...string) SQLSelectChainBuilder
-
-	// USE WITH CAUTION
-	// Reset all previous appended selects
-	// Useful for this part in GenerateCTEOption
-	// cteBuilder := sourceBuilder.
-	//		ClearSelects(). -- without this the previous selects inside the source builder will get included
-	// 		Distinct(
-	// 			fmt.Sprintf(`%s AS "value"`, labelValue),
-	// 			labelValue,
-	// 		).
-	// 		Select(
-	// 			fmt.Sprintf(`%s AS "key"`, labelKey),
-	// 			fmt.Sprintf(`"%s"."id" AS "id"`, refTable),
-	// 		)
-	//
-	// Example usage:
-	//
-	//	builder.ClearSelect()
-	ClearSelects() SQLSelectChainBuilder
-
-	// SelectCaseWhen adds a CASE WHEN expression as a column.
-	//
-	// Example:
-	//
-	//	builder.SelectCaseWhen("'Yes'", "'No'", "is_admin", "role = 'admin'")
-	//
-	// Generates:
-	//
-	//	CASE WHEN role = 'admin' THEN 'Yes' ELSE 'No' END AS is_admin
-	SelectCaseWhen(thenExpr, elseExpr, alias string, whenClause string, whenArgs ...interface{}) SQLSelectChainBuilder
-	// SelectBoolAnd adds a bool_or aggregate column with an alias.
-	//
-	// Example:
-	//
-	//	builder.SelectBoolAnd("is_active", "any_active")
-	//
-	// Generates:
-	//
-	//	bool_and(is_active) AS any_active
-	SelectBoolAnd(expr, alias string, args ...interface{}) SQLSelectChainBuilder
-	// SelectBoolOr adds a bool_or aggregate column with an alias.
-	//
-	// Example:
-	//
-	//	builder.SelectBoolOr("is_active", "any_active")
-	//
-	// Generates:
-	//
-	//	bool_or(is_active) AS any_active
-	SelectBoolOr(expr, alias string, args ...interface{}) SQLSelectChainBuilder
-
-	SelectArrayAggregation(alias string, source string, config ArrayAggConfig) SQLSelectChainBuilder
-
-	// SelectJSONArrayElements selects elements from a Go slice of maps
-	// and expands them as rows using jsonb_array_elements().
-	//
-	// Each element in the slice is marshaled to JSON and passed as a query argument.
-	//
-	// Example:
-	//
-	//	arr := []map[string]string{{"id": "1"}, {"id": "2"}}
-	//	builder.SelectJSONArrayElements("items", arr)
-	//
-	// Generates:
-	//
-	//	jsonb_array_elements($1::jsonb) AS items
-	SelectJSONArrayElements(alias string, arrayElements []map[string]string) SQLSelectChainBuilder
-	// SelectJSONAggregate builds a JSON object or JSON array aggregation using jsonb_build_object
-	// or jsonb_agg(jsonb_build_object(...)). It supports optional filtering and ordering.
-	//
-	// The `dto` parameter can be either:
-	//   - a struct (fields are mapped from JSON tags), or
-	//   - a map[string]string (keys = JSON keys, values = SQL expressions).
-	//
-	// If asArrayAggregation is true, the result is wrapped with jsonb_agg().
-	//
-	// Example (array aggregation):
-	//
-	//	builder.SelectJSONAggregate(
-	//	    "order_items",
-	//	    map[string]string{"id": "items.id", "name": "items.name"},
-	//	    "items.is_active = TRUE",
-	//	    true,
-	//	    "items.created_at",
-	//	)
-	//
-	// or with a struct:
-	//
-	//	builder.SelectJSONAggregate(
-	//	    "order_items",
-	//	    dto.OrderItem{},
-	//	    "items.is_active = TRUE",
-	//	    true,
-	//	    "items.created_at",
-	//	)
-	//
-	// Generates:
-	//
-	//	jsonb_agg(jsonb_build_object('id', items.id, 'name', items.name) ORDER BY items.created_at)
-	//	  FILTER (WHERE items.is_active = TRUE) AS order_items
-	SelectJSONAggregate(alias string, dto any, condition string, asArrayAggregation bool, orderByClauses ...string) SQLSelectChainBuilder
-	// Read documentation for SelectJSONAggregate since the function is similar but with additional COALESCE
-	// Generates:
-	//
-	//	COALESCE(jsonb_agg(DISTINCT jsonb_build_object('id', items.id, 'name', items.name) ORDER BY items.created_at)
-	//	  FILTER (WHERE items.is_active = TRUE) AS order_items, ${coalesce})
-	SelectJSONAggregateCoalesce(alias string, dto any, condition string, asArrayAggregation bool, coalesce string, orderByClauses ...string) SQLSelectChainBuilder
-	// Read documentation for SelectJSONAggregate since the function is similar but with additional DISTINCT
-	// Generates:
-	//
-	//	jsonb_agg(DISTINCT jsonb_build_object('id', items.id, 'name', items.name) ORDER BY items.created_at)
-	//	  FILTER (WHERE items.is_active = TRUE) AS order_items
-	SelectJSONAggregateDistinct(alias string, dto any, condition string, asArrayAggregation bool, orderByClauses ...string) SQLSelectChainBuilder
-	// SelectJSONAggregateFunc builds a nested JSON object by executing a callback
-	// that itself adds JSON aggregate fields. The resulting fields are combined
-	// into a single jsonb_build_object aliased as `alias`.
-	//
-	// Example:
-	//
-	//	builder.SelectJSONAggregateFunc("hasSystemRole", func(b *sql_query.SelectBuilder) {
-	//	    b.SelectJSONAggregate(
-	//	        string(rt),
-	//	        map[string]string{
-	//	            "create": getSystemRolePermission("create"),
-	//	            "view":   getSystemRolePermission("view"),
-	//	            "update": getSystemRolePermission("update"),
-	//	            "delete": getSystemRolePermission("delete"),
-	//	        },
-	//	        fmt.Sprintf("usr.role_attribute = $%d", len(builder.Args)+1),
-	//	        false,
-	//	    )
-	//	})
-	//
-	// Generates:
-	//
-	//	jsonb_build_object(
-	//	    'SomeRole', CASE WHEN usr.role_attribute = $1 THEN jsonb_build_object(
-	//	        'create', <expr>, 'view', <expr>, 'update', <expr>, 'delete', <expr>
-	//	    ) ELSE NULL END
-	//	) AS hasSystemRole
-	SelectJSONAggregateFunc(alias string, fn func(builder *SelectBuilder)) SQLSelectChainBuilder
-
-	// Where implements SQLSelectChainBuilder. (Accumulates previous value if called again)
-	Where(filters map[string]SQLCondition) SQLSelectChainBuilder
-	// WhereOr implements SQLSelectChainBuilder. (Accumulates previous value if called again)
-	WhereOr(filters ...map[string]SQLCondition) SQLSelectChainBuilder
-
-	// Search implements SQLSelectChainBuilder and accumulates conditions if called multiple times.
-	// Adds a case-insensitive ILIKE condition across multiple fields, combined with OR.
-	//
-	// To search inside array columns, add suffix ":array" to the column name.
-	// This uses EXISTS + unnest for better performance & usabilitiy than array_to_string.
-	//
-	// Example:
-	//
-	//	builder.Search("john", []string{"status::text", "last_name", "tags:array"})
-	//
-	// Generates:
-	//
-	//	(first_name ILIKE $1 OR last_name ILIKE $1 OR EXISTS (SELECT 1 FROM unnest(tags) AS val WHERE val ILIKE $1))
-	Search(keyword string, fields []string) SQLSelectChainBuilder
-	// SetLimit sets a fixed LIMIT value for the query (overwrites any previous limit).
-	//
-	// Example:
-	//
-	//	builder.SetLimit(5)
-	SetLimit(limit int) SQLSelectChainBuilder
-	// Join adds an INNER JOIN clause with the specified ON condition.
-	//
-	// Example:
-	//
-	//	builder.Join("roles r", "r.id = u.role_id")
-	Join(table string, onCondition string, additionalConditions ...map[string]SQLCondition) SQLSelectChainBuilder
-	// LeftJoin adds a LEFT JOIN clause with the specified ON condition.
-	//
-	// Example:
-	//
-	//	builder.LeftJoin("roles r", "r.id = u.role_id")
-	LeftJoin(table string, onCondition string, additionalConditions ...map[string]SQLCondition) SQLSelectChainBuilder
-
-	// Example:
-	//
-	// LeftJoinLateralWithQuery("ta", categoryWithRecursiveBuilder().(*sql_query.SelectBuilder).SQLEloquentQuery, "TRUE").
-	//
-	// Output:
-	//
-	// 	LEFT JOIN LATERAL (
-	//     `Sql queries here`
-	//   ) `alias` ON `condition`
-	LeftJoinLateralWithQuery(joinName string, joinQueryBuilder *SQLEloquentQuery, mainCondition string, additionalConditions ...map[string]SQLCondition) SQLSelectChainBuilder
-
-	// Paginate implements SQLSelectChainBuilder. (Overrides previous value if called again).
-	// Paginate applies LIMIT, OFFSET, and ORDER BY using a Pagination struct.
-	// It supports single or multiple sorting rules.
-	//
-	// Example:
-	//
-	//	builder.Paginate(Pagination{Page: 2, Limit: 10, SortBy: "name", SortOrder: 1})
-	Paginate(query Pagination) SQLSelectChainBuilder
-	// OrderBy adds sorting rules to the query. Multiple calls accumulate sorting.
-	//
-	// Example:
-	//
-	//	builder.OrderBy([]string{"created_at"}, false) // DESC
-	OrderBy(sortBy []string, asc bool) SQLSelectChainBuilder
-	// GroupBy adds one or more columns to the GROUP BY clause.
-	// Multiple calls accumulate columns.
-	//
-	// Example:
-	//
-	//	builder.GroupBy("department", "role")
-	GroupBy(groupBy ...string) SQLSelectChainBuilder
-	// Having implements SQLSelectChainBuilder. (Overrides previous value if called again).
-	// Having adds a HAVING clause for grouped queries.
-	// Overwrites any previous HAVING condition.
-	//
-	// Example:
-	//
-	//	builder.GroupBy("role").Having(map[string]SQLCondition{
-	//	    "count(*)": {Op: ">", Value: 5},
-	//	})
-	Having(havingClauses map[string]SQLCondition) SQLSelectChainBuilder
-
-	// WithCTEBuilder adds a Common Table Expression (CTE) to the query.
-	// It adjusts argument placeholders to avoid conflicts.
-	// This function just add the defined CTE to the top of query.
-	// You need to JOIN/LEFT JOIN the CTE builder to let main expression know that it should use CTE.
-	//
-	// Example:
-	//
-	//	cte := NewSQLSelectBuilder[Order]("orders").Select("id", "user_id")
-	//	builder.WithCTEBuilder("recent_orders", cte.(*sql_query.SelectBuilder).SQLEloquentQuery)
-	//
-	// Generates:
-	//
-	//	WITH recent_orders AS (SELECT id, user_id FROM orders) ...
-	WithCTEBuilder(cteName string, cteBuilder *SQLEloquentQuery) SQLSelectChainBuilder
-
-	// WithRecursiveCTEBuilder adds a Common Table Expression (CTE) to the query.
-	// It adjusts argument placeholders to avoid conflicts.
-	// This function just add the defined CTE to the top of query.
-	// You need to JOIN/LEFT JOIN the CTE builder to let main expression know that it should use CTE.
-	//
-	// Example:
-	//
-	//	cte := NewSQLSelectBuilder[Order]("orders").Select("id", "user_id")
-	//	builder.WithRecursiveCTEBuilder("recent_orders", cte.(*sql_query.SelectBuilder).SQLEloquentQuery)
-	//
-	// Generates:
-	//
-	//	WITH RECURSIVE recent_orders AS (SELECT id, user_id FROM orders) ...
-	WithRecursiveCTEBuilder(cteName string, cteBuilder *SQLEloquentQuery) SQLSelectChainBuilder
-
-	// Add "UNION ALL" in between the queries
-	// example:
-	//  SELECT id
-	// FROM categories
-	// WHERE id = 0
-	//
-	// UNION ALL
-	//
-	// SELECT c.id
-	// FROM categories c
-	// INNER JOIN category_tree ct ON c.parent_id = ct.id
-	UnionAll(cteBuilders ...*SQLEloquentQuery) SQLSelectChainBuilder
-
-	// Build finalizes the SELECT query and returns the query string and arguments.
-	// Returns an error if the query is invalid (e.g., HAVING without GROUP BY).
-	Build() (string, []interface{}, error)
-}
-
-type SelectBuilder struct {
-	*SQLEloquentQuery
-}
-
-func (s *SelectBuilder) Where(filters map[string]SQLCondition) SQLSelectChainBuilder {
-	s.SQLEloquentQuery.sharedWhereAndQuery(filters)
-	return s
-}
-
-func (s *SelectBuilder) WhereOr(filters ...map[string]SQLCondition) SQLSelectChainBuilder {
-	s.SQLEloquentQuery.sharedWhereOrQuery(filters...)
-	return s
-}
-
-func (s *SelectBuilder) GetCurrentArgIndex() int {
-	return len(s.Args)
-}
-
-func (s *SelectBuilder) AddArgs(arg ...interface{}) SQLSelectChainBuilder {
-	s.Args = append(s.Args, arg...)
-	return s
-}
-
-func (s *SelectBuilder) StartPlaceholderFrom(index int) SQLSelectChainBuilder {
-	if index < 1 {
-		index = 1
-	}
-
-	if len(s.Args) > 0 {
-		// Need to pad so that first existing arg becomes $index
-		pad := index - 1
-		if pad > 0 {
-			temp := make([]interface{}, pad+len(s.Args))
-			// fill padding with nils
-			for i := 0; i < pad; i++ {
-				temp[i] = nil
-			}
-			// copy old args after padding
-			copy(temp[pad:], s.Args)
-			s.Args = temp
-		}
-	} else {
-		// If empty, just pad with nils up to index-1
-		if index > 1 {
-			temp := make([]interface{}, index-1)
-			for i := 0; i < index-1; i++ {
-				temp[i] = nil
-			}
-			s.Args = temp
-		}
-	}
-
-	return s
-}
-
-func (s *SelectBuilder) Search(keyword string, fields []string) SQLSelectChainBuilder {
-	if keyword != "" && len(fields) > 0 {
-		var orClauses []string
-		orArgs := []interface{}{}
-
-		for _, field := range fields {
-			isArrayColumn := strings.HasSuffix(field, ":array")
-
-			if isArrayColumn {
-				cleanColumn := strings.TrimSuffix(field, ":array")
-				orClauses = append(orClauses, fmt.Sprintf("EXISTS (SELECT 1 FROM unnest(%s) as val WHERE val ILIKE $%d)", cleanColumn, len(s.Args)+len(orArgs)+1))
-			} else {
-				orClauses = append(orClauses, fmt.Sprintf("%s ILIKE $%d", field, len(s.Args)+len(orArgs)+1))
-			}
-
-			orArgs = append(orArgs, "%"+keyword+"%")
-		}
-
-		// Combine all clauses with OR inside parentheses
-		orClauseGroup := fmt.Sprintf("(%s)", strings.Join(orClauses, " OR "))
-		s.Filters = append(s.Filters, orClauseGroup)
-		s.Args = append(s.Args, orArgs...)
-	}
-
-	return s
-}
-
-func (s *SelectBuilder) Distinct(alias string, columns ...string) SQLSelectChainBuilder {
-	s.DistinctAlias = alias
-	for _, newCol := range columns {
-		newAlias := extractAlias(newCol)
-
-		// Check if alias exists in current list
-		replaced := false
-		for i, existing := range s.DistinctBy {
-			extracted := extractAlias(existing)
-			if extracted != "" && extracted == newAlias {
-				s.DistinctBy[i] = newCol // Overwrite
-				replaced = true
-				break
-			}
-		}
-
-		if !replaced {
-			s.DistinctBy = append(s.DistinctBy, newCol)
-		}
-	}
-
-	return s
-}
-
-func (s *SelectBuilder) ClearSelects() SQLSelectChainBuilder {
-	s.Columns = []string{}
-	return s
-}
-
-func (s *SelectBuilder) Select(columns ...string) SQLSelectChainBuilder {
-	for _, newCol := range columns {
-		newAlias := extractAlias(newCol)
-
-		// Check if alias exists in current list
-		replaced := false
-		for i, existing := range s.Columns {
-			extracted := extractAlias(existing)
-			if extracted != "" && extracted == newAlias {
-				s.Columns[i] = newCol // Overwrite
-				replaced = true
-				break
-			}
-		}
-
-		if !replaced {
-			s.Columns = append(s.Columns, newCol)
-		}
-	}
-	return s
-}
-
-func (s *SelectBuilder) SelectBoolAnd(expr, alias string, args ...interface{}) SQLSelectChainBuilder {
-	boolAndColumn := fmt.Sprintf("bool_and(%s) AS \"%s\"", expr, alias)
-
-	// Check if alias exists in current list
-	replaced := false
-	for i, existing := range s.Columns {
-		extracted := extractAlias(existing)
-		if extracted != "" && extracted == strings.ToLower(alias) {
-			s.Columns[i] = boolAndColumn // Overwrite
-			replaced = true
-			break
-		}
-	}
-
-	if !replaced {
-		s.Columns = append(s.Columns, boolAndColumn)
-	}
-
-	s.Args = append(s.Args, args...)
-	return s
-}
-
-func (s *SelectBuilder) SelectBoolOr(expr, alias string, args ...interface{}) SQLSelectChainBuilder {
-	boolOrColumn := fmt.Sprintf("bool_or(%s) AS \"%s\"", expr, alias)
-
-	// Check if alias exists in current list
-	replaced := false
-	for i, existing := range s.Columns {
-		extracted := extractAlias(existing)
-		if extracted != "" && extracted == strings.ToLower(alias) {
-			s.Columns[i] = boolOrColumn // Overwrite
-			replaced = true
-			break
-		}
-	}
-
-	if !replaced {
-		s.Columns = append(s.Columns, boolOrColumn)
-	}
-
-	s.Args = append(s.Args, args...)
-	return s
-}
-
-func (s *SelectBuilder) SelectArrayAggregation(alias string, source string, config ArrayAggConfig) SQLSelectChainBuilder {
-	if config.Expr == "" {
-		s.LastError = errors.New("expression should not empty")
-	}
-
-	var orderByClause string
-	if config.SortBy != "" && config.SortOrder != 0 {
-		order := "ASC"
-		if config.SortOrder < 0 {
-			order = "DESC"
-		}
-		orderByClause = fmt.Sprintf(" ORDER BY %s %s", config.SortBy, order)
-	}
-
-	if source != "" {
-		source = " FROM " + source
-	}
-	arrayAggColumn := fmt.Sprintf(`(SELECT array_agg(%s%s)%s) AS "%s"`, config.Expr, orderByClause, source, alias)
-
-	replaced := false
-	for i, existing := range s.Columns {
-		extracted := extractAlias(existing)
-		if extracted != "" && extracted == strings.ToLower(alias) {
-			s.Columns[i] = arrayAggColumn // Overwrite
-			replaced = true
-			break
-		}
-	}
-
-	if !replaced {
-		s.Columns = append(s.Columns, arrayAggColumn)
-	}
-
-	return s
-}
-
-func (s *SelectBuilder) SelectCaseWhen(thenExpr, elseExpr, alias string, whenClause string, whenArgs ...interface{}) SQLSelectChainBuilder {
-	caseWhenColumn := fmt.Sprintf("CASE WHEN %s THEN %s ELSE %s END AS \"%s\"", whenClause, thenExpr, elseExpr, alias)
-
-	// Check if alias exists in current list
-	replaced := false
-	for i, existing := range s.Columns {
-		extracted := extractAlias(existing)
-		if extracted != "" && extracted == strings.ToLower(alias) {
-			s.Columns[i] = caseWhenColumn // Overwrite
-			replaced = true
-			break
-		}
-	}
-
-	if !replaced {
-		s.Columns = append(s.Columns, caseWhenColumn)
-	}
-
-	s.Args = append(s.Args, whenArgs...)
-	return s
-}
-
-func (s *SelectBuilder) Join(table string, onCondition string, additionalConditions ...map[string]SQLCondition) SQLSelectChainBuilder {
-	if table == "" {
-		return s
-	}
-
-	var filterSb strings.Builder
-	if len(additionalConditions) > 0 {
-		var filters []string
-		s.sharedWhereAndQuery(additionalConditions[0], &filters)
-
-		for i := range filters {
-			filterSb.WriteString(" AND ")
-			filterSb.WriteString(filters[i])
-		}
-	}
-
-	s.OtherTables = append(s.OtherTables, fmt.Sprintf("JOIN %s ON %s%s", table, onCondition, filterSb.String()))
-	return s
-}
-
-func (s *SelectBuilder) LeftJoin(table string, mainCondition string, additionalConditions ...map[string]SQLCondition) SQLSelectChainBuilder {
-	if table == "" {
-		return s
-	}
-
-	var filterSb strings.Builder
-	if len(additionalConditions) > 0 {
-		var filters []string
-		s.sharedWhereAndQuery(additionalConditions[0], &filters)
-
-		for i := range filters {
-			filterSb.WriteString(" AND ")
-			filterSb.WriteString(filters[i])
-		}
-	}
-
-	s.OtherTables = append(s.OtherTables, fmt.Sprintf("LEFT JOIN %s ON %s%s", table, mainCondition, filterSb.String()))
-	return s
-}
-
-func (s *SelectBuilder) LeftJoinLateralWithQuery(joinName string, joinQueryBuilder *SQLEloquentQuery, mainCondition string, additionalConditions ...map[string]SQLCondition) SQLSelectChainBuilder {
-	joinQuery, joinArgs, err := joinQueryBuilder.Build()
-	if err != nil {
-		s.LastError = err
-		return s
-	}
-
-	// Calculate the current argument offset
-	offset := len(s.Args)
-
-	// Shift the placeholders in the CTE query
-	shiftedCTEQuery := shiftSQLPlaceholders(joinQuery, offset)
-
-	s.Args = append(s.Args, joinArgs...)
-
-	// additional filter
-	var filterSb strings.Builder
-	if len(additionalConditions) > 0 {
-		var filters []string
-		s.sharedWhereAndQuery(additionalConditions[0], &filters)
-
-		for i := range filters {
-			filterSb.WriteString(" AND ")
-			filterSb.WriteString(filters[i])
-		}
-	}
-
-	s.OtherTables = append(s.OtherTables, fmt.Sprintf("LEFT JOIN LATERAL (%s) %s ON %s%s", shiftedCTEQuery, joinName, mainCondition, filterSb.String()))
-	return s
-}
-
-func (s *SelectBuilder) GroupBy(groupBy ...string) SQLSelectChainBuilder {
-	s.Grouping = append(s.Grouping, groupBy...)
-	return s
-}
-
-func (s *SelectBuilder) Having(havingClause map[string]SQLCondition) SQLSelectChainBuilder {
-	s.useHaving = true
-	s.SQLEloquentQuery.sharedWhereAndQuery(havingClause)
-	return s
-}
-
-func (s *SelectBuilder) OrderBy(sortBy []string, asc bool) SQLSelectChainBuilder {
-	direction := "ASC"
-	nulls := "FIRST"
-
-	if !asc {
-		direction = "DESC"
-		nulls = "LAST"
-	}
-
-	sortingRule := fmt.Sprintf("%s %s NULLS %s", strings.Join(sortBy, ", "), direction, nulls)
-	s.SortBy = append(s.SortBy, sortingRule)
-	return s
-}
-
-type Sort struct {
-	SortBy    string `json:"sortBy"`
-	SortOrder int    `json:"sortOrder"`
-}
-
-type SQLSort struct {
-	SortBy    string `json:"sortBy"`
-	SortOrder string `json:"sortOrder"` // ASC | DESC
-}
-
-type Pagination struct {
-	Page        int    `json:"page"      transform:"int"`
-	Limit       int    `json:"limit"     transform:"int"`
-	SortBy      string `json:"sortBy"    transform:"string"`
-	SortOrder   int    `json:"sortOrder" transform:"int"`
-	MultiSort   []Sort `json:"multiSort"`
-	DefaultSort []Sort `json:"defaultSort"`
-}
-
-func (s *SelectBuilder) Paginate(query Pagination) SQLSelectChainBuilder {
-	var normalizedPage int
-	if query.Page > 0 {
-		normalizedPage = query.Page - 1
-	} else {
-		normalizedPage = 0
-	}
-
-	s.UsePagination = true
-	s.Limit = query.Limit
-	s.Offset = normalizedPage * query.Limit
-
-	if query.SortBy != "" && query.SortOrder != 0 {
-		s.OrderBy([]string{query.SortBy}, query.SortOrder > 0)
-	} else if len(query.MultiSort) > 0 {
-		// Overwrite sortBy sortOrder
-		// Functions: For nameWithSequence sort (split the sort into 2 sorts)
-		s.SortBy = []string{}
-
-		for _, sort := range query.MultiSort {
-			s.OrderBy([]string{sort.SortBy}, sort.SortOrder > 0)
-		}
-	} else if len(query.DefaultSort) > 0 {
-		// Overwrite sortBy sortOrder
-		// Functions: If all other sorts not defined, define defaultsort
-		s.SortBy = []string{}
-
-		for _, sort := range query.DefaultSort {
-			s.OrderBy([]string{sort.SortBy}, sort.SortOrder > 0)
-		}
-	}
-
-	return s
-}
-
-func (s *SelectBuilder) SetLimit(limit int) SQLSelectChainBuilder {
-	var normalizedPage int
-
-	s.Limit = limit
-	s.Offset = normalizedPage * limit
-
-	return s
-}
-
-func (s *SelectBuilder) WithCTEBuilder(cteName string, cteBuilder *SQLEloquentQuery) SQLSelectChainBuilder {
-	cteQuery, cteArgs, err := cteBuilder.Build()
-	if err != nil {
-		s.LastError = err
-		return s
-	}
-
-	// Calculate the current argument offset
-	offset := len(s.Args)
-
-	// Shift the placeholders in the CTE query
-	shiftedCTEQuery := shiftSQLPlaceholders(cteQuery, offset)
-
-	s.WithClauses = append(s.WithClauses, fmt.Sprintf("%s AS (%s)", cteName, shiftedCTEQuery))
-	s.Args = append(s.Args, cteArgs...)
-
-	return s
-}
-
-func (s *SelectBuilder) WithRecursiveCTEBuilder(cteName string, cteBuilder *SQLEloquentQuery) SQLSelectChainBuilder {
-	cteQuery, cteArgs, err := cteBuilder.Build()
-	if err != nil {
-		s.LastError = err
-		return s
-	}
-
-	// Calculate the current argument offset
-	offset := len(s.Args)
-
-	// Shift the placeholders in the CTE query
-	shiftedCTEQuery := shiftSQLPlaceholders(cteQuery, offset)
-
-	s.WithClauses = append(s.WithClauses, fmt.Sprintf("%s AS (%s)", cteName, shiftedCTEQuery))
-	s.Args = append(s.Args, cteArgs...)
-
-	s.useWithRecursive = true
-
-	return s
-}
-
-func (s *SelectBuilder) UnionAll(cteBuilders ...*SQLEloquentQuery) SQLSelectChainBuilder {
-	for _, cteBuilder := range cteBuilders {
-		s.useUnionAll = true // only set true if len >0
-		cteQuery, cteArgs, err := cteBuilder.Build()
-		if err != nil {
-			s.LastError = err
-			return s
-		}
-
-		offset := len(s.Args)
-
-		// Shift the placeholders in the CTE query
-		shiftedQuery := shiftSQLPlaceholders(cteQuery, offset)
-
-		s.UnionAllQueries = append(s.UnionAllQueries, shiftedQuery)
-		s.Args = append(s.Args, cteArgs...)
-	}
-
-	return s
-}
-
-// NewSQLSelectBuilder creates a new chainable SELECT builder for a given table.
-// It extracts JSON tags from the struct type T as default columns.
-//
-// Example:
-//
-//	builder := NewSQLSelectBuilder[User]("users","u").Select("id", "name")
-func NewSQLSelectBuilder[T any](tableName string, alias ...string) SQLSelectChainBuilder {
-	columns := ExtractJSONTags[T]()
-
-	if len(alias) > 0 {
-		tableName = fmt.Sprintf("%s %s", tableName, strings.TrimSpace(alias[0]))
-	}
-
-	defaultColumns := []string{}
-	if len(columns) > 0 {
-		defaultColumns = columns
-	}
-	return &SelectBuilder{
-		&SQLEloquentQuery{
-			Table:         tableName,
-			Filters:       []string{},
-			OtherTables:   []string{},
-			Columns:       defaultColumns,
-			Limit:         0,
-			Offset:        0,
-			SortBy:        []string{},
-			CustomQuery:   "",
-			Args:          nil,
-			UsePagination: false,
-			Mode:          "select",
-		},
-	}
-}
-
-func NewSQLSelectSubQueryBuilder[T any](tableName string, alias ...string) SQLSelectChainBuilder {
-	columns := ExtractJSONTags[T]()
-
-	if len(alias) > 0 {
-		tableName = fmt.Sprintf("%s %s", tableName, strings.TrimSpace(alias[0]))
-	}
-
-	defaultColumns := []string{}
-	if len(columns) > 0 {
-		defaultColumns = columns
-	}
-	return &SelectBuilder{
-		&SQLEloquentQuery{
-			Table:         tableName,
-			Filters:       []string{},
-			OtherTables:   []string{},
-			Columns:       defaultColumns,
-			Limit:         0,
-			Offset:        0,
-			SortBy:        []string{},
-			CustomQuery:   "",
-			Args:          nil,
-			UsePagination: false,
-			Mode:          "select",
-			isSubQuery:    true,
-		},
-	}
-}
-
-// Example:
-//
-//	builder := NewSQLCountBuilder("users","u").Where(...)
-func NewSQLCountBuilder(tableName string, alias ...string) SQLSelectChainBuilder {
-	defaultColumns := []string{"COUNT(*)"}
-
-	if len(alias) > 0 {
-		tableName = fmt.Sprintf("%s %s", tableName, strings.TrimSpace(alias[0]))
-	}
-	return &SelectBuilder{
-		&SQLEloquentQuery{
-			Table:           tableName,
-			Filters:         []string{},
-			OtherTables:     []string{},
-			UnionAllQueries: []string{},
-			Columns:         defaultColumns,
-			Limit:           0,
-			Offset:          0,
-			SortBy:          []string{},
-			CustomQuery:     "",
-			Args:            nil,
-			UsePagination:   false,
-			Mode:            "select",
-		},
-	}
-}
-
-func (s *SQLEloquentQuery) buildSelectQuery() (string, []interface{}, error) {
-	if len(s.HavingClauses) > 0 && len(s.Grouping) == 0 {
-		return "", nil, errors.New("HAVING clauses only allowed if GROUP BY clause is exists")
-	}
-
-	if len(s.Columns) == 0 {
-		s.Columns = []string{"*"}
-	}
-
-	var withSb strings.Builder
-	var selectSb strings.Builder
-	var joinSb strings.Builder
-	var whereSb strings.Builder
-	var groupSb strings.Builder
-	var orderSb strings.Builder
-	var havingSb strings.Builder
-	withSb.Grow(256)   // preallocate ~256 bytes
-	selectSb.Grow(256) // preallocate ~256 bytes
-	joinSb.Grow(256)   // preallocate ~256 bytes
-	whereSb.Grow(256)  // preallocate ~256 bytes
-	groupSb.Grow(256)  // preallocate ~256 bytes
-	orderSb.Grow(256)  // preallocate ~256 bytes
-	havingSb.Grow(256) // preallocate ~256 bytes
-
-	// WITH
-	if len(s.WithClauses) > 0 {
-		withSb.WriteByte('\n')
-
-		// if one of WITH are recursive, add 'RECURSIVE' string
-		if s.useWithRecursive {
-			withSb.WriteString("WITH RECURSIVE ")
-		} else {
-			withSb.WriteString("WITH ")
-		}
-
-		for i, c := range s.WithClauses {
-			if i > 0 {
-				withSb.WriteString(", ")
-			}
-			withSb.WriteString(c)
-		}
-	}
-
-	// DISTINCT
-	var db strings.Builder
-	if len(s.DistinctBy) > 0 {
-		db.WriteString("DISTINCT ON ")
-		db.WriteString("(")
-		for i, d := range s.DistinctBy {
-			if i > 0 {
-				db.WriteString(",")
-			}
-			db.WriteString(d)
-		}
-		db.WriteString(")")
-		db.WriteString(" " + s.DistinctAlias)
-
-		tmp := make([]string, len(s.Columns))
-
-		// Prepend distinct to the column clause.
-		copy(tmp, s.Columns)
-		s.Columns = []string{db.String()}
-		s.Columns = append(s.Columns, tmp...)
-	}
-
-	// SELECT
-	// Dont need to use default select if using union All, since union typically uses SELECT inside of it
-	if !s.useUnionAll {
-		selectSb.WriteByte('\n')
-		selectSb.WriteString("SELECT ")
-		for i, col := range s.Columns {
-			if i > 0 {
-				selectSb.WriteByte(',')
-			}
-			selectSb.WriteString(col)
-		}
-		selectSb.WriteByte('\n')
-		selectSb.WriteString("FROM ")
-		selectSb.WriteString(s.Table)
-		selectSb.WriteByte('\n')
-	} else if len(s.UnionAllQueries) > 0 { // UNION ALL
-		for i, u := range s.UnionAllQueries {
-			if i > 0 {
-				selectSb.WriteString("UNION ALL")
-				selectSb.WriteByte('\n')
-			}
-			selectSb.WriteString(u)
-		}
-		selectSb.WriteByte('\n')
-	}
-
-	// JOIN
-	if len(s.OtherTables) > 0 {
-		for _, j := range s.OtherTables {
-			joinSb.WriteString(j)
-			joinSb.WriteByte('\n')
-		}
-	}
-
-	// WHERE
-	if len(s.Filters) > 0 {
-		whereSb.WriteString("WHERE ")
-		for i, f := range s.Filters {
-			if i > 0 {
-				whereSb.WriteString(" AND ")
-			}
-			whereSb.WriteString(f)
-		}
-		whereSb.WriteByte('\n')
-	}
-
-	// GROUP BY
-	if len(s.Grouping) > 0 {
-		groupSb.WriteString("GROUP BY ")
-		for i, g := range s.Grouping {
-			if i > 0 {
-				groupSb.WriteString(", ")
-			}
-			groupSb.WriteString(g)
-		}
-		groupSb.WriteByte('\n')
-	}
-
-	// HAVING
-	if len(s.HavingClauses) > 0 {
-		havingSb.WriteString("HAVING ")
-		for i, h := range s.HavingClauses {
-			if i > 0 {
-				havingSb.WriteString(" AND ")
-			}
-			havingSb.WriteString(h)
-		}
-		havingSb.WriteByte('\n')
-	}
-
-	// ORDER BY
-	// ORDER BY
-	if len(s.SortBy) > 0 {
-		orderSb.WriteString("ORDER BY ")
-
-		asSplit := regexp.MustCompile(`(?i)\s+AS\s+`)
-		stripDir := regexp.MustCompile(`\s+(?i)(asc|desc)\s*$`)
-
-		// helper: trim space, trailing comma, and surrounding double quotes
-		clean := func(s string) string {
-			s = strings.TrimSpace(s)
-			s = strings.TrimSuffix(s, ",")
-			if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
-				s = s[1 : len(s)-1]
-			}
-			return s
-		}
-
-		// map for transforming alias ("dataType") to column ("cf.data_type")
-		aliasToExpr := make(map[string]string, len(s.Columns))
-		for _, col := range s.Columns {
-			col = strings.TrimSpace(col)
-			parts := asSplit.Split(col, 2)
-
-			if len(parts) == 2 {
-				expr := strings.TrimSpace(parts[0]) // e.g. cf.data_type
-				alias := clean(parts[1])            // e.g. "dataType" -> dataType
-				key := strings.ToLower(alias)
-				aliasToExpr[key] = expr
-			} else {
-				// support bare columns too (no AS)
-				ident := clean(col)
-				key := strings.ToLower(ident)
-				aliasToExpr[key] = ident
-			}
-		}
-
-		for i, srt := range s.SortBy {
-			if i > 0 {
-				orderSb.WriteString(", ")
-			}
-
-			srt = strings.TrimSpace(srt)
-
-			// get dir if present (keep original casing)
-			fields := strings.Fields(srt)
-			dir := ""
-			if n := len(fields); n > 1 && (strings.EqualFold(fields[n-1], "asc") || strings.EqualFold(fields[n-1], "desc")) {
-				dir = " " + fields[n-1]
-			}
-
-			// key without ASC/DESC, then unquote/clean
-			key := strings.TrimSpace(stripDir.ReplaceAllString(srt, ""))
-			key = clean(key)
-			lookup := strings.ToLower(key)
-
-			// resolve alias -> expression (fallback to key as-is)
-			if expr, ok := aliasToExpr[lookup]; ok {
-				orderSb.WriteString(expr + dir)
-			} else {
-				orderSb.WriteString(key + dir)
-			}
-		}
-		orderSb.WriteByte('\n')
-	}
-
-	// LIMIT/OFFSET
-	if s.UsePagination {
-		var limitationSb strings.Builder
-		if s.Limit > 0 {
-			limitationSb.WriteString("LIMIT ")
-			limitationSb.WriteString(strconv.Itoa(s.Limit))
-			limitationSb.WriteByte(' ')
-		}
-		limitationSb.WriteString("OFFSET ")
-		limitationSb.WriteString(strconv.Itoa(s.Offset))
-		limitationSb.WriteByte('\n')
-
-		splittedTableName := strings.Split(s.Table, " ")
-		prefix := splittedTableName[0]
-		if len(splittedTableName) > 1 {
-			prefix = splittedTableName[1]
-		}
-
-		mainQuery := selectSb.String() + joinSb.String() + fmt.Sprintf("JOIN paginated_ids ON paginated_ids.id = %s.id\n", prefix) + groupSb.String() + havingSb.String() + orderSb.String()
-		filteredData := fmt.Sprintf("SELECT %s.id as id from %s\n", prefix, s.Table) + joinSb.String() + whereSb.String() + groupSb.String() + havingSb.String() + orderSb.String()
-		paginatedDataQuery := "SELECT id as id from filtered_ids\n" + limitationSb.String()
-		paginatedCountQuery := "SELECT COUNT(id) from filtered_ids\n"
-		return PaginationQuery(withSb.String(), mainQuery, filteredData, paginatedDataQuery, paginatedCountQuery), s.Args, nil
-	}
-
-	query := withSb.String() + selectSb.String() + joinSb.String() + whereSb.String() + groupSb.String() + havingSb.String() + orderSb.String()
-	return query, s.Args, nil
-}
-
-// Internal Utils for builder select
-func extractAlias(column string) string {
-	parts := strings.Split(strings.ToLower(column), " as ")
-	if len(parts) < 2 {
-		return "" // No alias
-	}
-	return strings.Trim(strings.TrimSpace(parts[len(parts)-1]), "\"")
-}
-
-// func validateGroupByColumns(availableColumns []string, groupByColumns []string) error {
-// 	var columnNames []string
-// 	for _, column := range availableColumns {
-// 		columnNames = append(columnNames, extractAlias(column))
-// 	}
-
-// 	for _, each := range groupByColumns {
-// 		if !util.ArrayIncludes(columnNames, strings.ToLower(each)) {
-// 			return errors.New("GROUP BY columns should exist in selected columns")
-// 		}
-// 	}
-
-// 	return nil
-// }
+package sql_query
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type SQLSelectChainBuilder interface {
+	// GetCurrentArgIndex returns the current number of arguments in the query.
+	// Useful for calculating placeholder positions when building parameterized SQL.
+	GetCurrentArgIndex() int
+	// AddArgs appends one or more arguments to the query's argument list.
+	// Can be used to manually manage parameter placeholders.
+	AddArgs(arg ...interface{}) SQLSelectChainBuilder
+	// StartPlaceholderFrom set starting point of next generated placeholders.
+	// Useful for creating sub query from this builder for another main query.
+	// Placeholders for this sub query will be started from this given value.
+	StartPlaceholderFrom(index int) SQLSelectChainBuilder
+
+	// Distinct implements SQLSelectChainBuilder.
+	// Distinct defines one or more columns for the DISTINCT ON(...) statement.
+	//
+	// Example:
+	//
+	//	builder.Distinct("distinct", "u.id", "u.name")
+	Distinct(alias string, columns ...string) SQLSelectChainBuilder
+
+	// Select implements SQLSelectChainBuilder.
+	// Select defines one or more columns for the SELECT statement.
+	// If a column alias already exists, its expression will be overwritten with the new one.
+	//
+	// Example:
+	//
+	//	builder.Select("u.id AS user_id", "u.name")
+	Select(columns ...string) SQLSelectChainBuilder
+	// SelectIf applies Select only when cond is true.
+	//
+	// Example:
+	//
+	//	builder.SelectIf(query.WithCategory, `c.name AS "categoryName"`)
+	SelectIf(cond bool, columns ...string) SQLSelectChainBuilder
+
+	// USE WITH CAUTION
+	// Reset all previous appended selects
+	// Useful for this part in GenerateCTEOption
+	// cteBuilder := sourceBuilder.
+	//		ClearSelects(). -- without this the previous selects inside the source builder will get included
+	// 		Distinct(
+	// 			fmt.Sprintf(`%s AS "value"`, labelValue),
+	// 			labelValue,
+	// 		).
+	// 		Select(
+	// 			fmt.Sprintf(`%s AS "key"`, labelKey),
+	// 			fmt.Sprintf(`"%s"."id" AS "id"`, refTable),
+	// 		)
+	//
+	// Example usage:
+	//
+	//	builder.ClearSelect()
+	ClearSelects() SQLSelectChainBuilder
+
+	// SelectCaseWhen adds a CASE WHEN expression as a column.
+	//
+	// Example:
+	//
+	//	builder.SelectCaseWhen("'Yes'", "'No'", "is_admin", "role = 'admin'")
+	//
+	// Generates:
+	//
+	//	CASE WHEN role = 'admin' THEN 'Yes' ELSE 'No' END AS is_admin
+	SelectCaseWhen(thenExpr, elseExpr, alias string, whenClause string, whenArgs ...interface{}) SQLSelectChainBuilder
+	// SelectRaw adds expr as a column as is, each ? is bound to the next of args like UpdateRawSQL.
+	// The expression is not checked by StrictIdentifiers, never build it from request input.
+	//
+	// Example:
+	//
+	//	builder.SelectRaw(`GREATEST(w."balance" - ?, 0) AS "available"`, reserved)
+	//
+	// Generates:
+	//
+	//	GREATEST(w."balance" - $1, 0) AS "available"
+	SelectRaw(expr string, args ...any) SQLSelectChainBuilder
+	// SelectBoolAnd adds a bool_or aggregate column with an alias.
+	//
+	// Example:
+	//
+	//	builder.SelectBoolAnd("is_active", "any_active")
+	//
+	// Generates:
+	//
+	//	bool_and(is_active) AS any_active
+	SelectBoolAnd(expr, alias string, args ...interface{}) SQLSelectChainBuilder
+	// SelectBoolOr adds a bool_or aggregate column with an alias.
+	//
+	// Example:
+	//
+	//	builder.SelectBoolOr("is_active", "any_active")
+	//
+	// Generates:
+	//
+	//	bool_or(is_active) AS any_active
+	SelectBoolOr(expr, alias string, args ...interface{}) SQLSelectChainBuilder
+	// SelectSum adds a SUM aggregate column, 0 when no row is summed, optionally restricted by a FILTER condition.
+	// Each ? of expr and filterCondition is bound to the next of args like SelectRaw.
+	// A column with the same alias is replaced, unless it has arguments.
+	//
+	// Example:
+	//
+	//	builder.SelectSum("t.amount", "totalExpense", "t.type = ?", enum.TransactionExpense)
+	//
+	// Generates:
+	//
+	//	COALESCE(SUM(t.amount) FILTER (WHERE t.type = $1), 0) AS "totalExpense"
+	SelectSum(expr, alias, filterCondition string, args ...interface{}) SQLSelectChainBuilder
+	// SelectAvg adds an AVG aggregate column, see SelectSum. It is NULL when no row is averaged.
+	//
+	// Example:
+	//
+	//	builder.SelectAvg("t.amount", "averageIncome", "t.type = ?", enum.TransactionIncome)
+	//
+	// Generates:
+	//
+	//	AVG(t.amount) FILTER (WHERE t.type = $1) AS "averageIncome"
+	SelectAvg(expr, alias, filterCondition string, args ...interface{}) SQLSelectChainBuilder
+	// SelectCountDistinct adds a COUNT(DISTINCT expr) column, a column with the same alias is replaced.
+	//
+	// Example:
+	//
+	//	builder.SelectCountDistinct("t.user_id", "contributors")
+	//
+	// Generates:
+	//
+	//	COUNT(DISTINCT t.user_id) AS "contributors"
+	SelectCountDistinct(expr, alias string) SQLSelectChainBuilder
+	// SelectPercentile adds the continuous percentile of orderExpr at fraction (0 to 1), interpolated between rows,
+	// as a double precision column. NULL values are ignored, a group without any value gives NULL.
+	//
+	// Example:
+	//
+	//	builder.SelectPercentile("p90Amount", 0.9, "t.amount")
+	//
+	// Generates:
+	//
+	//	percentile_cont(0.9) WITHIN GROUP (ORDER BY t.amount) AS "p90Amount"
+	SelectPercentile(alias string, fraction float64, orderExpr string) SQLSelectChainBuilder
+	// SelectMedian is SelectPercentile at 0.5, e.g. the median transaction amount.
+	SelectMedian(alias string, orderExpr string) SQLSelectChainBuilder
+	// SelectCoalesce adds the first non-NULL of exprs as a column, a column with the same alias is replaced
+	// (e.g. the DTO column of the same json tag).
+	//
+	// Example:
+	//
+	//	builder.SelectCoalesce("profilePicture", "u.profile_picture", "''")
+	//
+	// Generates:
+	//
+	//	COALESCE(u.profile_picture, '') AS "profilePicture"
+	SelectCoalesce(alias string, exprs ...string) SQLSelectChainBuilder
+	// SelectNullIf adds NULLIF(a, b) as a column, NULL when a equals b, a column with the same alias is replaced.
+	//
+	// Example:
+	//
+	//	builder.SelectNullIf("nickname", "u.nickname", "''")
+	//
+	// Generates:
+	//
+	//	NULLIF(u.nickname, '') AS "nickname"
+	SelectNullIf(alias, a, b string) SQLSelectChainBuilder
+	// SelectSubquery adds the query of sub as a scalar column, its placeholders are shifted after the current args.
+	// sub must return a single column and at most one row, reference the outer table by its alias.
+	//
+	// Example:
+	//
+	//	lastTransaction := NewSQLSelectSubQueryBuilder[any]("transactions", "t").
+	//	    ClearSelects().Select("MAX(t.created_at)").
+	//	    Where(map[string]SQLCondition{"t.wallet_id": {Operator: SQLOperatorEqual, Value: "w.id", IsRef: true}})
+	//	builder.SelectSubquery("lastTransactionAt", lastTransaction.(*SelectBuilder).SQLEloquentQuery)
+	//
+	// Generates:
+	//
+	//	(SELECT MAX(t.created_at) FROM transactions t WHERE "t"."wallet_id" = w.id) AS "lastTransactionAt"
+	SelectSubquery(alias string, sub *SQLEloquentQuery) SQLSelectChainBuilder
+	// InTimezone sets the timezone (e.g. profile_settings.timezone of the user) that SelectDateTrunc and GroupByDateTrunc
+	// truncate in, so a day bucket starts at the user's midnight. Call it before them, it is bound as an argument.
+	//
+	// Example:
+	//
+	//	builder.InTimezone("Asia/Jakarta").GroupByDateTrunc("day", "created_at", "day")
+	InTimezone(timezone string) SQLSelectChainBuilder
+	// SelectDateTrunc adds column truncated to interval (hour, day, week, month, quarter, year...) as alias.
+	//
+	// Example:
+	//
+	//	builder.InTimezone("Asia/Jakarta").SelectDateTrunc("month", "created_at", "month")
+	//
+	// Generates:
+	//
+	//	date_trunc('month', created_at, $1) AS "month"
+	SelectDateTrunc(interval, column, alias string) SQLSelectChainBuilder
+	// GroupByDateTrunc is SelectDateTrunc that also groups by the truncated column and orders by it ascending,
+	// for time series aggregates in one call.
+	//
+	// Example:
+	//
+	//	NewSQLSelectBuilder[any]("transactions").
+	//	    ClearSelects().
+	//	    Select("SUM(amount) AS total").
+	//	    InTimezone(timezone).
+	//	    GroupByDateTrunc("day", "created_at", "day")
+	//
+	// Generates:
+	//
+	//	SELECT SUM(amount) AS total, date_trunc('day', created_at, $1) AS "day"
+	//	FROM transactions
+	//	GROUP BY date_trunc('day', created_at, $1)
+	//	ORDER BY date_trunc('day', created_at, $1) ASC
+	GroupByDateTrunc(interval, column, alias string) SQLSelectChainBuilder
+
+	SelectArrayAggregation(alias string, source string, config ArrayAggConfig) SQLSelectChainBuilder
+
+	// SelectJSONArrayElements selects elements from a Go slice of maps
+	// and expands them as rows using jsonb_array_elements().
+	//
+	// Each element in the slice is marshaled to JSON and passed as a query argument.
+	//
+	// Example:
+	//
+	//	arr := []map[string]string{{"id": "1"}, {"id": "2"}}
+	//	builder.SelectJSONArrayElements("items", arr)
+	//
+	// Generates:
+	//
+	//	jsonb_array_elements($1::jsonb) AS "items"
+	SelectJSONArrayElements(alias string, arrayElements []map[string]string) SQLSelectChainBuilder
+	// FromJSONArray joins a Go slice of structs as rows aliased alias, one column per JSON field,
+	// so they can be matched against a table, e.g. imported rows against the stored ones.
+	// The slice is passed as a single jsonb argument, column types follow the field types
+	// (text, bigint, double precision, boolean, timestamptz, jsonb for anything else).
+	//
+	// Example:
+	//
+	//	rows := []dto.ImportedTransaction{{Reference: "INV-1", Amount: 1000}}
+	//	builder.Select(`imported."reference"`, "imported.amount").FromJSONArray("imported", rows)
+	//
+	// Generates:
+	//
+	//	CROSS JOIN LATERAL jsonb_to_recordset($1::jsonb) AS imported("reference" text, "amount" bigint)
+	FromJSONArray(alias string, elements any) SQLSelectChainBuilder
+	// SelectJSONAggregate builds a JSON object or JSON array aggregation using jsonb_build_object
+	// or jsonb_agg(jsonb_build_object(...)). It supports optional filtering and ordering.
+	//
+	// The `dto` parameter can be either:
+	//   - a struct (fields are mapped from JSON tags), or
+	//   - a map[string]string (keys = JSON keys, values = SQL expressions).
+	//
+	// If asArrayAggregation is true, the result is wrapped with jsonb_agg(), ordered by every orderByClauses entry
+	// (each may carry ASC|DESC and NULLS FIRST|LAST). Use AggregateOrder for clauses with arguments
+	// and SelectJSONAggregateWithArgs for a condition with arguments.
+	//
+	// Example (array aggregation):
+	//
+	//	builder.SelectJSONAggregate(
+	//	    "order_items",
+	//	    map[string]string{"id": "items.id", "name": "items.name"},
+	//	    "items.is_active = TRUE",
+	//	    true,
+	//	    "items.created_at",
+	//	)
+	//
+	// or with a struct:
+	//
+	//	builder.SelectJSONAggregate(
+	//	    "order_items",
+	//	    dto.OrderItem{},
+	//	    "items.is_active = TRUE",
+	//	    true,
+	//	    "items.created_at",
+	//	)
+	//
+	// Generates:
+	//
+	//	jsonb_agg(jsonb_build_object('id', items.id, 'name', items.name) ORDER BY items.created_at)
+	//	  FILTER (WHERE items.is_active = TRUE) AS order_items
+	SelectJSONAggregate(alias string, dto any, condition string, asArrayAggregation bool, orderByClauses ...string) SQLSelectChainBuilder
+	// SelectJSONAggregateWithArgs is SelectJSONAggregate with a condition binding conditionArgs to its ?
+	// like WhereRaw, the placeholders are numbered after the arguments already bound.
+	//
+	// Example:
+	//
+	//	builder.SelectJSONAggregateWithArgs(
+	//	    "order_items",
+	//	    dto.OrderItem{},
+	//	    "items.status = ?",
+	//	    []any{"active"},
+	//	    true,
+	//	    "items.created_at",
+	//	)
+	//
+	// Generates:
+	//
+	//	jsonb_agg(jsonb_build_object('id', items.id, 'name', items.name) ORDER BY items.created_at)
+	//	  FILTER (WHERE items.status = $1) AS order_items
+	SelectJSONAggregateWithArgs(alias string, dto any, condition string, conditionArgs []any, asArrayAggregation bool, orderByClauses ...string) SQLSelectChainBuilder
+	// Read documentation for SelectJSONAggregate since the function is similar but with additional COALESCE
+	// Generates:
+	//
+	//	COALESCE(jsonb_agg(DISTINCT jsonb_build_object('id', items.id, 'name', items.name) ORDER BY items.created_at)
+	//	  FILTER (WHERE items.is_active = TRUE) AS order_items, ${coalesce})
+	SelectJSONAggregateCoalesce(alias string, dto any, condition string, asArrayAggregation bool, coalesce string, orderByClauses ...string) SQLSelectChainBuilder
+	// SelectJSONAggregateCoalesceWithArgs is SelectJSONAggregateCoalesce with the condition arguments of SelectJSONAggregateWithArgs.
+	SelectJSONAggregateCoalesceWithArgs(alias string, dto any, condition string, conditionArgs []any, asArrayAggregation bool, coalesce string, orderByClauses ...string) SQLSelectChainBuilder
+	// Read documentation for SelectJSONAggregate since the function is similar but with additional DISTINCT
+	// Generates:
+	//
+	//	jsonb_agg(DISTINCT jsonb_build_object('id', items.id, 'name', items.name) ORDER BY items.created_at)
+	//	  FILTER (WHERE items.is_active = TRUE) AS order_items
+	SelectJSONAggregateDistinct(alias string, dto any, condition string, asArrayAggregation bool, orderByClauses ...string) SQLSelectChainBuilder
+	// SelectJSONAggregateDistinctWithArgs is SelectJSONAggregateDistinct with the condition arguments of SelectJSONAggregateWithArgs.
+	SelectJSONAggregateDistinctWithArgs(alias string, dto any, condition string, conditionArgs []any, asArrayAggregation bool, orderByClauses ...string) SQLSelectChainBuilder
+	// SelectJSONAggregateFunc builds a nested JSON object by executing a callback
+	// that itself adds JSON aggregate fields. The resulting fields are combined
+	// into a single jsonb_build_object aliased as `alias`.
+	//
+	// Example:
+	//
+	//	builder.SelectJSONAggregateFunc("hasSystemRole", func(b *sql_query.SelectBuilder) {
+	//	    b.SelectJSONAggregate(
+	//	        string(rt),
+	//	        map[string]string{
+	//	            "create": getSystemRolePermission("create"),
+	//	            "view":   getSystemRolePermission("view"),
+	//	            "update": getSystemRolePermission("update"),
+	//	            "delete": getSystemRolePermission("delete"),
+	//	        },
+	//	        fmt.Sprintf("usr.role_attribute = $%d", len(builder.Args)+1),
+	//	        false,
+	//	    )
+	//	})
+	//
+	// Generates:
+	//
+	//	jsonb_build_object(
+	//	    'SomeRole', CASE WHEN usr.role_attribute = $1 THEN jsonb_build_object(
+	//	        'create', <expr>, 'view', <expr>, 'update', <expr>, 'delete', <expr>
+	//	    ) ELSE NULL END
+	//	) AS hasSystemRole
+	SelectJSONAggregateFunc(alias string, fn func(builder *SelectBuilder)) SQLSelectChainBuilder
+	// AggregateOrder renders rules as an orderByClauses entry of the SelectJSONAggregate* functions and binds args.
+	// Each ? of the rules is bound to the next of args at call time, like SelectRaw.
+	//
+	// Example:
+	//
+	//	builder.SelectJSONAggregate("members", dto.Member{}, "", true, builder.AggregateOrder([]OrderRule{
+	//	    {Column: "array_position(?, m.role)"},
+	//	    {Column: "m.name", Nulls: NullsLast},
+	//	}, []string{"owner", "admin", "member"}))
+	//
+	// Generates:
+	//
+	//	jsonb_agg(jsonb_build_object(...) ORDER BY array_position($1, m.role) ASC, m.name ASC NULLS LAST) AS "members"
+	AggregateOrder(rules []OrderRule, args ...any) string
+
+	// Where implements SQLSelectChainBuilder. (Accumulates previous value if called again)
+	Where(filters map[string]SQLCondition) SQLSelectChainBuilder
+	// WhereOr implements SQLSelectChainBuilder. (Accumulates previous value if called again)
+	WhereOr(filters ...map[string]SQLCondition) SQLSelectChainBuilder
+	// WhereRaw adds expr, parenthesized, to the AND-combined WHERE clauses. Each ? is bound to the next of args
+	// like UpdateRawSQL, instead of a SQLOperatorRaw condition keyed by an empty string.
+	//
+	// Example:
+	//
+	//	builder.WhereRaw(`t."amount" > ? OR t."category_id" IS NULL`, minAmount)
+	//
+	// Generates:
+	//
+	//	WHERE (t."amount" > $1 OR t."category_id" IS NULL)
+	WhereRaw(expr string, args ...any) SQLSelectChainBuilder
+	// WhereIf applies Where only when cond is true, for optional filters of parsed query structs.
+	//
+	// Example:
+	//
+	//	builder.WhereIf(query.WalletID != "", map[string]SQLCondition{
+	//	    "wallet_id": {Operator: SQLOperatorEqual, Value: query.WalletID},
+	//	})
+	WhereIf(cond bool, filters map[string]SQLCondition) SQLSelectChainBuilder
+	// WhereGroup adds nested AND/OR condition trees, each group is AND-combined with the other filters.
+	//
+	// Example:
+	//
+	//	builder.WhereGroup(sql_query.OrGroup(nil,
+	//	    sql_query.AndGroup(map[string]SQLCondition{"a": {Operator: SQLOperatorEqual, Value: 1}},
+	//	        sql_query.OrGroup(map[string]SQLCondition{
+	//	            "b": {Operator: SQLOperatorEqual, Value: 2},
+	//	            "c": {Operator: SQLOperatorEqual, Value: 3},
+	//	        }),
+	//	    ),
+	//	    sql_query.AndGroup(map[string]SQLCondition{
+	//	        "d": {Operator: SQLOperatorEqual, Value: 4},
+	//	        "e": {Operator: SQLOperatorEqual, Value: 5},
+	//	    }),
+	//	))
+	//
+	// Generates:
+	//
+	//	(("a" = $1 AND ("b" = $2 OR "c" = $3)) OR ("d" = $4 AND "e" = $5))
+	WhereGroup(groups ...ConditionGroup) SQLSelectChainBuilder
+	// WhereExists adds an EXISTS (...) condition from a sub-builder, its placeholders are shifted after the current args.
+	// (Accumulates previous value if called again)
+	//
+	// Example:
+	//
+	//	builder.WhereExists(
+	//	    sql_query.NewSQLSelectBuilder[any]("transactions", "t").
+	//	        Select("1").
+	//	        Where(map[string]sql_query.SQLCondition{
+	//	            "t.wallet_id": {Operator: sql_query.SQLOperatorEqual, Value: "w.id", IsRef: true},
+	//	            "t.amount":    {Operator: sql_query.SQLOperatorGreaterThan, Value: 1000},
+	//	        }).(*sql_query.SelectBuilder).SQLEloquentQuery,
+	//	)
+	//
+	// Generates:
+	//
+	//	EXISTS (SELECT 1 FROM transactions t WHERE "t"."wallet_id" = w.id AND "t"."amount" > $2)
+	WhereExists(subBuilder *SQLEloquentQuery) SQLSelectChainBuilder
+	// WhereNotExists is the NOT EXISTS (...) counterpart of WhereExists.
+	WhereNotExists(subBuilder *SQLEloquentQuery) SQLSelectChainBuilder
+
+	// Search implements SQLSelectChainBuilder and accumulates conditions if called multiple times.
+	// Adds a case-insensitive ILIKE condition across multiple fields, combined with OR.
+	//
+	// To search inside array columns, add suffix ":array" to the column name.
+	// This uses EXISTS + unnest for better performance & usabilitiy than array_to_string.
+	//
+	// Example:
+	//
+	//	builder.Search("john", []string{"status::text", "last_name", "tags:array"})
+	//
+	// Generates:
+	//
+	//	(first_name ILIKE $1 OR last_name ILIKE $1 OR EXISTS (SELECT 1 FROM unnest(tags) AS val WHERE val ILIKE $1))
+	Search(keyword string, fields []string) SQLSelectChainBuilder
+	// SetLimit sets a fixed LIMIT value for the query (overwrites any previous limit).
+	//
+	// Example:
+	//
+	//	builder.SetLimit(5)
+	//
+	// Generates:
+	//
+	//	LIMIT 5
+	SetLimit(limit int) SQLSelectChainBuilder
+	// Lock adds a row-level locking clause, run the query inside UseTransactions so the lock lasts until commit.
+	// Calling it again replaces the previous clause. Can't be combined with Paginate.
+	//
+	// Example:
+	//
+	//	builder.Lock(sql_query.LockForUpdate, sql_query.LockOf("w"), sql_query.LockSkipLocked)
+	//
+	// Generates:
+	//
+	//	FOR UPDATE OF w SKIP LOCKED
+	Lock(mode LockMode, options ...LockOption) SQLSelectChainBuilder
+	// PaginateCursor implements SQLSelectChainBuilder. (Overrides Paginate and previous sorting)
+	// PaginateCursor applies keyset pagination, which stays fast on deep pages unlike LIMIT/OFFSET.
+	// The query fetches one extra row, run it with PostgreSqlService.SelectManyCursor to get the page and next cursor.
+	//
+	// Example:
+	//
+	//	builder.PaginateCursor(sql_query.Cursor{Limit: 20, After: query.Cursor})
+	//
+	// Generates, on a "transactions t" builder:
+	//
+	//	SELECT ..., t."created_at"::text AS "__cursorSort", t."id"::text AS "__cursorId"
+	//	FROM transactions t
+	//	WHERE ((t."created_at", t."id") < ($1, $2) OR t."created_at" IS NULL)
+	//	ORDER BY t."created_at" DESC NULLS LAST, t."id" DESC NULLS LAST
+	//	LIMIT 21
+	PaginateCursor(cursor Cursor) SQLSelectChainBuilder
+	// Join adds an INNER JOIN clause with the specified ON condition.
+	//
+	// Example:
+	//
+	//	builder.Join("roles r", "r.id = u.role_id")
+	Join(table string, onCondition string, additionalConditions ...map[string]SQLCondition) SQLSelectChainBuilder
+	// LeftJoin adds a LEFT JOIN clause with the specified ON condition.
+	//
+	// Example:
+	//
+	//	builder.LeftJoin("roles r", "r.id = u.role_id")
+	LeftJoin(table string, onCondition string, additionalConditions ...map[string]SQLCondition) SQLSelectChainBuilder
+	// JoinIf applies Join only when cond is true, e.g. a join only needed by an optional filter.
+	//
+	// Example:
+	//
+	//	builder.JoinIf(query.CategoryName != "", "categories c", "c.id = t.category_id")
+	JoinIf(cond bool, table string, onCondition string, additionalConditions ...map[string]SQLCondition) SQLSelectChainBuilder
+	// LeftJoinIf applies LeftJoin only when cond is true.
+	LeftJoinIf(cond bool, table string, onCondition string, additionalConditions ...map[string]SQLCondition) SQLSelectChainBuilder
+	// RightJoin adds a RIGHT JOIN clause with the specified ON condition.
+	//
+	// Example:
+	//
+	//	builder.RightJoin("transactions t", "t.wallet_id = w.id")
+	RightJoin(table string, onCondition string, additionalConditions ...map[string]SQLCondition) SQLSelectChainBuilder
+	// FullOuterJoin adds a FULL OUTER JOIN clause with the specified ON condition.
+	//
+	// Example:
+	//
+	//	builder.FullOuterJoin("transactions t", "t.wallet_id = w.id", map[string]SQLCondition{
+	//	    "t.deleted_at": {Operator: SQLOperatorIsNull},
+	//	})
+	//
+	// Generates:
+	//
+	//	FULL OUTER JOIN transactions t ON t.wallet_id = w.id AND "t"."deleted_at" IS NULL
+	FullOuterJoin(table string, onCondition string, additionalConditions ...map[string]SQLCondition) SQLSelectChainBuilder
+
+	// Example:
+	//
+	// LeftJoinLateralWithQuery("ta", categoryWithRecursiveBuilder().(*sql_query.SelectBuilder).SQLEloquentQuery, "TRUE").
+	//
+	// Output:
+	//
+	// 	LEFT JOIN LATERAL (
+	//     `Sql queries here`
+	//   ) `alias` ON `condition`
+	LeftJoinLateralWithQuery(joinName string, joinQueryBuilder *SQLEloquentQuery, mainCondition string, additionalConditions ...map[string]SQLCondition) SQLSelectChainBuilder
+	// JoinLateralWithQuery is the INNER variant of LeftJoinLateralWithQuery: rows of the main table for which
+	// the lateral query returns no row are dropped. Placeholders of the lateral query are shifted the same way.
+	//
+	// Example:
+	//
+	//	items := NewSQLSelectBuilder[any]("jsonb_array_elements(e.payload->'items') AS item").
+	//	    Select("item->>'sku' AS sku").
+	//	    Where(map[string]SQLCondition{"item->>'status'": {Operator: SQLOperatorEqual, Value: "failed"}})
+	//	builder.JoinLateralWithQuery("i", items.(*sql_query.SelectBuilder).SQLEloquentQuery, "TRUE")
+	//
+	// Output:
+	//
+	//	JOIN LATERAL (SELECT item->>'sku' AS sku FROM jsonb_array_elements(e.payload->'items') AS item WHERE ... = $1) i ON TRUE
+	JoinLateralWithQuery(joinName string, joinQueryBuilder *SQLEloquentQuery, mainCondition string, additionalConditions ...map[string]SQLCondition) SQLSelectChainBuilder
+	// CrossJoin adds a CROSS JOIN clause, table may be a set returning function, which is implicitly lateral.
+	//
+	// Example:
+	//
+	//	builder.CrossJoin("jsonb_array_elements(e.payload->'items') AS item")
+	//
+	// Generates:
+	//
+	//	CROSS JOIN jsonb_array_elements(e.payload->'items') AS item
+	CrossJoin(table string) SQLSelectChainBuilder
+
+	// BuildPaginated is Build for a paginated query, also returning the page's PaginationMeta.
+	// Pass the meta to FormatPaginationResult to fill TotalPages and HasNextPage from the queried total.
+	//
+	// Example:
+	//
+	//	query, args, meta, err := builder.Paginate(pagination).BuildPaginated()
+	//	...
+	//	return sql_query.FormatPaginationResult(result, meta)
+	//	→ {"totalRecords": 45, "totalPages": 5, "hasNextPage": true, "data": [...]} (page 2, limit 10)
+	BuildPaginated() (string, []interface{}, PaginationMeta, error)
+
+	// Paginate implements SQLSelectChainBuilder. (Overrides previous value if called again).
+	// Paginate applies LIMIT, OFFSET, and ORDER BY using a Pagination struct.
+	// It supports single or multiple sorting rules.
+	//
+	// Example:
+	//
+	//	builder.Paginate(Pagination{Page: 2, Limit: 10, SortBy: "name", SortOrder: 1})
+	Paginate(query Pagination) SQLSelectChainBuilder
+	// OrderBy adds sorting rules to the query. Multiple calls accumulate sorting.
+	//
+	// Example:
+	//
+	//	builder.OrderBy([]string{"created_at"}, false) // DESC
+	OrderBy(sortBy []string, asc bool) SQLSelectChainBuilder
+	// OrderByAdvanced adds one sorting rule per column, each with its own direction and nulls placement.
+	// Nulls left empty keeps the Postgres default (NULLS LAST for ASC, NULLS FIRST for DESC).
+	// Multiple calls accumulate sorting, like OrderBy.
+	//
+	// Example:
+	//
+	//	builder.OrderByAdvanced([]OrderRule{
+	//	    {Column: "due_date", Direction: SortAsc, Nulls: NullsLast},
+	//	    {Column: "amount", Direction: SortDesc},
+	//	})
+	//
+	// Generates:
+	//
+	//	ORDER BY due_date ASC NULLS LAST, amount DESC
+	OrderByAdvanced(rules []OrderRule) SQLSelectChainBuilder
+	// OrderByCase adds a CASE expression as sorting rule, e.g. to list some statuses first.
+	// Its ? are bound to args like those of SelectRaw. Multiple calls accumulate sorting.
+	//
+	// Example:
+	//
+	//	builder.OrderByCase("CASE WHEN status = ? THEN 0 WHEN status = ? THEN 1 ELSE 2 END", []any{"pending", "processing"}, true).
+	//	    OrderBy([]string{"created_at"}, false)
+	//
+	// Generates:
+	//
+	//	ORDER BY CASE WHEN status = $1 THEN 0 WHEN status = $2 THEN 1 ELSE 2 END ASC, created_at DESC NULLS LAST
+	OrderByCase(caseExpr string, args []any, asc bool) SQLSelectChainBuilder
+	// GroupBy adds one or more columns to the GROUP BY clause.
+	// Multiple calls accumulate columns. An alias of a selected column is replaced by its expression.
+	//
+	// Example:
+	//
+	//	builder.GroupBy("department", "role")
+	//
+	//	builder.Select(`cf.data_type AS "dataType"`, `COUNT(*) AS "total"`).GroupBy("dataType")
+	//
+	// Generates:
+	//
+	//	SELECT cf.data_type AS "dataType", COUNT(*) AS "total" ... GROUP BY cf.data_type
+	GroupBy(groupBy ...string) SQLSelectChainBuilder
+	// StrictIdentifiers makes Build reject Select, OrderBy and GroupBy input that isn't a plain identifier
+	// (column, table.column, optionally quoted, with an optional plain AS alias), use it whenever sort or group
+	// fields come from query strings. With allowed set, identifiers must also be one of them (case-insensitive).
+	// Default columns from struct tags are trusted, computed columns need the dedicated Select helpers.
+	//
+	// Example:
+	//
+	//	builder.StrictIdentifiers("createdAt", "amount").Paginate(pagination) // sortBy=amount;DROP TABLE -> error
+	StrictIdentifiers(allowed ...string) SQLSelectChainBuilder
+	// StrictFilterKeys makes Build reject the query when a key of Where, WhereOr or WhereGroup isn't a column of
+	// the builder's DTO nor one of known, listing every unknown key, so typos fail in tests instead of matching
+	// no row. Register the columns of joined tables (or "alias.*" for all of them) and filtered columns the DTO
+	// doesn't select in known. RAW conditions and expressions (functions, JSON paths) aren't checked.
+	//
+	// Example:
+	//
+	//	NewSQLSelectBuilder[TransactionItem]("transactions", "t").
+	//	    Join("wallets w", "w.id = t.wallet_id").
+	//	    StrictFilterKeys("t.deleted_at", "w.*").
+	//	    Where(map[string]SQLCondition{"t.wallet_idd": {Operator: SQLOperatorEqual, Value: walletID}})
+	//	→ invalid values: unknown filter key: t.wallet_idd
+	StrictFilterKeys(known ...string) SQLSelectChainBuilder
+	// Having implements SQLSelectChainBuilder. (Accumulates previous value if called again).
+	// Having adds AND-combined HAVING conditions for grouped queries.
+	//
+	// Example:
+	//
+	//	builder.GroupBy("role").Having(map[string]SQLCondition{
+	//	    "count(*)": {Operator: SQLOperatorGreaterThan, Value: 5},
+	//	})
+	Having(havingClauses map[string]SQLCondition) SQLSelectChainBuilder
+	// HavingOr implements SQLSelectChainBuilder. (Accumulates previous value if called again).
+	// Each map is AND-combined internally, then OR-joined together, like WhereOr.
+	//
+	// Example:
+	//
+	//	builder.GroupBy("category_id").HavingOr(
+	//	    map[string]SQLCondition{"count(*)": {Operator: SQLOperatorGreaterThan, Value: 5}},
+	//	    map[string]SQLCondition{"sum(amount)": {Operator: SQLOperatorGreaterThan, Value: 1000}},
+	//	)
+	//
+	// Generates:
+	//
+	//	HAVING ((count(*) > $1) OR (sum(amount) > $2))
+	HavingOr(havingClauses ...map[string]SQLCondition) SQLSelectChainBuilder
+
+	// WithCTEBuilder adds a Common Table Expression (CTE) to the query.
+	// It adjusts argument placeholders to avoid conflicts.
+	// This function just add the defined CTE to the top of query.
+	// You need to JOIN/LEFT JOIN the CTE builder to let main expression know that it should use CTE.
+	//
+	// Example:
+	//
+	//	cte := NewSQLSelectBuilder[Order]("orders").Select("id", "user_id")
+	//	builder.WithCTEBuilder("recent_orders", cte.(*sql_query.SelectBuilder).SQLEloquentQuery)
+	//
+	// Generates:
+	//
+	//	WITH recent_orders AS (SELECT id, user_id FROM orders) ...
+	//
+	// An optional CTEOption hints the planner:
+	//
+	//	materialized := true
+	//	builder.WithCTEBuilder("page", cte.(*sql_query.SelectBuilder).SQLEloquentQuery, CTEOption{Materialized: &materialized})
+	//	→ WITH page AS MATERIALIZED (...) ...
+	WithCTEBuilder(cteName string, cteBuilder *SQLEloquentQuery, options ...CTEOption) SQLSelectChainBuilder
+
+	// ToCountBuilder returns a new builder counting the rows this one selects: it shares the WHERE, JOIN and
+	// CTEs (with their args) but drops the columns, ORDER BY, LIMIT/OFFSET, pagination and locks.
+	// Grouped, DISTINCT ON and set operation queries are counted through a derived table.
+	// This builder is left untouched, so derive the count after every filter was added.
+	//
+	// Example:
+	//
+	//	count, args, err := builder.ToCountBuilder().Build()
+	//
+	// Generates:
+	//
+	//	SELECT COUNT(*) FROM transactions t JOIN wallets w ON w.id = t.wallet_id WHERE "t"."type" = $1
+	//	SELECT COUNT(*) FROM (SELECT ... GROUP BY t.category_id) AS counted    (grouped)
+	ToCountBuilder() SQLSelectChainBuilder
+
+	// Sample implements SQLSelectChainBuilder. (Overrides previous value if called again)
+	// Sample adds TABLESAMPLE to the table, reading about percentage percent of its rows instead of scanning
+	// it whole, for estimates over large tables. method is SampleSystem or SampleBernoulli, a seed makes the
+	// sample repeatable. Derived tables of FromSubquery can't be sampled.
+	//
+	// Example:
+	//
+	//	builder.Select("AVG(amount)").Sample(sql_query.SampleSystem, 1, 42)
+	//
+	// Generates:
+	//
+	//	SELECT AVG(amount) FROM transactions TABLESAMPLE SYSTEM (1) REPEATABLE (42)
+	Sample(method string, percentage float64, seed ...int64) SQLSelectChainBuilder
+
+	// FromSubquery selects from the derived table built by sub instead of the table given at construction.
+	// sub's placeholders are shifted after the arguments already bound, so it may be called anywhere in the chain.
+	// Columns, filters and joins refer to the derived table by its alias.
+	//
+	// Example:
+	//
+	//	totals := NewSQLSelectBuilder[any]("transactions").
+	//	    Select("wallet_id", "SUM(amount) AS total").
+	//	    Where(map[string]SQLCondition{"type": {Operator: SQLOperatorEqual, Value: "expense"}}).
+	//	    GroupBy("wallet_id")
+	//	builder.FromSubquery("totals", totals.(*sql_query.SelectBuilder).SQLEloquentQuery).
+	//	    Where(map[string]SQLCondition{"totals.total": {Operator: SQLOperatorGreaterThan, Value: 1000}})
+	//
+	// Generates:
+	//
+	//	SELECT ... FROM (SELECT wallet_id, SUM(amount) AS total FROM transactions WHERE "type" = $1 GROUP BY wallet_id) AS totals
+	//	WHERE "totals"."total" > $2
+	FromSubquery(alias string, sub *SQLEloquentQuery) SQLSelectChainBuilder
+
+	// WithRecursiveCTEBuilder adds a Common Table Expression (CTE) to the query.
+	// It adjusts argument placeholders to avoid conflicts.
+	// This function just add the defined CTE to the top of query.
+	// You need to JOIN/LEFT JOIN the CTE builder to let main expression know that it should use CTE.
+	//
+	// Example:
+	//
+	//	cte := NewSQLSelectBuilder[Order]("orders").Select("id", "user_id")
+	//	builder.WithRecursiveCTEBuilder("recent_orders", cte.(*sql_query.SelectBuilder).SQLEloquentQuery)
+	//
+	// Generates:
+	//
+	//	WITH RECURSIVE recent_orders AS (SELECT id, user_id FROM orders) ...
+	WithRecursiveCTEBuilder(cteName string, cteBuilder *SQLEloquentQuery, options ...CTEOption) SQLSelectChainBuilder
+	// GetCTE returns the builder added under cteName by WithCTEBuilder or WithRecursiveCTEBuilder.
+	// A name can only be added once, adding it again sets an error: join the existing CTE from every
+	// branch needing it instead.
+	//
+	// Example:
+	//
+	//	if _, ok := builder.GetCTE("recent_orders"); !ok {
+	//	    builder.WithCTEBuilder("recent_orders", cte.(*sql_query.SelectBuilder).SQLEloquentQuery)
+	//	}
+	//	builder.LeftJoin("recent_orders", `"recent_orders"."user_id" = "users"."id"`)
+	GetCTE(cteName string) (*SQLEloquentQuery, bool)
+
+	// Add "UNION ALL" in between the queries
+	// example:
+	//  SELECT id
+	// FROM categories
+	// WHERE id = 0
+	//
+	// UNION ALL
+	//
+	// SELECT c.id
+	// FROM categories c
+	// INNER JOIN category_tree ct ON c.parent_id = ct.id
+	UnionAll(cteBuilders ...*SQLEloquentQuery) SQLSelectChainBuilder
+	// Union combines the queries with UNION (duplicates removed), like UnionAll the builder's own SELECT is replaced.
+	// OrderBy and SetLimit apply to the combined result and sort by output column names, Paginate and Lock are rejected.
+	// Queries with their own ORDER BY or LIMIT are parenthesized.
+	//
+	// Example:
+	//
+	//	builder.Union(incomeBuilder, expenseBuilder).OrderBy([]string{"createdAt"}, false).SetLimit(10)
+	//
+	// Generates:
+	//
+	//	SELECT ... FROM income
+	//	UNION
+	//	SELECT ... FROM expense
+	//	ORDER BY "createdAt" DESC NULLS LAST
+	//	LIMIT 10
+	Union(builders ...*SQLEloquentQuery) SQLSelectChainBuilder
+	// Intersect keeps the rows returned by every query, see Union.
+	Intersect(builders ...*SQLEloquentQuery) SQLSelectChainBuilder
+	// Except removes the rows of the given queries from the rows combined so far, see Union.
+	//
+	// Example:
+	//
+	//	builder.UnionAll(allWallets).Except(archivedWallets)
+	Except(builders ...*SQLEloquentQuery) SQLSelectChainBuilder
+
+	// UseDialect overrides DefaultDialect for this query, MySQL and SQLite get `?` placeholders.
+	//
+	// Example:
+	//
+	//	builder.UseDialect(sql_query.DialectMySQL)
+	UseDialect(dialect Dialect) SQLSelectChainBuilder
+
+	// Build finalizes the SELECT query and returns the query string and arguments.
+	// Returns an error if the query is invalid (e.g., HAVING without GROUP BY).
+	Build() (string, []interface{}, error)
+	// Prepare builds the query as a PreparedStatement, named after a hash of its SQL, for service ExecPrepared.
+	// Only the Postgres dialect can be prepared.
+	//
+	// Example:
+	//
+	//	statement, err := builder.Prepare()
+	//	_, err = svc.ExecPrepared(&result, ctx, statement)
+	Prepare() (PreparedStatement, error)
+	// BuildWithMeta builds like Build and also describes the query, e.g. to route reads to a replica
+	// or tag metrics by table without parsing the SQL.
+	//
+	// Example:
+	//
+	//	query, args, meta, err := NewSQLSelectBuilder[Wallet]("wallets", "w").
+	//	    Join("wallet_members wm", `wm."wallet_id" = w."id"`).
+	//	    SetLimit(10).
+	//	    BuildWithMeta()
+	//
+	// Generates:
+	//
+	//	QueryMeta{Tables: []string{"wallets", "wallet_members"}, Mode: SQLSelect, ArgCount: 0, UsesPagination: true}
+	BuildWithMeta() (string, []interface{}, QueryMeta, error)
+	// BuildDebug builds the query with the args inlined as escaped literals, for copy-pasting logged queries into psql.
+	// Never execute its output.
+	BuildDebug() (string, error)
+}
+
+type SelectBuilder struct {
+	*SQLEloquentQuery
+}
+
+func (s *SelectBuilder) UseDialect(dialect Dialect) SQLSelectChainBuilder {
+	s.Dialect = dialect
+	return s
+}
+
+func (s *SelectBuilder) Where(filters map[string]SQLCondition) SQLSelectChainBuilder {
+	s.recordFilterKeys(filters)
+	s.SQLEloquentQuery.sharedWhereAndQuery(filters)
+	return s
+}
+
+func (s *SelectBuilder) WhereOr(filters ...map[string]SQLCondition) SQLSelectChainBuilder {
+	s.recordFilterKeys(filters...)
+	s.SQLEloquentQuery.sharedWhereOrQuery(filters...)
+	return s
+}
+
+func (s *SelectBuilder) WhereRaw(expr string, args ...any) SQLSelectChainBuilder {
+	if strings.TrimSpace(expr) == "" {
+		return s
+	}
+
+	bound, err := s.bindQuestionMarks(expr, args)
+	if err != nil {
+		s.LastError = err
+		return s
+	}
+
+	s.Filters = append(s.Filters, "("+bound+")")
+	return s
+}
+
+func (s *SelectBuilder) WhereGroup(groups ...ConditionGroup) SQLSelectChainBuilder {
+	s.recordGroupFilterKeys(groups...)
+	s.SQLEloquentQuery.sharedWhereGroup(groups...)
+	return s
+}
+
+func (s *SelectBuilder) WhereExists(subBuilder *SQLEloquentQuery) SQLSelectChainBuilder {
+	s.whereSubQuery("EXISTS", subBuilder)
+	return s
+}
+
+func (s *SelectBuilder) WhereNotExists(subBuilder *SQLEloquentQuery) SQLSelectChainBuilder {
+	s.whereSubQuery("NOT EXISTS", subBuilder)
+	return s
+}
+
+func (s *SelectBuilder) whereSubQuery(operator string, subBuilder *SQLEloquentQuery) {
+	subQuery, subArgs, err := subBuilder.build()
+	if err != nil {
+		s.LastError = err
+		return
+	}
+
+	shiftedSubQuery := shiftSQLPlaceholders(strings.TrimSpace(subQuery), len(s.Args))
+
+	s.Filters = append(s.Filters, fmt.Sprintf("%s (%s)", operator, shiftedSubQuery))
+	s.Args = appendArgs(s.Args, subArgs)
+	s.touchTablesOf(subBuilder)
+}
+
+func (s *SelectBuilder) GetCurrentArgIndex() int {
+	return len(s.Args)
+}
+
+func (s *SelectBuilder) AddArgs(arg ...interface{}) SQLSelectChainBuilder {
+	s.Args = appendArgs(s.Args, arg)
+	return s
+}
+
+func (s *SelectBuilder) StartPlaceholderFrom(index int) SQLSelectChainBuilder {
+	if index < 1 {
+		index = 1
+	}
+	s.paddedArgs = index > 1
+
+	if len(s.Args) > 0 {
+		// Need to pad so that first existing arg becomes $index
+		pad := index - 1
+		if pad > 0 {
+			temp := make([]interface{}, pad+len(s.Args))
+			// fill padding with nils
+			for i := 0; i < pad; i++ {
+				temp[i] = nil
+			}
+			// copy old args after padding
+			copy(temp[pad:], s.Args)
+			s.Args = temp
+		}
+	} else {
+		// If empty, just pad with nils up to index-1
+		if index > 1 {
+			temp := make([]interface{}, index-1)
+			for i := 0; i < index-1; i++ {
+				temp[i] = nil
+			}
+			s.Args = temp
+		}
+	}
+
+	return s
+}
+
+func (s *SelectBuilder) Search(keyword string, fields []string) SQLSelectChainBuilder {
+	if keyword != "" && len(fields) > 0 {
+		var orClauses []string
+		orArgs := []interface{}{}
+
+		for _, field := range fields {
+			isArrayColumn := strings.HasSuffix(field, ":array")
+
+			if isArrayColumn {
+				cleanColumn := strings.TrimSuffix(field, ":array")
+				orClauses = append(orClauses, fmt.Sprintf("EXISTS (SELECT 1 FROM unnest(%s) as val WHERE val ILIKE $%d)", cleanColumn, len(s.Args)+len(orArgs)+1))
+			} else {
+				orClauses = append(orClauses, fmt.Sprintf("%s ILIKE $%d", field, len(s.Args)+len(orArgs)+1))
+			}
+
+			orArgs = append(orArgs, "%"+keyword+"%")
+		}
+
+		// Combine all clauses with OR inside parentheses
+		orClauseGroup := fmt.Sprintf("(%s)", strings.Join(orClauses, " OR "))
+		s.Filters = append(s.Filters, orClauseGroup)
+		s.Args = appendArgs(s.Args, orArgs)
+	}
+
+	return s
+}
+
+func (s *SelectBuilder) Distinct(alias string, columns ...string) SQLSelectChainBuilder {
+	s.DistinctAlias = alias
+	for _, newCol := range columns {
+		newAlias := extractAlias(newCol)
+
+		// Check if alias exists in current list
+		replaced := false
+		for i, existing := range s.DistinctBy {
+			extracted := extractAlias(existing)
+			if extracted != "" && extracted == newAlias {
+				s.DistinctBy[i] = newCol // Overwrite
+				replaced = true
+				break
+			}
+		}
+
+		if !replaced {
+			s.DistinctBy = append(s.DistinctBy, newCol)
+		}
+	}
+
+	return s
+}
+
+func (s *SelectBuilder) ClearSelects() SQLSelectChainBuilder {
+	s.Columns = []string{}
+	s.selectedColumns = s.selectedColumns[:0]
+	return s
+}
+
+func (s *SelectBuilder) Select(columns ...string) SQLSelectChainBuilder {
+	// Kept apart from the trusted struct columns for StrictIdentifiers
+	s.selectedColumns = append(s.selectedColumns, columns...)
+
+	for _, newCol := range columns {
+		newAlias := extractAlias(newCol)
+
+		// Check if alias exists in current list
+		replaced := false
+		for i, existing := range s.Columns {
+			extracted := extractAlias(existing)
+			if extracted != "" && extracted == newAlias {
+				s.Columns[i] = newCol // Overwrite
+				replaced = true
+				break
+			}
+		}
+
+		if !replaced {
+			s.Columns = append(s.Columns, newCol)
+		}
+	}
+	return s
+}
+
+func (s *SelectBuilder) SelectBoolAnd(expr, alias string, args ...interface{}) SQLSelectChainBuilder {
+	boolAndColumn := fmt.Sprintf("bool_and(%s) AS \"%s\"", expr, alias)
+
+	// Check if alias exists in current list
+	replaced := false
+	for i, existing := range s.Columns {
+		extracted := extractAlias(existing)
+		if extracted != "" && extracted == strings.ToLower(alias) {
+			s.Columns[i] = boolAndColumn // Overwrite
+			replaced = true
+			break
+		}
+	}
+
+	if !replaced {
+		s.Columns = append(s.Columns, boolAndColumn)
+	}
+
+	s.Args = append(s.Args, args...)
+	return s
+}
+
+func (s *SelectBuilder) SelectBoolOr(expr, alias string, args ...interface{}) SQLSelectChainBuilder {
+	boolOrColumn := fmt.Sprintf("bool_or(%s) AS \"%s\"", expr, alias)
+
+	// Check if alias exists in current list
+	replaced := false
+	for i, existing := range s.Columns {
+		extracted := extractAlias(existing)
+		if extracted != "" && extracted == strings.ToLower(alias) {
+			s.Columns[i] = boolOrColumn // Overwrite
+			replaced = true
+			break
+		}
+	}
+
+	if !replaced {
+		s.Columns = append(s.Columns, boolOrColumn)
+	}
+
+	s.Args = append(s.Args, args...)
+	return s
+}
+
+func (s *SelectBuilder) SelectSubquery(alias string, sub *SQLEloquentQuery) SQLSelectChainBuilder {
+	subQuery, subArgs, err := sub.build()
+	if err != nil {
+		s.LastError = err
+		return s
+	}
+
+	column := fmt.Sprintf(`(%s) AS "%s"`, shiftSQLPlaceholders(strings.TrimSpace(subQuery), len(s.Args)), alias)
+	s.Args = appendArgs(s.Args, subArgs)
+	s.touchTablesOf(sub)
+
+	// Check if alias exists in current list
+	for i, existing := range s.Columns {
+		if extracted := extractAlias(existing); extracted != "" && extracted == strings.ToLower(alias) {
+			s.Columns[i] = column // Overwrite
+			return s
+		}
+	}
+
+	s.Columns = append(s.Columns, column)
+	return s
+}
+
+func (s *SelectBuilder) SelectArrayAggregation(alias string, source string, config ArrayAggConfig) SQLSelectChainBuilder {
+	if config.Expr == "" {
+		s.LastError = errors.New("expression should not empty")
+	}
+
+	var orderByClause string
+	if config.SortBy != "" && config.SortOrder != 0 {
+		order := "ASC"
+		if config.SortOrder < 0 {
+			order = "DESC"
+		}
+		orderByClause = fmt.Sprintf(" ORDER BY %s %s", config.SortBy, order)
+	}
+
+	if source != "" {
+		source = " FROM " + source
+	}
+	arrayAggColumn := fmt.Sprintf(`(SELECT array_agg(%s%s)%s) AS "%s"`, config.Expr, orderByClause, source, alias)
+
+	replaced := false
+	for i, existing := range s.Columns {
+		extracted := extractAlias(existing)
+		if extracted != "" && extracted == strings.ToLower(alias) {
+			s.Columns[i] = arrayAggColumn // Overwrite
+			replaced = true
+			break
+		}
+	}
+
+	if !replaced {
+		s.Columns = append(s.Columns, arrayAggColumn)
+	}
+
+	return s
+}
+
+func (s *SelectBuilder) SelectCaseWhen(thenExpr, elseExpr, alias string, whenClause string, whenArgs ...interface{}) SQLSelectChainBuilder {
+	caseWhenColumn := fmt.Sprintf("CASE WHEN %s THEN %s ELSE %s END AS \"%s\"", whenClause, thenExpr, elseExpr, alias)
+
+	// Check if alias exists in current list
+	replaced := false
+	for i, existing := range s.Columns {
+		extracted := extractAlias(existing)
+		if extracted != "" && extracted == strings.ToLower(alias) {
+			s.Columns[i] = caseWhenColumn // Overwrite
+			replaced = true
+			break
+		}
+	}
+
+	if !replaced {
+		s.Columns = append(s.Columns, caseWhenColumn)
+	}
+
+	s.Args = appendArgs(s.Args, whenArgs)
+	return s
+}
+
+func (s *SelectBuilder) SelectRaw(expr string, args ...any) SQLSelectChainBuilder {
+	if strings.TrimSpace(expr) == "" {
+		return s
+	}
+
+	bound, err := s.bindQuestionMarks(expr, args)
+	if err != nil {
+		s.LastError = err
+		return s
+	}
+
+	s.Columns = append(s.Columns, bound)
+	return s
+}
+
+func (s *SelectBuilder) Join(table string, onCondition string, additionalConditions ...map[string]SQLCondition) SQLSelectChainBuilder {
+	s.addJoin("JOIN", table, onCondition, additionalConditions...)
+	return s
+}
+
+func (s *SelectBuilder) LeftJoin(table string, mainCondition string, additionalConditions ...map[string]SQLCondition) SQLSelectChainBuilder {
+	s.addJoin("LEFT JOIN", table, mainCondition, additionalConditions...)
+	return s
+}
+
+func (s *SelectBuilder) RightJoin(table string, onCondition string, additionalConditions ...map[string]SQLCondition) SQLSelectChainBuilder {
+	s.addJoin("RIGHT JOIN", table, onCondition, additionalConditions...)
+	return s
+}
+
+func (s *SelectBuilder) FullOuterJoin(table string, onCondition string, additionalConditions ...map[string]SQLCondition) SQLSelectChainBuilder {
+	s.addJoin("FULL OUTER JOIN", table, onCondition, additionalConditions...)
+	return s
+}
+
+// Shared by the plain join methods, additional conditions are AND-ed to the ON condition.
+func (s *SelectBuilder) addJoin(joinType string, table string, onCondition string, additionalConditions ...map[string]SQLCondition) {
+	if table == "" {
+		return
+	}
+
+	var filterSb strings.Builder
+	if len(additionalConditions) > 0 {
+		var filters []string
+		s.sharedWhereAndQuery(additionalConditions[0], &filters)
+
+		for i := range filters {
+			filterSb.WriteString(" AND ")
+			filterSb.WriteString(filters[i])
+		}
+	}
+
+	s.OtherTables = append(s.OtherTables, fmt.Sprintf("%s %s ON %s%s", joinType, table, onCondition, filterSb.String()))
+	s.touchTable(table)
+}
+
+func (s *SelectBuilder) CrossJoin(table string) SQLSelectChainBuilder {
+	if table == "" {
+		return s
+	}
+
+	s.OtherTables = append(s.OtherTables, "CROSS JOIN "+table)
+	s.touchTable(table)
+	return s
+}
+
+func (s *SelectBuilder) LeftJoinLateralWithQuery(joinName string, joinQueryBuilder *SQLEloquentQuery, mainCondition string, additionalConditions ...map[string]SQLCondition) SQLSelectChainBuilder {
+	s.addLateralJoin("LEFT JOIN LATERAL", joinName, joinQueryBuilder, mainCondition, additionalConditions...)
+	return s
+}
+
+func (s *SelectBuilder) JoinLateralWithQuery(joinName string, joinQueryBuilder *SQLEloquentQuery, mainCondition string, additionalConditions ...map[string]SQLCondition) SQLSelectChainBuilder {
+	s.addLateralJoin("JOIN LATERAL", joinName, joinQueryBuilder, mainCondition, additionalConditions...)
+	return s
+}
+
+// Shared by the lateral join methods, the lateral query's placeholders follow the arguments already bound.
+func (s *SelectBuilder) addLateralJoin(joinType string, joinName string, joinQueryBuilder *SQLEloquentQuery, mainCondition string, additionalConditions ...map[string]SQLCondition) {
+	joinQuery, joinArgs, err := joinQueryBuilder.build()
+	if err != nil {
+		s.LastError = err
+		return
+	}
+
+	// Calculate the current argument offset
+	offset := len(s.Args)
+
+	// Shift the placeholders in the CTE query
+	shiftedCTEQuery := shiftSQLPlaceholders(joinQuery, offset)
+
+	s.Args = appendArgs(s.Args, joinArgs)
+
+	// additional filter
+	var filterSb strings.Builder
+	if len(additionalConditions) > 0 {
+		var filters []string
+		s.sharedWhereAndQuery(additionalConditions[0], &filters)
+
+		for i := range filters {
+			filterSb.WriteString(" AND ")
+			filterSb.WriteString(filters[i])
+		}
+	}
+
+	s.OtherTables = append(s.OtherTables, fmt.Sprintf("%s (%s) %s ON %s%s", joinType, shiftedCTEQuery, joinName, mainCondition, filterSb.String()))
+	s.touchTablesOf(joinQueryBuilder)
+}
+
+func (s *SelectBuilder) GroupBy(groupBy ...string) SQLSelectChainBuilder {
+	s.Grouping = append(s.Grouping, groupBy...)
+	return s
+}
+
+func (s *SelectBuilder) Having(havingClause map[string]SQLCondition) SQLSelectChainBuilder {
+	// Built apart so the conditions never leak into WHERE, nor later Where calls into HAVING
+	var clauses []string
+	s.SQLEloquentQuery.sharedWhereAndQuery(havingClause, &clauses)
+	s.HavingClauses = append(s.HavingClauses, clauses...)
+	return s
+}
+
+func (s *SelectBuilder) HavingOr(havingClauses ...map[string]SQLCondition) SQLSelectChainBuilder {
+	var clauses []string
+	s.SQLEloquentQuery.whereOrDestination(havingClauses, &clauses)
+	s.HavingClauses = append(s.HavingClauses, clauses...)
+	return s
+}
+
+func (s *SelectBuilder) OrderBy(sortBy []string, asc bool) SQLSelectChainBuilder {
+	direction := "ASC"
+	nulls := "FIRST"
+
+	if !asc {
+		direction = "DESC"
+		nulls = "LAST"
+	}
+
+	sortingRule := fmt.Sprintf("%s %s NULLS %s", strings.Join(sortBy, ", "), direction, nulls)
+	s.SortBy = append(s.SortBy, sortingRule)
+	return s
+}
+
+type Sort struct {
+	SortBy    string `json:"sortBy"`
+	SortOrder int    `json:"sortOrder"`
+	// Nulls is "first" or "last", empty keeps OrderBy's placement (NULLS FIRST for ASC, NULLS LAST for DESC)
+	Nulls string `json:"nulls"`
+}
+
+type SQLSort struct {
+	SortBy    string `json:"sortBy"`
+	SortOrder string `json:"sortOrder"` // ASC | DESC
+}
+
+type Pagination struct {
+	Page        int    `json:"page"      transform:"int"`
+	Limit       int    `json:"limit"     transform:"int"`
+	SortBy      string `json:"sortBy"    transform:"string"`
+	SortOrder   int    `json:"sortOrder" transform:"int"`
+	MultiSort   []Sort `json:"multiSort"`
+	DefaultSort []Sort `json:"defaultSort"`
+}
+
+func (s *SelectBuilder) Paginate(query Pagination) SQLSelectChainBuilder {
+	var normalizedPage int
+	if query.Page > 0 {
+		normalizedPage = query.Page - 1
+	} else {
+		normalizedPage = 0
+	}
+
+	s.UsePagination = true
+	s.Limit = query.Limit
+	s.Offset = normalizedPage * query.Limit
+
+	if query.SortBy != "" && query.SortOrder != 0 {
+		s.OrderBy([]string{query.SortBy}, query.SortOrder > 0)
+	} else if len(query.MultiSort) > 0 {
+		// Overwrite sortBy sortOrder
+		// Functions: For nameWithSequence sort (split the sort into 2 sorts)
+		// OrderByCase terms stay, their arguments are already bound
+		s.SortBy = slices.Clone(s.sortExpressions)
+
+		for _, sort := range query.MultiSort {
+			s.orderBySort(sort)
+		}
+	} else if len(query.DefaultSort) > 0 {
+		// Overwrite sortBy sortOrder
+		// Functions: If all other sorts not defined, define defaultsort
+		s.SortBy = slices.Clone(s.sortExpressions)
+
+		for _, sort := range query.DefaultSort {
+			s.orderBySort(sort)
+		}
+	}
+
+	return s
+}
+
+// PaginationMeta describes the page of a paginated query, TotalRecords and the fields derived from it are
+// only known once the query ran, see WithTotal.
+type PaginationMeta struct {
+	Page         int  `json:"page"`
+	Limit        int  `json:"limit"`
+	TotalRecords int  `json:"totalRecords"`
+	TotalPages   int  `json:"totalPages"`
+	HasNext      bool `json:"hasNext"`
+}
+
+// WithTotal returns the meta completed with the total records of the query.
+func (m PaginationMeta) WithTotal(totalRecords int) PaginationMeta {
+	m.TotalRecords = totalRecords
+	m.TotalPages = 0
+	if m.Limit > 0 {
+		m.TotalPages = (totalRecords + m.Limit - 1) / m.Limit
+	}
+	m.HasNext = m.Page*m.Limit < totalRecords
+
+	return m
+}
+
+func (s *SelectBuilder) BuildPaginated() (string, []interface{}, PaginationMeta, error) {
+	if !s.UsePagination {
+		return "", nil, PaginationMeta{}, fmt.Errorf("%w: BuildPaginated needs Paginate", ErrInvalidValues)
+	}
+
+	meta := PaginationMeta{Page: 1, Limit: s.Limit}
+	if s.Limit > 0 {
+		meta.Page = s.Offset/s.Limit + 1
+	}
+
+	query, args, err := s.Build()
+	return query, args, meta, err
+}
+
+func (s *SelectBuilder) SetLimit(limit int) SQLSelectChainBuilder {
+	var normalizedPage int
+
+	s.Limit = limit
+	s.Offset = normalizedPage * limit
+
+	return s
+}
+
+func (s *SelectBuilder) WithCTEBuilder(cteName string, cteBuilder *SQLEloquentQuery, options ...CTEOption) SQLSelectChainBuilder {
+	if !s.registerCTE(cteName, cteBuilder) {
+		return s
+	}
+
+	cteQuery, cteArgs, err := cteBuilder.build()
+	if err != nil {
+		s.LastError = err
+		return s
+	}
+
+	// Calculate the current argument offset
+	offset := len(s.Args)
+
+	// Shift the placeholders in the CTE query
+	shiftedCTEQuery := shiftSQLPlaceholders(cteQuery, offset)
+
+	s.WithClauses = append(s.WithClauses, cteClause(cteName, shiftedCTEQuery, options))
+	s.Args = appendArgs(s.Args, cteArgs)
+
+	return s
+}
+
+func (s *SelectBuilder) FromSubquery(alias string, sub *SQLEloquentQuery) SQLSelectChainBuilder {
+	alias = strings.TrimSpace(alias)
+	if sub == nil || !isPlainIdentifier(alias) {
+		s.LastError = fmt.Errorf("%w: FromSubquery needs a subquery and a plain alias, got %q", ErrInvalidValues, alias)
+		return s
+	}
+	if s.tableSample != "" {
+		s.LastError = fmt.Errorf("%w: a derived table of FromSubquery can't be sampled", ErrInvalidValues)
+		return s
+	}
+
+	subQuery, subArgs, err := sub.build()
+	if err != nil {
+		s.LastError = err
+		return s
+	}
+
+	s.Table = fmt.Sprintf("(%s) AS %s", shiftSQLPlaceholders(subQuery, len(s.Args)), alias)
+	s.fromAlias = alias
+	s.touchTablesOf(sub)
+	s.Args = appendArgs(s.Args, subArgs)
+
+	return s
+}
+
+func (s *SelectBuilder) WithRecursiveCTEBuilder(cteName string, cteBuilder *SQLEloquentQuery, options ...CTEOption) SQLSelectChainBuilder {
+	if !s.registerCTE(cteName, cteBuilder) {
+		return s
+	}
+
+	cteQuery, cteArgs, err := cteBuilder.build()
+	if err != nil {
+		s.LastError = err
+		return s
+	}
+
+	// Calculate the current argument offset
+	offset := len(s.Args)
+
+	// Shift the placeholders in the CTE query
+	shiftedCTEQuery := shiftSQLPlaceholders(cteQuery, offset)
+
+	s.WithClauses = append(s.WithClauses, cteClause(cteName, shiftedCTEQuery, options))
+	s.Args = appendArgs(s.Args, cteArgs)
+
+	s.useWithRecursive = true
+
+	return s
+}
+
+func (s *SelectBuilder) UnionAll(cteBuilders ...*SQLEloquentQuery) SQLSelectChainBuilder {
+	s.addSetOperation("UNION ALL", cteBuilders)
+	return s
+}
+
+func (s *SelectBuilder) Union(builders ...*SQLEloquentQuery) SQLSelectChainBuilder {
+	s.addSetOperation("UNION", builders)
+	return s
+}
+
+func (s *SelectBuilder) Intersect(builders ...*SQLEloquentQuery) SQLSelectChainBuilder {
+	s.addSetOperation("INTERSECT", builders)
+	return s
+}
+
+func (s *SelectBuilder) Except(builders ...*SQLEloquentQuery) SQLSelectChainBuilder {
+	s.addSetOperation("EXCEPT", builders)
+	return s
+}
+
+// addSetOperation appends the queries with operator placed before each of them,
+// the operator before the very first query is never rendered.
+func (s *SelectBuilder) addSetOperation(operator string, builders []*SQLEloquentQuery) {
+	for _, builder := range builders {
+		s.useSetOperation = true // only set true if len >0
+		query, args, err := builder.build()
+		if err != nil {
+			s.LastError = err
+			return
+		}
+
+		// Shift the placeholders in the combined query
+		query = shiftSQLPlaceholders(query, len(s.Args))
+
+		// ORDER BY/LIMIT of a combined query would otherwise apply to the whole result
+		if len(builder.SortBy) > 0 || builder.Limit > 0 || builder.Offset > 0 {
+			query = "(" + strings.TrimSpace(query) + ")\n"
+		}
+
+		s.UnionAllQueries = append(s.UnionAllQueries, query)
+		s.setOperators = append(s.setOperators, operator)
+		s.Args = appendArgs(s.Args, args)
+		s.touchTablesOf(builder)
+	}
+}
+
+// tablePrefix returns the name the columns of the main table are qualified with, its alias when it has one.
+func (s *SQLEloquentQuery) tablePrefix() string {
+	if s.fromAlias != "" {
+		return s.fromAlias
+	}
+
+	splittedTableName := strings.Split(s.Table, " ")
+	if len(splittedTableName) > 1 {
+		return splittedTableName[1]
+	}
+
+	return splittedTableName[0]
+}
+
+// NewSQLSelectBuilder creates a new chainable SELECT builder for a given table.
+// It extracts JSON tags from the struct type T as default columns.
+//
+// Example:
+//
+//	builder := NewSQLSelectBuilder[User]("users","u").Select("id", "name")
+func NewSQLSelectBuilder[T any](tableName string, alias ...string) SQLSelectChainBuilder {
+	columns := ExtractJSONTags[T]()
+
+	if len(alias) > 0 {
+		tableName = fmt.Sprintf("%s %s", tableName, strings.TrimSpace(alias[0]))
+	}
+
+	defaultColumns := []string{}
+	if len(columns) > 0 {
+		defaultColumns = columns
+	}
+	return &SelectBuilder{
+		&SQLEloquentQuery{
+			Table:         tableName,
+			Filters:       []string{},
+			OtherTables:   []string{},
+			Columns:       defaultColumns,
+			Limit:         0,
+			Offset:        0,
+			SortBy:        []string{},
+			CustomQuery:   "",
+			Args:          nil,
+			UsePagination: false,
+			Mode:          "select",
+			dtoType:       reflect.TypeOf((*T)(nil)).Elem(),
+		},
+	}
+}
+
+func NewSQLSelectSubQueryBuilder[T any](tableName string, alias ...string) SQLSelectChainBuilder {
+	columns := ExtractJSONTags[T]()
+
+	if len(alias) > 0 {
+		tableName = fmt.Sprintf("%s %s", tableName, strings.TrimSpace(alias[0]))
+	}
+
+	defaultColumns := []string{}
+	if len(columns) > 0 {
+		defaultColumns = columns
+	}
+	return &SelectBuilder{
+		&SQLEloquentQuery{
+			Table:         tableName,
+			Filters:       []string{},
+			OtherTables:   []string{},
+			Columns:       defaultColumns,
+			Limit:         0,
+			Offset:        0,
+			SortBy:        []string{},
+			CustomQuery:   "",
+			Args:          nil,
+			UsePagination: false,
+			Mode:          "select",
+			isSubQuery:    true,
+		},
+	}
+}
+
+// Example:
+//
+//	builder := NewSQLCountBuilder("users","u").Where(...)
+func NewSQLCountBuilder(tableName string, alias ...string) SQLSelectChainBuilder {
+	defaultColumns := []string{"COUNT(*)"}
+
+	if len(alias) > 0 {
+		tableName = fmt.Sprintf("%s %s", tableName, strings.TrimSpace(alias[0]))
+	}
+	return &SelectBuilder{
+		&SQLEloquentQuery{
+			Table:           tableName,
+			Filters:         []string{},
+			OtherTables:     []string{},
+			UnionAllQueries: []string{},
+			Columns:         defaultColumns,
+			Limit:           0,
+			Offset:          0,
+			SortBy:          []string{},
+			CustomQuery:     "",
+			Args:            nil,
+			UsePagination:   false,
+			Mode:            "select",
+		},
+	}
+}
+
+func (s *SelectBuilder) ToCountBuilder() SQLSelectChainBuilder {
+	count := &SelectBuilder{
+		&SQLEloquentQuery{
+			WithClauses:      slices.Clone(s.WithClauses),
+			Table:            s.Table,
+			Filters:          slices.Clone(s.Filters),
+			OtherTables:      slices.Clone(s.OtherTables),
+			UnionAllQueries:  []string{},
+			Columns:          []string{"COUNT(*)"},
+			SortBy:           []string{},
+			Args:             slices.Clone(s.Args),
+			Mode:             "select",
+			LastError:        s.LastError,
+			Dialect:          s.Dialect,
+			useWithRecursive: s.useWithRecursive,
+			tableSample:      s.tableSample,
+			fromAlias:        s.fromAlias,
+			ctes:             maps.Clone(s.ctes),
+		},
+	}
+	if len(s.Grouping) == 0 && len(s.DistinctBy) == 0 && !s.useSetOperation {
+		return count
+	}
+
+	// Counts the groups (or distinct rows), the CTEs stay on the outer query and every placeholder keeps its number
+	counted := &SQLEloquentQuery{
+		Table:           s.Table,
+		Filters:         count.Filters,
+		OtherTables:     count.OtherTables,
+		UnionAllQueries: slices.Clone(s.UnionAllQueries),
+		Columns:         slices.Clone(s.Columns),
+		DistinctBy:      slices.Clone(s.DistinctBy),
+		DistinctAlias:   s.DistinctAlias,
+		Grouping:        slices.Clone(s.Grouping),
+		HavingClauses:   slices.Clone(s.HavingClauses),
+		Args:            count.Args,
+		Mode:            "select",
+		tableSample:     s.tableSample,
+		useSetOperation: s.useSetOperation,
+		setOperators:    slices.Clone(s.setOperators),
+	}
+	query, _, err := counted.buildSelectQuery()
+	if err != nil {
+		count.LastError = err
+		return count
+	}
+
+	count.Table = fmt.Sprintf("(%s) AS counted", query)
+	count.Filters = []string{}
+	count.OtherTables = []string{}
+	count.tableSample = ""
+	count.fromAlias = "counted"
+
+	return count
+}
+
+func (s *SQLEloquentQuery) buildSelectQuery() (string, []interface{}, error) {
+	if s.LastError != nil {
+		return "", nil, s.LastError
+	}
+
+	if len(s.HavingClauses) > 0 && len(s.Grouping) == 0 {
+		return "", nil, errors.New("HAVING clauses only allowed if GROUP BY clause is exists")
+	}
+
+	if err := s.checkIdentifiers(); err != nil {
+		return "", nil, err
+	}
+	if err := s.checkFilterKeys(); err != nil {
+		return "", nil, err
+	}
+
+	if len(s.Columns) == 0 {
+		s.Columns = []string{"*"}
+	}
+
+	// Pooled buffers keep their grown capacity between builds, String() copies so reuse is safe
+	withSb := acquireBuffer()
+	selectSb := acquireBuffer()
+	joinSb := acquireBuffer()
+	whereSb := acquireBuffer()
+	groupSb := acquireBuffer()
+	orderSb := acquireBuffer()
+	havingSb := acquireBuffer()
+	defer releaseBuffers(withSb, selectSb, joinSb, whereSb, groupSb, orderSb, havingSb)
+
+	// WITH
+	if len(s.WithClauses) > 0 {
+		withSb.WriteByte('\n')
+
+		// if one of WITH are recursive, add 'RECURSIVE' string
+		if s.useWithRecursive {
+			withSb.WriteString("WITH RECURSIVE ")
+		} else {
+			withSb.WriteString("WITH ")
+		}
+
+		for i, c := range s.WithClauses {
+			if i > 0 {
+				withSb.WriteString(", ")
+			}
+			withSb.WriteString(c)
+		}
+	}
+
+	// DISTINCT
+	var db strings.Builder
+	if len(s.DistinctBy) > 0 {
+		db.WriteString("DISTINCT ON ")
+		db.WriteString("(")
+		for i, d := range s.DistinctBy {
+			if i > 0 {
+				db.WriteString(",")
+			}
+			db.WriteString(d)
+		}
+		db.WriteString(")")
+		db.WriteString(" " + s.DistinctAlias)
+
+		tmp := make([]string, len(s.Columns))
+
+		// Prepend distinct to the column clause.
+		copy(tmp, s.Columns)
+		s.Columns = []string{db.String()}
+		s.Columns = append(s.Columns, tmp...)
+	}
+
+	// SELECT
+	// Dont need to use default select if using union All, since union typically uses SELECT inside of it
+	if !s.useSetOperation {
+		selectSb.WriteByte('\n')
+		selectSb.WriteString("SELECT ")
+		for i, col := range s.Columns {
+			if i > 0 {
+				selectSb.WriteByte(',')
+			}
+			selectSb.WriteString(col)
+		}
+		for _, col := range s.cursorColumns {
+			selectSb.WriteByte(',')
+			selectSb.WriteString(col)
+		}
+		selectSb.WriteByte('\n')
+		selectSb.WriteString("FROM ")
+		selectSb.WriteString(s.Table)
+		// Paginated queries sample the filtered ids only, the page is then joined to the full table
+		if !s.UsePagination {
+			selectSb.WriteString(s.tableSample)
+		}
+		selectSb.WriteByte('\n')
+	} else if len(s.UnionAllQueries) > 0 { // UNION [ALL] / INTERSECT / EXCEPT
+		if len(s.Filters) > 0 || len(s.OtherTables) > 0 || len(s.Grouping) > 0 {
+			return "", nil, errors.New("WHERE, JOIN and GROUP BY can't follow UNION/INTERSECT/EXCEPT, add them to the combined queries")
+		}
+		if s.UsePagination || s.lockClause != "" {
+			return "", nil, errors.New("Paginate and Lock can't be applied to UNION/INTERSECT/EXCEPT, use SetLimit instead")
+		}
+
+		for i, u := range s.UnionAllQueries {
+			if i > 0 {
+				selectSb.WriteString(s.setOperators[i])
+				selectSb.WriteByte('\n')
+			}
+			selectSb.WriteString(u)
+		}
+		selectSb.WriteByte('\n')
+	}
+
+	// JOIN
+	if len(s.OtherTables) > 0 {
+		for _, j := range s.OtherTables {
+			joinSb.WriteString(j)
+			joinSb.WriteByte('\n')
+		}
+	}
+
+	// WHERE
+	if len(s.Filters) > 0 {
+		whereSb.WriteString("WHERE ")
+		for i, f := range s.Filters {
+			if i > 0 {
+				whereSb.WriteString(" AND ")
+			}
+			whereSb.WriteString(f)
+		}
+		whereSb.WriteByte('\n')
+	}
+
+	// map for transforming alias ("dataType") to column ("cf.data_type"), shared by GROUP BY and ORDER BY
+	aliasToExpr := s.aliasExpressions()
+
+	// GROUP BY
+	if len(s.Grouping) > 0 {
+		groupSb.WriteString("GROUP BY ")
+		for i, g := range s.Grouping {
+			if i > 0 {
+				groupSb.WriteString(", ")
+			}
+
+			// Postgres folds an unquoted camelCase alias and reads a name as an input column first, group by the expression.
+			// Columns selected without alias resolve to themselves and are written as given.
+			key := cleanIdentifier(g)
+			if expr, ok := aliasToExpr[strings.ToLower(key)]; ok && expr != key {
+				groupSb.WriteString(expr)
+			} else {
+				groupSb.WriteString(g)
+			}
+		}
+		groupSb.WriteByte('\n')
+	}
+
+	// HAVING
+	if len(s.HavingClauses) > 0 {
+		havingSb.WriteString("HAVING ")
+		for i, h := range s.HavingClauses {
+			if i > 0 {
+				havingSb.WriteString(" AND ")
+			}
+			havingSb.WriteString(h)
+		}
+		havingSb.WriteByte('\n')
+	}
+
+	// ORDER BY
+	// ORDER BY
+	if len(s.SortBy) > 0 {
+		orderSb.WriteString("ORDER BY ")
+
+		for i, srt := range s.SortBy {
+			if i > 0 {
+				orderSb.WriteString(", ")
+			}
+
+			// key without ASC/DESC (direction keeps original casing), then unquote/clean
+			key, dir := splitSortDirection(srt)
+			key = cleanIdentifier(key)
+			lookup := strings.ToLower(key)
+
+			if slices.Contains(s.sortExpressions, srt) {
+				orderSb.WriteString(srt)
+				continue
+			}
+
+			// resolve alias -> expression (fallback to key as-is)
+			// Combined results only know their output names, keep camelCase aliases quoted
+			if s.useSetOperation {
+				if key != lookup && isPlainIdentifier(key) {
+					key = `"` + key + `"`
+				}
+				orderSb.WriteString(key + dir)
+			} else if expr, ok := aliasToExpr[lookup]; ok {
+				orderSb.WriteString(expr + dir)
+			} else {
+				orderSb.WriteString(key + dir)
+			}
+		}
+		orderSb.WriteByte('\n')
+	}
+
+	// LIMIT/OFFSET
+	if s.UsePagination {
+		if s.lockClause != "" {
+			return "", nil, ErrLockWithPagination
+		}
+
+		var limitationSb strings.Builder
+		if s.Limit > 0 {
+			limitationSb.WriteString("LIMIT ")
+			limitationSb.WriteString(strconv.Itoa(s.Limit))
+			limitationSb.WriteByte(' ')
+		}
+		limitationSb.WriteString("OFFSET ")
+		limitationSb.WriteString(strconv.Itoa(s.Offset))
+		limitationSb.WriteByte('\n')
+
+		prefix := s.tablePrefix()
+
+		mainQuery := selectSb.String() + joinSb.String() + fmt.Sprintf("JOIN paginated_ids ON paginated_ids.id = %s.id\n", prefix) + groupSb.String() + havingSb.String() + orderSb.String()
+		filteredData := fmt.Sprintf("SELECT %s.id as id from %s%s\n", prefix, s.Table, s.tableSample) + joinSb.String() + whereSb.String() + groupSb.String() + havingSb.String() + orderSb.String()
+		paginatedDataQuery := "SELECT id as id from filtered_ids\n" + limitationSb.String()
+		paginatedCountQuery := "SELECT COUNT(id) from filtered_ids\n"
+		return PaginationQuery(withSb.String(), mainQuery, filteredData, paginatedDataQuery, paginatedCountQuery), s.Args, nil
+	}
+
+	query := withSb.String() + selectSb.String() + joinSb.String() + whereSb.String() + groupSb.String() + havingSb.String() + orderSb.String()
+
+	if s.cursorLimit > 0 {
+		query += "LIMIT " + strconv.Itoa(s.cursorLimit) + "\n"
+	} else if s.Limit > 0 {
+		query += "LIMIT " + strconv.Itoa(s.Limit) + "\n"
+		if s.Offset > 0 {
+			query += "OFFSET " + strconv.Itoa(s.Offset) + "\n"
+		}
+	}
+
+	if s.lockClause != "" {
+		query += s.lockClause + "\n"
+	}
+
+	return query, s.Args, nil
+}
+
+// Internal Utils for builder select
+
+// aliasExpressions maps the lower cased alias of every selected column to its expression.
+func (s *SQLEloquentQuery) aliasExpressions() map[string]string {
+	aliasToExpr := make(map[string]string, len(s.Columns))
+	for _, col := range s.Columns {
+		expr, alias := splitColumnAlias(col)
+		aliasToExpr[strings.ToLower(alias)] = expr
+	}
+
+	return aliasToExpr
+}
+
+func extractAlias(column string) string {
+	parts := strings.Split(strings.ToLower(column), " as ")
+	if len(parts) < 2 {
+		return "" // No alias
+	}
+	return strings.Trim(strings.TrimSpace(parts[len(parts)-1]), "\"")
+}
+
+// func validateGroupByColumns(availableColumns []string, groupByColumns []string) error {
+// 	var columnNames []string
+// 	for _, column := range availableColumns {
+// 		columnNames = append(columnNames, extractAlias(column))
+// 	}
+
+// 	for _, each := range groupByColumns {
+// 		if !util.ArrayIncludes(columnNames, strings.ToLower(each)) {
+// 			return errors.New("GROUP BY columns should exist in selected columns")
+// 		}
+// 	}
+
+// 	return nil
+// }
@@ -1,221 +1,289 @@
-package sql_query
-
-import (
-	"encoding/json"
-	"fmt"
-	"reflect"
-	"sort"
-	"strings"
-)
-
-func (s *SelectBuilder) SelectJSONArrayElements(alias string, arrayElements []map[string]string) SQLSelectChainBuilder {
-	if alias == "" {
-		alias = "array_elements"
-	}
-
-	jsonBytes, err := json.Marshal(arrayElements)
-	if err != nil {
-		s.LastError = fmt.Errorf("SelectJSONArrayElements marshal error: %w", err)
-		return s
-	}
-
-	jsonStr := string(jsonBytes)
-	placeholder := len(s.Args) + 1
-	s.Args = append(s.Args, jsonStr)
-
-	formatted := fmt.Sprintf(`jsonb_array_elements(%d::jsonb) AS "%s"`, placeholder, alias)
-	s.Columns = append(s.Columns, formatted)
-
-	return s
-}
-
-func (s *SelectBuilder) SelectJSONAggregate(alias string, dto any, condition string, asArrayAggregation bool, orderByClauses ...string) SQLSelectChainBuilder {
-	var mappedJSON map[string]string
-
-	v := reflect.ValueOf(dto)
-	if v.Kind() != reflect.Struct {
-		mappedJSON = dto.(map[string]string)
-	} else {
-		mappedJSON = MakeMapJSONTagsFromValue(dto)
-	}
-
-	if len(mappedJSON) == 0 {
-		return s
-	}
-
-	var keyValuePairs []string
-	var keys []string
-	for k := range mappedJSON {
-		keys = append(keys, k)
-	}
-	sort.Strings(keys)
-
-	for _, jsonKey := range keys {
-		columnExpr := mappedJSON[jsonKey]
-		keyValuePairs = append(keyValuePairs,
-			fmt.Sprintf("'%s'", jsonKey),
-			columnExpr,
-		)
-	}
-
-	var formattedColumn string
-	if asArrayAggregation {
-		orderBy := ""
-		if len(orderByClauses) > 0 {
-			orderBy = fmt.Sprintf(" ORDER BY %s", orderByClauses[0])
-		}
-		formattedColumn = fmt.Sprintf("jsonb_agg(jsonb_build_object(%s)%s)", strings.Join(keyValuePairs, ", "), orderBy)
-		if condition != "" {
-			formattedColumn = fmt.Sprintf("%s FILTER (WHERE %s)", formattedColumn, condition)
-		}
-	} else {
-		formattedColumn = fmt.Sprintf("jsonb_build_object(%s)", strings.Join(keyValuePairs, ", "))
-		if condition != "" {
-			formattedColumn = fmt.Sprintf("CASE WHEN %s THEN %s ELSE NULL END", condition, formattedColumn)
-		}
-	}
-	formattedColumn = fmt.Sprintf(`%s AS "%s"`, formattedColumn, alias)
-
-	if s.WrapAggregation && !asArrayAggregation {
-		s.NestedAggregation = append(s.NestedAggregation, formattedColumn)
-	} else {
-		s.Columns = append(s.Columns, formattedColumn)
-	}
-
-	return s
-}
-
-func (s *SelectBuilder) SelectJSONAggregateCoalesce(alias string, dto any, condition string, asArrayAggregation bool, coalesce string, orderByClauses ...string) SQLSelectChainBuilder {
-	var mappedJSON map[string]string
-
-	v := reflect.ValueOf(dto)
-	if v.Kind() != reflect.Struct {
-		mappedJSON = dto.(map[string]string)
-	} else {
-		mappedJSON = MakeMapJSONTagsFromValue(dto)
-	}
-
-	if len(mappedJSON) == 0 {
-		return s
-	}
-
-	var keyValuePairs []string
-	var keys []string
-	for k := range mappedJSON {
-		keys = append(keys, k)
-	}
-	sort.Strings(keys)
-
-	for _, jsonKey := range keys {
-		columnExpr := mappedJSON[jsonKey]
-		keyValuePairs = append(keyValuePairs,
-			fmt.Sprintf("'%s'", jsonKey),
-			columnExpr,
-		)
-	}
-
-	var formattedColumn string
-	if asArrayAggregation {
-		orderBy := ""
-		if len(orderByClauses) > 0 {
-			orderBy = fmt.Sprintf(" ORDER BY %s", orderByClauses[0])
-		}
-		formattedColumn = fmt.Sprintf("jsonb_agg(jsonb_build_object(%s)%s)", strings.Join(keyValuePairs, ", "), orderBy)
-
-		if condition != "" {
-			formattedColumn = fmt.Sprintf("%s FILTER (WHERE %s)", formattedColumn, condition)
-		}
-		formattedColumn = fmt.Sprintf("COALESCE(%s,%s)", formattedColumn, coalesce)
-
-	} else {
-		formattedColumn = fmt.Sprintf("jsonb_build_object(%s)", strings.Join(keyValuePairs, ", "))
-
-		if condition != "" {
-			formattedColumn = fmt.Sprintf("CASE WHEN %s THEN %s ELSE NULL END", condition, formattedColumn)
-		}
-		formattedColumn = fmt.Sprintf("COALESCE(%s,%s)", formattedColumn, coalesce)
-
-	}
-	formattedColumn = fmt.Sprintf(`%s AS "%s"`, formattedColumn, alias)
-
-	if s.WrapAggregation && !asArrayAggregation {
-		s.NestedAggregation = append(s.NestedAggregation, formattedColumn)
-	} else {
-		s.Columns = append(s.Columns, formattedColumn)
-	}
-
-	return s
-}
-
-func (s *SelectBuilder) SelectJSONAggregateDistinct(alias string, dto any, condition string, asArrayAggregation bool, orderByClauses ...string) SQLSelectChainBuilder {
-	var mappedJSON map[string]string
-
-	v := reflect.ValueOf(dto)
-	if v.Kind() != reflect.Struct {
-		mappedJSON = dto.(map[string]string)
-	} else {
-		mappedJSON = MakeMapJSONTagsFromValue(dto)
-	}
-
-	if len(mappedJSON) == 0 {
-		return s
-	}
-
-	var keyValuePairs []string
-	var keys []string
-	for k := range mappedJSON {
-		keys = append(keys, k)
-	}
-	sort.Strings(keys)
-
-	for _, jsonKey := range keys {
-		columnExpr := mappedJSON[jsonKey]
-		keyValuePairs = append(keyValuePairs,
-			fmt.Sprintf("'%s'", jsonKey),
-			columnExpr,
-		)
-	}
-
-	var formattedColumn string
-	if asArrayAggregation {
-		orderBy := ""
-		if len(orderByClauses) > 0 {
-			orderBy = fmt.Sprintf(" ORDER BY %s", orderByClauses[0])
-		}
-		formattedColumn = fmt.Sprintf("jsonb_agg(DISTINCT jsonb_build_object(%s)%s)", strings.Join(keyValuePairs, ", "), orderBy)
-		if condition != "" {
-			formattedColumn = fmt.Sprintf("%s FILTER (WHERE %s)", formattedColumn, condition)
-		}
-	} else {
-		formattedColumn = fmt.Sprintf("jsonb_build_object(%s)", strings.Join(keyValuePairs, ", "))
-		if condition != "" {
-			formattedColumn = fmt.Sprintf("CASE WHEN %s THEN %s ELSE NULL END", condition, formattedColumn)
-		}
-	}
-	formattedColumn = fmt.Sprintf(`%s AS "%s"`, formattedColumn, alias)
-
-	if s.WrapAggregation && !asArrayAggregation {
-		s.NestedAggregation = append(s.NestedAggregation, formattedColumn)
-	} else {
-		s.Columns = append(s.Columns, formattedColumn)
-	}
-
-	return s
-}
-
-func (s *SelectBuilder) SelectJSONAggregateFunc(alias string, fn func(builder *SelectBuilder)) SQLSelectChainBuilder {
-	if alias == "" {
-		alias = "json_result"
-	}
-
-	s.WrapAggregation = true
-	fn(s)
-
-	s.Columns = append(
-		s.Columns,
-		fmt.Sprintf(`jsonb_build_object(%s) AS "%s"`, strings.Join(s.NestedAggregation, ", "), alias),
-	)
-
-	s.WrapAggregation = false
-	return s
-}
+package sql_query
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+func (s *SelectBuilder) SelectJSONArrayElements(alias string, arrayElements []map[string]string) SQLSelectChainBuilder {
+	if alias == "" {
+		alias = "array_elements"
+	}
+
+	jsonBytes, err := json.Marshal(arrayElements)
+	if err != nil {
+		s.LastError = fmt.Errorf("SelectJSONArrayElements marshal error: %w", err)
+		return s
+	}
+
+	jsonStr := string(jsonBytes)
+	placeholder := len(s.Args) + 1
+	s.Args = append(s.Args, jsonStr)
+
+	formatted := fmt.Sprintf(`jsonb_array_elements($%d::jsonb) AS "%s"`, placeholder, alias)
+	s.Columns = append(s.Columns, formatted)
+
+	return s
+}
+
+func (s *SelectBuilder) FromJSONArray(alias string, elements any) SQLSelectChainBuilder {
+	if !isPlainIdentifier(alias) {
+		s.LastError = fmt.Errorf("%w: FromJSONArray alias %q must be a plain identifier", ErrInvalidValues, alias)
+		return s
+	}
+
+	typ := reflect.TypeOf(elements)
+	if typ == nil || typ.Kind() != reflect.Slice {
+		s.LastError = fmt.Errorf("%w: FromJSONArray needs a slice of structs, got %T", ErrInvalidValues, elements)
+		return s
+	}
+
+	columns, err := recordsetColumns(typ.Elem())
+	if err != nil {
+		s.LastError = err
+		return s
+	}
+
+	jsonBytes, err := json.Marshal(elements)
+	if err != nil {
+		s.LastError = fmt.Errorf("FromJSONArray marshal error: %w", err)
+		return s
+	}
+	// A nil slice marshals to null, which jsonb_to_recordset rejects
+	if reflect.ValueOf(elements).IsNil() {
+		jsonBytes = []byte("[]")
+	}
+
+	s.Args = append(s.Args, string(jsonBytes))
+	s.OtherTables = append(s.OtherTables, fmt.Sprintf(
+		"CROSS JOIN LATERAL jsonb_to_recordset($%d::jsonb) AS %s(%s)",
+		len(s.Args), alias, strings.Join(columns, ", "),
+	))
+
+	return s
+}
+
+// recordsetColumns returns the column definitions of jsonb_to_recordset for the JSON fields of a struct type,
+// named after their JSON key since the record's columns are matched to the keys.
+func recordsetColumns(typ reflect.Type) ([]string, error) {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: FromJSONArray needs a slice of structs, got elements of %s", ErrInvalidValues, typ)
+	}
+
+	var columns []string
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		columns = append(columns, fmt.Sprintf(`"%s" %s`, name, recordsetType(field.Type)))
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("%w: FromJSONArray elements of %s have no JSON field", ErrInvalidValues, typ)
+	}
+
+	return columns, nil
+}
+
+// recordsetType maps a Go field type to the Postgres type its JSON value is read as.
+func recordsetType(typ reflect.Type) string {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == reflect.TypeOf(time.Time{}) {
+		return "timestamptz"
+	}
+
+	switch typ.Kind() {
+	case reflect.String:
+		return "text"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "bigint"
+	case reflect.Float32, reflect.Float64:
+		return "double precision"
+	}
+
+	return "jsonb"
+}
+
+func (s *SelectBuilder) SelectJSONAggregate(alias string, dto any, condition string, asArrayAggregation bool, orderByClauses ...string) SQLSelectChainBuilder {
+	s.selectJSONAggregate(alias, dto, condition, nil, asArrayAggregation, false, "", orderByClauses)
+	return s
+}
+
+func (s *SelectBuilder) SelectJSONAggregateWithArgs(alias string, dto any, condition string, conditionArgs []any, asArrayAggregation bool, orderByClauses ...string) SQLSelectChainBuilder {
+	s.selectJSONAggregate(alias, dto, condition, conditionArgs, asArrayAggregation, false, "", orderByClauses)
+	return s
+}
+
+func (s *SelectBuilder) SelectJSONAggregateCoalesce(alias string, dto any, condition string, asArrayAggregation bool, coalesce string, orderByClauses ...string) SQLSelectChainBuilder {
+	s.selectJSONAggregate(alias, dto, condition, nil, asArrayAggregation, false, coalesce, orderByClauses)
+	return s
+}
+
+func (s *SelectBuilder) SelectJSONAggregateCoalesceWithArgs(alias string, dto any, condition string, conditionArgs []any, asArrayAggregation bool, coalesce string, orderByClauses ...string) SQLSelectChainBuilder {
+	s.selectJSONAggregate(alias, dto, condition, conditionArgs, asArrayAggregation, false, coalesce, orderByClauses)
+	return s
+}
+
+func (s *SelectBuilder) SelectJSONAggregateDistinct(alias string, dto any, condition string, asArrayAggregation bool, orderByClauses ...string) SQLSelectChainBuilder {
+	s.selectJSONAggregate(alias, dto, condition, nil, asArrayAggregation, true, "", orderByClauses)
+	return s
+}
+
+func (s *SelectBuilder) SelectJSONAggregateDistinctWithArgs(alias string, dto any, condition string, conditionArgs []any, asArrayAggregation bool, orderByClauses ...string) SQLSelectChainBuilder {
+	s.selectJSONAggregate(alias, dto, condition, conditionArgs, asArrayAggregation, true, "", orderByClauses)
+	return s
+}
+
+// selectJSONAggregate is shared by the SelectJSONAggregate* functions, an empty coalesce leaves the aggregate unwrapped.
+// The ? of condition are bound to conditionArgs, when given, like those of WhereRaw.
+func (s *SelectBuilder) selectJSONAggregate(
+	alias string,
+	dto any,
+	condition string,
+	conditionArgs []any,
+	asArrayAggregation bool,
+	distinct bool,
+	coalesce string,
+	orderByClauses []string,
+) {
+	var mappedJSON map[string]string
+
+	v := reflect.ValueOf(dto)
+	if v.Kind() != reflect.Struct {
+		mappedJSON = dto.(map[string]string)
+	} else {
+		mappedJSON = MakeMapJSONTagsFromValue(dto)
+	}
+
+	if len(mappedJSON) == 0 {
+		return
+	}
+
+	// Without args the condition is taken as is, its placeholders may refer to arguments bound with AddArgs
+	if len(conditionArgs) > 0 {
+		bound, err := s.bindQuestionMarks(condition, conditionArgs)
+		if err != nil {
+			s.LastError = err
+			return
+		}
+		condition = bound
+	}
+
+	var keyValuePairs []string
+	var keys []string
+	for k := range mappedJSON {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, jsonKey := range keys {
+		columnExpr := mappedJSON[jsonKey]
+		keyValuePairs = append(keyValuePairs,
+			fmt.Sprintf("'%s'", jsonKey),
+			columnExpr,
+		)
+	}
+
+	var formattedColumn string
+	if asArrayAggregation {
+		aggregate := "jsonb_agg("
+		if distinct {
+			aggregate = "jsonb_agg(DISTINCT "
+		}
+
+		orderBy := jsonAggregateOrderBy(orderByClauses)
+		formattedColumn = fmt.Sprintf("%sjsonb_build_object(%s)%s)", aggregate, strings.Join(keyValuePairs, ", "), orderBy)
+		if condition != "" {
+			formattedColumn = fmt.Sprintf("%s FILTER (WHERE %s)", formattedColumn, condition)
+		}
+	} else {
+		formattedColumn = fmt.Sprintf("jsonb_build_object(%s)", strings.Join(keyValuePairs, ", "))
+		if condition != "" {
+			formattedColumn = fmt.Sprintf("CASE WHEN %s THEN %s ELSE NULL END", condition, formattedColumn)
+		}
+	}
+	if coalesce != "" {
+		formattedColumn = fmt.Sprintf("COALESCE(%s,%s)", formattedColumn, coalesce)
+	}
+	formattedColumn = fmt.Sprintf(`%s AS "%s"`, formattedColumn, alias)
+
+	if s.WrapAggregation && !asArrayAggregation {
+		s.NestedAggregation = append(s.NestedAggregation, formattedColumn)
+	} else {
+		s.Columns = append(s.Columns, formattedColumn)
+	}
+}
+
+// jsonAggregateOrderBy renders the ORDER BY of a jsonb_agg from every non-empty clause, in the given order.
+func jsonAggregateOrderBy(orderByClauses []string) string {
+	clauses := make([]string, 0, len(orderByClauses))
+	for _, clause := range orderByClauses {
+		if clause = strings.TrimSpace(clause); clause != "" {
+			clauses = append(clauses, clause)
+		}
+	}
+	if len(clauses) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf(" ORDER BY %s", strings.Join(clauses, ", "))
+}
+
+func (s *SelectBuilder) AggregateOrder(rules []OrderRule, args ...any) string {
+	sortingRules := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		sortingRule, err := rule.sortingRule()
+		if err != nil {
+			s.LastError = err
+			return ""
+		}
+
+		sortingRules = append(sortingRules, sortingRule)
+	}
+
+	orderBy, err := s.bindQuestionMarks(strings.Join(sortingRules, ", "), args)
+	if err != nil {
+		s.LastError = err
+		return ""
+	}
+
+	return orderBy
+}
+
+func (s *SelectBuilder) SelectJSONAggregateFunc(alias string, fn func(builder *SelectBuilder)) SQLSelectChainBuilder {
+	if alias == "" {
+		alias = "json_result"
+	}
+
+	s.WrapAggregation = true
+	fn(s)
+
+	s.Columns = append(
+		s.Columns,
+		fmt.Sprintf(`jsonb_build_object(%s) AS "%s"`, strings.Join(s.NestedAggregation, ", "), alias),
+	)
+
+	s.WrapAggregation = false
+	return s
+}
@@ -0,0 +1,52 @@
+package sql_query
+
+import (
+	"errors"
+	"testing"
+
+	sqltesting "github.com/mystaline/clefinport-be/pkg/sql_query/testing"
+)
+
+func TestSelectJSONAggregateWithArgs(t *testing.T) {
+	builder := NewSQLSelectBuilder[any]("orders").
+		Where(map[string]SQLCondition{"orders.user_id": {Operator: SQLOperatorEqual, Value: "42"}}).
+		SelectJSONAggregateWithArgs("items", map[string]string{"id": "items.id"}, "items.status = ?", []any{"active"}, true, "items.created_at").
+		SelectJSONAggregateCoalesceWithArgs("tags", map[string]string{"name": "tags.name"}, "tags.kind = ?", []any{"label"}, true, "'[]'::jsonb").
+		SelectJSONAggregate("notes", map[string]string{"body": "notes.body"}, "notes.body <> ''", true, "notes.id")
+
+	sqltesting.AssertSQL(t, builder, `
+		SELECT
+			jsonb_agg(jsonb_build_object('id', items.id) ORDER BY items.created_at) FILTER (WHERE items.status = $2) AS "items",
+			COALESCE(jsonb_agg(jsonb_build_object('name', tags.name)) FILTER (WHERE tags.kind = $3),'[]'::jsonb) AS "tags",
+			jsonb_agg(jsonb_build_object('body', notes.body) ORDER BY notes.id) FILTER (WHERE notes.body <> '') AS "notes"
+		FROM orders
+		WHERE "orders"."user_id" = $1`,
+		[]any{"42", "active", "label"},
+	)
+}
+
+func TestSelectJSONAggregateWithArgsMismatch(t *testing.T) {
+	_, _, err := NewSQLSelectBuilder[any]("orders").
+		SelectJSONAggregateDistinctWithArgs("items", map[string]string{"id": "items.id"}, "items.status = ?", []any{"active", "draft"}, true).
+		Build()
+	if !errors.Is(err, ErrInvalidValues) {
+		t.Fatalf("Build() error = %v, want ErrInvalidValues", err)
+	}
+}
+
+func TestAggregateOrder(t *testing.T) {
+	builder := NewSQLSelectBuilder[any]("teams t").
+		Where(map[string]SQLCondition{"t.id": {Operator: SQLOperatorEqual, Value: "7"}})
+	builder.SelectJSONAggregateWithArgs("members", map[string]string{"name": "m.name"}, "m.active = ?", []any{true}, true,
+		builder.AggregateOrder([]OrderRule{
+			{Column: "array_position(?, m.role)"},
+			{Column: "m.name", Nulls: NullsLast},
+		}, []string{"owner", "admin"}))
+
+	sqltesting.AssertSQL(t, builder, `
+		SELECT jsonb_agg(jsonb_build_object('name', m.name) ORDER BY array_position($2, m.role) ASC, m.name ASC NULLS LAST) FILTER (WHERE m.active = $3) AS "members"
+		FROM teams t
+		WHERE "t"."id" = $1`,
+		[]any{"7", []string{"owner", "admin"}, true},
+	)
+}
@@ -0,0 +1,62 @@
+package sql_query
+
+import (
+	"testing"
+
+	sqltesting "github.com/mystaline/clefinport-be/pkg/sql_query/testing"
+)
+
+func TestOrderByCase(t *testing.T) {
+	builder := NewSQLSelectBuilder[any]("orders").
+		Where(map[string]SQLCondition{"orders.user_id": {Operator: SQLOperatorEqual, Value: "42"}}).
+		OrderByCase("CASE WHEN status = ? THEN 0 WHEN status = ? THEN 1 ELSE 2 END", []any{"pending", "processing"}, true).
+		OrderByCase("CASE WHEN priority = ? THEN 0 ELSE 1 END", []any{"high"}, false)
+
+	sqltesting.AssertSQL(t, builder, `
+		SELECT *
+		FROM orders
+		WHERE "orders"."user_id" = $1
+		ORDER BY CASE WHEN status = $2 THEN 0 WHEN status = $3 THEN 1 ELSE 2 END ASC,
+			CASE WHEN priority = $4 THEN 0 ELSE 1 END DESC`,
+		[]any{"42", "pending", "processing", "high"},
+	)
+}
+
+func TestWhereEpochTime(t *testing.T) {
+	from, to := int64(1672506000000), int64(1675097999000)
+
+	builder := NewSQLSelectBuilder[any]("transactions").
+		Where(map[string]SQLCondition{"created_at": {Operator: SQLOperatorBetween, Value: []*int64{&from, &to}, IsEpochTime: true}})
+
+	sqltesting.AssertSQL(t, builder, `
+		SELECT *
+		FROM transactions
+		WHERE "created_at" BETWEEN to_timestamp($1) AND to_timestamp($2)`,
+		[]any{int64(1672506000), int64(1675097999)},
+	)
+
+	builder = NewSQLSelectBuilder[any]("transactions").
+		Where(map[string]SQLCondition{"created_at": {
+			Operator: SQLOperatorBetween, Value: []*int64{&from, &to}, IsEpochTime: true, Timezone: "Asia/Jakarta",
+		}})
+
+	sqltesting.AssertSQL(t, builder, `
+		SELECT *
+		FROM transactions
+		WHERE "created_at" BETWEEN date_trunc('day', to_timestamp($1), $2)
+			AND (date_trunc('day', to_timestamp($3), $4) + interval '1 day' - interval '1 microsecond')`,
+		[]any{int64(1672506000), "Asia/Jakarta", int64(1675097999), "Asia/Jakarta"},
+	)
+
+	builder = NewSQLSelectBuilder[any]("transactions").
+		Where(map[string]SQLCondition{"created_at": {
+			Operator: SQLOperatorBetween, Value: []*int64{nil, &to}, IsEpochTime: true, Timezone: "Asia/Jakarta",
+		}})
+
+	sqltesting.AssertSQL(t, builder, `
+		SELECT *
+		FROM transactions
+		WHERE "created_at" <= (date_trunc('day', to_timestamp($1), $2) + interval '1 day' - interval '1 microsecond')`,
+		[]any{int64(1675097999), "Asia/Jakarta"},
+	)
+}
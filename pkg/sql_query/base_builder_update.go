@@ -1,9 +1,13 @@
 package sql_query
 
 import (
+	"database/sql/driver"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"reflect"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -102,12 +106,16 @@ type SQLUpdateInitBuilder interface {
 	UpdateEach(values interface{}, rowIdentifier string) SQLUpdateChainBuilder
 
 	// AddCase initializes a conditional CASE expression for the given column in an UPDATE statement.
-	// It use completely different CASE expressions from previous AddCase and allows chaining multiple conditional branches using Case() and Else().
+	// Each column gets its own CASE expression, rendered in the order of the AddCase calls.
+	// Calling AddCase again for the same column appends more branches to its CASE expression.
+	// The columns set by a preceding Update, Increment or Decrement follow the CASE columns.
 	//
 	// Example:
-	//   builder.AddCase("status", func(b UpdateCases) {
+	//   builder.Update(map[string]any{"note": note}).AddCase("status", func(b UpdateCases) {
 	//       b.Case(...)
 	//       b.Else(...)
+	//   }).AddCase("priority", func(b UpdateCases) {
+	//       b.Case(...)
 	//   })
 	//
 	// Parameters:
@@ -119,7 +127,8 @@ type SQLUpdateInitBuilder interface {
 	AddCase(setColumn string, fn func(b UpdateCases)) SQLUpdateChainBuilder
 
 	// Increment is used to replace Update() for adding int value cause update cant handle that
-	// Increment builds an UPDATE query that increases integer columns by a given value.
+	// Increment builds an UPDATE query that increases numeric columns by a given value.
+	// Values must be integers, floats or driver.Valuer implementations such as decimal types.
 	// Automatically sets updated_at = NOW().
 	//
 	// Example:
@@ -128,6 +137,39 @@ type SQLUpdateInitBuilder interface {
 	//
 	// → UPDATE table SET "count" = "count" + $1, "updated_at" = NOW()
 	Increment(values map[string]any) SQLUpdateChainBuilder
+	// Decrement is Increment subtracting the values, see GuardMin to keep the columns from going below a floor.
+	// Values must be integers, floats or driver.Valuer implementations such as decimal types.
+	//
+	// Example:
+	//
+	//	builder.Decrement(map[string]any{"balance": 15000})
+	//
+	// → UPDATE table SET "balance" = "balance" - $1, "updated_at" = NOW()
+	Decrement(values map[string]any) SQLUpdateChainBuilder
+
+	// StampActor sets the updated_by column of Update, UpdateEach and Increment to stamp.Actor,
+	// unless the values already set it. Call it before them.
+	//
+	// Example:
+	//
+	//	NewSQLUpdateBuilder("wallets").
+	//	    StampActor(ActorStamp{Actor: userID, UpdatedBy: "updated_by"}).
+	//	    Update(map[string]any{"name": "Savings"})
+	//
+	// → UPDATE wallets SET "name" = $1, "updated_at" = NOW(), "updated_by" = $2
+	StampActor(stamp ActorStamp) SQLUpdateInitBuilder
+	// WithoutTouch keeps Update, UpdateEach, Increment, Decrement and AddCase from setting updated_at = NOW(),
+	// for maintenance migrations and replicated writes that must not look like fresh changes. Call it before them.
+	// An updated_at given in the values is still written.
+	//
+	// Example:
+	//
+	//	NewSQLUpdateBuilder("wallets").
+	//	    WithoutTouch().
+	//	    Update(map[string]any{"currency": "IDR"})
+	//
+	// → UPDATE wallets SET "currency" = $1
+	WithoutTouch() SQLUpdateInitBuilder
 
 	// updateEachClausesGenerator looks at every struct in the slice and builds:
 	//  1. The SET part of the query (e.g., "name = v.name"),
@@ -166,7 +208,10 @@ type SQLUpdateInitBuilder interface {
 // e.g. .Update(...).From(...).Build()
 type SQLUpdateChainBuilder interface {
 	// AddCase initializes a conditional CASE expression for the given column in an UPDATE statement.
-	// It clears any existing CASE expressions and allows chaining multiple conditional branches using Case() and Else().
+	// Each column gets its own CASE expression, rendered in the order of the AddCase calls.
+	// Calling AddCase again for the same column appends more branches to its CASE expression.
+	// The columns set by the preceding Update, Increment or Decrement follow the CASE columns,
+	// a column can't be set by both.
 	//
 	// Example:
 	//   builder.AddCase("status", func(b UpdateCases) {
@@ -201,6 +246,29 @@ type SQLUpdateChainBuilder interface {
 	Where(filters map[string]SQLCondition) SQLUpdateChainBuilder
 	// WhereOr implements SQLUpdateChainBuilder. (Accumulates previous value if called again)
 	WhereOr(filters ...map[string]SQLCondition) SQLUpdateChainBuilder
+	// WhereIf applies Where only when cond is true.
+	WhereIf(cond bool, filters map[string]SQLCondition) SQLUpdateChainBuilder
+	// WhereGroup adds nested AND/OR condition trees, each group is AND-combined with the other filters.
+	//
+	// Example:
+	//
+	//	builder.WhereGroup(sql_query.OrGroup(nil,
+	//	    sql_query.AndGroup(map[string]SQLCondition{"a": {Operator: SQLOperatorEqual, Value: 1}},
+	//	        sql_query.OrGroup(map[string]SQLCondition{
+	//	            "b": {Operator: SQLOperatorEqual, Value: 2},
+	//	            "c": {Operator: SQLOperatorEqual, Value: 3},
+	//	        }),
+	//	    ),
+	//	    sql_query.AndGroup(map[string]SQLCondition{
+	//	        "d": {Operator: SQLOperatorEqual, Value: 4},
+	//	        "e": {Operator: SQLOperatorEqual, Value: 5},
+	//	    }),
+	//	))
+	//
+	// Generates:
+	//
+	//	(("a" = $1 AND ("b" = $2 OR "c" = $3)) OR ("d" = $4 AND "e" = $5))
+	WhereGroup(groups ...ConditionGroup) SQLUpdateChainBuilder
 
 	// Join adds an INNER JOIN clause with the specified ON condition.
 	//
@@ -214,6 +282,10 @@ type SQLUpdateChainBuilder interface {
 	//
 	//	builder.LeftJoin("roles r", "r.id = u.role_id")
 	LeftJoin(table string, onCondition string, additionalConditions ...map[string]SQLCondition) SQLUpdateChainBuilder
+	// JoinIf applies Join only when cond is true.
+	JoinIf(cond bool, table string, onCondition string, additionalConditions ...map[string]SQLCondition) SQLUpdateChainBuilder
+	// LeftJoinIf applies LeftJoin only when cond is true.
+	LeftJoinIf(cond bool, table string, onCondition string, additionalConditions ...map[string]SQLCondition) SQLUpdateChainBuilder
 
 	// WithCTEBuilder adds a Common Table Expression (CTE) to the query.
 	// It adjusts argument placeholders to avoid conflicts.
@@ -227,7 +299,13 @@ type SQLUpdateChainBuilder interface {
 	// Generates:
 	//
 	//	WITH recent_orders AS (SELECT id, user_id FROM orders) ...
-	WithCTEBuilder(cteName string, cteBuilder *SQLEloquentQuery) SQLUpdateChainBuilder
+	//
+	// An optional CTEOption hints the planner:
+	//
+	//	materialized := true
+	//	builder.WithCTEBuilder("page", cte.(*sql_query.SelectBuilder).SQLEloquentQuery, CTEOption{Materialized: &materialized})
+	//	→ WITH page AS MATERIALIZED (...) ...
+	WithCTEBuilder(cteName string, cteBuilder *SQLEloquentQuery, options ...CTEOption) SQLUpdateChainBuilder
 
 	// WithRecursiveCTEBuilder adds a Common Table Expression (CTE) to the query.
 	// It adjusts argument placeholders to avoid conflicts.
@@ -242,7 +320,18 @@ type SQLUpdateChainBuilder interface {
 	// Generates:
 	//
 	//	WITH RECURSIVE recent_orders AS (SELECT id, user_id FROM orders) ...
-	WithRecursiveCTEBuilder(cteName string, cteBuilder *SQLEloquentQuery) SQLUpdateChainBuilder
+	WithRecursiveCTEBuilder(cteName string, cteBuilder *SQLEloquentQuery, options ...CTEOption) SQLUpdateChainBuilder
+	// GetCTE returns the builder added under cteName by WithCTEBuilder or WithRecursiveCTEBuilder.
+	// A name can only be added once, adding it again sets an error: join the existing CTE from every
+	// branch needing it instead.
+	//
+	// Example:
+	//
+	//	if _, ok := builder.GetCTE("recent_orders"); !ok {
+	//	    builder.WithCTEBuilder("recent_orders", cte.(*sql_query.SelectBuilder).SQLEloquentQuery)
+	//	}
+	//	builder.LeftJoin("recent_orders", `"recent_orders"."user_id" = "users"."id"`)
+	GetCTE(cteName string) (*SQLEloquentQuery, bool)
 
 	// Return implements SQLUpdateChainBuilder. (Only able to be called once, overrides previous values if re-called).
 	// Return sets the columns to return after the update.
@@ -259,15 +348,115 @@ type SQLUpdateChainBuilder interface {
 	// → FROM users u, roles r
 	From(tables []string) SQLUpdateChainBuilder
 
+	// UpdateFromSelect implements SQLUpdateChainBuilder. (Accumulates previous value if called again)
+	// UpdateFromSelect sets setColumn to the single value returned by sub, evaluated per updated row,
+	// so it may reference the updated table. Placeholders of sub are shifted after the builder's arguments.
+	// Call it after Update, UpdateEach or Increment, setColumn must not be set by them.
+	//
+	// Example:
+	//
+	//	sub := NewSQLSelectBuilder[any]("transactions", "t").
+	//	    Select("COALESCE(SUM(t.amount), 0)").
+	//	    Where(map[string]SQLCondition{
+	//	        "t.wallet_id":  {Operator: SQLOperatorEqual, Value: "user_wallets.wallet_id", IsRef: true},
+	//	        "t.deleted_at": {Operator: SQLOperatorIsNull},
+	//	    })
+	//	builder.Update(map[string]any{}).
+	//	    UpdateFromSelect("balance", sub.(*SelectBuilder).SQLEloquentQuery).
+	//	    Where(map[string]SQLCondition{"wallet_id": {Operator: SQLOperatorEqual, Value: walletID}})
+	//
+	// → UPDATE user_wallets SET "updated_at" = NOW(), "balance" = (SELECT COALESCE(SUM(t.amount), 0) FROM transactions t WHERE ...) WHERE "wallet_id" = $1
+	UpdateFromSelect(setColumn string, sub *SQLEloquentQuery) SQLUpdateChainBuilder
+
+	// UpdateJSONBField implements SQLUpdateChainBuilder. (Accumulates previous value if called again)
+	// UpdateJSONBField sets the value at path inside the jsonb column, leaving the rest of the document untouched.
+	// value is encoded with encoding/json, createMissing adds the key when path doesn't exist yet.
+	// Path elements are object keys or array indexes, letters, digits, '_' and '-' only.
+	// Calling it again for the same column nests the jsonb_set calls, so several paths update in one statement.
+	// Call it after Update, UpdateEach or Increment, column must not be set by them.
+	//
+	// Example:
+	//
+	//	builder.Update(map[string]any{}).
+	//	    UpdateJSONBField("profile_settings", []string{"notifications", "email"}, false, true).
+	//	    UpdateJSONBField("profile_settings", []string{"theme"}, "dark", true).
+	//	    Where(map[string]SQLCondition{"id": {Operator: SQLOperatorEqual, Value: userID}})
+	//
+	// → UPDATE users SET "updated_at" = NOW(), "profile_settings" = jsonb_set(jsonb_set("profile_settings", '{notifications,email}', $1::jsonb, true), '{theme}', $2::jsonb, true) WHERE "id" = $3
+	UpdateJSONBField(column string, path []string, value any, createMissing bool) SQLUpdateChainBuilder
+
+	// WithVersion implements SQLUpdateChainBuilder. (Only able to be called once per column)
+	// WithVersion enables optimistic locking: the update only matches rows whose column still equals currentVersion,
+	// and increments it. Zero updated rows then mean the row changed since it was read, see StaleVersionError.
+	// Update does the same for the struct field tagged `special:"version"`.
+	// Call it after Update, UpdateEach or Increment, column must not be set by them.
+	//
+	// Example:
+	//
+	//	builder.Update(map[string]any{"name": name}).
+	//	    WithVersion("version", wallet.Version).
+	//	    Where(map[string]SQLCondition{"id": {Operator: SQLOperatorEqual, Value: wallet.ID}})
+	//
+	// → UPDATE wallets SET "name" = $1, "updated_at" = NOW(), "version" = "version" + 1 WHERE "version" = $2 AND "id" = $3
+	WithVersion(column string, currentVersion int) SQLUpdateChainBuilder
+
+	// Increment adds its columns to the SET list of the preceding Update, UpdateEach, Increment or Decrement.
+	// A column can only be set once.
+	//
+	// Example:
+	//
+	//	builder.Decrement(map[string]any{"balance": amount}).
+	//	    Increment(map[string]any{"spent": amount}).
+	//	    GuardMin("balance", 0)
+	//
+	// → UPDATE user_wallets SET "balance" = "balance" - $1, "updated_at" = NOW(), "spent" = "spent" + $2 WHERE "balance" - $1 >= $3
+	Increment(values map[string]any) SQLUpdateChainBuilder
+	// Decrement is Increment subtracting the values.
+	Decrement(values map[string]any) SQLUpdateChainBuilder
+
+	// GuardMin implements SQLUpdateChainBuilder. (Accumulates previous value if called again)
+	// GuardMin only updates the rows where column stays >= min once stepped by Increment or Decrement,
+	// so a deduction can't drive a balance negative. A guarded row is left untouched: UpdateMany reports
+	// fewer rows, UpdateOne fails with pgx.ErrNoRows.
+	// Call it after Increment or Decrement, column must be one of their values.
+	//
+	// Example:
+	//
+	//	builder.Decrement(map[string]any{"balance": amount}).
+	//	    GuardMin("balance", 0).
+	//	    Where(map[string]SQLCondition{"wallet_id": {Operator: SQLOperatorEqual, Value: walletID}})
+	//
+	// → UPDATE user_wallets SET "balance" = "balance" - $1, "updated_at" = NOW() WHERE "balance" - $1 >= $2 AND "wallet_id" = $3
+	GuardMin(column string, min any) SQLUpdateChainBuilder
+
+	// UseDialect overrides DefaultDialect for this query, MySQL and SQLite get `?` placeholders.
+	//
+	// Example:
+	//
+	//	builder.UseDialect(sql_query.DialectMySQL)
+	UseDialect(dialect Dialect) SQLUpdateChainBuilder
+
 	// buildUpdateQuery constructs the final UPDATE query string and its arguments.
-	// Ensures that CustomQuery is set and that a WHERE clause exists for safety.
+	// Ensures that something is set and that a WHERE clause exists for safety.
 	Build() (string, []interface{}, error)
+	// Prepare builds the query as a PreparedStatement for service ExecPrepared, like the SELECT one.
+	Prepare() (PreparedStatement, error)
+	// BuildWithMeta builds like Build and also returns the tables, mode and arg count of the query.
+	BuildWithMeta() (string, []interface{}, QueryMeta, error)
+	// BuildDebug builds the query with the args inlined as escaped literals, for copy-pasting logged queries into psql.
+	// Never execute its output.
+	BuildDebug() (string, error)
 }
 
 type UpdateBuilder struct {
 	*SQLEloquentQuery
 }
 
+func (s *UpdateBuilder) UseDialect(dialect Dialect) SQLUpdateChainBuilder {
+	s.Dialect = dialect
+	return s
+}
+
 func (s *UpdateBuilder) Where(filters map[string]SQLCondition) SQLUpdateChainBuilder {
 	s.SQLEloquentQuery.sharedWhereAndQuery(filters)
 	return s
@@ -278,6 +467,11 @@ func (s *UpdateBuilder) WhereOr(filters ...map[string]SQLCondition) SQLUpdateCha
 	return s
 }
 
+func (s *UpdateBuilder) WhereGroup(groups ...ConditionGroup) SQLUpdateChainBuilder {
+	s.SQLEloquentQuery.sharedWhereGroup(groups...)
+	return s
+}
+
 func (s *UpdateBuilder) Join(
 	table string,
 	onCondition string,
@@ -299,6 +493,7 @@ func (s *UpdateBuilder) Join(
 	}
 
 	s.OtherTables = append(s.OtherTables, fmt.Sprintf("JOIN %s ON %s%s", table, onCondition, filterSb.String()))
+	s.touchTable(table)
 	return s
 }
 
@@ -323,11 +518,16 @@ func (s *UpdateBuilder) LeftJoin(
 	}
 
 	s.OtherTables = append(s.OtherTables, fmt.Sprintf("LEFT JOIN %s ON %s%s", table, mainCondition, filterSb.String()))
+	s.touchTable(table)
 	return s
 }
 
-func (s *UpdateBuilder) WithCTEBuilder(cteName string, cteBuilder *SQLEloquentQuery) SQLUpdateChainBuilder {
-	cteQuery, cteArgs, err := cteBuilder.Build()
+func (s *UpdateBuilder) WithCTEBuilder(cteName string, cteBuilder *SQLEloquentQuery, options ...CTEOption) SQLUpdateChainBuilder {
+	if !s.registerCTE(cteName, cteBuilder) {
+		return s
+	}
+
+	cteQuery, cteArgs, err := cteBuilder.build()
 	if err != nil {
 		s.LastError = err
 		return s
@@ -339,14 +539,18 @@ func (s *UpdateBuilder) WithCTEBuilder(cteName string, cteBuilder *SQLEloquentQu
 	// Shift the placeholders in the CTE query
 	shiftedCTEQuery := shiftSQLPlaceholders(cteQuery, offset)
 
-	s.WithClauses = append(s.WithClauses, fmt.Sprintf("%s AS (%s)", cteName, shiftedCTEQuery))
-	s.Args = append(s.Args, cteArgs...)
+	s.WithClauses = append(s.WithClauses, cteClause(cteName, shiftedCTEQuery, options))
+	s.Args = appendArgs(s.Args, cteArgs)
 
 	return s
 }
 
-func (s *UpdateBuilder) WithRecursiveCTEBuilder(cteName string, cteBuilder *SQLEloquentQuery) SQLUpdateChainBuilder {
-	cteQuery, cteArgs, err := cteBuilder.Build()
+func (s *UpdateBuilder) WithRecursiveCTEBuilder(cteName string, cteBuilder *SQLEloquentQuery, options ...CTEOption) SQLUpdateChainBuilder {
+	if !s.registerCTE(cteName, cteBuilder) {
+		return s
+	}
+
+	cteQuery, cteArgs, err := cteBuilder.build()
 	if err != nil {
 		s.LastError = err
 		return s
@@ -358,14 +562,28 @@ func (s *UpdateBuilder) WithRecursiveCTEBuilder(cteName string, cteBuilder *SQLE
 	// Shift the placeholders in the CTE query
 	shiftedCTEQuery := shiftSQLPlaceholders(cteQuery, offset)
 
-	s.WithClauses = append(s.WithClauses, fmt.Sprintf("%s AS (%s)", cteName, shiftedCTEQuery))
-	s.Args = append(s.Args, cteArgs...)
+	s.WithClauses = append(s.WithClauses, cteClause(cteName, shiftedCTEQuery, options))
+	s.Args = appendArgs(s.Args, cteArgs)
 
 	s.useWithRecursive = true
 
 	return s
 }
 
+func (s *UpdateBuilder) WithoutTouch() SQLUpdateInitBuilder {
+	s.withoutTouch = true
+	return s
+}
+
+// touch appends the updated_at = NOW() clause, unless WithoutTouch was called.
+func (s *UpdateBuilder) touch(setClauses []string) []string {
+	if s.withoutTouch {
+		return setClauses
+	}
+
+	return append(setClauses, `"updated_at" = NOW()`)
+}
+
 func (s *UpdateBuilder) ExcludeEmpty() SQLUpdateChainBuilder {
 	s.excludeEmptyValue = true
 	return s
@@ -386,9 +604,19 @@ func (s *UpdateBuilder) Conflict(constraint, do string) SQLUpdateChainBuilder {
 }
 
 func (s *UpdateBuilder) AddCase(setColumn string, fn func(b UpdateCases)) SQLUpdateChainBuilder {
-	s.UpdateCaseClauses = make(map[string][]UpdateCaseParam, 0)
-	s.UpdateCaseClauses[setColumn] = []UpdateCaseParam{}
-	s.currentUpdateCaseKey = setColumn
+	s.currentUpdateCase = -1
+	for i := range s.UpdateCaseClauses {
+		if s.UpdateCaseClauses[i].Column == setColumn {
+			s.currentUpdateCase = i
+			break
+		}
+	}
+
+	if s.currentUpdateCase < 0 {
+		s.UpdateCaseClauses = append(s.UpdateCaseClauses, UpdateCaseClause{Column: setColumn})
+		s.currentUpdateCase = len(s.UpdateCaseClauses) - 1
+	}
+
 	fn(s)
 	return s
 }
@@ -417,10 +645,8 @@ func (s *UpdateBuilder) Case(conditions MultiFilterCondition, value interface{},
 		valueSb.WriteString(strconv.Itoa(len(s.Args)))
 	}
 
-	s.UpdateCaseClauses[s.currentUpdateCaseKey] = append(
-		s.UpdateCaseClauses[s.currentUpdateCaseKey],
-		UpdateCaseParam{conditions: filters, value: valueSb.String()},
-	)
+	current := &s.UpdateCaseClauses[s.currentUpdateCase]
+	current.Params = append(current.Params, UpdateCaseParam{conditions: filters, value: valueSb.String()})
 }
 
 func (s *UpdateBuilder) Else(value interface{}, isRef bool) {
@@ -433,10 +659,8 @@ func (s *UpdateBuilder) Else(value interface{}, isRef bool) {
 		valueSb.WriteString(strconv.Itoa(len(s.Args)))
 	}
 
-	s.UpdateCaseClauses[s.currentUpdateCaseKey] = append(
-		s.UpdateCaseClauses[s.currentUpdateCaseKey],
-		UpdateCaseParam{conditions: []string{}, value: valueSb.String(), isElse: true},
-	)
+	current := &s.UpdateCaseClauses[s.currentUpdateCase]
+	current.Params = append(current.Params, UpdateCaseParam{conditions: []string{}, value: valueSb.String(), isElse: true})
 }
 
 func (s *UpdateBuilder) Update(values interface{}) SQLUpdateChainBuilder {
@@ -449,15 +673,15 @@ func (s *UpdateBuilder) Update(values interface{}) SQLUpdateChainBuilder {
 	} else if v.Kind() == reflect.Map {
 		setClauses, hasUpdatedAt = s.extractUpdateFieldsMap(values.(map[string]any))
 	} else {
-		s.LastError = fmt.Errorf("invalid update values: expected struct or map, got %T", values)
+		s.LastError = fmt.Errorf("%w: expected struct or map, got %T", ErrInvalidValues, values)
 		return s
 	}
 
 	if !hasUpdatedAt {
-		setClauses = append(setClauses, `"updated_at" = NOW()`)
+		setClauses = s.touch(setClauses)
 	}
-
-	s.CustomQuery = fmt.Sprintf(`UPDATE %s SET %s`, s.Table, strings.Join(setClauses, ", "))
+	s.setClauses = s.stampUpdateClauses(setClauses)
+	s.updateValues = ""
 
 	return s
 }
@@ -467,14 +691,14 @@ func (s *UpdateBuilder) UpdateEach(values interface{}, rowIdentifier string) SQL
 
 	// Slice data, plus length checking
 	if v.Kind() != reflect.Slice || v.Len() == 0 {
-		s.LastError = errors.New("update many values must be non-empty slice of struct")
+		s.LastError = fmt.Errorf("%w: update many values must be non-empty slice of struct", ErrInvalidValues)
 		return s
 	}
 
 	// Check type of slice, should be slice of struct (only check the first index)
 	firstElem := v.Index(0)
 	if firstElem.Kind() != reflect.Struct {
-		s.LastError = errors.New("update slice must contain structs")
+		s.LastError = fmt.Errorf("%w: update slice must contain structs", ErrInvalidValues)
 		return s
 	}
 
@@ -492,12 +716,12 @@ func (s *UpdateBuilder) UpdateEach(values interface{}, rowIdentifier string) SQL
 	setClauses = append(setClauses, mappedSet...)
 	valueClauses = append(valueClauses, mappedValue...)
 	placeholders = append(placeholders, mappedPlaceholders...)
+	// After the generator, it resets the args
+	s.setClauses = s.stampUpdateClauses(setClauses)
 
-	// Build everything into one query dedicated for update many
-	s.CustomQuery = fmt.Sprintf(
-		`UPDATE %s SET %s FROM (VALUES %s) as v(%s)`,
-		s.Table,
-		strings.Join(setClauses, ", "),
+	// Rows are read from a VALUES list dedicated for update many
+	s.updateValues = fmt.Sprintf(
+		` FROM (VALUES %s) as v(%s)`,
 		strings.Join(placeholders, ", "),
 		strings.Join(valueClauses, ","),
 	)
@@ -508,24 +732,197 @@ func (s *UpdateBuilder) UpdateEach(values interface{}, rowIdentifier string) SQL
 func (s *UpdateBuilder) Increment(
 	values map[string]any,
 ) SQLUpdateChainBuilder {
-	var setClauses []string
+	return s.step(values, "+")
+}
 
-	for key, val := range values {
+func (s *UpdateBuilder) Decrement(
+	values map[string]any,
+) SQLUpdateChainBuilder {
+	return s.step(values, "-")
+}
+
+// step adds (+) or subtracts (-) values from their columns.
+// Following Update, UpdateEach or another step, it adds its columns to their SET list.
+func (s *UpdateBuilder) step(values map[string]any, operator string) SQLUpdateChainBuilder {
+	// Sorted, so the SET list and its placeholders don't depend on the map iteration order
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	if s.steps == nil {
+		s.steps = map[string]string{}
+	}
+
+	var setClauses []string
+	for _, key := range keys {
+		val := values[key]
 		snake := CamelToSnake(key)
+		if s.rejectGeneratedColumn(snake) {
+			return s
+		}
+		if !isNumericValue(val) {
+			s.LastError = fmt.Errorf("%w: %s must be a number, got %T", ErrInvalidValues, snake, val)
+			return s
+		}
+		if _, stepped := s.steps[snake]; stepped || s.setClauseIndex(snake) >= 0 {
+			s.LastError = fmt.Errorf("%w: column %s is already set", ErrInvalidValues, snake)
+			return s
+		}
 
-		setClauses = append(
-			setClauses,
-			fmt.Sprintf(`"%s" = "%s" + $%d`, snake, snake, len(s.Args)+1),
-		)
-		s.Args = append(s.Args, val)
+		s.Args = appendArgs(s.Args, []interface{}{val})
+		step := fmt.Sprintf(`"%s" %s $%d`, snake, operator, len(s.Args))
+		setClauses = append(setClauses, fmt.Sprintf(`"%s" = %s`, snake, step))
+		s.steps[snake] = step
 	}
 
-	setClauses = append(setClauses, `"updated_at" = NOW()`)
+	if len(s.setClauses) > 0 {
+		s.setClauses = append(s.setClauses, setClauses...)
+		return s
+	}
+
+	setClauses = s.touch(setClauses)
+	s.setClauses = s.stampUpdateClauses(setClauses)
+
+	return s
+}
+
+func (s *UpdateBuilder) GuardMin(column string, min any) SQLUpdateChainBuilder {
+	column = CamelToSnake(strings.Trim(strings.TrimSpace(column), `"`))
+	step, ok := s.steps[column]
+	if !ok {
+		s.LastError = fmt.Errorf("%w: GuardMin on %s must follow an Increment or Decrement of it", ErrInvalidValues, column)
+		return s
+	}
+	if !isNumericValue(min) {
+		s.LastError = fmt.Errorf("%w: GuardMin of %s must be a number, got %T", ErrInvalidValues, column, min)
+		return s
+	}
+
+	s.Args = appendArgs(s.Args, []interface{}{min})
+	s.Filters = append(s.Filters, fmt.Sprintf(`%s >= $%d`, step, len(s.Args)))
+
+	return s
+}
+
+// isNumericValue reports whether value can step a numeric column: integers, floats, or a driver.Valuer
+// (decimal types), which Postgres checks itself.
+func isNumericValue(value any) bool {
+	if _, ok := value.(driver.Valuer); ok {
+		return true
+	}
+
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+
+	return false
+}
+
+func (s *UpdateBuilder) UpdateFromSelect(setColumn string, sub *SQLEloquentQuery) SQLUpdateChainBuilder {
+	if len(s.setClauses) == 0 {
+		s.LastError = fmt.Errorf("%w: UpdateFromSelect must follow Update, UpdateEach or Increment", ErrInvalidValues)
+		return s
+	}
+
+	column := strings.Trim(strings.TrimSpace(setColumn), `"`)
+	if s.rejectGeneratedColumn(column) {
+		return s
+	}
+	if s.setClauseIndex(column) >= 0 {
+		s.LastError = fmt.Errorf("%w: column %s is already set", ErrInvalidValues, column)
+		return s
+	}
+
+	subQuery, subArgs, err := sub.build()
+	if err != nil {
+		s.LastError = err
+		return s
+	}
+
+	setClause := fmt.Sprintf(`"%s" = (%s)`, column, shiftSQLPlaceholders(strings.TrimSpace(subQuery), len(s.Args)))
+	s.Args = appendArgs(s.Args, subArgs)
+	s.touchTablesOf(sub)
+	s.setClauses = append(s.setClauses, setClause)
 
-	s.CustomQuery = fmt.Sprintf(`UPDATE %s SET %s`, s.Table, strings.Join(setClauses, ", "))
 	return s
 }
 
+// JSONB path elements, object keys or array indexes (negative ones count from the end)
+var jsonbPathRegexp = regexp.MustCompile(`^[\w-]+$`)
+
+func (s *UpdateBuilder) UpdateJSONBField(column string, path []string, value any, createMissing bool) SQLUpdateChainBuilder {
+	if len(s.setClauses) == 0 {
+		s.LastError = fmt.Errorf("%w: UpdateJSONBField must follow Update, UpdateEach or Increment", ErrInvalidValues)
+		return s
+	}
+
+	column = strings.Trim(strings.TrimSpace(column), `"`)
+	if s.rejectGeneratedColumn(column) {
+		return s
+	}
+	if len(path) == 0 {
+		s.LastError = fmt.Errorf("%w: UpdateJSONBField on %s needs a path", ErrInvalidValues, column)
+		return s
+	}
+	for _, element := range path {
+		if !jsonbPathRegexp.MatchString(element) {
+			s.LastError = fmt.Errorf("%w: invalid jsonb path element %q", ErrInvalidValues, element)
+			return s
+		}
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		s.LastError = fmt.Errorf("%w: jsonb value of %s: %v", ErrInvalidValues, column, err)
+		return s
+	}
+
+	// The document being updated, or the jsonb_set of the previous call on the same column
+	target, nested := s.jsonbSetClauses[column]
+	index := s.setClauseIndex(column)
+	if !nested {
+		if index >= 0 {
+			s.LastError = fmt.Errorf("%w: column %s is already set", ErrInvalidValues, column)
+			return s
+		}
+		target = fmt.Sprintf(`"%s"`, column)
+	}
+
+	s.Args = append(s.Args, string(encoded))
+	target = fmt.Sprintf(`jsonb_set(%s, '{%s}', $%d::jsonb, %t)`, target, strings.Join(path, ","), len(s.Args), createMissing)
+	setClause := fmt.Sprintf(`"%s" = %s`, column, target)
+
+	if nested {
+		s.setClauses[index] = setClause
+	} else {
+		s.setClauses = append(s.setClauses, setClause)
+	}
+
+	if s.jsonbSetClauses == nil {
+		s.jsonbSetClauses = map[string]string{}
+	}
+	s.jsonbSetClauses[column] = target
+
+	return s
+}
+
+// setClauseIndex returns the index of the SET clause of column in setClauses, -1 when the column isn't set.
+func (s *SQLEloquentQuery) setClauseIndex(column string) int {
+	prefix := fmt.Sprintf(`"%s" = `, column)
+	for i, setClause := range s.setClauses {
+		if strings.HasPrefix(setClause, prefix) {
+			return i
+		}
+	}
+
+	return -1
+}
+
 func (s *UpdateBuilder) From(tables []string) SQLUpdateChainBuilder {
 	if len(tables) < 1 {
 		return s
@@ -534,6 +931,9 @@ func (s *UpdateBuilder) From(tables []string) SQLUpdateChainBuilder {
 	var otherTables []string
 	otherTables = append(otherTables, fmt.Sprintf("FROM %s", strings.Join(tables, ", ")))
 	s.OtherTables = otherTables
+	for _, table := range tables {
+		s.touchTable(table)
+	}
 	return s
 }
 
@@ -554,7 +954,7 @@ func NewSQLUpdateBuilder(tableName string, alias ...string) SQLUpdateInitBuilder
 			Filters:     []string{},
 			OtherTables: []string{},
 			Columns:     []string{},
-			CustomQuery: "UPDATE",
+			CustomQuery: "",
 			Args:        nil,
 			Mode:        "update",
 		},
@@ -563,11 +963,11 @@ func NewSQLUpdateBuilder(tableName string, alias ...string) SQLUpdateInitBuilder
 
 func (s *SQLEloquentQuery) buildUpdateQuery() (string, []interface{}, error) {
 	if s.LastError != nil {
-		return "", nil, errors.New(s.LastError.Error())
+		return "", nil, s.LastError
 	}
 
-	if s.CustomQuery == "" {
-		return "", nil, errors.New("invalid update query: CustomQuery not set")
+	if len(s.setClauses) == 0 && len(s.UpdateCaseClauses) == 0 {
+		return "", nil, errors.New("invalid update query: nothing to set")
 	}
 
 	var initSb strings.Builder
@@ -586,10 +986,15 @@ func (s *SQLEloquentQuery) buildUpdateQuery() (string, []interface{}, error) {
 
 	initSb.WriteByte('\n')
 	if len(s.UpdateCaseClauses) > 0 {
-		initSb.WriteString(buildUpdateCase(s.UpdateCaseClauses, s.Table))
+		setList, err := s.caseSetList()
+		if err != nil {
+			return "", nil, err
+		}
+		s.CustomQuery = buildUpdateCase(s.UpdateCaseClauses, s.Table, setList, !s.withoutTouch)
 	} else {
-		initSb.WriteString(s.CustomQuery)
+		s.CustomQuery = fmt.Sprintf(`UPDATE %s SET %s%s`, s.Table, strings.Join(s.setClauses, ", "), s.updateValues)
 	}
+	initSb.WriteString(s.CustomQuery)
 
 	// WITH
 	if len(s.WithClauses) > 0 {
@@ -616,7 +1021,7 @@ func (s *SQLEloquentQuery) buildUpdateQuery() (string, []interface{}, error) {
 	}
 
 	if len(s.Filters) < 1 {
-		return "", nil, errors.New("unsafe query: DELETE/UPDATE must have WHERE clause")
+		return "", nil, fmt.Errorf("%w: unsafe query: DELETE/UPDATE must have WHERE clause", ErrInvalidValues)
 	}
 
 	// WHERE
@@ -661,6 +1066,9 @@ func (s *UpdateBuilder) updateEachClausesGenerator(
 			for j := 0; j < t.NumField(); j++ {
 				field := t.Field(j)
 				columnTag := field.Tag.Get("column")
+				if strings.Contains(field.Tag.Get("special"), "generated") {
+					continue
+				}
 
 				// If column tag is equal with rowIdentifier given by param, then it should not append into set clauses, only append to value clauses for WHERE condition
 				if columnTag == rowIdentifier {
@@ -672,7 +1080,7 @@ func (s *UpdateBuilder) updateEachClausesGenerator(
 			}
 
 			if ok := ArrayIncludes(valueClauses, "updated_at"); !ok {
-				setClauses = append(setClauses, `"updated_at" = NOW()`)
+				setClauses = s.touch(setClauses)
 			}
 		}
 
@@ -681,6 +1089,9 @@ func (s *UpdateBuilder) updateEachClausesGenerator(
 		// Add fields
 		for j := 0; j < t.NumField(); j++ {
 			field := t.Field(j)
+			if strings.Contains(field.Tag.Get("special"), "generated") {
+				continue
+			}
 			transformTag := field.Tag.Get("transform")
 			args = append(args, v.Field(j).Interface())
 			rowPlaceholders = append(
@@ -710,7 +1121,7 @@ func (s *UpdateBuilder) extractUpdateFieldsStruct(v reflect.Value) ([]string, bo
 		field := t.Field(i)
 		val := v.Field(i)
 
-		if val.IsZero() && s.excludeEmptyValue {
+		if val.IsZero() && s.excludeEmptyValue && !strings.Contains(field.Tag.Get("special"), "version") {
 			continue
 		}
 
@@ -724,7 +1135,23 @@ func (s *UpdateBuilder) extractUpdateFieldsStruct(v reflect.Value) ([]string, bo
 		specialTag := field.Tag.Get("special")
 
 		// Handle ignored fields
-		if jsonTag == "-" || strings.Contains(specialTag, "generated") {
+		if jsonTag == "-" {
+			continue
+		}
+		// The version is checked and incremented, never written from the struct
+		if strings.Contains(specialTag, "version") {
+			if current, ok := versionValue(val); ok {
+				setClauses = append(setClauses, s.versionCheck(versionColumn(field), current))
+			} else {
+				s.LastError = fmt.Errorf("%w: version field %s must be an integer", ErrInvalidValues, field.Name)
+			}
+			continue
+		}
+		// Generated columns are only skipped, unless explicitly written with raw SQL
+		if strings.Contains(specialTag, "generated") {
+			if _, ok := val.Interface().(UpdateRawSQL); ok {
+				s.LastError = fmt.Errorf("%w: %s", ErrGeneratedColumn, field.Name)
+			}
 			continue
 		}
 
@@ -769,10 +1196,10 @@ func (s *UpdateBuilder) extractUpdateFieldsStruct(v reflect.Value) ([]string, bo
 
 		switch v := fieldVal.(type) {
 		case UpdateRawSQL:
-			expr := v.Expr
-			for _, arg := range v.Args {
-				expr = strings.Replace(expr, "?", fmt.Sprintf("$%d", len(s.Args)+1), 1)
-				s.Args = append(s.Args, arg)
+			expr, err := s.bindQuestionMarks(v.Expr, v.Args)
+			if err != nil {
+				s.LastError = err
+				continue
 			}
 			setClauses = append(setClauses, fmt.Sprintf(`"%s" = %s`, col, expr))
 
@@ -823,19 +1250,22 @@ func (s *UpdateBuilder) extractUpdateFieldsMap(v map[string]any) ([]string, bool
 
 		// Use column name as key, or field name if not provided
 		col := key
+		if s.rejectGeneratedColumn(col) {
+			continue
+		}
 		if col == "updated_at" {
 			hasUpdatedAt = true
 		}
 
 		switch v := value.(type) {
 		case UpdateRawSQL:
-			fmt.Println("v := value.(type) UpdateRawSQL", v)
-			expr := v.Expr
+			traceQuery("update raw sql", "column", col, "expr", v.Expr, "args", len(v.Args))
 
 			// replace ? with correct $n placeholders
-			for _, arg := range v.Args {
-				expr = strings.Replace(expr, "?", fmt.Sprintf("$%d", len(s.Args)+1), 1)
-				s.Args = append(s.Args, arg)
+			expr, err := s.bindQuestionMarks(v.Expr, v.Args)
+			if err != nil {
+				s.LastError = err
+				continue
 			}
 			setClauses = append(setClauses, fmt.Sprintf(`"%s" = %s`, col, expr))
 		default:
@@ -847,15 +1277,33 @@ func (s *UpdateBuilder) extractUpdateFieldsMap(v map[string]any) ([]string, bool
 	return setClauses, hasUpdatedAt
 }
 
+// caseSetList returns the SET list of a preceding Update, Increment or Decrement, rendered after the CASE columns.
+// UpdateEach reads its values from a VALUES list the CASE expressions can't be combined with.
+func (s *SQLEloquentQuery) caseSetList() (string, error) {
+	if s.updateValues != "" {
+		return "", fmt.Errorf("%w: AddCase can't be combined with UpdateEach", ErrInvalidValues)
+	}
+
+	for _, each := range s.UpdateCaseClauses {
+		column := strings.Trim(each.Column, `"`)
+		if s.setClauseIndex(column) >= 0 {
+			return "", fmt.Errorf("%w: column %s is set by both Update and AddCase", ErrInvalidValues, column)
+		}
+	}
+
+	return strings.Join(s.setClauses, ", "), nil
+}
+
 // buildUpdateCase constructs a SQL UPDATE statement with CASE expressions.
 //
-// It takes a map of column names to slices of UpdateCaseParam, where each slice
-// represents the WHEN/THEN/ELSE clauses for that column's CASE statement.
+// It takes the CASE expression of each column in AddCase order, so the generated SET clause is stable.
+// WHEN branches keep their order, the ELSE branch is always rendered last (the last Else wins).
 //
 // Parameters:
-//   - updateCaseClauses: A map where each key is a column name, and each value is a
-//     slice of UpdateCaseParam defining the conditional logic for updating that column.
+//   - updateCaseClauses: one UpdateCaseClause per column, defining the conditional logic for updating that column.
 //   - tableName: The name of the table to update.
+//   - setList: SET clauses of a preceding Update call, updated_at included unless WithoutTouch, or empty.
+//   - touch: Whether updated_at is set to NOW(), false after WithoutTouch.
 //
 // Returns:
 //   - A string containing the full SQL UPDATE query with conditional CASE logic.
@@ -868,34 +1316,48 @@ func (s *UpdateBuilder) extractUpdateFieldsMap(v map[string]any) ([]string, bool
 //	    WHEN condition1 AND condition2 THEN value1
 //	    ELSE default_value
 //	  END,
+//	  column2 = CASE
+//	    WHEN condition3 THEN value2
+//	  END,
 //	  updated_at = NOW()
 //
 // Notes:
-//   - The function appends "updated_at = NOW()" to the final SET clause.
+//   - The function appends setList, or "updated_at = NOW()" when touch is true and there is no setList.
 //   - It assumes all values and conditions are properly escaped/formatted.
-func buildUpdateCase(updateCaseClauses map[string][]UpdateCaseParam, tableName string) string {
-	var updateExpr string
-	updateExpr = "UPDATE " + tableName + "\n"
-	updateExpr += "SET\n"
+func buildUpdateCase(updateCaseClauses []UpdateCaseClause, tableName string, setList string, touch bool) string {
+	var updateSb strings.Builder
+	updateSb.WriteString("UPDATE " + tableName + "\n")
+	updateSb.WriteString("SET\n")
 
-	for key, each := range updateCaseClauses {
-		updateExpr += key + " = CASE\n"
-		fmt.Println("each", each)
+	for _, each := range updateCaseClauses {
+		updateSb.WriteString(each.Column + " = CASE\n")
+		traceQuery("update case", "column", each.Column, "params", len(each.Params))
 
-		for _, param := range each {
+		elseValue := ""
+		for _, param := range each.Params {
 			if param.isElse {
-				updateExpr += "ELSE " + param.value
-			} else {
-				updateExpr += "WHEN " + strings.Join(param.conditions, " AND ") + " THEN " + param.value
+				elseValue = param.value
+				continue
 			}
-			updateExpr += "\n"
+			updateSb.WriteString("WHEN " + strings.Join(param.conditions, " AND ") + " THEN " + param.value + "\n")
+		}
+		if elseValue != "" {
+			updateSb.WriteString("ELSE " + elseValue + "\n")
 		}
 
-		updateExpr += "END,\n"
+		updateSb.WriteString("END,\n")
+	}
+
+	if setList != "" {
+		updateSb.WriteString(setList)
+		return updateSb.String()
+	}
+	if !touch {
+		return strings.TrimSuffix(updateSb.String(), ",\n")
 	}
 
-	updateExpr += "updated_at = NOW()"
-	return updateExpr
+	updateSb.WriteString("updated_at = NOW()")
+	return updateSb.String()
 }
 
 func isZeroValue(v interface{}) bool {
@@ -0,0 +1,160 @@
+package sql_query
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	sqltesting "github.com/mystaline/clefinport-be/pkg/sql_query/testing"
+)
+
+type walletBalance struct {
+	ID      string `column:"id"      transform:"bigint"`
+	Balance int64  `column:"balance" transform:"bigint"`
+}
+
+func walletTransactionSum() *SQLEloquentQuery {
+	return NewSQLSelectBuilder[any]("transactions").
+		Select(`SUM("amount")`).
+		WhereRaw(`"wallet_id" = wallets."id" AND "note" <> ?`, "seed").(*SelectBuilder).SQLEloquentQuery
+}
+
+func TestUpdateFromSelect(t *testing.T) {
+	t.Run("a raw value mentioning the column doesn't set it", func(t *testing.T) {
+		builder := NewSQLUpdateBuilder("wallets").
+			WithoutTouch().
+			Update(map[string]any{
+				"status": UpdateRawSQL{Expr: `CASE WHEN "balance" = ? THEN 'empty' ELSE "status" END`, Args: []any{0}},
+			}).
+			UpdateFromSelect("balance", walletTransactionSum()).
+			Where(map[string]SQLCondition{"id": {Operator: SQLOperatorEqual, Value: "7"}})
+
+		sqltesting.AssertSQL(t, builder, `
+			UPDATE wallets SET
+				"status" = CASE WHEN "balance" = $1 THEN 'empty' ELSE "status" END,
+				"balance" = (SELECT SUM("amount") FROM transactions WHERE ("wallet_id" = wallets."id" AND "note" <> $2))
+			WHERE "id" = $3
+			RETURNING id`,
+			[]any{0, "seed", "7"},
+		)
+	})
+
+	t.Run("set before the VALUES list of UpdateEach", func(t *testing.T) {
+		builder := NewSQLUpdateBuilder("wallets").
+			WithoutTouch().
+			UpdateEach([]walletBalance{{ID: "7", Balance: 10}, {ID: "8", Balance: 20}}, "id").
+			UpdateFromSelect("last_transaction_amount", walletTransactionSum())
+
+		sqltesting.AssertSQL(t, builder, `
+			UPDATE wallets SET
+				"balance" = v."balance",
+				"last_transaction_amount" = (SELECT SUM("amount") FROM transactions WHERE ("wallet_id" = wallets."id" AND "note" <> $5))
+			FROM (VALUES ($1::bigint, $2::bigint), ($3::bigint, $4::bigint)) as v("id","balance")
+			WHERE wallets."id" = v."id"
+			RETURNING id`,
+			[]any{"7", int64(10), "8", int64(20), "seed"},
+		)
+	})
+
+	t.Run("rejects a column already set", func(t *testing.T) {
+		_, _, err := NewSQLUpdateBuilder("wallets").
+			Update(map[string]any{"balance": 0}).
+			UpdateFromSelect("balance", walletTransactionSum()).
+			Where(map[string]SQLCondition{"id": {Operator: SQLOperatorEqual, Value: "7"}}).
+			Build()
+		if !errors.Is(err, ErrInvalidValues) {
+			t.Fatalf("Build() error = %v, want ErrInvalidValues", err)
+		}
+	})
+}
+
+func TestUpdateJSONBField(t *testing.T) {
+	t.Run("nests calls on the same column", func(t *testing.T) {
+		builder := NewSQLUpdateBuilder("users").
+			WithoutTouch().
+			Update(map[string]any{"full_name": "Ayu"}).
+			UpdateJSONBField("settings", []string{"theme"}, "dark", true).
+			UpdateJSONBField("settings", []string{"notifications", "email"}, false, true).
+			Where(map[string]SQLCondition{"id": {Operator: SQLOperatorEqual, Value: "7"}})
+
+		sqltesting.AssertSQL(t, builder, `
+			UPDATE users SET
+				"full_name" = $1,
+				"settings" = jsonb_set(jsonb_set("settings", '{theme}', $2::jsonb, true), '{notifications,email}', $3::jsonb, true)
+			WHERE "id" = $4
+			RETURNING id`,
+			[]any{"Ayu", `"dark"`, "false", "7"},
+		)
+	})
+}
+
+func TestAddCaseKeepsSetClauses(t *testing.T) {
+	builder := NewSQLUpdateBuilder("wallets").
+		WithoutTouch().
+		Update(map[string]any{
+			"note": UpdateRawSQL{Expr: `CASE WHEN "status" = ? THEN 'archived' ELSE "note" END`, Args: []any{"closed"}},
+		}).
+		AddCase(`"status"`, func(b UpdateCases) {
+			b.Case(MultiFilterCondition{And: map[string]SQLCondition{"balance": {Operator: SQLOperatorEqual, Value: 0}}}, "empty", false)
+			b.Else(`"status"`, true)
+		}).
+		Where(map[string]SQLCondition{"id": {Operator: SQLOperatorEqual, Value: "7"}})
+
+	sqltesting.AssertSQL(t, builder, `
+		UPDATE wallets SET
+			"status" = CASE WHEN "balance" = $2 THEN $3 ELSE "status" END,
+			"note" = CASE WHEN "status" = $1 THEN 'archived' ELSE "note" END
+		WHERE "id" = $4
+		RETURNING id`,
+		[]any{"closed", 0, "empty", "7"},
+	)
+}
+
+func TestStepGuardMin(t *testing.T) {
+	t.Run("sorts the stepped columns", func(t *testing.T) {
+		for range 10 {
+			query, _, err := NewSQLUpdateBuilder("user_wallets").
+				WithoutTouch().
+				Increment(map[string]any{"spent": 15, "balance": 10, "count": 1}).
+				Where(map[string]SQLCondition{"wallet_id": {Operator: SQLOperatorEqual, Value: "7"}}).
+				Build()
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := "\nUPDATE user_wallets SET \"balance\" = \"balance\" + $1, \"count\" = \"count\" + $2, \"spent\" = \"spent\" + $3"
+			if !strings.HasPrefix(query, want) {
+				t.Fatalf("Build() = %s, want it to start with %s", query, want)
+			}
+		}
+	})
+
+	t.Run("guards the columns of every step", func(t *testing.T) {
+		builder := NewSQLUpdateBuilder("user_wallets").
+			Decrement(map[string]any{"balance": 15}).
+			Increment(map[string]any{"reserved": 15}).
+			GuardMin("balance", 0).
+			GuardMin("reserved", 5).
+			Where(map[string]SQLCondition{"wallet_id": {Operator: SQLOperatorEqual, Value: "7"}})
+
+		sqltesting.AssertSQL(t, builder, `
+			UPDATE user_wallets SET
+				"balance" = "balance" - $1,
+				"updated_at" = NOW(),
+				"reserved" = "reserved" + $2
+			WHERE "balance" - $1 >= $3 AND "reserved" + $2 >= $4 AND "wallet_id" = $5
+			RETURNING id`,
+			[]any{15, 15, 0, 5, "7"},
+		)
+	})
+
+	t.Run("rejects a column stepped twice", func(t *testing.T) {
+		_, _, err := NewSQLUpdateBuilder("user_wallets").
+			Increment(map[string]any{"balance": 10}).
+			Decrement(map[string]any{"balance": 5}).
+			Where(map[string]SQLCondition{"wallet_id": {Operator: SQLOperatorEqual, Value: "7"}}).
+			Build()
+		if !errors.Is(err, ErrInvalidValues) {
+			t.Fatalf("Build() error = %v, want ErrInvalidValues", err)
+		}
+	})
+}
@@ -0,0 +1,85 @@
+package sql_query
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+type benchmarkTransaction struct {
+	ID         string `json:"id"         column:"t.id::text"`
+	WalletName string `json:"walletName" column:"w.name"`
+	Amount     int64  `json:"amount"     column:"t.amount"`
+	CreatedAt  string `json:"createdAt"  column:"t.created_at"`
+}
+
+// benchmarkSelect is a list query of the size use cases build: a join, filters, alias sorting and pagination.
+func benchmarkSelect() *SQLEloquentQuery {
+	return NewSQLSelectBuilder[benchmarkTransaction]("transactions", "t").
+		Join("wallets w", "w.id = t.wallet_id").
+		Where(map[string]SQLCondition{
+			"w.user_id":  {Operator: SQLOperatorEqual, Value: "42"},
+			"t.amount":   {Operator: SQLOperatorGreaterThan, Value: 100},
+			"t.category": {Operator: SQLOperatorIn, Value: []string{"food", "rent"}},
+		}).
+		OrderBy([]string{"createdAt", "walletName"}, false).
+		Paginate(Pagination{Page: 3, Limit: 20}).(*SelectBuilder).SQLEloquentQuery
+}
+
+func BenchmarkBuildSelectQuery(b *testing.B) {
+	b.Run("chain and build", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, _, err := benchmarkSelect().buildSelectQuery(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("build only", func(b *testing.B) {
+		query := benchmarkSelect()
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, _, err := query.buildSelectQuery(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkShiftSQLPlaceholders(b *testing.B) {
+	query, _, err := benchmarkSelect().buildSelectQuery()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		shiftSQLPlaceholders(query, 7)
+	}
+}
+
+func BenchmarkCamelToSnake(b *testing.B) {
+	names := []string{"walletName", "createdAt", "HTTPStatusCode", "id", "userIDForExport"}
+
+	b.Run("precompiled", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			for _, name := range names {
+				CamelToSnake(name)
+			}
+		}
+	})
+
+	// What CamelToSnake cost when it compiled its expressions on every call
+	b.Run("compiled per call", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			for _, name := range names {
+				snake := regexp.MustCompile("(.)([A-Z][a-z]+)").ReplaceAllString(name, "${1}_${2}")
+				snake = regexp.MustCompile("([a-z0-9])([A-Z])").ReplaceAllString(snake, "${1}_${2}")
+				_ = strings.ToLower(snake)
+			}
+		}
+	})
+}
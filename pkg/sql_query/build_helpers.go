@@ -1,454 +1,610 @@
-package sql_query
-
-import (
-	"fmt"
-	"reflect"
-	"regexp"
-	"strconv"
-	"strings"
-)
-
-func (s *SQLEloquentQuery) sharedWhereJsonArray(column string, each SQLCondition) string {
-	var sb strings.Builder
-
-	sb.WriteString("EXISTS (")
-	sb.WriteByte('\n')
-	sb.WriteString("SELECT")
-	sb.WriteByte('\n')
-	sb.WriteString("FROM")
-	sb.WriteByte('\n')
-	sb.WriteString(fmt.Sprintf("jsonb_array_elements(%s)", column))
-	sb.WriteByte('\n')
-	sb.WriteString("WHERE")
-	sb.WriteByte('\n')
-
-	var clause string
-
-	if each.Value == nil &&
-		each.Operator != SQLOperatorIsNull &&
-		each.Operator != SQLOperatorIsNotNull {
-		return ""
-	}
-
-	switch each.Operator {
-	/* ───────────── IS NULL / IS NOT NULL ──────────── */
-	case SQLOperatorIsNull, SQLOperatorIsNotNull:
-		clause = fmt.Sprintf(`value ->> '%s' %s`, each.Key, each.Operator)
-
-	/* ─────────────────── BETWEEN ──────────────────── */
-	case SQLOperatorBetween, SQLOperatorNotBetween:
-		v := reflect.ValueOf(each.Value)
-		if v.Kind() != reflect.Slice || v.Len() != 2 {
-			return ""
-		}
-
-		firstVal := getVal(v.Index(0))
-		secondVal := getVal(v.Index(1))
-
-		// Helper to get argument index in query ($1, $2, ...)
-		argIdx := func(offset int) int {
-			return len(s.Args) - offset
-		}
-
-		// Filtering for epoch time (in query struct, this field should be []*int to support nil value in slice)
-		if each.IsEpochTime {
-			// Both values present
-			if firstVal.IsValid() && secondVal.IsValid() {
-				s.Args = append(s.Args, firstVal.Int()/1000, secondVal.Int()/1000)
-				clause = fmt.Sprintf(`value ->> '%s' %s to_timestamp($%d) AND to_timestamp($%d)`,
-					each.Key, each.Operator, argIdx(1), argIdx(0))
-			} else if !firstVal.IsValid() { // only second value
-				s.Args = append(s.Args, secondVal.Int()/1000)
-				clause = fmt.Sprintf(`value ->> '%s' %s to_timestamp($%d)`,
-					each.Key, SQLOperatorLTE, argIdx(0))
-			} else { // only first value
-				s.Args = append(s.Args, firstVal.Int()/1000)
-				clause = fmt.Sprintf(`value ->> '%s' %s to_timestamp($%d)`,
-					each.Key, SQLOperatorGTE, argIdx(0))
-			}
-			break
-		}
-
-		// Non-epoch case
-		s.Args = append(s.Args, firstVal.Interface(), secondVal.Interface())
-		clause = fmt.Sprintf(`value ->> '%s' %s $%d AND $%d`,
-			each.Key, each.Operator, argIdx(1), argIdx(0))
-
-	/* ────────────────── = ANY($n) ─────────────────── */
-	// products.user.id = ANY ($1) (array became args)
-	case SQLOperatorAny, SQLOperatorIn, SQLOperatorNotIn:
-		v := reflect.ValueOf(each.Value)
-		v = getVal(v)
-
-		if !v.IsValid() || (v.Kind() == reflect.Slice && v.IsNil()) {
-			return ""
-		}
-
-		if v.Len() == 0 {
-			if each.Operator != SQLOperatorNotIn {
-				// Empty slice/array means show nothing
-				clause = "FALSE"
-			} else {
-				// Empty slice/array means show all
-				clause = "TRUE"
-			}
-			break
-		}
-
-		switch each.Operator {
-		case SQLOperatorIn, SQLOperatorNotIn:
-			ph := make([]string, v.Len())
-			for i := 0; i < v.Len(); i++ {
-				ph[i] = fmt.Sprintf("$%d", len(s.Args)+1)
-				s.Args = append(s.Args, v.Index(i).Interface())
-			}
-
-			clause = fmt.Sprintf(`value ->> '%s' %s (%s)`, each.Key, each.Operator, strings.Join(ph, ", "))
-		case SQLOperatorAny:
-			clause = fmt.Sprintf(`value ->> '%s' = ANY($%d)`, each.Key, len(s.Args)+1)
-			s.Args = append(s.Args, each.Value)
-		}
-
-	/* ──────────────────── DEFAULT ─────────────────── */
-	default:
-		// Reference to other columns like users.id = user_assets.user_id
-		if each.IsRef {
-			clause = fmt.Sprintf(`value ->> '%s' %s %v`, each.Key, each.Operator, each.Value)
-			break
-		}
-
-		if each.SourceIsValue {
-			s.Args = append(s.Args, column)
-			clause = fmt.Sprintf(`$%d %s $%d`, len(s.Args), each.Operator, len(s.Args)+1)
-			s.Args = append(s.Args, each.Value)
-			break
-		}
-
-		// Common operator, products.user.id = $1 (literal value with args)=
-		clause = fmt.Sprintf(`value ->> '%s' %s $%d`, each.Key, each.Operator, len(s.Args)+1)
-		s.Args = append(s.Args, each.Value)
-	}
-
-	if clause == "" {
-		return ""
-	}
-
-	sb.WriteString(clause)
-	sb.WriteByte('\n')
-	sb.WriteByte(')')
-
-	return sb.String()
-}
-
-// sharedWhereAndQuery builds SQL WHERE/HAVING clauses from filters.
-// It supports operators like =, IN, BETWEEN, IS NULL, RAW, and column refs (IsRef).
-// Generated SQL fragments are added to Filters or HavingClauses, and Args is updated.
-//
-// WHERE filters for table & HAVING clauses won't be generated if you provide destination in params (pointer of slice string), but this function will assign value to the destination instead.
-//
-// Example:
-//
-//	filters := map[string]sql_query.SQLCondition{
-//	    "age": {Operator: ">", Value: 18},
-//	    "status": {Operator: "IN", Value: []string{"active", "pending"}},
-//	}
-//	builder.sharedWhereAndQuery(filters)
-//	// Produces: "age" > $1 AND "status" IN ($2, $3)
-func (s *SQLEloquentQuery) sharedWhereAndQuery(
-	filters map[string]SQLCondition,
-	v ...*[]string,
-) {
-	var dest []string
-	useDestination := len(v) > 0
-
-	for column, each := range filters {
-		// Skip value nil except for IS NULL / IS NOT NULL
-		if each.Value == nil &&
-			each.Operator != SQLOperatorIsNull &&
-			each.Operator != SQLOperatorIsNotNull {
-			continue
-		}
-
-		var clause string
-
-		// Handle array of object json filtering.
-		if each.IsArray && each.Key != "" {
-			clause = s.sharedWhereJsonArray(column, each)
-			if clause != "" {
-				s.Filters = append(s.Filters, clause)
-			}
-			continue
-		}
-
-		// Needed for filtering with subquery, e.g: WHERE category_id IN (SELECT id FROM category_recursive)
-		if each.IsSubQuery {
-			clause = fmt.Sprintf(`%s %s %v`, column, each.Operator, each.Value)
-			s.Filters = append(s.Filters, clause)
-			continue
-		}
-
-		switch each.Operator {
-		/* ───────────────────── RAW ───────────────────── */
-		case SQLOperatorRaw:
-			// Value should be ready to use SQL query
-			raw, ok := each.Value.(string)
-			if !ok {
-				continue // atau panic
-			}
-			clause = raw
-			// If exists, ExtraArgs will be appended into main Args
-			for _, arg := range each.ExtraArgs {
-				clause = strings.Replace(clause, "?", fmt.Sprintf("$%d", len(s.Args)+1), 1)
-				s.Args = append(s.Args, arg)
-			}
-
-		/* ───────────── IS NULL / IS NOT NULL ──────────── */
-		case SQLOperatorIsNull, SQLOperatorIsNotNull:
-			quotedColumn := escapeQuoteColumns(column)
-			clause = fmt.Sprintf(`%s %s`, quotedColumn, each.Operator)
-
-		/* ─────────────────── BETWEEN ──────────────────── */
-		case SQLOperatorBetween, SQLOperatorNotBetween:
-			v := reflect.ValueOf(each.Value)
-			if v.Kind() != reflect.Slice || v.Len() != 2 {
-				continue // Skip if length is not 2
-			}
-
-			firstVal := getVal(v.Index(0))
-			secondVal := getVal(v.Index(1))
-
-			// Helper to get argument index in query ($1, $2, ...)
-			argIdx := func(offset int) int {
-				return len(s.Args) - offset
-			}
-
-			// Filtering for epoch time (in query struct, this field should be []*int to support nil value in slice)
-			if each.IsEpochTime {
-				// Both values present
-				if firstVal.IsValid() && secondVal.IsValid() {
-					s.Args = append(s.Args, firstVal.Int()/1000, secondVal.Int()/1000)
-					clause = fmt.Sprintf(`%s %s to_timestamp($%d) AND to_timestamp($%d)`,
-						escapeQuoteColumns(column), each.Operator, argIdx(1), argIdx(0))
-				} else if !firstVal.IsValid() { // only second value
-					s.Args = append(s.Args, secondVal.Int()/1000)
-					clause = fmt.Sprintf(`%s %s to_timestamp($%d)`,
-						escapeQuoteColumns(column), SQLOperatorLTE, argIdx(0))
-				} else { // only first value
-					s.Args = append(s.Args, firstVal.Int()/1000)
-					clause = fmt.Sprintf(`%s %s to_timestamp($%d)`,
-						escapeQuoteColumns(column), SQLOperatorGTE, argIdx(0))
-				}
-				break
-			}
-
-			// Non-epoch case
-			s.Args = append(s.Args, firstVal.Interface(), secondVal.Interface())
-			clause = fmt.Sprintf(`%s %s $%d AND $%d`,
-				escapeQuoteColumns(column), each.Operator, argIdx(1), argIdx(0))
-
-		/* ────────────────── = ANY($n) ─────────────────── */
-		// products.user.id = ANY ($1) (array became args)
-		case SQLOperatorAny, SQLOperatorIn, SQLOperatorNotIn:
-			quotedColumn := escapeQuoteColumns(column)
-
-			v := reflect.ValueOf(each.Value)
-			v = getVal(v)
-
-			if !v.IsValid() || (v.Kind() == reflect.Slice && v.IsNil()) {
-				continue
-			}
-
-			if v.Len() == 0 {
-				if each.Operator != SQLOperatorNotIn {
-					// Empty slice/array means show nothing
-					clause = "FALSE"
-				} else {
-					// Empty slice/array means show all
-					clause = "TRUE"
-				}
-				break
-			}
-
-			switch each.Operator {
-			case SQLOperatorIn, SQLOperatorNotIn:
-				ph := make([]string, v.Len())
-				for i := 0; i < v.Len(); i++ {
-					ph[i] = fmt.Sprintf("$%d", len(s.Args)+1)
-					s.Args = append(s.Args, v.Index(i).Interface())
-				}
-
-				clause = fmt.Sprintf(`%s %s (%s)`, escapeQuoteColumns(column), each.Operator, strings.Join(ph, ", "))
-			case SQLOperatorAny:
-				clause = fmt.Sprintf(`%s = ANY($%d)`, quotedColumn, len(s.Args)+1)
-				s.Args = append(s.Args, each.Value)
-			}
-
-		/* ──────────────────── DEFAULT ─────────────────── */
-		default:
-			// Reference to other columns like users.id = user_assets.user_id
-			if each.IsRef {
-				quotedColumn := escapeQuoteColumns(column)
-				clause = fmt.Sprintf(`%s %s %v`, quotedColumn, each.Operator, each.Value)
-				break
-			}
-
-			if each.SourceIsValue {
-				s.Args = append(s.Args, column)
-				clause = fmt.Sprintf(`$%d %s $%d`, len(s.Args), each.Operator, len(s.Args)+1)
-				s.Args = append(s.Args, each.Value)
-				break
-			}
-
-			// Common operator, products.user.id = $1 (literal value with args)=
-			clause = fmt.Sprintf(`%s %s $%d`, escapeQuoteColumns(column), each.Operator, len(s.Args)+1)
-			s.Args = append(s.Args, each.Value)
-		}
-
-		// If there's destination in param, then HavingClauses and Filters wont be used, instead assign value to destination
-		if useDestination {
-			dest = append(dest, clause)
-			continue
-		}
-
-		// Insert to Where clause or Having clause based on given params
-		if s.useHaving {
-			s.HavingClauses = append(s.HavingClauses, clause)
-		} else {
-			s.Filters = append(s.Filters, clause)
-		}
-	}
-
-	if useDestination {
-		if v[0] == nil {
-			v[0] = &[]string{}
-		}
-		*v[0] = dest
-	}
-}
-
-// sharedWhereOrQuery builds OR-combined conditions from multiple filter maps.
-// Each map is AND-combined internally, then OR-joined together. Args is updated.
-//
-// Example:
-//
-//	builder.sharedWhereOrQuery(
-//	    map[string]SQLCondition{"role": {Operator: "=", Value: "admin"}},
-//	    map[string]SQLCondition{"role": {Operator: "=", Value: "editor"}},
-//	)
-//	// Produces: (("role" = $1) OR ("role" = $2))
-func (s *SQLEloquentQuery) sharedWhereOrQuery(filters ...map[string]SQLCondition) {
-	s.whereOrDestination(filters)
-}
-
-// Core function of `Where Or` query.
-// WHERE filters for table won't be generated if you provide destination in params (pointer of slice string), but this function will assign value to the destination instead.
-func (s *SQLEloquentQuery) whereOrDestination(
-	filters []map[string]SQLCondition,
-	v ...*[]string,
-) {
-	var dest []string
-	useDestination := len(v) > 0
-	orConditions := []string{}
-
-	for _, filter := range filters {
-		inner := &SQLEloquentQuery{Args: s.Args}
-		inner.sharedWhereAndQuery(filter)
-		orClause := fmt.Sprintf("(%s)", strings.Join(inner.Filters, " AND "))
-		orConditions = append(orConditions, orClause)
-		s.Args = inner.Args
-	}
-
-	if !useDestination {
-		s.Filters = append(s.Filters, fmt.Sprintf("(%s)", strings.Join(orConditions, " OR ")))
-	} else {
-		if v[0] == nil {
-			v[0] = &[]string{}
-		}
-		*v[0] = dest
-	}
-}
-
-// Extract values (dereference if pointer)
-func getVal(val reflect.Value) reflect.Value {
-	if val.Kind() == reflect.Ptr {
-		return val.Elem()
-	}
-	return val
-}
-
-func escapeQuoteColumns(column string) string {
-	var hasSuffix bool
-	var suffix string
-	if strings.Contains(column, "::") {
-		hasSuffix = true
-		splittedByCast := strings.Split(column, "::")
-		suffix = "::" + splittedByCast[len(splittedByCast)-1]
-		column = splittedByCast[0]
-	} else if strings.Contains(column, "->>") {
-		hasSuffix = true
-		splittedByOperator := strings.Split(column, "->>")
-		suffix = "->>" + splittedByOperator[len(splittedByOperator)-1]
-		column = splittedByOperator[0]
-	}
-
-	parts := strings.Split(column, ".")
-	for i, part := range parts {
-		parts[i] = fmt.Sprintf(`"%s"`, part)
-	}
-	quotedColumn := column
-	if !strings.Contains(column, "(") {
-		quotedColumn = strings.Join(parts, ".")
-	}
-
-	if hasSuffix {
-		quotedColumn += suffix
-	}
-	return quotedColumn
-}
-
-func shiftSQLPlaceholders(query string, offset int) string {
-	placeholderRegexp := regexp.MustCompile(`\$(\d+)`)
-	return placeholderRegexp.ReplaceAllStringFunc(query, func(placeholder string) string {
-		numStr := placeholder[1:]
-		num, err := strconv.Atoi(numStr)
-		if err != nil {
-			return placeholder // fallback to original if parse fails
-		}
-		return fmt.Sprintf("$%d", num+offset)
-	})
-}
-
-// // flattenArgs handles []any or single value
-// func (b *SQLBuilder) flattenArgs(val interface{}) []interface{} {
-// 	v := reflect.ValueOf(val)
-// 	if v.Kind() == reflect.Slice {
-// 		args := make([]interface{}, v.Len())
-// 		for i := 0; i < v.Len(); i++ {
-// 			args[i] = v.Index(i).Interface()
-// 		}
-// 		return args
-// 	}
-// 	// fallback for single values
-// 	return []interface{}{val}
-// }
-
-// func (b *SQLBuilder) makePlaceholders(val interface{}) string {
-// 	v := reflect.ValueOf(val)
-// 	if v.Kind() != reflect.Slice {
-// 		return fmt.Sprintf("$%d", b.argIndex)
-// 	}
-
-// 	placeholders := make([]string, v.Len())
-// 	for i := 0; i < v.Len(); i++ {
-// 		placeholders[i] = fmt.Sprintf("$%d", b.argIndex+i)
-// 	}
-// 	return strings.Join(placeholders, ", ")
-// }
-
-// func (b *SQLBuilder) countArgs(val interface{}) int {
-// 	v := reflect.ValueOf(val)
-// 	if v.Kind() == reflect.Slice {
-// 		return v.Len()
-// 	}
-// 	return 1
-// }
+package sql_query
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+func (s *SQLEloquentQuery) sharedWhereJsonArray(column string, each SQLCondition) string {
+	var sb strings.Builder
+
+	sb.WriteString("EXISTS (")
+	sb.WriteByte('\n')
+	sb.WriteString("SELECT")
+	sb.WriteByte('\n')
+	sb.WriteString("FROM")
+	sb.WriteByte('\n')
+	sb.WriteString(fmt.Sprintf("jsonb_array_elements(%s)", column))
+	sb.WriteByte('\n')
+	sb.WriteString("WHERE")
+	sb.WriteByte('\n')
+
+	var clause string
+
+	if each.Value == nil &&
+		each.Operator != SQLOperatorIsNull &&
+		each.Operator != SQLOperatorIsNotNull {
+		return ""
+	}
+
+	switch each.Operator {
+	/* ───────────── IS NULL / IS NOT NULL ──────────── */
+	case SQLOperatorIsNull, SQLOperatorIsNotNull:
+		clause = fmt.Sprintf(`value ->> '%s' %s`, each.Key, each.Operator)
+
+	/* ─────────────────── BETWEEN ──────────────────── */
+	case SQLOperatorBetween, SQLOperatorNotBetween:
+		v := reflect.ValueOf(each.Value)
+		if v.Kind() != reflect.Slice || v.Len() != 2 {
+			return ""
+		}
+
+		firstVal := getVal(v.Index(0))
+		secondVal := getVal(v.Index(1))
+
+		// Helper to get argument index in query ($1, $2, ...)
+		argIdx := func(offset int) int {
+			return len(s.Args) - offset
+		}
+
+		// Filtering for epoch time (in query struct, this field should be []*int to support nil value in slice)
+		if each.IsEpochTime {
+			// Both values present
+			if firstVal.IsValid() && secondVal.IsValid() {
+				from := s.epochTimestamp(firstVal.Int(), each.Timezone, false)
+				to := s.epochTimestamp(secondVal.Int(), each.Timezone, true)
+				clause = fmt.Sprintf(`value ->> '%s' %s %s AND %s`,
+					each.Key, each.Operator, from, to)
+			} else if !firstVal.IsValid() { // only second value
+				clause = fmt.Sprintf(`value ->> '%s' %s %s`,
+					each.Key, SQLOperatorLTE, s.epochTimestamp(secondVal.Int(), each.Timezone, true))
+			} else { // only first value
+				clause = fmt.Sprintf(`value ->> '%s' %s %s`,
+					each.Key, SQLOperatorGTE, s.epochTimestamp(firstVal.Int(), each.Timezone, false))
+			}
+			break
+		}
+
+		// Non-epoch case
+		s.Args = append(s.Args, firstVal.Interface(), secondVal.Interface())
+		clause = fmt.Sprintf(`value ->> '%s' %s $%d AND $%d`,
+			each.Key, each.Operator, argIdx(1), argIdx(0))
+
+	/* ────────────────── = ANY($n) ─────────────────── */
+	// products.user.id = ANY ($1) (array became args)
+	case SQLOperatorAny, SQLOperatorIn, SQLOperatorNotIn:
+		v := reflect.ValueOf(each.Value)
+		v = getVal(v)
+
+		if !v.IsValid() || (v.Kind() == reflect.Slice && v.IsNil()) {
+			return ""
+		}
+
+		if v.Len() == 0 {
+			if each.Operator != SQLOperatorNotIn {
+				// Empty slice/array means show nothing
+				clause = "FALSE"
+			} else {
+				// Empty slice/array means show all
+				clause = "TRUE"
+			}
+			break
+		}
+
+		switch each.Operator {
+		case SQLOperatorIn, SQLOperatorNotIn:
+			ph := make([]string, v.Len())
+			for i := 0; i < v.Len(); i++ {
+				ph[i] = fmt.Sprintf("$%d", len(s.Args)+1)
+				s.Args = append(s.Args, v.Index(i).Interface())
+			}
+
+			clause = fmt.Sprintf(`value ->> '%s' %s (%s)`, each.Key, each.Operator, strings.Join(ph, ", "))
+		case SQLOperatorAny:
+			clause = fmt.Sprintf(`value ->> '%s' = ANY($%d)`, each.Key, len(s.Args)+1)
+			s.Args = append(s.Args, each.Value)
+		}
+
+	/* ──────────────────── DEFAULT ─────────────────── */
+	default:
+		// Reference to other columns like users.id = user_assets.user_id
+		if each.IsRef {
+			clause = fmt.Sprintf(`value ->> '%s' %s %v`, each.Key, each.Operator, each.Value)
+			break
+		}
+
+		if each.SourceIsValue {
+			s.Args = append(s.Args, column)
+			clause = fmt.Sprintf(`$%d %s $%d`, len(s.Args), each.Operator, len(s.Args)+1)
+			s.Args = append(s.Args, each.Value)
+			break
+		}
+
+		// Common operator, products.user.id = $1 (literal value with args)=
+		clause = fmt.Sprintf(`value ->> '%s' %s $%d`, each.Key, each.Operator, len(s.Args)+1)
+		s.Args = append(s.Args, each.Value)
+	}
+
+	if clause == "" {
+		return ""
+	}
+
+	sb.WriteString(clause)
+	sb.WriteByte('\n')
+	sb.WriteByte(')')
+
+	return sb.String()
+}
+
+// sharedWhereAndQuery builds SQL WHERE/HAVING clauses from filters.
+// It supports operators like =, IN, BETWEEN, IS NULL, RAW, and column refs (IsRef).
+// Generated SQL fragments are added to Filters or HavingClauses, and Args is updated.
+//
+// WHERE filters for table & HAVING clauses won't be generated if you provide destination in params (pointer of slice string), but this function will assign value to the destination instead.
+//
+// Columns are visited in sorted order, so a filter map always builds the same SQL with the same placeholder numbering,
+// whatever the map iteration order (prepared statements and SQL snapshots rely on it).
+//
+// Example:
+//
+//	filters := map[string]sql_query.SQLCondition{
+//	    "age": {Operator: ">", Value: 18},
+//	    "status": {Operator: "IN", Value: []string{"active", "pending"}},
+//	}
+//	builder.sharedWhereAndQuery(filters)
+//	// Produces: "age" > $1 AND "status" IN ($2, $3)
+func (s *SQLEloquentQuery) sharedWhereAndQuery(
+	filters map[string]SQLCondition,
+	v ...*[]string,
+) {
+	var dest []string
+	useDestination := len(v) > 0
+
+	for _, column := range slices.Sorted(maps.Keys(filters)) {
+		each := filters[column]
+
+		// Skip value nil except for IS NULL / IS NOT NULL
+		if each.Value == nil &&
+			each.Operator != SQLOperatorIsNull &&
+			each.Operator != SQLOperatorIsNotNull {
+			continue
+		}
+
+		var clause string
+
+		// Handle array of object json filtering.
+		if each.IsArray && each.Key != "" {
+			clause = s.sharedWhereJsonArray(column, each)
+			if clause != "" {
+				s.Filters = append(s.Filters, clause)
+			}
+			continue
+		}
+
+		// Needed for filtering with subquery, e.g: WHERE category_id IN (SELECT id FROM category_recursive)
+		if each.IsSubQuery {
+			clause = fmt.Sprintf(`%s %s %v`, column, each.Operator, each.Value)
+			s.Filters = append(s.Filters, clause)
+			continue
+		}
+
+		switch each.Operator {
+		/* ───────────────────── RAW ───────────────────── */
+		case SQLOperatorRaw:
+			// Value should be ready to use SQL query
+			raw, ok := each.Value.(string)
+			if !ok {
+				continue // atau panic
+			}
+			// If exists, ExtraArgs will be appended into main Args
+			bound, err := s.bindQuestionMarks(raw, each.ExtraArgs)
+			if err != nil {
+				s.LastError = err
+				continue
+			}
+			clause = bound
+
+		/* ───────────── IS NULL / IS NOT NULL ──────────── */
+		case SQLOperatorIsNull, SQLOperatorIsNotNull:
+			quotedColumn := escapeQuoteColumns(column)
+			clause = fmt.Sprintf(`%s %s`, quotedColumn, each.Operator)
+
+		/* ─────────────────── BETWEEN ──────────────────── */
+		case SQLOperatorBetween, SQLOperatorNotBetween:
+			v := reflect.ValueOf(each.Value)
+			if v.Kind() != reflect.Slice || v.Len() != 2 {
+				continue // Skip if length is not 2
+			}
+
+			firstVal := getVal(v.Index(0))
+			secondVal := getVal(v.Index(1))
+
+			// Helper to get argument index in query ($1, $2, ...)
+			argIdx := func(offset int) int {
+				return len(s.Args) - offset
+			}
+
+			// Filtering for epoch time (in query struct, this field should be []*int to support nil value in slice)
+			if each.IsEpochTime {
+				// Both values present
+				if firstVal.IsValid() && secondVal.IsValid() {
+					from := s.epochTimestamp(firstVal.Int(), each.Timezone, false)
+					to := s.epochTimestamp(secondVal.Int(), each.Timezone, true)
+					clause = fmt.Sprintf(`%s %s %s AND %s`,
+						escapeQuoteColumns(column), each.Operator, from, to)
+				} else if !firstVal.IsValid() { // only second value
+					clause = fmt.Sprintf(`%s %s %s`,
+						escapeQuoteColumns(column), SQLOperatorLTE, s.epochTimestamp(secondVal.Int(), each.Timezone, true))
+				} else { // only first value
+					clause = fmt.Sprintf(`%s %s %s`,
+						escapeQuoteColumns(column), SQLOperatorGTE, s.epochTimestamp(firstVal.Int(), each.Timezone, false))
+				}
+				break
+			}
+
+			// Non-epoch case
+			s.Args = append(s.Args, firstVal.Interface(), secondVal.Interface())
+			clause = fmt.Sprintf(`%s %s $%d AND $%d`,
+				escapeQuoteColumns(column), each.Operator, argIdx(1), argIdx(0))
+
+		/* ────────────────── = ANY($n) ─────────────────── */
+		// products.user.id = ANY ($1) (array became args)
+		case SQLOperatorAny, SQLOperatorIn, SQLOperatorNotIn:
+			// Row values and sub-builders, e.g. ("wallet_id", "category_id") IN (VALUES ($1, $2), ($3, $4))
+			if tupleClause, ok, err := s.tupleInClause(column, each); ok {
+				if err != nil {
+					s.LastError = err
+					continue
+				}
+				clause = tupleClause
+				break
+			}
+
+			quotedColumn := escapeQuoteColumns(column)
+
+			v := reflect.ValueOf(each.Value)
+			v = getVal(v)
+
+			if !v.IsValid() || (v.Kind() == reflect.Slice && v.IsNil()) {
+				continue
+			}
+
+			if v.Len() == 0 {
+				if each.Operator != SQLOperatorNotIn {
+					// Empty slice/array means show nothing
+					clause = "FALSE"
+				} else {
+					// Empty slice/array means show all
+					clause = "TRUE"
+				}
+				break
+			}
+
+			switch each.Operator {
+			case SQLOperatorIn, SQLOperatorNotIn:
+				clause = s.inListClause(quotedColumn, each.Operator, v)
+			case SQLOperatorAny:
+				clause = fmt.Sprintf(`%s = ANY($%d)`, quotedColumn, len(s.Args)+1)
+				s.Args = append(s.Args, each.Value)
+			}
+
+		/* ─────────────── @> / && / <@ ($n) ─────────────── */
+		// "tags" @> $1, pgx binds Go slices as Postgres arrays
+		case SQLOperatorArrayContains, SQLOperatorArrayOverlap, SQLOperatorArrayContainedBy:
+			value, ok := arrayOperand(each.Value)
+			if !ok {
+				continue
+			}
+
+			clause = fmt.Sprintf(`%s %s $%d`, escapeQuoteColumns(column), each.Operator, len(s.Args)+1)
+			s.Args = append(s.Args, value)
+
+		/* ──────────────────── DEFAULT ─────────────────── */
+		default:
+			// Reference to other columns like users.id = user_assets.user_id
+			if each.IsRef {
+				quotedColumn := escapeQuoteColumns(column)
+				clause = fmt.Sprintf(`%s %s %v`, quotedColumn, each.Operator, each.Value)
+				break
+			}
+
+			if each.SourceIsValue {
+				s.Args = append(s.Args, column)
+				clause = fmt.Sprintf(`$%d %s $%d`, len(s.Args), each.Operator, len(s.Args)+1)
+				s.Args = append(s.Args, each.Value)
+				break
+			}
+
+			// Common operator, products.user.id = $1 (literal value with args)=
+			clause = fmt.Sprintf(`%s %s $%d`, escapeQuoteColumns(column), each.Operator, len(s.Args)+1)
+			s.Args = append(s.Args, each.Value)
+		}
+
+		// If there's destination in param, then HavingClauses and Filters wont be used, instead assign value to destination
+		if useDestination {
+			dest = append(dest, clause)
+			continue
+		}
+
+		s.Filters = append(s.Filters, clause)
+	}
+
+	if useDestination {
+		if v[0] == nil {
+			v[0] = &[]string{}
+		}
+		*v[0] = dest
+	}
+}
+
+// sharedWhereOrQuery builds OR-combined conditions from multiple filter maps.
+// Each map is AND-combined internally, then OR-joined together. Args is updated.
+//
+// Example:
+//
+//	builder.sharedWhereOrQuery(
+//	    map[string]SQLCondition{"role": {Operator: "=", Value: "admin"}},
+//	    map[string]SQLCondition{"role": {Operator: "=", Value: "editor"}},
+//	)
+//	// Produces: (("role" = $1) OR ("role" = $2))
+func (s *SQLEloquentQuery) sharedWhereOrQuery(filters ...map[string]SQLCondition) {
+	s.whereOrDestination(filters)
+}
+
+// Core function of `Where Or` query.
+// WHERE filters for table won't be generated if you provide destination in params (pointer of slice string), but this function will assign value to the destination instead.
+func (s *SQLEloquentQuery) whereOrDestination(
+	filters []map[string]SQLCondition,
+	v ...*[]string,
+) {
+	var dest []string
+	useDestination := len(v) > 0
+	orConditions := []string{}
+
+	for _, filter := range filters {
+		inner := &SQLEloquentQuery{Args: s.Args}
+		inner.sharedWhereAndQuery(filter)
+		s.Args = inner.Args
+		if inner.LastError != nil {
+			s.LastError = inner.LastError
+			return
+		}
+
+		// A map of nil values only has no condition, "()" would not parse
+		if len(inner.Filters) == 0 {
+			continue
+		}
+		orClause := fmt.Sprintf("(%s)", strings.Join(inner.Filters, " AND "))
+		orConditions = append(orConditions, orClause)
+	}
+
+	if len(orConditions) > 0 {
+		dest = append(dest, fmt.Sprintf("(%s)", strings.Join(orConditions, " OR ")))
+	}
+
+	if !useDestination {
+		s.Filters = append(s.Filters, dest...)
+	} else {
+		if v[0] == nil {
+			v[0] = &[]string{}
+		}
+		*v[0] = dest
+	}
+}
+
+// arrayOperand returns value as an array argument, wrapping single values into a one element slice.
+// Nil slices are skipped like nil values of the other operators.
+func arrayOperand(value interface{}) (interface{}, bool) {
+	if _, ok := value.(driver.Valuer); ok {
+		return value, true
+	}
+
+	v := getVal(reflect.ValueOf(value))
+	if !v.IsValid() {
+		return nil, false
+	}
+
+	switch v.Kind() {
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil, false
+		}
+		return v.Interface(), true
+	case reflect.Array:
+		return v.Interface(), true
+	}
+
+	single := reflect.MakeSlice(reflect.SliceOf(v.Type()), 1, 1)
+	single.Index(0).Set(v)
+
+	return single.Interface(), true
+}
+
+// epochTimestamp binds an epoch millis value as seconds and returns its to_timestamp expression, compared as an instant.
+// With a timezone the value is bucketed to its day in that zone, e.g. date_trunc('day', to_timestamp($1), $2),
+// from its first instant for a lower bound and to its last for an upper one,
+// so a day range picked by an Asia/Jakarta user covers their whole days instead of UTC's.
+func (s *SQLEloquentQuery) epochTimestamp(millis int64, timezone string, upperBound bool) string {
+	s.Args = appendArgs(s.Args, []interface{}{millis / 1000})
+	expr := fmt.Sprintf("to_timestamp($%d)", len(s.Args))
+
+	timezone = strings.TrimSpace(timezone)
+	if timezone == "" {
+		return expr
+	}
+
+	// The three arguments date_trunc returns a timestamptz, still compared as an instant
+	s.Args = appendArgs(s.Args, []interface{}{timezone})
+	expr = fmt.Sprintf("date_trunc('day', %s, $%d)", expr, len(s.Args))
+	if upperBound {
+		return fmt.Sprintf("(%s + interval '1 day' - interval '1 microsecond')", expr)
+	}
+
+	return expr
+}
+
+// Extract values (dereference if pointer)
+func getVal(val reflect.Value) reflect.Value {
+	if val.Kind() == reflect.Ptr {
+		return val.Elem()
+	}
+	return val
+}
+
+func escapeQuoteColumns(column string) string {
+	var hasSuffix bool
+	var suffix string
+	if strings.Contains(column, "::") {
+		hasSuffix = true
+		splittedByCast := strings.Split(column, "::")
+		suffix = "::" + splittedByCast[len(splittedByCast)-1]
+		column = splittedByCast[0]
+	} else if strings.Contains(column, "->>") {
+		hasSuffix = true
+		splittedByOperator := strings.Split(column, "->>")
+		suffix = "->>" + splittedByOperator[len(splittedByOperator)-1]
+		column = splittedByOperator[0]
+	}
+
+	parts := strings.Split(column, ".")
+	for i, part := range parts {
+		parts[i] = fmt.Sprintf(`"%s"`, part)
+	}
+	quotedColumn := column
+	if !strings.Contains(column, "(") {
+		quotedColumn = strings.Join(parts, ".")
+	}
+
+	if hasSuffix {
+		quotedColumn += suffix
+	}
+	return quotedColumn
+}
+
+// TupleValues is the value of a multi-column IN / NOT IN condition, one inner slice per row.
+// The condition key lists the columns separated by comma, each row must have one value per column.
+type TupleValues [][]interface{}
+
+// tupleInClause handles IN / NOT IN conditions whose value is TupleValues or a sub-builder.
+// ok is false for every other value, which then goes through the common IN handling.
+//
+// Example:
+//
+//	"wallet_id, category_id": {Operator: SQLOperatorIn, Value: TupleValues{{1, 2}, {3, 4}}}
+//	// Produces: ("wallet_id", "category_id") IN (VALUES ($1, $2), ($3, $4))
+//
+//	"category_id": {Operator: SQLOperatorIn, Value: categoryTreeBuilder.(*SelectBuilder).SQLEloquentQuery}
+//	// Produces: "category_id" IN (SELECT ...), placeholders of the sub-query shifted after the current args
+func (s *SQLEloquentQuery) tupleInClause(column string, each SQLCondition) (string, bool, error) {
+	if each.Operator != SQLOperatorIn && each.Operator != SQLOperatorNotIn {
+		return "", false, nil
+	}
+
+	// Expressions such as COALESCE(a, b) are kept as a single column
+	columns := []string{column}
+	if listed := strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(column), "("), ")"); !strings.Contains(listed, "(") {
+		columns = strings.Split(listed, ",")
+	}
+	quotedColumns := make([]string, len(columns))
+	for i, c := range columns {
+		quotedColumns[i] = escapeQuoteColumns(strings.TrimSpace(c))
+	}
+
+	target := quotedColumns[0]
+	if len(quotedColumns) > 1 {
+		target = "(" + strings.Join(quotedColumns, ", ") + ")"
+	}
+
+	switch value := each.Value.(type) {
+	case TupleValues:
+		if len(value) == 0 {
+			if each.Operator == SQLOperatorNotIn {
+				return "TRUE", true, nil
+			}
+			return "FALSE", true, nil
+		}
+
+		rows := make([]string, len(value))
+		for i, row := range value {
+			if len(row) != len(columns) {
+				return "", true, fmt.Errorf("invalid %s condition on %s: row %d has %d values, expected %d", each.Operator, column, i, len(row), len(columns))
+			}
+
+			ph := make([]string, len(row))
+			for j := range row {
+				ph[j] = fmt.Sprintf("$%d", len(s.Args)+j+1)
+			}
+			s.Args = appendArgs(s.Args, row)
+			rows[i] = "(" + strings.Join(ph, ", ") + ")"
+		}
+
+		return fmt.Sprintf(`%s %s (VALUES %s)`, target, each.Operator, strings.Join(rows, ", ")), true, nil
+
+	case *SQLEloquentQuery:
+		subQuery, subArgs, err := value.build()
+		if err != nil {
+			return "", true, err
+		}
+
+		shiftedSubQuery := shiftSQLPlaceholders(strings.TrimSpace(subQuery), len(s.Args))
+		s.Args = appendArgs(s.Args, subArgs)
+		s.touchTablesOf(value)
+
+		return fmt.Sprintf(`%s %s (%s)`, target, each.Operator, shiftedSubQuery), true, nil
+	}
+
+	return "", false, nil
+}
+
+func shiftSQLPlaceholders(query string, offset int) string {
+	if offset == 0 {
+		return query
+	}
+
+	return replacePlaceholders(query, func(num, start, end int) string {
+		if num < 0 {
+			return query[start:end] // fallback to original if parse fails
+		}
+		return "$" + strconv.Itoa(num+offset)
+	})
+}
+
+// // flattenArgs handles []any or single value
+// func (b *SQLBuilder) flattenArgs(val interface{}) []interface{} {
+// 	v := reflect.ValueOf(val)
+// 	if v.Kind() == reflect.Slice {
+// 		args := make([]interface{}, v.Len())
+// 		for i := 0; i < v.Len(); i++ {
+// 			args[i] = v.Index(i).Interface()
+// 		}
+// 		return args
+// 	}
+// 	// fallback for single values
+// 	return []interface{}{val}
+// }
+
+// func (b *SQLBuilder) makePlaceholders(val interface{}) string {
+// 	v := reflect.ValueOf(val)
+// 	if v.Kind() != reflect.Slice {
+// 		return fmt.Sprintf("$%d", b.argIndex)
+// 	}
+
+// 	placeholders := make([]string, v.Len())
+// 	for i := 0; i < v.Len(); i++ {
+// 		placeholders[i] = fmt.Sprintf("$%d", b.argIndex+i)
+// 	}
+// 	return strings.Join(placeholders, ", ")
+// }
+
+// func (b *SQLBuilder) countArgs(val interface{}) int {
+// 	v := reflect.ValueOf(val)
+// 	if v.Kind() == reflect.Slice {
+// 		return v.Len()
+// 	}
+// 	return 1
+// }
@@ -0,0 +1,102 @@
+package sql_query
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Buffers larger than this are dropped instead of pooled, so one huge query doesn't pin memory forever
+const maxPooledBufferSize = 64 << 10
+
+var bufferPool = sync.Pool{
+	New: func() any {
+		buf := new(bytes.Buffer)
+		buf.Grow(256)
+		return buf
+	},
+}
+
+func acquireBuffer() *bytes.Buffer {
+	return bufferPool.Get().(*bytes.Buffer)
+}
+
+func releaseBuffers(buffers ...*bytes.Buffer) {
+	for _, buf := range buffers {
+		if buf.Cap() > maxPooledBufferSize {
+			continue
+		}
+		buf.Reset()
+		bufferPool.Put(buf)
+	}
+}
+
+var selectBuilderPool = sync.Pool{
+	New: func() any {
+		return &SelectBuilder{&SQLEloquentQuery{}}
+	},
+}
+
+// AcquireSelectBuilder works like NewSQLSelectBuilder but reuses a pooled builder.
+// Call Release once the query string and args are no longer needed (after the query is executed),
+// the builder must not be used after that.
+//
+// Example:
+//
+//	builder := sql_query.AcquireSelectBuilder[dto.GetWalletInfoData]("wallets")
+//	defer sql_query.Release(builder)
+//
+//	query, args, err := builder.Where(...).Build()
+func AcquireSelectBuilder[T any](tableName string, alias ...string) SQLSelectChainBuilder {
+	builder := selectBuilderPool.Get().(*SelectBuilder)
+
+	if len(alias) > 0 {
+		tableName = fmt.Sprintf("%s %s", tableName, strings.TrimSpace(alias[0]))
+	}
+
+	builder.reset()
+	builder.pooled = true
+	builder.Table = tableName
+	builder.Mode = SQLSelect
+	builder.dtoType = reflect.TypeOf((*T)(nil)).Elem()
+	// Copy instead of sharing the columns cache, Select overwrites matching aliases in place
+	builder.Columns = append(builder.Columns, ExtractJSONTags[T]()...)
+
+	return builder
+}
+
+// Release returns a builder obtained from AcquireSelectBuilder to the pool.
+// Builders created by NewSQLSelectBuilder are ignored. A released builder is cleared for its next user,
+// so it must not be released while still composed into another one, e.g. as a CTE kept by its parent.
+func Release(builder SQLSelectChainBuilder) {
+	selectBuilder, ok := builder.(*SelectBuilder)
+	if !ok || selectBuilder == nil || !selectBuilder.pooled {
+		return
+	}
+
+	selectBuilder.reset()
+	selectBuilderPool.Put(selectBuilder)
+}
+
+// reset clears all state while keeping the capacity of the clause slices.
+// Args is dropped rather than truncated because it was handed out by Build.
+func (s *SelectBuilder) reset() {
+	q := s.SQLEloquentQuery
+	*q = SQLEloquentQuery{
+		NestedAggregation: q.NestedAggregation[:0],
+		WithClauses:       q.WithClauses[:0],
+		Filters:           q.Filters[:0],
+		OtherTables:       q.OtherTables[:0],
+		UnionAllQueries:   q.UnionAllQueries[:0],
+		setOperators:      q.setOperators[:0],
+		Columns:           q.Columns[:0],
+		selectedColumns:   q.selectedColumns[:0],
+		DistinctBy:        q.DistinctBy[:0],
+		SortBy:            q.SortBy[:0],
+		Grouping:          q.Grouping[:0],
+		HavingClauses:     q.HavingClauses[:0],
+		tables:            q.tables[:0],
+	}
+}
@@ -0,0 +1,41 @@
+package sql_query
+
+import (
+	"testing"
+
+	sqltesting "github.com/mystaline/clefinport-be/pkg/sql_query/testing"
+)
+
+type pooledWallet struct {
+	ID     string `json:"id"     column:"id"`
+	UserID string `json:"userId" column:"user_id"`
+}
+
+func TestAcquiredBuilderKnowsItsDTOColumns(t *testing.T) {
+	builder := AcquireSelectBuilder[pooledWallet]("wallets")
+	defer Release(builder)
+
+	builder.StrictFilterKeys().
+		Where(map[string]SQLCondition{"user_id": {Operator: SQLOperatorEqual, Value: "42"}})
+
+	sqltesting.AssertSQL(t, builder, `
+		SELECT id as "id", user_id as "userId"
+		FROM wallets
+		WHERE "user_id" = $1`,
+		[]any{"42"},
+	)
+}
+
+func TestReleaseIgnoresBuildersNotFromThePool(t *testing.T) {
+	builder := NewSQLSelectBuilder[pooledWallet]("wallets").
+		Where(map[string]SQLCondition{"user_id": {Operator: SQLOperatorEqual, Value: "42"}})
+
+	Release(builder)
+
+	sqltesting.AssertSQL(t, builder, `
+		SELECT id as "id", user_id as "userId"
+		FROM wallets
+		WHERE "user_id" = $1`,
+		[]any{"42"},
+	)
+}
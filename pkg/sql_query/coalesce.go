@@ -0,0 +1,33 @@
+package sql_query
+
+import (
+	"fmt"
+	"strings"
+)
+
+func (s *SelectBuilder) SelectCoalesce(alias string, exprs ...string) SQLSelectChainBuilder {
+	nonEmpty := make([]string, 0, len(exprs))
+	for _, expr := range exprs {
+		if expr = strings.TrimSpace(expr); expr != "" {
+			nonEmpty = append(nonEmpty, expr)
+		}
+	}
+	if len(nonEmpty) == 0 || alias == "" {
+		s.LastError = fmt.Errorf("%w: COALESCE needs an alias and an expression, got %q and %d", ErrInvalidValues, alias, len(nonEmpty))
+		return s
+	}
+
+	s.selectAliased(fmt.Sprintf(`COALESCE(%s) AS "%s"`, strings.Join(nonEmpty, ", "), alias), alias, nil)
+	return s
+}
+
+func (s *SelectBuilder) SelectNullIf(alias, a, b string) SQLSelectChainBuilder {
+	a, b = strings.TrimSpace(a), strings.TrimSpace(b)
+	if a == "" || b == "" || alias == "" {
+		s.LastError = fmt.Errorf("%w: NULLIF needs an alias and two expressions, got %q, %q and %q", ErrInvalidValues, alias, a, b)
+		return s
+	}
+
+	s.selectAliased(fmt.Sprintf(`NULLIF(%s, %s) AS "%s"`, a, b, alias), alias, nil)
+	return s
+}
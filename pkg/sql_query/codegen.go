@@ -0,0 +1,99 @@
+package sql_query
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/mystaline/clefinport-be/pkg/db"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// RowScanner is implemented by DTOs with a generated ScanRow (see pkg/cmd/sqlgen).
+// ScanRowObject and ScanRowsArray use it instead of the reflection + JSON round trip when present.
+type RowScanner interface {
+	// ScanRow scans the current row into the receiver, matching columns by their alias (json tag).
+	ScanRow(rows pgx.Rows) error
+}
+
+// InsertBinder is implemented by DTOs with a generated BindInsert (see pkg/cmd/sqlgen).
+// Insert uses it instead of walking the struct with reflection when present.
+type InsertBinder interface {
+	// InsertColumns returns the quoted column names, without id, created_at and updated_at.
+	InsertColumns() []string
+	// BindInsert returns the values in the same order as InsertColumns.
+	BindInsert() []any
+}
+
+var rowScannerType = reflect.TypeOf((*RowScanner)(nil)).Elem()
+
+func scanRowsWithScanner(sliceVal reflect.Value, rows pgx.Rows) error {
+	elemType := sliceVal.Type().Elem()
+
+	for rows.Next() {
+		newElemPtr := reflect.New(elemType)
+		if err := newElemPtr.Interface().(RowScanner).ScanRow(rows); err != nil {
+			return fmt.Errorf("scan failed: %w", err)
+		}
+
+		sliceVal.Set(reflect.Append(sliceVal, newElemPtr.Elem()))
+	}
+
+	return rows.Err()
+}
+
+// Generated counterpart of cachedInsertSingle/cachedInsertMany, rows always get a Snowflake id.
+func (s *InsertBuilder) boundInsert(binders []InsertBinder) SQLInsertChainBuilder {
+	db.InitSnowflake()
+
+	bindColumns := binders[0].InsertColumns()
+	columns := make([]string, 0, len(bindColumns)+3)
+	columns = append(columns, "id")
+	columns = append(columns, bindColumns...)
+	columns = append(columns, "updated_at", "created_at")
+
+	args := make([]interface{}, 0, len(binders)*(len(bindColumns)+1))
+	var placeholders strings.Builder
+	for i, binder := range binders {
+		if i > 0 {
+			placeholders.WriteByte(',')
+		}
+
+		args = append(args, db.Node.Generate().Int64())
+		placeholders.WriteString("($")
+		placeholders.WriteString(strconv.Itoa(len(args)))
+
+		for _, value := range binder.BindInsert() {
+			args = append(args, value)
+			placeholders.WriteString(",$")
+			placeholders.WriteString(strconv.Itoa(len(args)))
+		}
+
+		placeholders.WriteString(",NOW(),NOW())")
+	}
+
+	s.Args = args
+	s.preBuild(columns, []string{placeholders.String()})
+
+	return s
+}
+
+func asInsertBinders(v reflect.Value) ([]InsertBinder, bool) {
+	if v.Kind() == reflect.Struct {
+		binder, ok := v.Interface().(InsertBinder)
+		return []InsertBinder{binder}, ok
+	}
+
+	binders := make([]InsertBinder, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		binder, ok := v.Index(i).Interface().(InsertBinder)
+		if !ok {
+			return nil, false
+		}
+		binders[i] = binder
+	}
+
+	return binders, true
+}
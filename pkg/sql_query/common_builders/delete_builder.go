@@ -1,20 +1,21 @@
 package common_builders
 
 import (
-	"log"
-
 	"github.com/mystaline/clefinport-be/pkg/sql_query"
 )
 
-func DeleteBuilder(tableName string, query map[string]sql_query.SQLCondition) (string, []interface{}) {
-	res, args, err := sql_query.NewSQLDeleteBuilder(tableName).
+func DeleteBuilder(tableName string, query map[string]sql_query.SQLCondition) (string, []interface{}, error) {
+	return sql_query.NewSQLDeleteBuilder(tableName).
 		Delete("id").
 		Where(query).
 		Build()
+}
 
-	if err != nil {
-		log.Println(err)
-	}
-
-	return res, args
+// DeleteManyBuilder skips RETURNING, only the affected row count is read.
+func DeleteManyBuilder(tableName string, query map[string]sql_query.SQLCondition) (string, []interface{}, error) {
+	return sql_query.NewSQLDeleteBuilder(tableName).
+		Delete().
+		ReturnNone().
+		Where(query).
+		Build()
 }
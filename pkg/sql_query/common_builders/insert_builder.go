@@ -1,18 +1,17 @@
 package common_builders
 
 import (
-	"log"
-
 	"github.com/mystaline/clefinport-be/pkg/sql_query"
 )
 
-func InsertBuilder(tableName string, body interface{}, returningColumn ...string) (string, []interface{}) {
-	res, args, err := sql_query.NewSQLInsertBuilder(tableName).
+func InsertBuilder(
+	tableName string,
+	stamp sql_query.ActorStamp,
+	body interface{},
+	returningColumn ...string,
+) (string, []interface{}, error) {
+	return sql_query.NewSQLInsertBuilder(tableName).
+		StampActor(stamp).
 		Insert(body, returningColumn...).
 		Build()
-	if err != nil {
-		log.Println(err)
-	}
-
-	return res, args
 }
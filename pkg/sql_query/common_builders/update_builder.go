@@ -1,25 +1,25 @@
 package common_builders
 
 import (
-	"log"
-
 	"github.com/mystaline/clefinport-be/pkg/sql_query"
 )
 
 func UpdateBuilder(
 	tableName string,
+	stamp sql_query.ActorStamp,
+	touch bool,
 	query map[string]sql_query.SQLCondition,
 	body interface{},
 	returningColumn ...string,
-) (string, []interface{}) {
-	res, args, err := sql_query.NewSQLUpdateBuilder(tableName).
+) (string, []interface{}, error) {
+	builder := sql_query.NewSQLUpdateBuilder(tableName).StampActor(stamp)
+	if !touch {
+		builder = builder.WithoutTouch()
+	}
+
+	return builder.
 		Update(body).
 		Return(returningColumn...).
 		Where(query).
 		Build()
-	if err != nil {
-		log.Println(err)
-	}
-
-	return res, args
 }
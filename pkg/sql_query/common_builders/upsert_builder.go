@@ -0,0 +1,27 @@
+package common_builders
+
+import (
+	"reflect"
+
+	"github.com/mystaline/clefinport-be/pkg/sql_query"
+)
+
+// UpsertBuilder upserts a struct or a slice of structs, returning the id and whether each row was inserted.
+func UpsertBuilder(
+	tableName string,
+	stamp sql_query.ActorStamp,
+	conflictColumns []string,
+	updateColumns []string,
+	body interface{},
+) (string, []interface{}, error) {
+	values := reflect.ValueOf(body)
+	if values.Kind() == reflect.Struct {
+		values = reflect.Append(reflect.MakeSlice(reflect.SliceOf(values.Type()), 0, 1), values)
+	}
+
+	return sql_query.NewSQLInsertBuilder(tableName).
+		StampActor(stamp).
+		UpsertMany(values.Interface(), conflictColumns, updateColumns).
+		ReturnInserted().
+		Build()
+}
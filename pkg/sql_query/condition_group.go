@@ -0,0 +1,85 @@
+package sql_query
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GroupOperator joins the members of a ConditionGroup.
+type GroupOperator string
+
+const (
+	GroupAnd GroupOperator = "AND"
+	GroupOr  GroupOperator = "OR"
+)
+
+// ConditionGroup is a node of a WHERE tree, its Conditions and Groups are joined by Operator.
+// Build it with AndGroup and OrGroup, see WhereGroup.
+type ConditionGroup struct {
+	Operator   GroupOperator
+	Conditions map[string]SQLCondition
+	Groups     []ConditionGroup
+}
+
+// AndGroup joins conditions and child groups with AND.
+func AndGroup(conditions map[string]SQLCondition, groups ...ConditionGroup) ConditionGroup {
+	return ConditionGroup{Operator: GroupAnd, Conditions: conditions, Groups: groups}
+}
+
+// OrGroup joins conditions and child groups with OR.
+// Conditions on the same column need their own child groups, e.g. OrGroup(nil, AndGroup(a), AndGroup(b)).
+func OrGroup(conditions map[string]SQLCondition, groups ...ConditionGroup) ConditionGroup {
+	return ConditionGroup{Operator: GroupOr, Conditions: conditions, Groups: groups}
+}
+
+func (s *SQLEloquentQuery) sharedWhereGroup(groups ...ConditionGroup) {
+	for _, group := range groups {
+		clause, err := s.buildConditionGroup(group)
+		if err != nil {
+			s.LastError = err
+			return
+		}
+		if clause != "" {
+			s.Filters = append(s.Filters, clause)
+		}
+	}
+}
+
+// buildConditionGroup renders group as a parenthesized clause, placeholders continue from s.Args.
+// Groups without any condition render as an empty string and are left out of their parent.
+func (s *SQLEloquentQuery) buildConditionGroup(group ConditionGroup) (string, error) {
+	operator := group.Operator
+	if operator == "" {
+		operator = GroupAnd
+	}
+	if operator != GroupAnd && operator != GroupOr {
+		return "", fmt.Errorf("invalid condition group operator %q", operator)
+	}
+
+	inner := &SQLEloquentQuery{Args: s.Args}
+	inner.sharedWhereAndQuery(group.Conditions)
+	s.Args = inner.Args
+	if inner.LastError != nil {
+		return "", inner.LastError
+	}
+
+	parts := inner.Filters
+	for _, child := range group.Groups {
+		clause, err := s.buildConditionGroup(child)
+		if err != nil {
+			return "", err
+		}
+		if clause != "" {
+			parts = append(parts, clause)
+		}
+	}
+
+	switch len(parts) {
+	case 0:
+		return "", nil
+	case 1:
+		return parts[0], nil
+	}
+
+	return "(" + strings.Join(parts, " "+string(operator)+" ") + ")", nil
+}
@@ -0,0 +1,80 @@
+package sql_query
+
+// Conditional variants of the chaining methods, they apply the wrapped call only when cond is true
+// so optional filters parsed from query structs chain without breaking the builder into if statements.
+
+func (s *SelectBuilder) WhereIf(cond bool, filters map[string]SQLCondition) SQLSelectChainBuilder {
+	if !cond {
+		return s
+	}
+	return s.Where(filters)
+}
+
+func (s *SelectBuilder) SelectIf(cond bool, columns ...string) SQLSelectChainBuilder {
+	if !cond {
+		return s
+	}
+	return s.Select(columns...)
+}
+
+func (s *SelectBuilder) JoinIf(
+	cond bool,
+	table string,
+	onCondition string,
+	additionalConditions ...map[string]SQLCondition,
+) SQLSelectChainBuilder {
+	if !cond {
+		return s
+	}
+	return s.Join(table, onCondition, additionalConditions...)
+}
+
+func (s *SelectBuilder) LeftJoinIf(
+	cond bool,
+	table string,
+	onCondition string,
+	additionalConditions ...map[string]SQLCondition,
+) SQLSelectChainBuilder {
+	if !cond {
+		return s
+	}
+	return s.LeftJoin(table, onCondition, additionalConditions...)
+}
+
+func (s *UpdateBuilder) WhereIf(cond bool, filters map[string]SQLCondition) SQLUpdateChainBuilder {
+	if !cond {
+		return s
+	}
+	return s.Where(filters)
+}
+
+func (s *UpdateBuilder) JoinIf(
+	cond bool,
+	table string,
+	onCondition string,
+	additionalConditions ...map[string]SQLCondition,
+) SQLUpdateChainBuilder {
+	if !cond {
+		return s
+	}
+	return s.Join(table, onCondition, additionalConditions...)
+}
+
+func (s *UpdateBuilder) LeftJoinIf(
+	cond bool,
+	table string,
+	onCondition string,
+	additionalConditions ...map[string]SQLCondition,
+) SQLUpdateChainBuilder {
+	if !cond {
+		return s
+	}
+	return s.LeftJoin(table, onCondition, additionalConditions...)
+}
+
+func (s *DeleteBuilder) WhereIf(cond bool, filters map[string]SQLCondition) SQLDeleteChainBuilder {
+	if !cond {
+		return s
+	}
+	return s.Where(filters)
+}
@@ -0,0 +1,49 @@
+package sql_query
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CTEOption tunes a CTE added by WithCTEBuilder or WithRecursiveCTEBuilder.
+type CTEOption struct {
+	// Materialized forces (true) or forbids (false) computing the CTE once, nil leaves it to the planner.
+	// Postgres 12+ inlines a CTE referenced once, which can push a heavy pagination CTE into a worse plan.
+	Materialized *bool
+}
+
+// cteClause formats `name AS [NOT] MATERIALIZED (query)`, the last option wins.
+func cteClause(cteName, query string, options []CTEOption) string {
+	hint := ""
+	if len(options) > 0 && options[len(options)-1].Materialized != nil {
+		if *options[len(options)-1].Materialized {
+			hint = "MATERIALIZED "
+		} else {
+			hint = "NOT MATERIALIZED "
+		}
+	}
+
+	return fmt.Sprintf("%s AS %s(%s)", cteName, hint, query)
+}
+
+func (s *SQLEloquentQuery) GetCTE(cteName string) (*SQLEloquentQuery, bool) {
+	cte, ok := s.ctes[strings.ToLower(cteName)]
+	return cte, ok
+}
+
+// registerCTE records cteBuilder under cteName, a name already taken sets LastError.
+// Unquoted names are case insensitive in Postgres, so are they here.
+func (s *SQLEloquentQuery) registerCTE(cteName string, cteBuilder *SQLEloquentQuery) bool {
+	key := strings.ToLower(cteName)
+	if _, ok := s.ctes[key]; ok {
+		s.LastError = fmt.Errorf("%w: CTE %s is already defined, join it again instead (see GetCTE)", ErrInvalidValues, cteName)
+		return false
+	}
+
+	if s.ctes == nil {
+		s.ctes = map[string]*SQLEloquentQuery{}
+	}
+	s.ctes[key] = cteBuilder
+	s.touchTablesOf(cteBuilder)
+	return true
+}
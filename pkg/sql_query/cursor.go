@@ -0,0 +1,201 @@
+package sql_query
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Keyset values are selected under these aliases so the next cursor can be taken from the last row,
+// they are not part of the DTO and ignored when scanning into it.
+const (
+	CursorSortAlias = "__cursorSort"
+	CursorIDAlias   = "__cursorId"
+)
+
+// DefaultCursorLimit is the page size of a Cursor without Limit.
+const DefaultCursorLimit = 20
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor configures keyset pagination, see PaginateCursor.
+type Cursor struct {
+	Limit int `json:"limit"  transform:"int"`
+	// After is the NextCursor of the previous page, empty for the first page.
+	After string `json:"cursor" transform:"string"`
+	// SortBy is the keyset column, created_at by default. Plain column names are qualified with the main table's
+	// alias so they stay unambiguous with joins. It may be NULL, NULLs come last newest first and first oldest first.
+	SortBy string `json:"-"`
+	// IDColumn breaks ties between equal SortBy values, id by default. It must be unique and not null.
+	IDColumn string `json:"-"`
+	// Asc pages from oldest to newest, newest first by default.
+	Asc bool `json:"-"`
+}
+
+func (c Cursor) withDefaults() Cursor {
+	if c.Limit <= 0 {
+		c.Limit = DefaultCursorLimit
+	}
+	if c.SortBy == "" {
+		c.SortBy = "created_at"
+	}
+	if c.IDColumn == "" {
+		c.IDColumn = "id"
+	}
+
+	return c
+}
+
+// CursorPage is the result of a keyset paginated query.
+type CursorPage[T any] struct {
+	Data       []T    `json:"data"`
+	NextCursor string `json:"nextCursor"`
+	HasMore    bool   `json:"hasMore"`
+}
+
+// EncodeCursor builds an opaque cursor from the keyset values of a row, a nil sortValue stands for NULL.
+func EncodeCursor(sortValue *string, id string) string {
+	raw, _ := json.Marshal([2]*string{sortValue, &id})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeCursor returns the keyset values of a cursor built by EncodeCursor.
+func DecodeCursor(cursor string) (sortValue *string, id string, err error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, "", ErrInvalidCursor
+	}
+
+	var values [2]*string
+	if err := json.Unmarshal(raw, &values); err != nil || values[1] == nil {
+		return nil, "", ErrInvalidCursor
+	}
+
+	return values[0], *values[1], nil
+}
+
+func (s *SelectBuilder) PaginateCursor(cursor Cursor) SQLSelectChainBuilder {
+	cursor = cursor.withDefaults()
+	cursor.SortBy = s.qualifyCursorColumn(cursor.SortBy)
+	cursor.IDColumn = s.qualifyCursorColumn(cursor.IDColumn)
+
+	if cursor.After != "" {
+		sortValue, id, err := DecodeCursor(cursor.After)
+		if err != nil {
+			s.LastError = err
+			return s
+		}
+
+		// Keyset values travel as text, Postgres casts them to the column types
+		s.Filters = append(s.Filters, cursorFilter(cursor, sortValue != nil, len(s.Args)))
+		if sortValue != nil {
+			s.Args = appendArgs(s.Args, []interface{}{*sortValue, id})
+		} else {
+			s.Args = appendArgs(s.Args, []interface{}{id})
+		}
+	}
+
+	s.UsePagination = false
+	s.SortBy = s.SortBy[:0]
+	s.OrderBy([]string{cursor.SortBy}, cursor.Asc)
+	s.OrderBy([]string{cursor.IDColumn}, cursor.Asc)
+
+	// One extra row tells whether there is a next page
+	s.cursorLimit = cursor.Limit + 1
+
+	s.cursorColumns = []string{
+		fmt.Sprintf(`%s::text AS "%s"`, cursor.SortBy, CursorSortAlias),
+		fmt.Sprintf(`%s::text AS "%s"`, cursor.IDColumn, CursorIDAlias),
+	}
+
+	return s
+}
+
+// qualifyCursorColumn qualifies a plain column name with the main table's alias, other expressions are kept.
+func (s *SelectBuilder) qualifyCursorColumn(column string) string {
+	column = strings.TrimSpace(column)
+	if !isPlainIdentifier(strings.Trim(column, `"`)) {
+		return column
+	}
+
+	return fmt.Sprintf(`%s."%s"`, s.tablePrefix(), strings.Trim(column, `"`))
+}
+
+// cursorFilter selects the rows after the cursor in the order of PaginateCursor, where NULL sorts before any value.
+// Its placeholders follow the offset arguments already bound, the sort value's first when the cursor has one.
+//
+//	newest first, from a value: ((sort, id) < ($1, $2) OR sort IS NULL)
+//	newest first, from NULL:    (sort IS NULL AND id < $1)
+//	oldest first, from a value: (sort, id) > ($1, $2)
+//	oldest first, from NULL:    (sort IS NOT NULL OR id > $1)
+func cursorFilter(cursor Cursor, hasSortValue bool, offset int) string {
+	sortBy, id := cursor.SortBy, cursor.IDColumn
+
+	switch {
+	case !cursor.Asc && hasSortValue:
+		return fmt.Sprintf("((%s, %s) < ($%d, $%d) OR %s IS NULL)", sortBy, id, offset+1, offset+2, sortBy)
+	case !cursor.Asc:
+		return fmt.Sprintf("(%s IS NULL AND %s < $%d)", sortBy, id, offset+1)
+	case hasSortValue:
+		return fmt.Sprintf("(%s, %s) > ($%d, $%d)", sortBy, id, offset+1, offset+2)
+	default:
+		return fmt.Sprintf("(%s IS NOT NULL OR %s > $%d)", sortBy, id, offset+1)
+	}
+}
+
+// ScanRowsCursor scans at most limit rows of a PaginateCursor query into v (pointer to a slice)
+// and returns the cursor of the next page, empty when this is the last page.
+func ScanRowsCursor(v any, rows pgx.Rows, limit int) (string, error) {
+	vVal := reflect.ValueOf(v)
+	if vVal.Kind() != reflect.Ptr || vVal.Elem().Kind() != reflect.Slice {
+		return "", fmt.Errorf("ScanRowsCursor: v must be a pointer to a slice")
+	}
+
+	sliceVal := vVal.Elem()
+	elemType := sliceVal.Type().Elem()
+	fieldDescs := rows.FieldDescriptions()
+
+	var lastSort *string
+	var lastID string
+	for rows.Next() {
+		// The extra row of PaginateCursor, only its existence matters
+		if sliceVal.Len() == limit {
+			return EncodeCursor(lastSort, lastID), nil
+		}
+
+		values, err := rows.Values()
+		if err != nil {
+			return "", err
+		}
+
+		rowMap := make(map[string]interface{}, len(fieldDescs))
+		for i, fd := range fieldDescs {
+			rowMap[fd.Name] = values[i]
+		}
+
+		lastSort = nil
+		if sortValue, ok := rowMap[CursorSortAlias].(string); ok {
+			lastSort = &sortValue
+		}
+		lastID, _ = rowMap[CursorIDAlias].(string)
+
+		jsonBytes, err := json.Marshal(rowMap)
+		if err != nil {
+			return "", fmt.Errorf("marshal failed: %w", err)
+		}
+
+		newElemPtr := reflect.New(elemType)
+		if err := json.Unmarshal(jsonBytes, newElemPtr.Interface()); err != nil {
+			return "", fmt.Errorf("unmarshal failed: %w", err)
+		}
+
+		sliceVal.Set(reflect.Append(sliceVal, newElemPtr.Elem()))
+	}
+
+	return "", rows.Err()
+}
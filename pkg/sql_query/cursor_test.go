@@ -0,0 +1,78 @@
+package sql_query
+
+import (
+	"errors"
+	"testing"
+
+	sqltesting "github.com/mystaline/clefinport-be/pkg/sql_query/testing"
+)
+
+func TestPaginateCursorQualifiesColumnsWithJoins(t *testing.T) {
+	sortValue := "2026-01-02 03:04:05+00"
+	builder := NewSQLSelectBuilder[any]("transactions", "t").
+		Select(`t."amount"`).
+		Join("wallets w", `w."id" = t."wallet_id"`).
+		PaginateCursor(Cursor{Limit: 2, After: EncodeCursor(&sortValue, "7")})
+
+	sqltesting.AssertSQL(t, builder, `
+		SELECT t."amount", t."created_at"::text AS "__cursorSort", t."id"::text AS "__cursorId"
+		FROM transactions t
+		JOIN wallets w ON w."id" = t."wallet_id"
+		WHERE ((t."created_at", t."id") < ($1, $2) OR t."created_at" IS NULL)
+		ORDER BY t."created_at" DESC NULLS LAST, t."id" DESC NULLS LAST
+		LIMIT 3`,
+		[]any{sortValue, "7"},
+	)
+}
+
+func TestPaginateCursorFromNullSortValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		asc     bool
+		wantSQL string
+	}{
+		{
+			name: "newest first",
+			wantSQL: `
+				SELECT "id", invoices."paid_at"::text AS "__cursorSort", invoices."id"::text AS "__cursorId"
+				FROM invoices
+				WHERE (invoices."paid_at" IS NULL AND invoices."id" < $1)
+				ORDER BY invoices."paid_at" DESC NULLS LAST, invoices."id" DESC NULLS LAST
+				LIMIT 21`,
+		},
+		{
+			name: "oldest first",
+			asc:  true,
+			wantSQL: `
+				SELECT "id", invoices."paid_at"::text AS "__cursorSort", invoices."id"::text AS "__cursorId"
+				FROM invoices
+				WHERE (invoices."paid_at" IS NOT NULL OR invoices."id" > $1)
+				ORDER BY invoices."paid_at" ASC NULLS FIRST, invoices."id" ASC NULLS FIRST
+				LIMIT 21`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := NewSQLSelectBuilder[any]("invoices").
+				Select(`"id"`).
+				PaginateCursor(Cursor{After: EncodeCursor(nil, "7"), SortBy: "paid_at", Asc: tt.asc})
+
+			sqltesting.AssertSQL(t, builder, tt.wantSQL, []any{"7"})
+		})
+	}
+}
+
+func TestDecodeCursor(t *testing.T) {
+	sortValue := "2026-01-02"
+	for _, want := range []*string{&sortValue, nil} {
+		got, id, err := DecodeCursor(EncodeCursor(want, "7"))
+		if err != nil || id != "7" || (got == nil) != (want == nil) || got != nil && *got != *want {
+			t.Errorf("DecodeCursor(EncodeCursor(%v, 7)) = %v, %q, %v", want, got, id, err)
+		}
+	}
+
+	if _, _, err := DecodeCursor("not a cursor"); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("DecodeCursor() error = %v, want ErrInvalidCursor", err)
+	}
+}
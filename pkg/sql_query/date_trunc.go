@@ -0,0 +1,79 @@
+package sql_query
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Fields accepted by Postgres date_trunc
+var dateTruncIntervals = []string{
+	"microseconds", "milliseconds", "second", "minute", "hour",
+	"day", "week", "month", "quarter", "year", "decade", "century", "millennium",
+}
+
+func (s *SelectBuilder) InTimezone(timezone string) SQLSelectChainBuilder {
+	s.timezone = strings.TrimSpace(timezone)
+	return s
+}
+
+func (s *SelectBuilder) SelectDateTrunc(interval, column, alias string) SQLSelectChainBuilder {
+	expr, ok := s.dateTruncExpr(interval, column)
+	if !ok {
+		return s
+	}
+
+	s.selectDateTrunc(expr, alias)
+	return s
+}
+
+func (s *SelectBuilder) GroupByDateTrunc(interval, column, alias string) SQLSelectChainBuilder {
+	expr, ok := s.dateTruncExpr(interval, column)
+	if !ok {
+		return s
+	}
+
+	s.selectDateTrunc(expr, alias)
+
+	// Same expression and placeholder as the column, Postgres matches them as one grouping key
+	s.Grouping = append(s.Grouping, expr)
+	sort := fmt.Sprintf(`"%s" ASC`, alias)
+	s.SortBy = append(s.SortBy, sort)
+	s.trustedExpressions = append(s.trustedExpressions, expr, sort)
+
+	return s
+}
+
+// dateTruncExpr validates interval and builds the date_trunc call, binding the timezone of InTimezone when set.
+// The three arguments form (Postgres 12+) truncates in that timezone and still returns a timestamptz.
+func (s *SelectBuilder) dateTruncExpr(interval, column string) (string, bool) {
+	interval = strings.ToLower(strings.TrimSpace(interval))
+	if !slices.Contains(dateTruncIntervals, interval) {
+		s.LastError = fmt.Errorf("%w: unsupported date_trunc interval %q", ErrInvalidValues, interval)
+		return "", false
+	}
+
+	column = strings.TrimSpace(column)
+	s.dateTruncColumns = append(s.dateTruncColumns, column)
+
+	if s.timezone == "" {
+		return fmt.Sprintf("date_trunc('%s', %s)", interval, column), true
+	}
+
+	s.Args = append(s.Args, s.timezone)
+	return fmt.Sprintf("date_trunc('%s', %s, $%d)", interval, column, len(s.Args)), true
+}
+
+func (s *SelectBuilder) selectDateTrunc(expr, alias string) {
+	column := fmt.Sprintf(`%s AS "%s"`, expr, alias)
+
+	// Check if alias exists in current list
+	for i, existing := range s.Columns {
+		if extracted := extractAlias(existing); extracted != "" && extracted == strings.ToLower(alias) {
+			s.Columns[i] = column // Overwrite
+			return
+		}
+	}
+
+	s.Columns = append(s.Columns, column)
+}
@@ -0,0 +1,134 @@
+package sql_query
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BuildDebug builds the query with every placeholder replaced by its literal value, ready to paste into psql.
+// Only meant for logs, always execute the parameterized query of Build.
+// Placeholders are Postgres style whatever the builder's Dialect.
+func (s *SQLEloquentQuery) BuildDebug() (string, error) {
+	query, args, err := s.build()
+	if err != nil {
+		return "", err
+	}
+
+	return InterpolateArgs(query, args)
+}
+
+// InterpolateArgs replaces the $n placeholders of query by args rendered as escaped SQL literals.
+//
+// Example:
+//
+//	sql_query.InterpolateArgs(`SELECT * FROM users WHERE name = $1 AND id = ANY($2)`, []any{"O'Neil", []int{1, 2}})
+//	// SELECT * FROM users WHERE name = 'O''Neil' AND id = ANY(ARRAY[1, 2])
+func InterpolateArgs(query string, args []interface{}) (string, error) {
+	var err error
+	query = replacePlaceholders(query, func(num, start, end int) string {
+		if num < 1 || num > len(args) {
+			err = ErrPlaceholderOutOfRange
+			return query[start:end]
+		}
+
+		literal, literalErr := sqlLiteral(args[num-1])
+		if literalErr != nil {
+			err = literalErr
+			return query[start:end]
+		}
+
+		return literal
+	})
+
+	return query, err
+}
+
+func sqlLiteral(arg interface{}) (string, error) {
+	switch value := arg.(type) {
+	case nil:
+		return "NULL", nil
+	case string:
+		return quoteLiteral(value), nil
+	case []byte:
+		return fmt.Sprintf(`'\x%s'::bytea`, hex.EncodeToString(value)), nil
+	case bool:
+		if value {
+			return "TRUE", nil
+		}
+		return "FALSE", nil
+	case time.Time:
+		return quoteLiteral(value.Format(time.RFC3339Nano)) + "::timestamptz", nil
+	case driver.Valuer:
+		v := reflect.ValueOf(value)
+		if v.Kind() == reflect.Ptr && v.IsNil() {
+			return "NULL", nil
+		}
+
+		driverValue, err := value.Value()
+		if err != nil {
+			return "", err
+		}
+		return sqlLiteral(driverValue)
+	}
+
+	v := reflect.ValueOf(arg)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return "NULL", nil
+		}
+		return sqlLiteral(v.Elem().Interface())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		if math.IsNaN(v.Float()) || math.IsInf(v.Float(), 0) {
+			return quoteLiteral(strconv.FormatFloat(v.Float(), 'g', -1, 64)) + "::float8", nil
+		}
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64), nil
+	case reflect.String:
+		return quoteLiteral(v.String()), nil
+	case reflect.Bool:
+		return sqlLiteral(v.Bool())
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return "NULL", nil
+		}
+		// ARRAY[] needs a type Postgres can't infer here, the empty array literal is cast by its context
+		if v.Len() == 0 {
+			return "'{}'", nil
+		}
+
+		elements := make([]string, v.Len())
+		for i := range elements {
+			element, err := sqlLiteral(v.Index(i).Interface())
+			if err != nil {
+				return "", err
+			}
+			elements[i] = element
+		}
+		return fmt.Sprintf("ARRAY[%s]", strings.Join(elements, ", ")), nil
+	case reflect.Map, reflect.Struct:
+		// pgx encodes them as json
+		encoded, err := json.Marshal(arg)
+		if err != nil {
+			return "", err
+		}
+		return quoteLiteral(string(encoded)), nil
+	}
+
+	return quoteLiteral(fmt.Sprint(arg)), nil
+}
+
+// quoteLiteral quotes value as a standard conforming string, backslashes are kept as is.
+func quoteLiteral(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
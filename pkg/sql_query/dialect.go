@@ -0,0 +1,84 @@
+package sql_query
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// Dialect decides the placeholder style of built queries.
+// Builders always number placeholders Postgres style ($1, $2) while composing,
+// Build rewrites them once at the end, so sub-builders, shiftSQLPlaceholders and the WHERE helpers
+// don't need to know the dialect.
+//
+// Only placeholders are translated. Identifiers stay double quoted, so MySQL needs the ANSI_QUOTES sql_mode,
+// and Postgres only syntax (casts with ::, ILIKE, RETURNING, jsonb operators) must be avoided by the caller.
+type Dialect string
+
+const (
+	DialectPostgres Dialect = "postgres"
+	DialectMySQL    Dialect = "mysql"
+	DialectSQLite   Dialect = "sqlite"
+)
+
+var ErrPlaceholderOutOfRange = errors.New("placeholder has no matching argument")
+
+var defaultDialect atomic.Value
+
+func init() {
+	defaultDialect.Store(DialectPostgres)
+}
+
+// SetDefaultDialect sets the dialect of builders without UseDialect, call it once at startup.
+//
+// Example:
+//
+//	sql_query.SetDefaultDialect(sql_query.DialectMySQL)
+func SetDefaultDialect(dialect Dialect) {
+	if dialect == "" {
+		dialect = DialectPostgres
+	}
+
+	defaultDialect.Store(dialect)
+}
+
+// DefaultDialect returns the dialect set by SetDefaultDialect, Postgres unless changed.
+func DefaultDialect() Dialect {
+	return defaultDialect.Load().(Dialect)
+}
+
+// usesPositionalPlaceholders reports whether the dialect only knows anonymous `?` placeholders.
+func (d Dialect) usesPositionalPlaceholders() bool {
+	return d == DialectMySQL || d == DialectSQLite
+}
+
+func (s *SQLEloquentQuery) dialect() Dialect {
+	if s.Dialect == "" {
+		return DefaultDialect()
+	}
+
+	return s.Dialect
+}
+
+// rebindPlaceholders turns numbered placeholders into `?` and orders args by occurrence,
+// a placeholder used twice gets its arg twice since `?` can't be referenced again.
+//
+//	rebindPlaceholders(`a = $2 OR b = $1 OR c = $2`, []any{"x", "y"}) // `a = ? OR b = ? OR c = ?`, [y x y]
+func rebindPlaceholders(query string, args []interface{}) (string, []interface{}, error) {
+	rebound := make([]interface{}, 0, len(args))
+
+	var err error
+	query = replacePlaceholders(query, func(num, start, end int) string {
+		if num < 1 || num > len(args) {
+			err = ErrPlaceholderOutOfRange
+			return query[start:end]
+		}
+
+		rebound = append(rebound, args[num-1])
+		return "?"
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	return query, rebound, nil
+}
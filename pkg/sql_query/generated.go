@@ -0,0 +1,55 @@
+package sql_query
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Generated columns (GENERATED ALWAYS AS ...) are computed by Postgres, writing them fails at execution.
+// Struct fields tagged `special:"generated"` are read like any other column (SELECT, RETURNING)
+// and skipped by every INSERT and UPDATE SET, as the value usually comes from a previous read.
+// Writes naming the column explicitly (map values, Increment, ConflictUpdate, UpdateRawSQL)
+// fail with ErrGeneratedColumn instead, for the columns registered with RegisterGeneratedColumns
+// or, for UpdateRawSQL, tagged on the struct.
+
+var ErrGeneratedColumn = errors.New("generated column cannot be written")
+
+// Generated columns by table name
+var generatedColumns sync.Map
+
+// RegisterGeneratedColumns declares the generated columns of table, call it once at startup.
+//
+// Example:
+//
+//	sql_query.RegisterGeneratedColumns(db.TransactionTableName, "amount_abs")
+func RegisterGeneratedColumns(table string, columns ...string) {
+	generatedColumns.Store(table, slices.Clone(columns))
+}
+
+// IsGeneratedColumn reports whether column was registered as generated for table, table may carry an alias.
+func IsGeneratedColumn(table, column string) bool {
+	fields := strings.Fields(table)
+	if len(fields) == 0 {
+		return false
+	}
+
+	columns, ok := generatedColumns.Load(fields[0])
+	if !ok {
+		return false
+	}
+
+	return slices.Contains(columns.([]string), strings.Trim(strings.TrimSpace(column), `"`))
+}
+
+// rejectGeneratedColumn sets LastError when column is a registered generated column of the builder's table.
+func (s *SQLEloquentQuery) rejectGeneratedColumn(column string) bool {
+	if !IsGeneratedColumn(s.Table, column) {
+		return false
+	}
+
+	s.LastError = fmt.Errorf("%w: %s.%s", ErrGeneratedColumn, strings.Fields(s.Table)[0], strings.Trim(column, `"`))
+	return true
+}
@@ -0,0 +1,87 @@
+package sql_query
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync/atomic"
+)
+
+// InListStrategy decides how IN / NOT IN lists are bound.
+// Every distinct list length is a distinct query text, so expanding `IN ($1, $2, ...)` per value
+// defeats the prepared statement caches of pgx and pgbouncer once lists vary in size.
+type InListStrategy struct {
+	// Binning pads expanded lists to the next power of two (1, 2, 4, 8, 16...) by repeating the last value,
+	// a handful of query shapes then cover every list length.
+	Binning bool
+	// AnyThreshold binds lists longer than it as a single array, `"id" = ANY($1)` / `"id" <> ALL($1)`.
+	// Postgres only, other dialects keep expanding. 0 always expands.
+	AnyThreshold int
+}
+
+const defaultInListAnyThreshold = 32
+
+var defaultInListStrategy atomic.Value
+
+func init() {
+	defaultInListStrategy.Store(InListStrategy{AnyThreshold: defaultInListAnyThreshold})
+}
+
+// SetInListStrategy sets how IN lists of every builder are bound, call it once at startup.
+//
+// Example:
+//
+//	sql_query.SetInListStrategy(sql_query.InListStrategy{Binning: true, AnyThreshold: 16})
+func SetInListStrategy(strategy InListStrategy) {
+	defaultInListStrategy.Store(strategy)
+}
+
+// DefaultInListStrategy returns the strategy set by SetInListStrategy,
+// lists longer than 32 values are bound with = ANY unless changed.
+func DefaultInListStrategy() InListStrategy {
+	return defaultInListStrategy.Load().(InListStrategy)
+}
+
+// inListClause binds the non empty list v of an IN / NOT IN condition on the already quoted column.
+//
+//	"id" IN ($1, $2, $3, $3)   // Binning, 3 values padded to 4
+//	"id" = ANY($1)             // more values than AnyThreshold
+//	"id" <> ALL($1)            // NOT IN, more values than AnyThreshold
+func (s *SQLEloquentQuery) inListClause(column string, operator SQLOperators, v reflect.Value) string {
+	strategy := DefaultInListStrategy()
+
+	// The dialect must be chosen before Where for the ANY form to be skipped on MySQL and SQLite.
+	// []interface{} lists keep expanding, pgx can't pick an array type for them.
+	if strategy.AnyThreshold > 0 && v.Len() > strategy.AnyThreshold &&
+		s.dialect() == DialectPostgres && v.Type().Elem().Kind() != reflect.Interface {
+		s.Args = append(s.Args, v.Interface())
+		if operator == SQLOperatorNotIn {
+			return fmt.Sprintf(`%s <> ALL($%d)`, column, len(s.Args))
+		}
+		return fmt.Sprintf(`%s = ANY($%d)`, column, len(s.Args))
+	}
+
+	size := v.Len()
+	if strategy.Binning {
+		size = nextPowerOfTwo(size)
+	}
+
+	ph := make([]string, size)
+	for i := 0; i < size; i++ {
+		// Repeating the last value doesn't change the result of IN nor NOT IN
+		value := v.Index(min(i, v.Len()-1)).Interface()
+		s.Args = append(s.Args, value)
+		ph[i] = fmt.Sprintf("$%d", len(s.Args))
+	}
+
+	return fmt.Sprintf(`%s %s (%s)`, column, operator, strings.Join(ph, ", "))
+}
+
+func nextPowerOfTwo(n int) int {
+	size := 1
+	for size < n {
+		size <<= 1
+	}
+
+	return size
+}
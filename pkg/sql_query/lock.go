@@ -0,0 +1,83 @@
+package sql_query
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// LockMode is the row-level locking clause of a SELECT.
+type LockMode string
+
+const (
+	// Blocks concurrent UPDATE, DELETE and other locks until the transaction ends
+	LockForUpdate LockMode = "FOR UPDATE"
+	// Like FOR UPDATE but doesn't block FOR KEY SHARE, enough when the key columns stay untouched
+	LockForNoKeyUpdate LockMode = "FOR NO KEY UPDATE"
+	// Blocks concurrent writers but not other FOR SHARE readers
+	LockForShare LockMode = "FOR SHARE"
+	// Only blocks DELETE and updates of key columns
+	LockForKeyShare LockMode = "FOR KEY SHARE"
+)
+
+// LockOption refines a LockMode, see LockOf, LockNoWait and LockSkipLocked.
+type LockOption string
+
+const (
+	// Fail with an error instead of waiting for locked rows
+	LockNoWait LockOption = "NOWAIT"
+	// Leave locked rows out of the result, for queue-like polling
+	LockSkipLocked LockOption = "SKIP LOCKED"
+)
+
+// LockOf limits the lock to the rows of the given tables (or aliases) of a joined query.
+func LockOf(tables ...string) LockOption {
+	return LockOption("OF " + strings.Join(tables, ", "))
+}
+
+var ErrLockWithPagination = errors.New("Lock can't be combined with Paginate, use SetLimit instead")
+
+func (s *SelectBuilder) Lock(mode LockMode, options ...LockOption) SQLSelectChainBuilder {
+	switch mode {
+	case LockForUpdate, LockForNoKeyUpdate, LockForShare, LockForKeyShare:
+	default:
+		s.LastError = fmt.Errorf("invalid lock mode %q", mode)
+		return s
+	}
+
+	var sb strings.Builder
+	sb.WriteString(string(mode))
+
+	var of, wait LockOption
+	for _, option := range options {
+		switch {
+		case option == LockNoWait || option == LockSkipLocked:
+			if wait != "" && wait != option {
+				s.LastError = errors.New("NOWAIT and SKIP LOCKED can't be combined")
+				return s
+			}
+			wait = option
+		case strings.HasPrefix(string(option), "OF "):
+			if strings.TrimSpace(string(option)) == "OF" {
+				s.LastError = errors.New("LockOf needs at least one table")
+				return s
+			}
+			of = option
+		default:
+			s.LastError = fmt.Errorf("invalid lock option %q", option)
+			return s
+		}
+	}
+
+	// OF must come before the wait policy
+	for _, option := range []LockOption{of, wait} {
+		if option != "" {
+			sb.WriteByte(' ')
+			sb.WriteString(string(option))
+		}
+	}
+
+	s.lockClause = sb.String()
+
+	return s
+}
@@ -0,0 +1,63 @@
+package sql_query
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strconv"
+	"sync/atomic"
+)
+
+// LevelTrace is below slog.LevelDebug, builder internals are only worth seeing while debugging the builder itself.
+const LevelTrace = slog.LevelDebug - 4
+
+// QueryLogger receives the builders' trace output.
+// The default logger is silent unless SQL_QUERY_TRACE is true, so production builds print nothing.
+//
+// Example:
+//
+//	sql_query.SetQueryLogger(sql_query.NewSlogQueryLogger(slog.Default()))
+type QueryLogger interface {
+	Trace(msg string, attrs ...any)
+}
+
+type nopQueryLogger struct{}
+
+func (nopQueryLogger) Trace(string, ...any) {}
+
+type slogQueryLogger struct {
+	logger *slog.Logger
+}
+
+func (l slogQueryLogger) Trace(msg string, attrs ...any) {
+	l.logger.Log(context.Background(), LevelTrace, msg, attrs...)
+}
+
+// NewSlogQueryLogger logs at LevelTrace, the handler of logger must enable that level for anything to show.
+func NewSlogQueryLogger(logger *slog.Logger) QueryLogger {
+	return slogQueryLogger{logger: logger}
+}
+
+var queryLogger atomic.Pointer[QueryLogger]
+
+func init() {
+	var logger QueryLogger = nopQueryLogger{}
+	if enabled, _ := strconv.ParseBool(os.Getenv("SQL_QUERY_TRACE")); enabled {
+		logger = NewSlogQueryLogger(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: LevelTrace})))
+	}
+
+	SetQueryLogger(logger)
+}
+
+// SetQueryLogger replaces the logger used by all builders, nil silences them.
+func SetQueryLogger(logger QueryLogger) {
+	if logger == nil {
+		logger = nopQueryLogger{}
+	}
+
+	queryLogger.Store(&logger)
+}
+
+func traceQuery(msg string, attrs ...any) {
+	(*queryLogger.Load()).Trace(msg, attrs...)
+}
@@ -0,0 +1,116 @@
+package sql_query
+
+import (
+	"fmt"
+	"strings"
+)
+
+type SortDirection string
+
+const (
+	SortAsc  SortDirection = "ASC"
+	SortDesc SortDirection = "DESC"
+)
+
+// NullsPlacement of an OrderRule, NullsDefault leaves it to Postgres.
+type NullsPlacement string
+
+const (
+	NullsDefault NullsPlacement = ""
+	NullsFirst   NullsPlacement = "FIRST"
+	NullsLast    NullsPlacement = "LAST"
+)
+
+// OrderRule is one column of OrderByAdvanced, Direction defaults to ASC.
+type OrderRule struct {
+	Column    string
+	Direction SortDirection
+	Nulls     NullsPlacement
+}
+
+func (s *SelectBuilder) OrderByAdvanced(rules []OrderRule) SQLSelectChainBuilder {
+	for _, rule := range rules {
+		sortingRule, err := rule.sortingRule()
+		if err != nil {
+			s.LastError = err
+			return s
+		}
+
+		s.SortBy = append(s.SortBy, sortingRule)
+	}
+
+	return s
+}
+
+func (s *SelectBuilder) OrderByCase(caseExpr string, args []any, asc bool) SQLSelectChainBuilder {
+	caseExpr = strings.TrimSpace(caseExpr)
+	upper := strings.ToUpper(caseExpr)
+	if !strings.HasPrefix(upper, "CASE ") || !strings.HasSuffix(upper, " END") {
+		s.LastError = fmt.Errorf("%w: order by expression must be CASE ... END, got %q", ErrInvalidValues, caseExpr)
+		return s
+	}
+
+	bound, err := s.bindQuestionMarks(caseExpr, args)
+	if err != nil {
+		s.LastError = err
+		return s
+	}
+
+	direction := SortAsc
+	if !asc {
+		direction = SortDesc
+	}
+
+	sortingRule := fmt.Sprintf("%s %s", bound, direction)
+	s.SortBy = append(s.SortBy, sortingRule)
+	s.sortExpressions = append(s.sortExpressions, sortingRule)
+	s.trustedExpressions = append(s.trustedExpressions, sortingRule)
+
+	return s
+}
+
+// orderBySort applies a Sort of Pagination, OrderByAdvanced only when the client picked the nulls placement.
+func (s *SelectBuilder) orderBySort(sort Sort) {
+	if sort.Nulls == "" {
+		s.OrderBy([]string{sort.SortBy}, sort.SortOrder > 0)
+		return
+	}
+
+	direction := SortDesc
+	if sort.SortOrder > 0 {
+		direction = SortAsc
+	}
+
+	s.OrderByAdvanced([]OrderRule{{
+		Column:    sort.SortBy,
+		Direction: direction,
+		Nulls:     NullsPlacement(strings.ToUpper(sort.Nulls)),
+	}})
+}
+
+// sortingRule renders the rule the way splitSortDirection reads it back, e.g. `due_date ASC NULLS LAST`.
+func (r OrderRule) sortingRule() (string, error) {
+	column := strings.TrimSpace(r.Column)
+	if column == "" {
+		return "", fmt.Errorf("%w: order rule without column", ErrInvalidValues)
+	}
+
+	direction := SortDirection(strings.ToUpper(string(r.Direction)))
+	switch direction {
+	case "":
+		direction = SortAsc
+	case SortAsc, SortDesc:
+	default:
+		return "", fmt.Errorf("%w: unsupported sort direction %q", ErrInvalidValues, r.Direction)
+	}
+
+	nulls := NullsPlacement(strings.ToUpper(string(r.Nulls)))
+	switch nulls {
+	case NullsDefault:
+		return fmt.Sprintf("%s %s", column, direction), nil
+	case NullsFirst, NullsLast:
+		return fmt.Sprintf("%s %s NULLS %s", column, direction, nulls), nil
+	}
+
+	return "", fmt.Errorf("%w: unsupported nulls placement %q", ErrInvalidValues, r.Nulls)
+}
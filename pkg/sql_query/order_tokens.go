@@ -0,0 +1,111 @@
+package sql_query
+
+import (
+	"strings"
+)
+
+// Small tokenizer helpers for ORDER BY alias resolution, replacing the per-build regexes.
+
+// cleanIdentifier trims space, trailing comma, and surrounding double quotes.
+func cleanIdentifier(s string) string {
+	s = strings.TrimSpace(s)
+	s = strings.TrimSuffix(s, ",")
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		s = s[1 : len(s)-1]
+	}
+	return s
+}
+
+// splitColumnAlias splits a select column into its expression and cleaned alias.
+// Only the first AS outside of parentheses and quotes counts, so `CAST(x AS text) AS "y"` resolves to y.
+// Columns without alias return the cleaned column as both expression and alias.
+//
+//	splitColumnAlias(`cf.data_type as "dataType"`) // "cf.data_type", "dataType"
+//	splitColumnAlias(`"name"`)                     // "name", "name"
+func splitColumnAlias(column string) (expr string, alias string) {
+	column = strings.TrimSpace(column)
+
+	depth := 0
+	inSingle, inDouble := false, false
+	for i := 0; i < len(column); i++ {
+		switch c := column[i]; {
+		case c == '\'' && !inDouble:
+			inSingle = !inSingle
+		case c == '"' && !inSingle:
+			inDouble = !inDouble
+		case inSingle || inDouble:
+			continue
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case depth == 0 && isSQLSpace(c):
+			asStart := i
+			for asStart < len(column) && isSQLSpace(column[asStart]) {
+				asStart++
+			}
+			asEnd := asStart + 2
+			if asEnd < len(column) &&
+				strings.EqualFold(column[asStart:asEnd], "as") &&
+				isSQLSpace(column[asEnd]) {
+				return strings.TrimSpace(column[:i]), cleanIdentifier(column[asEnd:])
+			}
+		}
+	}
+
+	ident := cleanIdentifier(column)
+	return ident, ident
+}
+
+// splitSortDirection separates a trailing `ASC|DESC [NULLS FIRST|LAST]` (case-insensitive) from a sort entry,
+// as produced by OrderBy. dir keeps its original casing and includes the leading space, or is empty.
+//
+//	splitSortDirection(`"createdAt" desc`)          // `"createdAt"`, " desc"
+//	splitSortDirection(`fullName DESC NULLS LAST`) // "fullName", " DESC NULLS LAST"
+func splitSortDirection(sort string) (key string, dir string) {
+	key = strings.TrimSpace(sort)
+
+	if rest, word := cutLastWord(key); strings.EqualFold(word, "first") || strings.EqualFold(word, "last") {
+		if rest, nulls := cutLastWord(rest); strings.EqualFold(nulls, "nulls") {
+			dir = " " + nulls + " " + word
+			key = rest
+		}
+	}
+
+	if rest, word := cutLastWord(key); rest != "" && (strings.EqualFold(word, "asc") || strings.EqualFold(word, "desc")) {
+		dir = " " + word + dir
+		key = rest
+	}
+
+	return key, dir
+}
+
+// cutLastWord splits s at its last whitespace, rest is empty when s is a single word.
+func cutLastWord(s string) (rest string, word string) {
+	lastSpace := strings.LastIndexFunc(s, func(r rune) bool {
+		return r < 128 && isSQLSpace(byte(r))
+	})
+	if lastSpace < 0 {
+		return "", s
+	}
+
+	return strings.TrimSpace(s[:lastSpace]), s[lastSpace+1:]
+}
+
+func isSQLSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// isPlainIdentifier reports whether s is a bare name (letters, digits, underscore) that may need quoting.
+func isPlainIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !(c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
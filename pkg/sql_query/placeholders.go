@@ -0,0 +1,137 @@
+package sql_query
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrPlaceholderMismatch is returned by Build when the numbered placeholders of the query don't match its args,
+// which Postgres would only report when running it ("expected N arguments, got M").
+var ErrPlaceholderMismatch = errors.New("placeholders don't match the arguments")
+
+// auditPlaceholders checks that the distinct $n of query are exactly $1..$len(args).
+func auditPlaceholders(query string, args []interface{}) error {
+	used := make([]bool, len(args)+1)
+
+	var err error
+	replacePlaceholders(query, func(num, start, end int) string {
+		if err == nil && (num < 1 || num > len(args)) {
+			err = fmt.Errorf("%w: %s has no argument, there are %d, near %q",
+				ErrPlaceholderMismatch, query[start:end], len(args), fragmentAround(query, start, end))
+		}
+		if num >= 1 && num <= len(args) {
+			used[num] = true
+		}
+		return query[start:end]
+	})
+	if err != nil {
+		return err
+	}
+
+	for num := 1; num <= len(args); num++ {
+		if !used[num] {
+			return fmt.Errorf("%w: argument %d (%v) has no $%d placeholder, there are %d arguments",
+				ErrPlaceholderMismatch, num, args[num-1], num, len(args))
+		}
+	}
+
+	return nil
+}
+
+// highestPlaceholder returns the highest $n of expr, 0 without placeholder.
+func highestPlaceholder(expr string) int {
+	highest := 0
+	replacePlaceholders(expr, func(num, start, end int) string {
+		highest = max(highest, num)
+		return expr[start:end]
+	})
+
+	return highest
+}
+
+// replacePlaceholders returns query with each $n, query[start:end], replaced by the result of replace.
+// Quoted literals and identifiers are skipped, e.g. '$1' or "col$1", so are names containing $ like a$1.
+// Every rewrite of placeholders goes through it, so shifting, rebinding and auditing agree on what a placeholder is.
+func replacePlaceholders(query string, replace func(num, start, end int) string) string {
+	var sb strings.Builder
+	written := 0
+
+	for i := 0; i < len(query); i++ {
+		switch c := query[i]; {
+		case c == '\'' || c == '"':
+			// Doubled quotes escape themselves, scanning them as two literals gives the same result
+			for i++; i < len(query) && query[i] != c; i++ {
+			}
+		case c == '$' && i+1 < len(query) && isDigit(query[i+1]) && (i == 0 || !isIdentifierChar(query[i-1])):
+			end := i + 1
+			for end < len(query) && isDigit(query[end]) {
+				end++
+			}
+
+			num, err := strconv.Atoi(query[i+1 : end])
+			if err != nil {
+				num = -1
+			}
+			sb.WriteString(query[written:i])
+			sb.WriteString(replace(num, i, end))
+			written = end
+			i = end - 1
+		}
+	}
+	if written == 0 {
+		return query
+	}
+
+	sb.WriteString(query[written:])
+	return sb.String()
+}
+
+// bindQuestionMarks replaces the first len(args) ? of expr by the placeholders of args appended to the builder's,
+// for raw expressions (UpdateRawSQL, SQLOperatorRaw, SelectRaw, WhereRaw). Quoted ? are left as is.
+// The jsonb ? operators can't be told apart from placeholders, use jsonb_exists() in expressions with args.
+func (s *SQLEloquentQuery) bindQuestionMarks(expr string, args []interface{}) (string, error) {
+	if len(args) == 0 {
+		return expr, nil
+	}
+
+	var sb strings.Builder
+	bound := 0
+	written := 0
+	for i := 0; i < len(expr) && bound < len(args); i++ {
+		switch c := expr[i]; c {
+		case '\'', '"':
+			for i++; i < len(expr) && expr[i] != c; i++ {
+			}
+		case '?':
+			sb.WriteString(expr[written:i])
+			sb.WriteString("$" + strconv.Itoa(len(s.Args)+bound+1))
+			written = i + 1
+			bound++
+		}
+	}
+	if bound < len(args) {
+		return "", fmt.Errorf("%w: %q has %d ? for %d args", ErrInvalidValues, expr, bound, len(args))
+	}
+
+	sb.WriteString(expr[written:])
+	s.Args = appendArgs(s.Args, args)
+	return sb.String(), nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentifierChar(c byte) bool {
+	return c == '_' || c == '$' || isDigit(c) || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// fragmentAround returns about 30 bytes of query on each side of query[start:end].
+func fragmentAround(query string, start, end int) string {
+	from := max(start-30, 0)
+	to := min(end+30, len(query))
+
+	return query[from:to]
+}
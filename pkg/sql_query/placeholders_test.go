@@ -0,0 +1,191 @@
+package sql_query
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestBindQuestionMarks(t *testing.T) {
+	tests := []struct {
+		name    string
+		prior   []interface{}
+		expr    string
+		args    []interface{}
+		want    string
+		wantErr bool
+	}{
+		{name: "no args", expr: `"a" = ?`, want: `"a" = ?`},
+		{name: "numbers after the builder's args", prior: []interface{}{1}, expr: `"a" = ? AND "b" = ?`, args: []interface{}{2, 3}, want: `"a" = $2 AND "b" = $3`},
+		{name: "skips quoted question marks", expr: `"a?" = '?' AND "b" = ?`, args: []interface{}{1}, want: `"a?" = '?' AND "b" = $1`},
+		{name: "leaves extra question marks", expr: `"a" = ? AND "b" ? 'key'`, args: []interface{}{1}, want: `"a" = $1 AND "b" ? 'key'`},
+		{name: "fewer question marks than args", expr: `"a" = ?`, args: []interface{}{1, 2}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &SQLEloquentQuery{Args: tt.prior}
+			got, err := s.bindQuestionMarks(tt.expr, tt.args)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidValues) {
+					t.Fatalf("bindQuestionMarks() error = %v, want ErrInvalidValues", err)
+				}
+				if len(s.Args) != len(tt.prior) {
+					t.Errorf("bindQuestionMarks() appended args on error: %v", s.Args)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("bindQuestionMarks() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("bindQuestionMarks() = %s, want %s", got, tt.want)
+			}
+			if len(s.Args) != len(tt.prior)+len(tt.args) {
+				t.Errorf("bindQuestionMarks() args = %v, want %d", s.Args, len(tt.prior)+len(tt.args))
+			}
+		})
+	}
+}
+
+func TestRawConditionMismatchFailsInsideWhereOr(t *testing.T) {
+	_, _, err := NewSQLSelectBuilder[any]("wallets").
+		WhereOr(
+			map[string]SQLCondition{
+				"type": {Operator: SQLOperatorEqual, Value: "cash"},
+				"":     {Operator: SQLOperatorRaw, Value: `"balance" > ?`, ExtraArgs: []any{10, 20}},
+			},
+			map[string]SQLCondition{
+				"type": {Operator: SQLOperatorEqual, Value: "bank"},
+			},
+		).
+		Build()
+	if !errors.Is(err, ErrInvalidValues) {
+		t.Fatalf("Build() error = %v, want ErrInvalidValues", err)
+	}
+}
+
+// placeholderNumbers returns the numbers of the $n of query, in order of appearance.
+func placeholderNumbers(query string) []int {
+	var numbers []int
+	replacePlaceholders(query, func(num, start, end int) string {
+		numbers = append(numbers, num)
+		return query[start:end]
+	})
+
+	return numbers
+}
+
+// assertPlaceholdersMatchArgs fails t unless the distinct $n of query are exactly $1..$argCount.
+func assertPlaceholdersMatchArgs(t *testing.T, query string, argCount int) {
+	t.Helper()
+
+	distinct := map[int]bool{}
+	for _, num := range placeholderNumbers(query) {
+		if num < 1 || num > argCount {
+			t.Fatalf("$%d out of range for %d args in %s", num, argCount, query)
+		}
+		distinct[num] = true
+	}
+	if len(distinct) != argCount {
+		t.Fatalf("%d distinct placeholders for %d args in %s", len(distinct), argCount, query)
+	}
+}
+
+func FuzzShiftSQLPlaceholders(f *testing.F) {
+	f.Add(`SELECT * FROM t WHERE a = $1 AND b = $2`, uint8(3))
+	f.Add(`SELECT '$1', "$2", a$1 FROM t WHERE a = $1`, uint8(1))
+	f.Add(`SELECT 'it''s $1' WHERE a = $10`, uint8(0))
+	f.Add(`$1$2`, uint8(255))
+
+	f.Fuzz(func(t *testing.T, query string, offset uint8) {
+		before := placeholderNumbers(query)
+		shifted := shiftSQLPlaceholders(query, int(offset))
+		after := placeholderNumbers(shifted)
+
+		if len(after) != len(before) {
+			t.Fatalf("shift by %d changed the placeholder count from %d to %d: %q -> %q", offset, len(before), len(after), query, shifted)
+		}
+		for i := range before {
+			if before[i] >= 0 && after[i] != before[i]+int(offset) {
+				t.Fatalf("shift by %d turned $%d into $%d: %q -> %q", offset, before[i], after[i], query, shifted)
+			}
+		}
+
+		// Appended to a parent using $1..$offset, a query using exactly $1..$n must use the args that follow
+		// Builders don't get anywhere near 1000 args, larger numbers would only allocate
+		argCount := maxNumber(before)
+		if argCount > 1000 {
+			return
+		}
+		if err := auditPlaceholders(query, make([]interface{}, argCount)); err == nil {
+			var parent strings.Builder
+			for num := 1; num <= int(offset); num++ {
+				fmt.Fprintf(&parent, "$%d, ", num)
+			}
+			assertPlaceholdersMatchArgs(t, parent.String()+"("+shifted+")", int(offset)+argCount)
+		}
+	})
+}
+
+func maxNumber(numbers []int) int {
+	highest := 0
+	for _, num := range numbers {
+		highest = max(highest, num)
+	}
+
+	return highest
+}
+
+// FuzzComposedPlaceholders composes CTEs, set operations and LATERAL joins with their own args and checks
+// the placeholders of the result still match its args one to one.
+func FuzzComposedPlaceholders(f *testing.F) {
+	f.Add("cash", int64(10), "it's $1 or ?", uint8(2), true)
+	f.Add("", int64(-1), "$2", uint8(0), false)
+
+	f.Fuzz(func(t *testing.T, text string, number int64, literal string, repeat uint8, union bool) {
+		quoted := "'" + strings.ReplaceAll(literal, "'", "''") + "'"
+
+		member := func() *SQLEloquentQuery {
+			return NewSQLSelectBuilder[any]("wallets").
+				Select(`"id"`).
+				SelectRaw(quoted+` || ? AS "label"`, text).
+				Where(map[string]SQLCondition{
+					"type":    {Operator: SQLOperatorEqual, Value: text},
+					"balance": {Operator: SQLOperatorGreaterThan, Value: number},
+				}).(*SelectBuilder).SQLEloquentQuery
+		}
+
+		cte := NewSQLSelectBuilder[any]("transactions").
+			Select(`"wallet_id"`).
+			WhereRaw(`"amount" > ? AND "note" <> `+quoted, number)
+		for range repeat % 4 {
+			cte.WhereOr(map[string]SQLCondition{"note": {Operator: SQLOperatorEqual, Value: text}})
+		}
+
+		lateral := NewSQLSelectBuilder[any]("transactions", "tx").
+			Select(`tx."amount"`).
+			WhereRaw(`tx."wallet_id" = w."id" AND tx."amount" < ?`, number).
+			SetLimit(1)
+
+		joined := NewSQLSelectBuilder[any]("wallets", "w").
+			Select(`w."id"`).
+			Where(map[string]SQLCondition{"w.name": {Operator: SQLOperatorEqual, Value: text}}).
+			LeftJoinLateralWithQuery("last_tx", lateral.(*SelectBuilder).SQLEloquentQuery, "TRUE")
+
+		main := NewSQLSelectBuilder[any]("wallets").
+			WithCTEBuilder("recent", cte.(*SelectBuilder).SQLEloquentQuery)
+		if union {
+			main.Union(joined.(*SelectBuilder).SQLEloquentQuery, member())
+		} else {
+			main.UnionAll(member(), joined.(*SelectBuilder).SQLEloquentQuery, member())
+		}
+
+		query, args, err := main.(*SelectBuilder).build()
+		if err != nil {
+			t.Fatalf("build() error = %v", err)
+		}
+		assertPlaceholdersMatchArgs(t, query, len(args))
+	})
+}
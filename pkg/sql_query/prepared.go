@@ -0,0 +1,35 @@
+package sql_query
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// PreparedStatement is a built query to run as a named prepared statement, see PostgreSqlService.ExecPrepared.
+// Name is derived from a hash of SQL, so every build of the same template shares the statement prepared
+// on a connection, and Postgres plans it once per connection instead of on every call.
+type PreparedStatement struct {
+	Name string
+	SQL  string
+	Args []interface{}
+}
+
+func (s *SQLEloquentQuery) Prepare() (PreparedStatement, error) {
+	if dialect := s.dialect(); dialect != DialectPostgres {
+		return PreparedStatement{}, fmt.Errorf("%w: Prepare needs the %s dialect, got %s", ErrInvalidValues, DialectPostgres, dialect)
+	}
+
+	query, args, err := s.Build()
+	if err != nil {
+		return PreparedStatement{}, err
+	}
+
+	return PreparedStatement{Name: PreparedStatementName(query), SQL: query, Args: args}, nil
+}
+
+// PreparedStatementName returns the statement name of query, the same for every build of one template.
+func PreparedStatementName(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return "stmt_" + hex.EncodeToString(sum[:8])
+}
@@ -25,9 +25,20 @@ func ScanRowObject(v any, row pgx.Rows) error {
 		return pgx.ErrNoRows
 	}
 
-	fieldDescs := row.FieldDescriptions()
+	return ScanCurrentRow(v, row)
+}
 
-	values, err := row.Values()
+// ScanCurrentRow scans the row rows is positioned on (after rows.Next) into v, a pointer to a struct.
+// Columns are matched by alias (json tag), like ScanRowObject.
+func ScanCurrentRow(v any, rows pgx.Rows) error {
+	// Generated scanner, no reflection needed
+	if scanner, ok := v.(RowScanner); ok {
+		return scanner.ScanRow(rows)
+	}
+
+	fieldDescs := rows.FieldDescriptions()
+
+	values, err := rows.Values()
 	if err != nil {
 		return err
 	}
@@ -103,6 +114,11 @@ func ScanRowsArray(v any, rows pgx.Rows) error {
 	sliceVal := vVal.Elem()
 	elemType := sliceVal.Type().Elem()
 
+	// Generated scanner, no reflection needed
+	if reflect.PointerTo(elemType).Implements(rowScannerType) {
+		return scanRowsWithScanner(sliceVal, rows)
+	}
+
 	fieldDescs := rows.FieldDescriptions()
 	for rows.Next() {
 		values, err := rows.Values()
@@ -177,17 +193,24 @@ func CachedScanRowsArray(v any, rows pgx.Rows) error {
 	return nil
 }
 
-func FormatPaginationResult[T any](result []dto.PaginationResult[T]) dto.PaginationResult[T] {
-	if len(result) < 1 {
-		response := dto.PaginationResult[T]{
-			Data:         []T{},
-			TotalRecords: 0,
-		}
+// FormatPaginationResult returns the row of a paginated query, an empty page when there is none.
+// With the meta of BuildPaginated, TotalPages and HasNextPage are filled from the total records.
+func FormatPaginationResult[T any](result []dto.PaginationResult[T], meta ...PaginationMeta) dto.PaginationResult[T] {
+	response := dto.PaginationResult[T]{
+		Data:         []T{},
+		TotalRecords: 0,
+	}
+	if len(result) > 0 {
+		response = result[0]
+	}
 
-		return response
+	if len(meta) > 0 {
+		completed := meta[0].WithTotal(response.TotalRecords)
+		response.TotalPages = completed.TotalPages
+		response.HasNextPage = completed.HasNext
 	}
 
-	return result[0]
+	return response
 }
 
 /*
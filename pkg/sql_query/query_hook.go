@@ -0,0 +1,54 @@
+package sql_query
+
+import "sync/atomic"
+
+// QueryShape is the structure of a successfully built query, handed to the QueryHook.
+type QueryShape struct {
+	Mode SQLMode
+	// Table is the FROM / target table as given to the builder, alias included, e.g. "transactions t"
+	Table string
+	// Filters are the AND-combined WHERE clauses, OR groups come as one parenthesized clause
+	Filters []string
+	SortBy  []string
+	GroupBy []string
+	// Paginated is true for LIMIT / OFFSET and cursor pagination
+	Paginated bool
+	Query     string
+}
+
+// QueryHook observes every query built by Build, e.g. to tag or collect query shapes in dev tools.
+// It runs synchronously on the building goroutine and must not keep the slices of shape.
+type QueryHook func(shape QueryShape)
+
+var queryHook atomic.Pointer[QueryHook]
+
+// SetQueryHook installs hook for every builder, nil removes it.
+//
+// Example:
+//
+//	sql_query.SetQueryHook(func(shape sql_query.QueryShape) { log.Println(shape.Table, shape.Filters) })
+func SetQueryHook(hook QueryHook) {
+	if hook == nil {
+		queryHook.Store(nil)
+		return
+	}
+
+	queryHook.Store(&hook)
+}
+
+func (s *SQLEloquentQuery) runQueryHook(query string) {
+	hook := queryHook.Load()
+	if hook == nil {
+		return
+	}
+
+	(*hook)(QueryShape{
+		Mode:      s.Mode,
+		Table:     s.Table,
+		Filters:   s.Filters,
+		SortBy:    s.SortBy,
+		GroupBy:   s.Grouping,
+		Paginated: s.UsePagination || s.Limit > 0 || len(s.cursorColumns) > 0,
+		Query:     query,
+	})
+}
@@ -0,0 +1,80 @@
+package sql_query
+
+import (
+	"slices"
+	"strings"
+)
+
+// QueryMeta describes a built query for the service layer (replica routing, metric tags, cache keys,
+// authorization hooks) so it doesn't have to parse the SQL.
+type QueryMeta struct {
+	// Tables read or written, sub-queries, CTEs and set operations included, without aliases and in order of appearance.
+	// CTE names and derived tables are not tables, function calls (e.g. jsonb_to_recordset) are left out.
+	Tables         []string
+	Mode           SQLMode
+	ArgCount       int
+	UsesPagination bool
+}
+
+func (s *SQLEloquentQuery) BuildWithMeta() (string, []interface{}, QueryMeta, error) {
+	query, args, err := s.Build()
+	if err != nil {
+		return query, args, QueryMeta{}, err
+	}
+
+	return query, args, QueryMeta{
+		Tables:         s.touchedTables(),
+		Mode:           s.Mode,
+		ArgCount:       len(args),
+		UsesPagination: s.UsePagination || s.Limit > 0 || len(s.cursorColumns) > 0,
+	}, nil
+}
+
+// touchTable records the table of a FROM, JOIN, USING or UPDATE ... FROM expression such as `"users" u`.
+func (s *SQLEloquentQuery) touchTable(expr string) {
+	if table := tableOf(expr); table != "" {
+		s.tables = append(s.tables, table)
+	}
+}
+
+// touchTablesOf records the tables of sub, a builder composed into this one.
+func (s *SQLEloquentQuery) touchTablesOf(sub *SQLEloquentQuery) {
+	s.tables = append(s.tables, sub.touchedTables()...)
+}
+
+// touchedTables returns the main table followed by the recorded ones, deduplicated, CTE names removed.
+func (s *SQLEloquentQuery) touchedTables() []string {
+	candidates := s.tables
+	// FromSubquery replaced Table with the derived table, its tables were recorded from the sub-builder
+	if s.fromAlias == "" {
+		candidates = append([]string{tableOf(s.Table)}, candidates...)
+	}
+
+	tables := []string{}
+	for _, table := range candidates {
+		if table == "" || slices.Contains(tables, table) {
+			continue
+		}
+		if _, isCTE := s.ctes[strings.ToLower(strings.Trim(table, `"`))]; isCTE {
+			continue
+		}
+		tables = append(tables, table)
+	}
+
+	return tables
+}
+
+// tableOf returns the table of expr with its alias dropped, "" for derived tables, LATERAL and function calls.
+func tableOf(expr string) string {
+	fields := strings.Fields(expr)
+	if len(fields) == 0 {
+		return ""
+	}
+
+	table := fields[0]
+	if strings.ContainsAny(table, "()") || strings.EqualFold(table, "LATERAL") {
+		return ""
+	}
+
+	return table
+}
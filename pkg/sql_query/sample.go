@@ -0,0 +1,39 @@
+package sql_query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Sampling methods of TABLESAMPLE
+const (
+	// Picks whole pages, fast but clustered rows are sampled together
+	SampleSystem = "SYSTEM"
+	// Picks each row, scans the whole table but gives an even sample
+	SampleBernoulli = "BERNOULLI"
+)
+
+func (s *SelectBuilder) Sample(method string, percentage float64, seed ...int64) SQLSelectChainBuilder {
+	method = strings.ToUpper(strings.TrimSpace(method))
+	if method != SampleSystem && method != SampleBernoulli {
+		s.LastError = fmt.Errorf("%w: sample method must be %s or %s, got %q", ErrInvalidValues, SampleSystem, SampleBernoulli, method)
+		return s
+	}
+	if percentage <= 0 || percentage > 100 {
+		s.LastError = fmt.Errorf("%w: sample percentage must be in (0, 100], got %v", ErrInvalidValues, percentage)
+		return s
+	}
+	if s.fromAlias != "" {
+		s.LastError = fmt.Errorf("%w: a derived table of FromSubquery can't be sampled", ErrInvalidValues)
+		return s
+	}
+
+	// Numbers only, written as literals so the clause doesn't depend on the placeholders bound so far
+	s.tableSample = fmt.Sprintf(" TABLESAMPLE %s (%s)", method, strconv.FormatFloat(percentage, 'f', -1, 64))
+	if len(seed) > 0 {
+		s.tableSample += fmt.Sprintf(" REPEATABLE (%d)", seed[0])
+	}
+
+	return s
+}
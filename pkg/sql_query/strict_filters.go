@@ -0,0 +1,107 @@
+package sql_query
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrUnknownFilterKey is returned by Build in StrictFilterKeys mode, it is wrapped in ErrInvalidValues
+// and lists every unknown key of the query.
+var ErrUnknownFilterKey = errors.New("unknown filter key")
+
+func (s *SelectBuilder) StrictFilterKeys(known ...string) SQLSelectChainBuilder {
+	s.strictFilterKeys = true
+	s.knownFilterKeys = append(s.knownFilterKeys, known...)
+	return s
+}
+
+// recordFilterKeys keeps the keys of Where, WhereOr and WhereGroup for checkFilterKeys.
+// RAW conditions and expressions (functions, JSON paths, row values) have no column to check.
+func (s *SQLEloquentQuery) recordFilterKeys(filters ...map[string]SQLCondition) {
+	for _, filter := range filters {
+		for key, condition := range filter {
+			if condition.Operator == SQLOperatorRaw || !strictIdentifierRegexp.MatchString(strings.TrimSpace(key)) {
+				continue
+			}
+			s.filterKeys = append(s.filterKeys, key)
+		}
+	}
+}
+
+func (s *SQLEloquentQuery) recordGroupFilterKeys(groups ...ConditionGroup) {
+	for _, group := range groups {
+		s.recordFilterKeys(group.Conditions)
+		s.recordGroupFilterKeys(group.Groups...)
+	}
+}
+
+// checkFilterKeys validates the recorded filter keys against the columns of the builder's DTO and the known keys.
+// A key qualified by the main table's name or alias matches an unqualified column, "alias.*" allows any column of alias.
+func (s *SQLEloquentQuery) checkFilterKeys() error {
+	if !s.strictFilterKeys {
+		return nil
+	}
+
+	known := map[string]bool{}
+	for _, column := range append(s.dtoColumnNames(), s.knownFilterKeys...) {
+		known[normalizeFilterKey(column)] = true
+	}
+
+	tables := strings.Fields(strings.ToLower(s.Table))
+	if s.fromAlias != "" {
+		tables = []string{strings.ToLower(s.fromAlias)}
+	}
+
+	var unknown []string
+	for _, key := range s.filterKeys {
+		normalized := normalizeFilterKey(key)
+		if known[normalized] {
+			continue
+		}
+
+		qualifier, column, qualified := strings.Cut(normalized, ".")
+		if qualified && (known[qualifier+".*"] || slices.Contains(tables, qualifier) && known[column]) {
+			continue
+		}
+		if !qualified && slices.ContainsFunc(tables, func(table string) bool { return known[table+"."+normalized] }) {
+			continue
+		}
+
+		if !slices.Contains(unknown, key) {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	slices.Sort(unknown)
+	return fmt.Errorf("%w: %w: %s", ErrInvalidValues, ErrUnknownFilterKey, strings.Join(unknown, ", "))
+}
+
+// dtoColumnNames returns the plain columns of the select DTO, e.g. `column:"t.amount"` or `json:"walletId"`.
+// Computed columns (functions, subqueries) are left out, casts are stripped.
+func (s *SQLEloquentQuery) dtoColumnNames() []string {
+	if s.dtoType == nil {
+		return nil
+	}
+
+	var columns []string
+	for _, meta := range ExtractFromType(s.dtoType) {
+		column := CamelToSnake(meta.JSONTag)
+		if meta.ColumnTag != "" {
+			column, _, _ = strings.Cut(meta.ColumnTag, "::")
+		}
+		if column != "" && column != "-" && strictIdentifierRegexp.MatchString(column) {
+			columns = append(columns, column)
+		}
+	}
+
+	return columns
+}
+
+func normalizeFilterKey(key string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(key), `"`, ""))
+}
@@ -0,0 +1,95 @@
+package sql_query
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// ErrUnsafeIdentifier is returned by Build in StrictIdentifiers mode, it is wrapped in ErrInvalidValues
+// since the identifier usually comes from the request.
+var ErrUnsafeIdentifier = errors.New("unsafe identifier")
+
+// column, table.column or schema.table.column, each part optionally double quoted
+var strictIdentifierRegexp = regexp.MustCompile(`^("[A-Za-z_][A-Za-z0-9_]*"|[A-Za-z_][A-Za-z0-9_]*)(\.("[A-Za-z_][A-Za-z0-9_]*"|[A-Za-z_][A-Za-z0-9_]*)){0,2}$`)
+
+func (s *SelectBuilder) StrictIdentifiers(allowed ...string) SQLSelectChainBuilder {
+	s.strictIdentifiers = true
+	s.allowedIdentifiers = allowed
+	return s
+}
+
+// checkIdentifiers validates the caller given identifiers of Select, OrderBy, GroupBy and the date_trunc helpers.
+func (s *SQLEloquentQuery) checkIdentifiers() error {
+	if !s.strictIdentifiers {
+		return nil
+	}
+
+	for _, column := range s.selectedColumns {
+		expr, alias := splitColumnAlias(column)
+		if err := s.checkIdentifier(expr); err != nil {
+			return err
+		}
+		if alias != cleanIdentifier(expr) {
+			if err := s.checkIdentifier(alias); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, column := range s.dateTruncColumns {
+		if err := s.checkIdentifier(column); err != nil {
+			return err
+		}
+	}
+
+	// OrderBy joins its keys with comma before the direction
+	for _, sort := range s.SortBy {
+		if slices.Contains(s.trustedExpressions, sort) {
+			continue
+		}
+
+		key, _ := splitSortDirection(sort)
+		for _, part := range strings.Split(key, ",") {
+			if err := s.checkIdentifier(part); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, group := range s.Grouping {
+		if slices.Contains(s.trustedExpressions, group) {
+			continue
+		}
+
+		for _, part := range strings.Split(group, ",") {
+			if err := s.checkIdentifier(part); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *SQLEloquentQuery) checkIdentifier(identifier string) error {
+	identifier = strings.TrimSpace(identifier)
+	if !strictIdentifierRegexp.MatchString(identifier) {
+		return fmt.Errorf("%w: %w: %q", ErrInvalidValues, ErrUnsafeIdentifier, identifier)
+	}
+
+	if len(s.allowedIdentifiers) == 0 {
+		return nil
+	}
+
+	cleaned := strings.ReplaceAll(identifier, `"`, "")
+	for _, allowed := range s.allowedIdentifiers {
+		if strings.EqualFold(cleaned, allowed) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %w: %q is not allowed", ErrInvalidValues, ErrUnsafeIdentifier, identifier)
+}
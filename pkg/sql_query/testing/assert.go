@@ -0,0 +1,166 @@
+// Package testing compares built queries to golden SQL in the tests of use cases. Whitespace and the numbering
+// of placeholders are normalized, so a test fails on what the query does, not on how the builder laid it out.
+//
+// Example:
+//
+//	import sqltesting "github.com/mystaline/clefinport-be/pkg/sql_query/testing"
+//
+//	sqltesting.AssertSQL(t, builder, `
+//	    SELECT "id" AS "id" FROM wallets
+//	    WHERE "user_id" = $1 AND "deleted_at" IS NULL`,
+//	    []any{"42"},
+//	)
+package testing
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	gotesting "testing"
+)
+
+// Builder is any chain builder of sql_query.
+type Builder interface {
+	Build() (string, []interface{}, error)
+}
+
+// AssertSQL fails t when builder doesn't build expectedSQL with expectedArgs, once both are normalized.
+func AssertSQL(t gotesting.TB, builder Builder, expectedSQL string, expectedArgs []any) {
+	t.Helper()
+
+	query, args, err := builder.Build()
+	if err != nil {
+		t.Errorf("Build() failed: %v", err)
+		return
+	}
+
+	gotSQL, gotArgs := Normalize(query, args)
+	wantSQL, wantArgs := Normalize(expectedSQL, expectedArgs)
+	if gotSQL != wantSQL {
+		t.Errorf("SQL mismatch\n got: %s\nwant: %s", gotSQL, wantSQL)
+	}
+	if !reflect.DeepEqual(normalizeArgs(gotArgs), normalizeArgs(wantArgs)) {
+		t.Errorf("args mismatch\n got: %#v\nwant: %#v", gotArgs, wantArgs)
+	}
+}
+
+// Normalize collapses the whitespace of query outside of quotes, drops it inside parentheses and around commas,
+// and renumbers the placeholders in order of first appearance, reordering args to match.
+// Arguments without placeholder are kept at the end, in their order.
+//
+//	Normalize("SELECT *\n  FROM t WHERE b = $2 AND a = $1", []any{"a", "b"})
+//	// "SELECT * FROM t WHERE b = $1 AND a = $2", []any{"b", "a"}
+func Normalize(query string, args []any) (string, []any) {
+	var sb strings.Builder
+	renumbered := map[int]int{}
+	var order []int
+
+	pendingSpace := false
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+
+		switch {
+		case c == '\'' || c == '"':
+			end := closingQuote(query, i)
+			writeSpace(&sb, &pendingSpace, c)
+			sb.WriteString(query[i:end])
+			i = end - 1
+			continue
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			pendingSpace = sb.Len() > 0
+			continue
+		case c == '$' && i+1 < len(query) && isDigit(query[i+1]) && (i == 0 || !isIdentifierChar(query[i-1])):
+			end := i + 1
+			for end < len(query) && isDigit(query[end]) {
+				end++
+			}
+			number, _ := strconv.Atoi(query[i+1 : end])
+			if _, ok := renumbered[number]; !ok {
+				renumbered[number] = len(order) + 1
+				order = append(order, number)
+			}
+
+			writeSpace(&sb, &pendingSpace, c)
+			fmt.Fprintf(&sb, "$%d", renumbered[number])
+			i = end - 1
+			continue
+		}
+
+		writeSpace(&sb, &pendingSpace, c)
+		sb.WriteByte(c)
+	}
+
+	normalizedArgs := make([]any, 0, len(args))
+	used := map[int]bool{}
+	for _, number := range order {
+		if number >= 1 && number <= len(args) {
+			normalizedArgs = append(normalizedArgs, args[number-1])
+			used[number] = true
+		}
+	}
+	for i, arg := range args {
+		if !used[i+1] {
+			normalizedArgs = append(normalizedArgs, arg)
+		}
+	}
+
+	return sb.String(), normalizedArgs
+}
+
+// writeSpace writes a pending space before c, unless it would be inside parentheses or around a comma.
+func writeSpace(sb *strings.Builder, pending *bool, c byte) {
+	if !*pending {
+		return
+	}
+	*pending = false
+
+	s := sb.String()
+	if c == ')' || c == ',' || strings.HasSuffix(s, "(") || strings.HasSuffix(s, ",") {
+		return
+	}
+	sb.WriteByte(' ')
+}
+
+// closingQuote returns the index after the quote closing the one at start, doubled quotes being escaped.
+func closingQuote(query string, start int) int {
+	quote := query[start]
+	for i := start + 1; i < len(query); i++ {
+		if query[i] != quote {
+			continue
+		}
+		if i+1 < len(query) && query[i+1] == quote {
+			i++
+			continue
+		}
+		return i + 1
+	}
+
+	return len(query)
+}
+
+// normalizeArgs makes the args comparable whatever their integer width, e.g. an int literal against an int64 id.
+func normalizeArgs(args []any) []any {
+	normalized := make([]any, len(args))
+	for i, arg := range args {
+		v := reflect.ValueOf(arg)
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			normalized[i] = v.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			normalized[i] = int64(v.Uint())
+		default:
+			normalized[i] = arg
+		}
+	}
+
+	return normalized
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentifierChar(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || isDigit(c)
+}
@@ -0,0 +1,150 @@
+package testing
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	gotesting "testing"
+)
+
+func TestNormalize(t *gotesting.T) {
+	tests := []struct {
+		name     string
+		query    string
+		args     []any
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:     "collapses whitespace",
+			query:    "SELECT *\n\t  FROM   t\r\nWHERE a = $1",
+			args:     []any{1},
+			wantSQL:  "SELECT * FROM t WHERE a = $1",
+			wantArgs: []any{1},
+		},
+		{
+			name:     "drops whitespace inside parentheses and around commas",
+			query:    "SELECT COALESCE( a , b ) FROM t WHERE id IN ( $1 ,\n $2 )",
+			args:     []any{1, 2},
+			wantSQL:  "SELECT COALESCE(a,b) FROM t WHERE id IN ($1,$2)",
+			wantArgs: []any{1, 2},
+		},
+		{
+			name:     "keeps quoted whitespace and placeholders",
+			query:    `SELECT 'a  $1', "b  c" FROM t WHERE x = 'it''s  $2' AND y = $1`,
+			args:     []any{"y"},
+			wantSQL:  `SELECT 'a  $1',"b  c" FROM t WHERE x = 'it''s  $2' AND y = $1`,
+			wantArgs: []any{"y"},
+		},
+		{
+			name:     "renumbers placeholders in order of appearance",
+			query:    "SELECT * FROM t WHERE b = $2 AND a = $1 OR b = $2",
+			args:     []any{"a", "b"},
+			wantSQL:  "SELECT * FROM t WHERE b = $1 AND a = $2 OR b = $1",
+			wantArgs: []any{"b", "a"},
+		},
+		{
+			name:     "keeps args without placeholder at the end",
+			query:    "SELECT * FROM t WHERE a = $3",
+			args:     []any{"x", "y", "a"},
+			wantSQL:  "SELECT * FROM t WHERE a = $1",
+			wantArgs: []any{"a", "x", "y"},
+		},
+		{
+			name:     "leaves names containing $ alone",
+			query:    "SELECT a$1 FROM t WHERE b = $1",
+			args:     []any{1},
+			wantSQL:  "SELECT a$1 FROM t WHERE b = $1",
+			wantArgs: []any{1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *gotesting.T) {
+			gotSQL, gotArgs := Normalize(tt.query, tt.args)
+			if gotSQL != tt.wantSQL {
+				t.Errorf("Normalize() SQL\n got: %s\nwant: %s", gotSQL, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+				t.Errorf("Normalize() args = %#v, want %#v", gotArgs, tt.wantArgs)
+			}
+		})
+	}
+}
+
+type builtQuery struct {
+	query string
+	args  []interface{}
+	err   error
+}
+
+func (b builtQuery) Build() (string, []interface{}, error) {
+	return b.query, b.args, b.err
+}
+
+// recorder is a TB recording failures instead of failing the test running it.
+type recorder struct {
+	gotesting.TB
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestAssertSQL(t *gotesting.T) {
+	tests := []struct {
+		name         string
+		builder      builtQuery
+		expectedSQL  string
+		expectedArgs []any
+		wantFailures int
+	}{
+		{
+			name: "matches regardless of layout and numbering",
+			builder: builtQuery{
+				query: `SELECT "id" AS "id" FROM wallets WHERE "deleted_at" IS NULL AND "user_id" = $2 AND "type" = $1`,
+				args:  []interface{}{"cash", int64(42)},
+			},
+			expectedSQL: `
+				SELECT "id" AS "id"
+				FROM wallets
+				WHERE "deleted_at" IS NULL
+				AND "user_id" = $1
+				AND "type" = $2`,
+			expectedArgs: []any{42, "cash"},
+		},
+		{
+			name:         "fails on different SQL",
+			builder:      builtQuery{query: "SELECT * FROM wallets WHERE a = $1", args: []interface{}{1}},
+			expectedSQL:  "SELECT * FROM wallets WHERE b = $1",
+			expectedArgs: []any{1},
+			wantFailures: 1,
+		},
+		{
+			name:         "fails on different args",
+			builder:      builtQuery{query: "SELECT * FROM wallets WHERE a = $1", args: []interface{}{1}},
+			expectedSQL:  "SELECT * FROM wallets WHERE a = $1",
+			expectedArgs: []any{2},
+			wantFailures: 1,
+		},
+		{
+			name:         "fails when the builder fails",
+			builder:      builtQuery{err: errors.New("invalid values")},
+			expectedSQL:  "SELECT * FROM wallets",
+			wantFailures: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *gotesting.T) {
+			r := &recorder{TB: t}
+			AssertSQL(r, tt.builder, tt.expectedSQL, tt.expectedArgs)
+			if len(r.failures) != tt.wantFailures {
+				t.Errorf("AssertSQL() reported %d failures, want %d: %q", len(r.failures), tt.wantFailures, r.failures)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package sql_query
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// PreserveTimestamps: the insert templates (cached or generated) always write NOW() for created_at and updated_at,
+// the provided values are bound afterwards, replacing the NOW() of their row and column.
+
+var timestampColumns = []string{"created_at", "updated_at"}
+
+func (s *InsertBuilder) PreserveTimestamps() SQLInsertInitBuilder {
+	s.preserveTimestamps = true
+	return s
+}
+
+// rowTimestamps returns the non zero created_at/updated_at values of each row of v, a struct or a slice of structs.
+func rowTimestamps(v reflect.Value) []map[string]any {
+	if v.Kind() == reflect.Struct {
+		return []map[string]any{structTimestamps(v)}
+	}
+
+	rows := make([]map[string]any, v.Len())
+	for i := range rows {
+		rows[i] = structTimestamps(v.Index(i))
+	}
+
+	return rows
+}
+
+func structTimestamps(v reflect.Value) map[string]any {
+	values := map[string]any{}
+	if v.Kind() != reflect.Struct {
+		return values
+	}
+
+	for _, meta := range ExtractFromType(v.Type()) {
+		column := CamelToSnake(meta.JSONTag)
+		if meta.ColumnTag != "" {
+			column = meta.ColumnTag[strings.Index(meta.ColumnTag, ".")+1:]
+		}
+		if !ArrayIncludes(timestampColumns, column) {
+			continue
+		}
+
+		value := v.FieldByIndex(meta.FieldIndex)
+		if value.IsZero() {
+			continue
+		}
+		values[column] = value.Interface()
+	}
+
+	return values
+}
+
+// bindRowTimestamps replaces the NOW() of the created_at/updated_at columns by the row's value, when it has one.
+func (s *SQLEloquentQuery) bindRowTimestamps(columns, valuePlaceholders []string) []string {
+	if !s.preserveTimestamps || len(s.rowTimestamps) == 0 {
+		return valuePlaceholders
+	}
+
+	timestampAt := map[int]string{}
+	for i, column := range columns {
+		column = strings.Trim(column, `"`)
+		if ArrayIncludes(timestampColumns, column) {
+			timestampAt[i] = column
+		}
+	}
+	if len(timestampAt) == 0 {
+		return valuePlaceholders
+	}
+
+	bound := make([]string, len(valuePlaceholders))
+	row := 0
+	for i, values := range valuePlaceholders {
+		var rows int
+		bound[i], rows = mapRowItems(values, func(rowInValues, item int, value string) string {
+			column, ok := timestampAt[item]
+			if !ok || value != "NOW()" || row+rowInValues >= len(s.rowTimestamps) {
+				return value
+			}
+			timestamp, ok := s.rowTimestamps[row+rowInValues][column]
+			if !ok {
+				return value
+			}
+
+			s.Args = append(s.Args, timestamp)
+			return "$" + strconv.Itoa(len(s.Args))
+		})
+		row += rows
+	}
+
+	return bound
+}
+
+// mapRowItems rewrites each value of the rows of a VALUES list, e.g. `($1,NOW()),($2,NOW())`, fn gets the row
+// and the value's position in it. It returns the rewritten list and its number of rows.
+// Calls like NOW() are kept whole by tracking the depth, like appendToRows.
+func mapRowItems(values string, fn func(row, item int, value string) string) (string, int) {
+	var sb, value strings.Builder
+	sb.Grow(len(values))
+
+	depth, row, item := 0, 0, 0
+	for _, char := range values {
+		switch {
+		case char == '(' && depth == 0:
+			depth++
+			item = 0
+			sb.WriteRune(char)
+			continue
+		case char == ',' && depth == 1:
+			sb.WriteString(fn(row, item, strings.TrimSpace(value.String())))
+			sb.WriteRune(char)
+			value.Reset()
+			item++
+			continue
+		case char == ')' && depth == 1:
+			depth--
+			sb.WriteString(fn(row, item, strings.TrimSpace(value.String())))
+			sb.WriteRune(char)
+			value.Reset()
+			row++
+			continue
+		case char == '(':
+			depth++
+		case char == ')':
+			depth--
+		}
+
+		if depth == 0 {
+			sb.WriteRune(char)
+		} else {
+			value.WriteRune(char)
+		}
+	}
+
+	return sb.String(), row
+}
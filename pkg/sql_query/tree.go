@@ -0,0 +1,66 @@
+package sql_query
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RecursiveTreeName is the CTE name BuildRecursiveTree's recursive member refers to, e.g. "categories_tree".
+func RecursiveTreeName(table string) string {
+	return strings.Trim(table, `"`) + "_tree"
+}
+
+// BuildRecursiveTree returns the recursive CTE walking the parentIdCol tree of table down from the rows of rootFilter,
+// to add with WithRecursiveCTEBuilder under RecursiveTreeName(table). Every row has its id and parent id columns,
+// its depth (1 for the roots) and the path of ids from its root, a row already on the path is not visited again
+// so a corrupted tree with a cycle still terminates. A nil rootFilter starts from every row.
+//
+// Example:
+//
+//	tree := sql_query.BuildRecursiveTree("categories", "id", "parent_id", map[string]sql_query.SQLCondition{
+//	    "id": {Operator: sql_query.SQLOperatorEqual, Value: categoryID},
+//	})
+//	builder.WithRecursiveCTEBuilder(sql_query.RecursiveTreeName("categories"), tree).
+//	    Join(`categories_tree tree`, `tree."id" = categories."id"`)
+//
+// Generates:
+//
+//	WITH RECURSIVE categories_tree AS (
+//	SELECT "id", "parent_id", 1 AS "depth", ARRAY["id"] AS "path"
+//	FROM categories
+//	WHERE "id" = $1
+//	UNION ALL
+//	SELECT child."id", child."parent_id", parent."depth" + 1, parent."path" || child."id"
+//	FROM categories child
+//	JOIN categories_tree parent ON child."parent_id" = parent."id"
+//	WHERE (NOT child."id" = ANY(parent."path"))
+//	) ...
+func BuildRecursiveTree(table, idCol, parentIdCol string, rootFilter map[string]SQLCondition) *SQLEloquentQuery {
+	idCol, parentIdCol = strings.Trim(idCol, `"`), strings.Trim(parentIdCol, `"`)
+
+	tree := NewSQLSelectBuilder[any](table).(*SelectBuilder)
+	if !isPlainIdentifier(idCol) || !isPlainIdentifier(parentIdCol) {
+		tree.LastError = fmt.Errorf("%w: tree columns must be plain identifiers, got %q and %q", ErrInvalidValues, idCol, parentIdCol)
+		return tree.SQLEloquentQuery
+	}
+	id, parentID := `"`+idCol+`"`, `"`+parentIdCol+`"`
+
+	anchor := NewSQLSelectBuilder[any](table).
+		Select(id, parentID, `1 AS "depth"`, fmt.Sprintf(`ARRAY[%s] AS "path"`, id))
+	if len(rootFilter) > 0 {
+		anchor.Where(rootFilter)
+	}
+
+	recursive := NewSQLSelectBuilder[any](table, "child").
+		Select(
+			"child."+id,
+			"child."+parentID,
+			`parent."depth" + 1`,
+			fmt.Sprintf(`parent."path" || child.%s`, id),
+		).
+		Join(RecursiveTreeName(table)+" parent", fmt.Sprintf(`child.%s = parent.%s`, parentID, id)).
+		WhereRaw(fmt.Sprintf(`NOT child.%s = ANY(parent."path")`, id))
+
+	tree.UnionAll(anchor.(*SelectBuilder).SQLEloquentQuery, recursive.(*SelectBuilder).SQLEloquentQuery)
+	return tree.SQLEloquentQuery
+}
@@ -0,0 +1,111 @@
+package sql_query
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Optimistic locking: the row is only updated when its version column still holds the version read before,
+// and the update increments it, so of two concurrent edits of the same read the second one matches no row
+// instead of silently overwriting the first one.
+//
+// Either call WithVersion on the builder, or tag the version field of the update struct `special:"version"`,
+// Update then writes the check from the field's value. UpdateEach doesn't check versions.
+
+// ErrStaleVersion is matched by StaleVersionError, use errors.Is.
+var ErrStaleVersion = errors.New("stale version")
+
+// StaleVersionError reports a versioned update matching no row: the row was updated or deleted since it was read.
+type StaleVersionError struct {
+	Table   string
+	Column  string
+	Version int
+}
+
+func (e *StaleVersionError) Error() string {
+	return fmt.Sprintf("%s: %s.%s is no longer %d", ErrStaleVersion, e.Table, e.Column, e.Version)
+}
+
+func (e *StaleVersionError) Is(target error) bool {
+	return target == ErrStaleVersion
+}
+
+func (s *UpdateBuilder) WithVersion(column string, currentVersion int) SQLUpdateChainBuilder {
+	if len(s.setClauses) == 0 {
+		s.LastError = fmt.Errorf("%w: WithVersion must follow Update, UpdateEach or Increment", ErrInvalidValues)
+		return s
+	}
+
+	column = strings.Trim(strings.TrimSpace(column), `"`)
+	if s.setClauseIndex(column) >= 0 {
+		s.LastError = fmt.Errorf("%w: version column %s is already set", ErrInvalidValues, column)
+		return s
+	}
+
+	s.setClauses = append(s.setClauses, s.versionCheck(column, currentVersion))
+	return s
+}
+
+// versionCheck filters on the current version and returns the SET clause incrementing it.
+func (s *UpdateBuilder) versionCheck(column string, currentVersion int) string {
+	s.sharedWhereAndQuery(map[string]SQLCondition{
+		column: {Operator: SQLOperatorEqual, Value: currentVersion},
+	})
+
+	return fmt.Sprintf(`"%s" = "%s" + 1`, column, column)
+}
+
+// VersionOf returns the column and value of the field of body tagged `special:"version"`, false when there is none.
+func VersionOf(body any) (string, int, bool) {
+	v := reflect.Indirect(reflect.ValueOf(body))
+	if v.Kind() != reflect.Struct {
+		return "", 0, false
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !strings.Contains(field.Tag.Get("special"), "version") {
+			continue
+		}
+
+		value, ok := versionValue(v.Field(i))
+		if !ok {
+			return "", 0, false
+		}
+
+		return versionColumn(field), value, true
+	}
+
+	return "", 0, false
+}
+
+func versionValue(v reflect.Value) (int, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int(v.Uint()), true
+	}
+
+	return 0, false
+}
+
+// versionColumn names the column of a version field like extractUpdateFieldsStruct does.
+func versionColumn(field reflect.StructField) string {
+	column := field.Tag.Get("column")
+	if column == "" {
+		if jsonTag := field.Tag.Get("json"); jsonTag != "" {
+			column = CamelToSnake(jsonTag)
+		} else {
+			column = CamelToSnake(field.Name)
+		}
+	}
+	if strings.Contains(column, ".") {
+		column = column[strings.Index(column, ".")+1:]
+	}
+
+	return column
+}
@@ -0,0 +1,25 @@
+package sql_query
+
+import (
+	"reflect"
+)
+
+// Prime fills the field meta, columns and insert template caches for T ahead of the first request.
+// Call it at startup for every DTO on a hot path, the caches are otherwise built lazily under load.
+//
+// Example:
+//
+//	sql_query.Prime[dto.GetUserInfoData]()
+func Prime[T any]() {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	if typ.Kind() != reflect.Struct {
+		return
+	}
+
+	ExtractJSONTags[T]()
+
+	// Insert templates are keyed separately for single and bulk inserts, the built queries are discarded
+	var zero T
+	NewSQLInsertBuilder(typ.Name()).Insert(zero)
+	NewSQLInsertBuilder(typ.Name()).Insert([]T{zero})
+}
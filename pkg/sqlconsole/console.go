@@ -0,0 +1,204 @@
+package sqlconsole
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/mystaline/clefinport-be/pkg/db"
+	"github.com/mystaline/clefinport-be/pkg/introspect"
+	"github.com/mystaline/clefinport-be/pkg/service"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Read-only SQL console for support staff, instead of direct psql access: a parameterized SELECT is validated
+// (see Validate), planned to check it only reads tables of the admin data browser and calls no denied function, then run in a read-only
+// transaction under a statement timeout with a forced LIMIT. Rows come back as JSON objects built by Postgres.
+// Auditing is left to the caller, which knows who asked.
+
+type Config struct {
+	// Rows returned when the query doesn't ask for a limit, 100 by default
+	DefaultRows int
+	// Upper bound of the asked limit, 1000 by default
+	MaxRows int
+	// statement_timeout of the console transaction, 5 seconds by default
+	StatementTimeout time.Duration
+}
+
+type Query struct {
+	SQL  string `json:"sql"`
+	Args []any  `json:"args"`
+	// Rows to return, DefaultRows when zero, capped to MaxRows
+	Limit int `json:"limit"`
+}
+
+type Result struct {
+	// One JSON object per row, keys in select order
+	Rows []json.RawMessage `json:"rows"`
+	// More rows matched than the limit
+	Truncated bool `json:"truncated"`
+	// Tables the plan reads
+	Tables []string `json:"tables"`
+}
+
+type Console struct {
+	Service   service.PostgreSqlService
+	Inspector *introspect.Inspector
+
+	config Config
+}
+
+// MakeConsole runs queries on svc, allowing the tables inspector lists.
+func MakeConsole(svc service.PostgreSqlService, inspector *introspect.Inspector, config Config) *Console {
+	if config.DefaultRows <= 0 {
+		config.DefaultRows = 100
+	}
+	if config.MaxRows <= 0 {
+		config.MaxRows = 1000
+	}
+	if config.StatementTimeout <= 0 {
+		config.StatementTimeout = 5 * time.Second
+	}
+
+	return &Console{
+		Service:   svc,
+		Inspector: inspector,
+		config:    config,
+	}
+}
+
+// Run validates and runs query, errors wrapping ErrRejected are the caller's mistake.
+// Postgres errors (syntax, unknown column, timeout) are returned as *pgconn.PgError.
+func (c *Console) Run(ctx context.Context, query Query) (*Result, error) {
+	sql, err := Validate(query.SQL, query.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = c.config.DefaultRows
+	}
+	limit = min(limit, c.config.MaxRows)
+
+	allowed, err := c.Inspector.TableNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	options := service.TxOptions{
+		TxOptions: pgx.TxOptions{AccessMode: pgx.ReadOnly},
+		Timeouts:  db.SessionTimeouts{Statement: c.config.StatementTimeout},
+	}
+
+	return service.UseTransactionsWithOptions(ctx, c.Service.GetPool(), options, func(tx pgx.Tx) (*Result, error) {
+		tables, err := plannedTables(ctx, tx, sql, query.Args)
+		if err != nil {
+			return nil, err
+		}
+		for _, table := range tables {
+			if !slices.Contains(allowed, table) {
+				return nil, fmt.Errorf("%w: table %s is not allowed", ErrRejected, table)
+			}
+		}
+
+		// One more row than asked tells whether there are more
+		args := append(slices.Clone(query.Args), limit+1)
+		rows, err := tx.Query(
+			ctx,
+			fmt.Sprintf(`SELECT row_to_json(console)::text FROM (%s) AS console LIMIT $%d`, sql, len(args)),
+			args...,
+		)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+
+		result := &Result{Rows: []json.RawMessage{}, Tables: tables}
+		for rows.Next() {
+			var row string
+			if err := rows.Scan(&row); err != nil {
+				return nil, err
+			}
+			if len(result.Rows) == limit {
+				result.Truncated = true
+				break
+			}
+			result.Rows = append(result.Rows, json.RawMessage(row))
+		}
+
+		return result, rows.Err()
+	})
+}
+
+type planNode struct {
+	RelationName string     `json:"Relation Name"`
+	Schema       string     `json:"Schema"`
+	Plans        []planNode `json:"Plans"`
+}
+
+// plannedTables returns the tables of the current schema the plan of sql reads, sorted.
+// Planning sees through subqueries, views and comma joins, a table of any other schema
+// or a denied function anywhere in the plan is an error.
+func plannedTables(ctx context.Context, tx pgx.Tx, sql string, args []any) ([]string, error) {
+	var currentSchema string
+	if err := tx.QueryRow(ctx, "SELECT current_schema()").Scan(&currentSchema); err != nil {
+		return nil, err
+	}
+
+	var explained string
+	if err := tx.QueryRow(ctx, "EXPLAIN (FORMAT JSON, VERBOSE) "+sql, args...).Scan(&explained); err != nil {
+		return nil, err
+	}
+
+	var plan any
+	if err := json.Unmarshal([]byte(explained), &plan); err != nil {
+		return nil, err
+	}
+	denied, err := deniedPlanFunction(plan)
+	if err != nil {
+		return nil, err
+	}
+	if denied != "" {
+		return nil, fmt.Errorf("%w: function %s is not allowed", ErrRejected, denied)
+	}
+
+	var plans []struct {
+		Plan planNode `json:"Plan"`
+	}
+	if err := json.Unmarshal([]byte(explained), &plans); err != nil {
+		return nil, err
+	}
+	if len(plans) == 0 {
+		return nil, errors.New("empty query plan")
+	}
+
+	tables := []string{}
+	var visit func(node planNode) error
+	visit = func(node planNode) error {
+		if node.RelationName != "" {
+			if node.Schema != currentSchema {
+				return fmt.Errorf("%w: table %s.%s is not allowed", ErrRejected, node.Schema, node.RelationName)
+			}
+			if !slices.Contains(tables, node.RelationName) {
+				tables = append(tables, node.RelationName)
+			}
+		}
+		for _, child := range node.Plans {
+			if err := visit(child); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := visit(plans[0].Plan); err != nil {
+		return nil, err
+	}
+
+	slices.Sort(tables)
+	return tables, nil
+}
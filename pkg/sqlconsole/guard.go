@@ -0,0 +1,168 @@
+package sqlconsole
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrRejected wraps every reason a console query is refused before it reaches Postgres.
+var ErrRejected = errors.New("query rejected")
+
+// MaxQueryLength bounds the statement text, longer ones are rejected.
+const MaxQueryLength = 10000
+
+var (
+	// $$...$$ and $tag$...$tag$ bodies can't be told apart from the surrounding statement without a parser
+	dollarQuoteRegexp = regexp.MustCompile(`\$(?:[A-Za-z_]\w*)?\$`)
+	placeholderRegexp = regexp.MustCompile(`\$(\d+)`)
+	leadingRegexp     = regexp.MustCompile(`(?i)^\(*\s*(SELECT|WITH)\b`)
+	// The transaction is read only anyway, these fail earlier with a readable reason.
+	// INTO covers SELECT INTO, UPDATE and SHARE the row locks of FOR UPDATE / FOR SHARE.
+	writeKeywordRegexp = regexp.MustCompile(`(?i)\b(INSERT|UPDATE|DELETE|MERGE|UPSERT|TRUNCATE|DROP|ALTER|CREATE|GRANT|REVOKE|COPY|CALL|DO|EXECUTE|PREPARE|DEALLOCATE|VACUUM|ANALYZE|CLUSTER|REINDEX|REFRESH|LOCK|LISTEN|NOTIFY|SET|RESET|INTO|SHARE)\b`)
+	// Server administration, file access, sleeps and settings are reachable from a plain SELECT.
+	// Matched against the expressions of the plan, see deniedPlanFunction, not against the query text.
+	deniedFunctionRegexp = regexp.MustCompile(`(?i)\b(pg_\w+|lo_\w+|dblink\w*|set_config|current_setting|query_to_xml\w*|txid_\w+)\s*\(`)
+)
+
+// Validate checks the statement text and its placeholders, it returns the statement without its trailing semicolon.
+// Only one SELECT (or WITH ... SELECT) without comments is accepted, its values must be $n placeholders
+// given in args: quoted literals and identifiers are stripped before the keyword checks, a keyword inside one is harmless.
+// The tables it reads and the functions it calls are checked by Console.Run, from the plan.
+func Validate(sql string, args []any) (string, error) {
+	sql = strings.TrimSpace(sql)
+	sql = strings.TrimSpace(strings.TrimSuffix(sql, ";"))
+	if sql == "" {
+		return "", fmt.Errorf("%w: empty query", ErrRejected)
+	}
+	if len(sql) > MaxQueryLength {
+		return "", fmt.Errorf("%w: query is longer than %d characters", ErrRejected, MaxQueryLength)
+	}
+
+	if dollarQuoteRegexp.MatchString(sql) {
+		return "", fmt.Errorf("%w: dollar quoted strings are not allowed", ErrRejected)
+	}
+
+	code, err := stripQuoted(sql)
+	if err != nil {
+		return "", err
+	}
+	if strings.Contains(code, ";") {
+		return "", fmt.Errorf("%w: only one statement is allowed", ErrRejected)
+	}
+	if strings.Contains(code, "--") || strings.Contains(code, "/*") {
+		return "", fmt.Errorf("%w: comments are not allowed", ErrRejected)
+	}
+	if !leadingRegexp.MatchString(code) {
+		return "", fmt.Errorf("%w: only SELECT queries are allowed", ErrRejected)
+	}
+	if keyword := writeKeywordRegexp.FindString(code); keyword != "" {
+		return "", fmt.Errorf("%w: %s is not allowed", ErrRejected, strings.ToUpper(keyword))
+	}
+	if err := checkPlaceholders(code, len(args)); err != nil {
+		return "", err
+	}
+
+	return sql, nil
+}
+
+// stripQuoted empties the '...' literals and "..." identifiers of sql, keeping only their quotes.
+// A doubled quote escapes itself, in E'...' literals a backslash escapes the next character too.
+func stripQuoted(sql string) (string, error) {
+	var sb strings.Builder
+	for i := 0; i < len(sql); i++ {
+		quote := sql[i]
+		if quote != '\'' && quote != '"' {
+			sb.WriteByte(quote)
+			continue
+		}
+
+		// E'...' only when the E starts a token, xE'...' is the identifier xE followed by a literal
+		escapes := quote == '\'' && i > 0 && (sql[i-1] == 'E' || sql[i-1] == 'e') &&
+			(i == 1 || !isIdentifierChar(sql[i-2]))
+
+		closed := false
+		for i++; i < len(sql); i++ {
+			if escapes && sql[i] == '\\' {
+				i++
+				continue
+			}
+			if sql[i] != quote {
+				continue
+			}
+			if i+1 < len(sql) && sql[i+1] == quote {
+				i++
+				continue
+			}
+			closed = true
+			break
+		}
+		if !closed {
+			return "", fmt.Errorf("%w: unterminated quoted string or identifier", ErrRejected)
+		}
+
+		sb.WriteByte(quote)
+		sb.WriteByte(quote)
+	}
+
+	return sb.String(), nil
+}
+
+// deniedPlanFunction returns a denied function called by the plan of EXPLAIN (FORMAT JSON, VERBOSE).
+// VERBOSE prints every expression (Output, Filter, Index Cond, Function Call...) as deparsed by Postgres,
+// names unquoted and literals in standard form, so quoting or escaping in the query text can't hide a call.
+func deniedPlanFunction(plan any) (string, error) {
+	switch value := plan.(type) {
+	case string:
+		code, err := stripQuoted(value)
+		if err != nil {
+			return "", err
+		}
+		if match := deniedFunctionRegexp.FindStringSubmatch(code); match != nil {
+			return match[1], nil
+		}
+	case []any:
+		for _, each := range value {
+			if denied, err := deniedPlanFunction(each); denied != "" || err != nil {
+				return denied, err
+			}
+		}
+	case map[string]any:
+		// In key order, the same plan reports the same function
+		for _, key := range slices.Sorted(maps.Keys(value)) {
+			if denied, err := deniedPlanFunction(value[key]); denied != "" || err != nil {
+				return denied, err
+			}
+		}
+	}
+
+	return "", nil
+}
+
+func isIdentifierChar(c byte) bool {
+	return c == '_' || c == '$' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// checkPlaceholders requires $1..$n to match the n args, an unused arg would hint at a value inlined in the text.
+func checkPlaceholders(code string, argCount int) error {
+	used := map[int]bool{}
+	for _, match := range placeholderRegexp.FindAllStringSubmatch(code, -1) {
+		position, _ := strconv.Atoi(match[1])
+		if position < 1 || position > argCount {
+			return fmt.Errorf("%w: $%d has no argument, %d given", ErrRejected, position, argCount)
+		}
+		used[position] = true
+	}
+
+	for position := 1; position <= argCount; position++ {
+		if !used[position] {
+			return fmt.Errorf("%w: argument %d is not used by any $%d", ErrRejected, position, position)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package sqlconsole
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		sql      string
+		args     []any
+		rejected bool
+	}{
+		{name: "select with placeholder", sql: `SELECT id FROM users WHERE email = $1;`, args: []any{"a@b.c"}},
+		{name: "doubled quote", sql: `SELECT 'it''s' FROM users`},
+		{name: "quote inside identifier", sql: `SELECT name AS "it's" FROM users`},
+		{name: "escaped quote hides a comment", sql: `SELECT E'\'', pg_sleep(9) --'`, rejected: true},
+		{name: "escaped backslash", sql: `SELECT E'\\', pg_sleep(9) --'`, rejected: true},
+		{name: "identifier quote hides a statement", sql: `SELECT "'"; DELETE FROM users; SELECT '"'`, rejected: true},
+		{name: "unterminated escape string", sql: `SELECT E'\'`, rejected: true},
+		{name: "write keyword", sql: `SELECT * INTO copy FROM users`, rejected: true},
+		{name: "dollar quote", sql: `SELECT $$x$$`, rejected: true},
+		{name: "unused argument", sql: `SELECT 1`, args: []any{1}, rejected: true},
+		// Functions are checked on the plan, see TestDeniedPlanFunction
+		{name: "quoted function name", sql: `SELECT "pg_read_file"('/etc/passwd')`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Validate(tt.sql, tt.args)
+			if tt.rejected && !errors.Is(err, ErrRejected) {
+				t.Fatalf("Validate(%q) error = %v, want ErrRejected", tt.sql, err)
+			}
+			if !tt.rejected && err != nil {
+				t.Fatalf("Validate(%q) error = %v, want nil", tt.sql, err)
+			}
+		})
+	}
+}
+
+func TestDeniedPlanFunction(t *testing.T) {
+	tests := []struct {
+		name string
+		plan string
+		want string
+	}{
+		{
+			name: "plain select",
+			plan: `[{"Plan": {"Node Type": "Seq Scan", "Relation Name": "users", "Output": ["users.id", "lower(users.email)"]}}]`,
+		},
+		{
+			// SELECT "pg_read_file"('/etc/passwd'), the quotes are gone once deparsed
+			name: "quoted function name",
+			plan: `[{"Plan": {"Node Type": "Result", "Output": ["pg_read_file('/etc/passwd'::text)"]}}]`,
+			want: "pg_read_file",
+		},
+		{
+			// SELECT E'\'', pg_sleep(9) deparses its literal in standard form
+			name: "call after escaped literal",
+			plan: `[{"Plan": {"Node Type": "Result", "Output": ["''''::text", "pg_sleep('9'::double precision)"]}}]`,
+			want: "pg_sleep",
+		},
+		{
+			name: "call inside a literal",
+			plan: `[{"Plan": {"Node Type": "Result", "Output": ["'pg_sleep(9)'::text"]}}]`,
+		},
+		{
+			name: "filter of a child node",
+			plan: `[{"Plan": {"Node Type": "Limit", "Plans": [{"Node Type": "Seq Scan", "Relation Name": "users",
+				"Filter": "(current_setting('is_superuser'::text) = 'on'::text)"}]}}]`,
+			want: "current_setting",
+		},
+		{
+			name: "function scan",
+			plan: `[{"Plan": {"Node Type": "Function Scan", "Function Call": "pg_ls_dir('.'::text)"}}]`,
+			want: "pg_ls_dir",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var plan any
+			if err := json.Unmarshal([]byte(tt.plan), &plan); err != nil {
+				t.Fatal(err)
+			}
+
+			denied, err := deniedPlanFunction(plan)
+			if err != nil {
+				t.Fatalf("deniedPlanFunction() error = %v", err)
+			}
+			if denied != tt.want {
+				t.Fatalf("deniedPlanFunction() = %q, want %q", denied, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package tablestats
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Handler serves the latest snapshots of monitors in the Prometheus text format, mount it on GET /metrics.
+//
+// Example output:
+//
+//	# HELP clefinport_table_rows Estimated live rows of the table.
+//	# TYPE clefinport_table_rows gauge
+//	clefinport_table_rows{database="clefinport_wallet",table="wallet_outboxes"} 12
+func Handler(monitors ...*Monitor) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var rows, bytes, alerting strings.Builder
+
+		for _, monitor := range monitors {
+			snapshots, _ := monitor.Latest()
+
+			monitor.mu.Lock()
+			for _, snapshot := range snapshots {
+				labels := fmt.Sprintf(`{database="%s",table="%s"}`, escapeLabel(string(monitor.database)), escapeLabel(snapshot.Table))
+				fmt.Fprintf(&rows, "clefinport_table_rows%s %d\n", labels, snapshot.Rows)
+				fmt.Fprintf(&bytes, "clefinport_table_total_bytes%s %d\n", labels, snapshot.TotalBytes)
+
+				if _, ok := monitor.config.Thresholds[snapshot.Table]; ok {
+					value := 0
+					if monitor.alerting[snapshot.Table] {
+						value = 1
+					}
+					fmt.Fprintf(&alerting, "clefinport_table_above_threshold%s %d\n", labels, value)
+				}
+			}
+			monitor.mu.Unlock()
+		}
+
+		var sb strings.Builder
+		sb.WriteString("# HELP clefinport_table_rows Estimated live rows of the table.\n")
+		sb.WriteString("# TYPE clefinport_table_rows gauge\n")
+		sb.WriteString(rows.String())
+		sb.WriteString("# HELP clefinport_table_total_bytes Size of the table with its indexes and TOAST.\n")
+		sb.WriteString("# TYPE clefinport_table_total_bytes gauge\n")
+		sb.WriteString(bytes.String())
+		sb.WriteString("# HELP clefinport_table_above_threshold 1 while the table is above its alert threshold.\n")
+		sb.WriteString("# TYPE clefinport_table_above_threshold gauge\n")
+		sb.WriteString(alerting.String())
+
+		ctx.Set(fiber.HeaderContentType, "text/plain; version=0.0.4; charset=utf-8")
+		return ctx.SendString(sb.String())
+	}
+}
+
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
@@ -0,0 +1,231 @@
+package tablestats
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/mystaline/clefinport-be/pkg/db"
+	"github.com/mystaline/clefinport-be/pkg/service"
+	"github.com/mystaline/clefinport-be/pkg/sql_query"
+)
+
+// Periodic row count and size snapshot of every table of a service database.
+// Snapshots are kept in the table_metrics table for trends, the latest one is served to Prometheus,
+// and tables crossing a threshold raise an alert: a growing outbox means its relay is stuck.
+//
+//	id bigint, table_name text, row_count bigint, total_bytes bigint, created_at, updated_at
+//
+// Row counts are the statistics collector's live tuple estimates, exact counts would scan the log tables.
+
+type Snapshot struct {
+	Table      string `json:"table"      column:"table_name"`
+	Rows       int64  `json:"rows"       column:"row_count"`
+	TotalBytes int64  `json:"totalBytes" column:"total_bytes"`
+}
+
+// Threshold of a table, zero fields are not checked.
+type Threshold struct {
+	Rows  int64
+	Bytes int64
+}
+
+// Alert is raised once when a table crosses its threshold, and again only after it went back below.
+type Alert struct {
+	Database  db.DBName
+	Snapshot  Snapshot
+	Threshold Threshold
+}
+
+func (a Alert) String() string {
+	return fmt.Sprintf("table %s.%s above threshold: %d rows (max %d), %d bytes (max %d)",
+		a.Database, a.Snapshot.Table, a.Snapshot.Rows, a.Threshold.Rows, a.Snapshot.TotalBytes, a.Threshold.Bytes)
+}
+
+// DefaultThresholds flag outboxes whose relay stopped and log tables outgrowing their retention.
+func DefaultThresholds() map[string]Threshold {
+	return map[string]Threshold{
+		db.UserOutboxTableName:   {Rows: 10_000},
+		db.WalletOutboxTableName: {Rows: 10_000},
+		db.LogOutboxTableName:    {Rows: 10_000},
+		db.EventLogTableName:     {Bytes: 10 << 30},
+		db.ChangeLogTableName:    {Bytes: 10 << 30},
+		db.SessionLogTableName:   {Bytes: 10 << 30},
+	}
+}
+
+type Config struct {
+	// Interval between snapshots, 5 minutes by default
+	Interval time.Duration
+	// Retention of the table_metrics rows, 30 days by default
+	Retention time.Duration
+	// Thresholds by table name, DefaultThresholds when nil
+	Thresholds map[string]Threshold
+	// OnAlert is called for every raised alert, e.g. to page someone. Alerts are logged either way.
+	OnAlert func(alert Alert)
+}
+
+type Monitor struct {
+	database db.DBName
+	service  service.PostgreSqlService
+	config   Config
+
+	mu        sync.Mutex
+	latest    []Snapshot
+	updatedAt time.Time
+	alerting  map[string]bool
+}
+
+// MakeMonitor creates the monitor of svc's database, Start runs it.
+func MakeMonitor(database db.DBName, svc service.PostgreSqlService, config Config) *Monitor {
+	if config.Interval <= 0 {
+		config.Interval = 5 * time.Minute
+	}
+	if config.Retention <= 0 {
+		config.Retention = 30 * 24 * time.Hour
+	}
+	if config.Thresholds == nil {
+		config.Thresholds = DefaultThresholds()
+	}
+
+	return &Monitor{
+		database: database,
+		service:  svc,
+		config:   config,
+		alerting: map[string]bool{},
+	}
+}
+
+// Start takes a snapshot right away and then every Interval until ctx is cancelled.
+func (m *Monitor) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(m.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			if err := m.Run(ctx); err != nil {
+				log.Printf("table stats %s: %v", m.database, err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Run takes one snapshot, checks the thresholds and records it.
+func (m *Monitor) Run(ctx context.Context) error {
+	snapshots, err := Collect(ctx, m.service)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	m.latest = snapshots
+	m.updatedAt = time.Now()
+	alerts := m.checkThresholds(snapshots)
+	m.mu.Unlock()
+
+	for _, alert := range alerts {
+		log.Print(alert)
+		if m.config.OnAlert != nil {
+			m.config.OnAlert(alert)
+		}
+	}
+
+	return m.record(ctx, snapshots)
+}
+
+// Latest returns the last snapshot and when it was taken, nil before the first run.
+func (m *Monitor) Latest() ([]Snapshot, time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.latest, m.updatedAt
+}
+
+// Collect returns the live row estimate and total size (indexes and TOAST included) of every table of the current schema.
+func Collect(ctx context.Context, svc service.PostgreSqlService) ([]Snapshot, error) {
+	query := `
+SELECT relname::text AS "table", n_live_tup AS "rows", pg_total_relation_size(relid) AS "totalBytes"
+FROM pg_stat_user_tables
+WHERE schemaname = current_schema()
+ORDER BY relname`
+
+	snapshots := []Snapshot{}
+	if err := svc.SelectMany(&snapshots, ctx, query); err != nil {
+		return nil, err
+	}
+
+	return snapshots, nil
+}
+
+// checkThresholds returns the alerts of tables newly above their threshold, mu must be held.
+func (m *Monitor) checkThresholds(snapshots []Snapshot) []Alert {
+	var alerts []Alert
+	for _, snapshot := range snapshots {
+		threshold, ok := m.config.Thresholds[snapshot.Table]
+		if !ok {
+			continue
+		}
+
+		above := (threshold.Rows > 0 && snapshot.Rows > threshold.Rows) ||
+			(threshold.Bytes > 0 && snapshot.TotalBytes > threshold.Bytes)
+		if above && !m.alerting[snapshot.Table] {
+			alerts = append(alerts, Alert{Database: m.database, Snapshot: snapshot, Threshold: threshold})
+		}
+		m.alerting[snapshot.Table] = above
+	}
+
+	return alerts
+}
+
+// record stores snapshots and prunes expired ones. Every instance of a service runs a monitor,
+// the advisory lock lets a single one record each round.
+func (m *Monitor) record(ctx context.Context, snapshots []Snapshot) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	_, err := service.UseTransactions(ctx, m.service.GetPool(), func(tx pgx.Tx) (bool, error) {
+		m.service.SetTransaction(tx)
+		defer m.service.SetTransaction(nil)
+
+		var locked bool
+		if err := tx.QueryRow(ctx, "SELECT pg_try_advisory_xact_lock(hashtext($1))", "table-metrics").Scan(&locked); err != nil {
+			return false, err
+		}
+		if !locked {
+			return false, nil
+		}
+
+		// Another instance may have recorded this round while this one was collecting
+		recent, err := m.service.CountWithFilter(ctx, db.TableMetricTableName, map[string]sql_query.SQLCondition{
+			"created_at": {Operator: sql_query.SQLOperatorGreaterThan, Value: time.Now().Add(-m.config.Interval / 2)},
+		})
+		if err != nil {
+			return false, err
+		}
+		if recent > 0 {
+			return false, nil
+		}
+
+		if _, err := m.service.InsertManyWithData(ctx, db.TableMetricTableName, snapshots); err != nil {
+			return false, err
+		}
+
+		_, err = m.service.DeleteManyWithFilter(ctx, db.TableMetricTableName, map[string]sql_query.SQLCondition{
+			"created_at": {Operator: sql_query.SQLOperatorLessThan, Value: time.Now().Add(-m.config.Retention)},
+		})
+		return err == nil, err
+	})
+
+	return err
+}
@@ -0,0 +1,282 @@
+package userarchive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/mystaline/clefinport-be/pkg/db"
+	"github.com/mystaline/clefinport-be/pkg/provider"
+	"github.com/mystaline/clefinport-be/pkg/service"
+)
+
+// Moves a user's account between environments (e.g. production -> staging for a support case).
+// Export dumps every row of the graph as JSON into one gzip archive, Import inserts them with fresh ids
+// so they can't collide with the target's rows, rewriting every reference to the new ids.
+// Ids are kept raw rather than pseudonymized (privacy.PseudonymizeRows), Import needs them to rewrite references.
+
+// FormatVersion is bumped whenever the archive layout changes, Read rejects other versions.
+const FormatVersion = 1
+
+var (
+	ErrUserNotFound       = errors.New("user not found")
+	ErrUnsupportedArchive = errors.New("unsupported archive version")
+)
+
+type Archive struct {
+	Version    int         `json:"version"`
+	UserID     string      `json:"userId"`
+	ExportedAt time.Time   `json:"exportedAt"`
+	Tables     []TableDump `json:"tables"`
+}
+
+// TableDump holds the rows of one partition, each row is the to_jsonb of the table row.
+type TableDump struct {
+	Table string            `json:"table"`
+	Rows  []json.RawMessage `json:"rows"`
+}
+
+// ImportResult tells the id the user got in the target environment and how many rows each table received.
+type ImportResult struct {
+	UserID string         `json:"userId"`
+	Rows   map[string]int `json:"rows"`
+}
+
+type exportedRow struct {
+	// Exported as text, jsonb decoded by the driver would turn snowflake ids into lossy float64
+	Row string `json:"row"`
+}
+
+// Export reads the user's graph from every database of graph.
+func Export(ctx context.Context, serviceProvider provider.IServiceProvider, userID string, graph []Partition) (*Archive, error) {
+	archive := &Archive{
+		Version:    FormatVersion,
+		UserID:     userID,
+		ExportedAt: time.Now().UTC(),
+		Tables:     make([]TableDump, 0, len(graph)),
+	}
+
+	services := map[db.DBName]service.PostgreSqlService{}
+	for i, partition := range graph {
+		svc, ok := services[partition.DB]
+		if !ok {
+			svc = serviceProvider.MakeService(partition.DB)
+			services[partition.DB] = svc
+		}
+
+		query := fmt.Sprintf(`SELECT to_jsonb(t)::text AS "row" FROM %s t WHERE %s`, partition.Table, partition.Filter)
+
+		var rows []exportedRow
+		if err := svc.SelectMany(&rows, ctx, query, userID); err != nil {
+			return nil, fmt.Errorf("export %s: %w", partition.Table, err)
+		}
+		// The first partition is the user itself
+		if i == 0 && len(rows) == 0 {
+			return nil, ErrUserNotFound
+		}
+
+		dump := TableDump{Table: partition.Table, Rows: make([]json.RawMessage, len(rows))}
+		for j, each := range rows {
+			dump.Rows[j] = json.RawMessage(each.Row)
+		}
+		archive.Tables = append(archive.Tables, dump)
+	}
+
+	return archive, nil
+}
+
+// Import inserts the archive into the databases of graph with remapped ids and returns the new user id.
+// Every database gets its own transaction, they are only committed once all tables went in.
+func Import(ctx context.Context, serviceProvider provider.IServiceProvider, archive *Archive, graph []Partition) (result *ImportResult, err error) {
+	if archive.Version != FormatVersion {
+		return nil, ErrUnsupportedArchive
+	}
+
+	dumps := make(map[string]TableDump, len(archive.Tables))
+	for _, dump := range archive.Tables {
+		dumps[dump.Table] = dump
+	}
+
+	services := map[db.DBName]service.PostgreSqlService{}
+	defer func() {
+		if err == nil {
+			return
+		}
+		for _, svc := range services {
+			_ = svc.RollbackTransaction(ctx)
+		}
+	}()
+
+	remapper := newIDRemapper()
+	result = &ImportResult{Rows: make(map[string]int, len(graph))}
+
+	for _, partition := range graph {
+		dump, ok := dumps[partition.Table]
+		if !ok || len(dump.Rows) == 0 {
+			continue
+		}
+
+		svc, ok := services[partition.DB]
+		if !ok {
+			svc = serviceProvider.MakeService(partition.DB)
+			tx, err := svc.GetPool().Begin(ctx)
+			if err != nil {
+				return nil, err
+			}
+			svc.SetTransaction(tx)
+			services[partition.DB] = svc
+		}
+
+		rows, err := remapper.remapRows(partition, dump.Rows)
+		if err != nil {
+			return nil, fmt.Errorf("import %s: %w", partition.Table, err)
+		}
+
+		payload, err := json.Marshal(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		// jsonb_populate_recordset casts every key to its column type, keys without column are ignored
+		query := fmt.Sprintf(`INSERT INTO %[1]s SELECT * FROM jsonb_populate_recordset(NULL::%[1]s, $1::jsonb)`, partition.Table)
+		inserted, err := svc.InsertMany(ctx, query, string(payload))
+		if err != nil {
+			return nil, fmt.Errorf("import %s: %w", partition.Table, err)
+		}
+		result.Rows[partition.Table] = int(inserted)
+	}
+
+	for _, svc := range services {
+		if err := svc.CommitTransaction(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if newID, ok := remapper.lookup(graph[0].Table, archive.UserID); ok {
+		result.UserID = fmt.Sprint(newID)
+	}
+
+	return result, nil
+}
+
+// Write encodes archive as gzipped JSON.
+func Write(w io.Writer, archive *Archive) error {
+	zw := gzip.NewWriter(w)
+	if err := json.NewEncoder(zw).Encode(archive); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+// Read decodes an archive produced by Write.
+func Read(r io.Reader) (*Archive, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid archive: %w", err)
+	}
+	defer zr.Close()
+
+	var archive Archive
+	if err := json.NewDecoder(zr).Decode(&archive); err != nil {
+		return nil, fmt.Errorf("invalid archive: %w", err)
+	}
+	if archive.Version != FormatVersion {
+		return nil, ErrUnsupportedArchive
+	}
+
+	return &archive, nil
+}
+
+// idRemapper hands out new ids of the same kind as the old ones, snowflake for numbers and UUID for uuid strings.
+type idRemapper struct {
+	// table -> old id -> new id
+	ids map[string]map[string]any
+}
+
+func newIDRemapper() *idRemapper {
+	db.InitSnowflake()
+
+	return &idRemapper{ids: map[string]map[string]any{}}
+}
+
+func (r *idRemapper) lookup(table string, oldID string) (any, bool) {
+	newID, ok := r.ids[table][oldID]
+	return newID, ok
+}
+
+func (r *idRemapper) remapRows(partition Partition, raw []json.RawMessage) ([]map[string]any, error) {
+	if r.ids[partition.Table] == nil {
+		r.ids[partition.Table] = map[string]any{}
+	}
+
+	rows := make([]map[string]any, len(raw))
+	for i, each := range raw {
+		decoder := json.NewDecoder(bytes.NewReader(each))
+		decoder.UseNumber()
+
+		var row map[string]any
+		if err := decoder.Decode(&row); err != nil {
+			return nil, err
+		}
+
+		// Tables keyed by their parent (profile_settings.user_id) have no id of their own
+		if oldID, ok := row["id"]; ok && oldID != nil {
+			newID, err := generateID(oldID)
+			if err != nil {
+				return nil, err
+			}
+			r.ids[partition.Table][fmt.Sprint(oldID)] = newID
+			row["id"] = newID
+		}
+
+		for _, ref := range partition.References {
+			value, ok := row[ref.Column]
+			if !ok || value == nil {
+				continue
+			}
+
+			if newID, found := r.lookup(ref.Table, fmt.Sprint(value)); found {
+				row[ref.Column] = newID
+				continue
+			}
+
+			switch ref.OnMissing {
+			case MissingNull:
+				row[ref.Column] = nil
+			case MissingKeep:
+			default:
+				return nil, fmt.Errorf("%s %v references %s outside of the archive", ref.Column, value, ref.Table)
+			}
+		}
+
+		rows[i] = row
+	}
+
+	return rows, nil
+}
+
+func generateID(oldID any) (any, error) {
+	switch id := oldID.(type) {
+	case json.Number:
+		return json.Number(db.Node.Generate().String()), nil
+	case string:
+		if _, err := uuid.Parse(id); err == nil {
+			return uuid.NewString(), nil
+		}
+		// bigint ids exported as text by a ::text column
+		if strings.Trim(id, "0123456789") == "" && id != "" {
+			return db.Node.Generate().String(), nil
+		}
+	}
+
+	return nil, fmt.Errorf("unsupported id %v", oldID)
+}
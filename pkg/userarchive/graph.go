@@ -0,0 +1,88 @@
+package userarchive
+
+import "github.com/mystaline/clefinport-be/pkg/db"
+
+// MissingPolicy decides what happens to a reference whose target row is not part of the archive.
+type MissingPolicy string
+
+const (
+	// MissingFail aborts the import, the archive is incomplete
+	MissingFail MissingPolicy = "fail"
+	// MissingNull clears the reference, e.g. other members of a shared wallet who don't move along
+	MissingNull MissingPolicy = "null"
+	// MissingKeep keeps the original id, for rows seeded identically in every environment (default categories)
+	MissingKeep MissingPolicy = "keep"
+)
+
+// Reference is a column pointing to the id of another table of the graph.
+type Reference struct {
+	Column    string
+	Table     string
+	OnMissing MissingPolicy
+}
+
+// Partition is one table of a user's graph.
+type Partition struct {
+	DB    db.DBName
+	Table string
+	// Filter selects the user's rows of the table aliased as t, $1 is the user id
+	Filter     string
+	References []Reference
+}
+
+// DefaultGraph lists the tables of a user's account, referenced tables first so ids are remapped before use.
+var DefaultGraph = []Partition{
+	{
+		DB:     db.UserServiceDBName,
+		Table:  db.UserTableName,
+		Filter: "t.id = $1",
+	},
+	{
+		DB:     db.UserServiceDBName,
+		Table:  db.ProfileSettingTableName,
+		Filter: "t.user_id = $1",
+		References: []Reference{
+			{Column: "user_id", Table: db.UserTableName, OnMissing: MissingFail},
+		},
+	},
+	{
+		DB:     db.WalletServiceDBName,
+		Table:  db.WalletTableName,
+		Filter: "t.id IN (SELECT wallet_id FROM " + db.UserWalletTableName + " WHERE user_id = $1)",
+	},
+	{
+		DB:     db.WalletServiceDBName,
+		Table:  db.UserWalletTableName,
+		Filter: "t.user_id = $1",
+		References: []Reference{
+			{Column: "user_id", Table: db.UserTableName, OnMissing: MissingFail},
+			{Column: "wallet_id", Table: db.WalletTableName, OnMissing: MissingFail},
+		},
+	},
+	{
+		DB:     db.WalletServiceDBName,
+		Table:  db.WalletMemberTableName,
+		Filter: "t.wallet_id IN (SELECT wallet_id FROM " + db.UserWalletTableName + " WHERE user_id = $1)",
+		References: []Reference{
+			{Column: "wallet_id", Table: db.WalletTableName, OnMissing: MissingFail},
+			{Column: "user_id", Table: db.UserTableName, OnMissing: MissingNull},
+		},
+	},
+	{
+		DB:     db.WalletServiceDBName,
+		Table:  db.CategoryTableName,
+		Filter: "t.user_id = $1",
+		References: []Reference{
+			{Column: "user_id", Table: db.UserTableName, OnMissing: MissingFail},
+		},
+	},
+	{
+		DB:     db.WalletServiceDBName,
+		Table:  db.TransactionTableName,
+		Filter: "t.wallet_id IN (SELECT wallet_id FROM " + db.UserWalletTableName + " WHERE user_id = $1)",
+		References: []Reference{
+			{Column: "wallet_id", Table: db.WalletTableName, OnMissing: MissingFail},
+			{Column: "category_id", Table: db.CategoryTableName, OnMissing: MissingKeep},
+		},
+	},
+}
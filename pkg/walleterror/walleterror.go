@@ -0,0 +1,171 @@
+// Package walleterror defines the wallet domain errors that cross the gRPC boundary. The wallet service returns them
+// as statuses carrying an ErrorInfo detail (Domain, Reason), and clients map them back to the same errors, so the
+// caller answers 404/403/409 instead of a generic 500.
+package walleterror
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/mystaline/clefinport-be/pkg/entity"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Domain of the ErrorInfo details of wallet errors.
+const Domain = "wallet.clefinport"
+
+type Reason string
+
+const (
+	ReasonWalletNotFound      Reason = "WALLET_NOT_FOUND"
+	ReasonNotAMember          Reason = "NOT_A_MEMBER"
+	ReasonInsufficientBalance Reason = "INSUFFICIENT_BALANCE"
+)
+
+// Sentinels to match with errors.Is, whatever the metadata of the error.
+var (
+	ErrWalletNotFound      = &Error{Reason: ReasonWalletNotFound}
+	ErrNotAMember          = &Error{Reason: ReasonNotAMember}
+	ErrInsufficientBalance = &Error{Reason: ReasonInsufficientBalance}
+)
+
+// Error is a wallet domain error, Metadata holds the ids it is about.
+type Error struct {
+	Reason   Reason            `json:"reason"`
+	Message  string            `json:"message"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+func WalletNotFound(walletID string) *Error {
+	return &Error{
+		Reason:   ReasonWalletNotFound,
+		Message:  "wallet not found",
+		Metadata: map[string]string{"walletId": walletID},
+	}
+}
+
+func NotAMember(walletID, userID string) *Error {
+	return &Error{
+		Reason:   ReasonNotAMember,
+		Message:  "user is not a member of the wallet",
+		Metadata: map[string]string{"walletId": walletID, "userId": userID},
+	}
+}
+
+func InsufficientBalance(walletID string, balance, amount int64) *Error {
+	return &Error{
+		Reason:  ReasonInsufficientBalance,
+		Message: fmt.Sprintf("balance of %d is not enough for %d", balance, amount),
+		Metadata: map[string]string{
+			"walletId": walletID,
+			"balance":  strconv.FormatInt(balance, 10),
+			"amount":   strconv.FormatInt(amount, 10),
+		},
+	}
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return string(e.Reason)
+	}
+
+	return e.Message
+}
+
+// Is matches errors of the same reason.
+func (e *Error) Is(target error) bool {
+	other, ok := target.(*Error)
+	return ok && other.Reason == e.Reason
+}
+
+func (e *Error) code() codes.Code {
+	switch e.Reason {
+	case ReasonWalletNotFound:
+		return codes.NotFound
+	case ReasonNotAMember:
+		return codes.PermissionDenied
+	case ReasonInsufficientBalance:
+		return codes.FailedPrecondition
+	}
+
+	return codes.Internal
+}
+
+// GRPCStatus is used by grpc servers, also when e is wrapped, e.g. in the Err of an entity.HttpError.
+func (e *Error) GRPCStatus() *status.Status {
+	st := status.New(e.code(), e.Error())
+
+	detailed, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   string(e.Reason),
+		Domain:   Domain,
+		Metadata: e.Metadata,
+	})
+	if err != nil {
+		return st
+	}
+
+	return detailed
+}
+
+// HttpError is used by entity.ToHttpError.
+func (e *Error) HttpError() *entity.HttpError {
+	var httpErr *entity.HttpError
+	switch e.Reason {
+	case ReasonWalletNotFound:
+		httpErr = entity.NotFound(e.Error())
+	case ReasonNotAMember:
+		httpErr = entity.Forbidden(e.Error())
+	case ReasonInsufficientBalance:
+		httpErr = entity.Conflict(e.Error())
+	default:
+		httpErr = entity.InternalServerError(e.Error())
+	}
+	httpErr.Err = e
+
+	return httpErr
+}
+
+// FromGRPC returns the wallet Error carried by the status of err, err unchanged when there is none.
+func FromGRPC(err error) error {
+	if err == nil {
+		return nil
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	for _, detail := range st.Details() {
+		info, ok := detail.(*errdetails.ErrorInfo)
+		if !ok || info.GetDomain() != Domain {
+			continue
+		}
+
+		return &Error{
+			Reason:   Reason(info.GetReason()),
+			Message:  st.Message(),
+			Metadata: info.GetMetadata(),
+		}
+	}
+
+	return err
+}
+
+// UnaryClientInterceptor maps the statuses of wallet errors back to *Error, see FromGRPC.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		return FromGRPC(invoker(ctx, method, req, reply, cc, opts...))
+	}
+}
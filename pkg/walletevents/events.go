@@ -0,0 +1,138 @@
+package walletevents
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/mystaline/clefinport-be/pkg/enum"
+)
+
+// Event-sourced storage of wallet activity, opt-in per wallet: once a wallet's stream is opened (Store.Open),
+// its activity is appended as events to wallet_events and a projector keeps the transactions and user_wallets
+// tables up to date in the same transaction, so every reader of those tables keeps working unchanged.
+// Events are never updated nor deleted, which gives the full history of the wallet and balance time travel
+// (Store.BalanceAsOf) beyond what the amended transactions rows still tell.
+//
+// The stream starts with a stream.opened event holding the balance at that time, history before it is not known.
+//
+// Expected table:
+//
+//	CREATE TABLE wallet_events (
+//	    id         bigint      PRIMARY KEY,
+//	    wallet_id  bigint      NOT NULL,
+//	    sequence   bigint      NOT NULL,
+//	    event_type text        NOT NULL,
+//	    payload    jsonb       NOT NULL,
+//	    created_at timestamptz NOT NULL,
+//	    updated_at timestamptz NOT NULL,
+//	    UNIQUE (wallet_id, sequence)
+//	);
+
+type EventType string
+
+const (
+	EventStreamOpened        EventType = "stream.opened"
+	EventTransactionRecorded EventType = "transaction.recorded"
+	EventTransactionAmended  EventType = "transaction.amended"
+	EventTransferCompleted   EventType = "transfer.completed"
+)
+
+// Event is one stored event, Sequence orders the events of a wallet.
+type Event struct {
+	ID         int64           `json:"id"         column:"id"`
+	WalletID   string          `json:"walletId"   column:"wallet_id::text"`
+	Sequence   int64           `json:"sequence"   column:"sequence"`
+	Type       EventType       `json:"type"       column:"event_type"`
+	Payload    json.RawMessage `json:"payload"    column:"payload"`
+	OccurredAt time.Time       `json:"occurredAt" column:"created_at"`
+}
+
+type insertEvent struct {
+	WalletID string          `json:"walletId"  column:"wallet_id"`
+	Sequence int64           `json:"sequence"  column:"sequence"`
+	Type     EventType       `json:"eventType" column:"event_type"`
+	Payload  json.RawMessage `json:"payload"   column:"payload"`
+}
+
+// StreamOpened starts the stream of a wallet.
+type StreamOpened struct {
+	Balance int64 `json:"balance"`
+}
+
+// TransactionRecorded is an income or an expense, TransactionID is set by the projector.
+type TransactionRecorded struct {
+	TransactionID string               `json:"transactionId"`
+	UserID        string               `json:"userId"`
+	Type          enum.TransactionType `json:"type"`
+	Amount        int64                `json:"amount"`
+	Note          string               `json:"note"`
+	Date          time.Time            `json:"date"`
+}
+
+// TransactionAmended corrects a recorded transaction, the previous values are set by the projector.
+type TransactionAmended struct {
+	TransactionID  string               `json:"transactionId"`
+	Type           enum.TransactionType `json:"type"`
+	Amount         int64                `json:"amount"`
+	PreviousAmount int64                `json:"previousAmount"`
+	Note           string               `json:"note"`
+	Date           time.Time            `json:"date"`
+}
+
+// TransferCompleted moves Amount between two wallets, it is appended to the stream of each event-sourced one.
+// The transaction ids are set by the projector.
+type TransferCompleted struct {
+	FromWalletID      string    `json:"fromWalletId"`
+	ToWalletID        string    `json:"toWalletId"`
+	UserID            string    `json:"userId"`
+	Amount            int64     `json:"amount"`
+	Note              string    `json:"note"`
+	Date              time.Time `json:"date"`
+	FromTransactionID string    `json:"fromTransactionId"`
+	ToTransactionID   string    `json:"toTransactionId"`
+}
+
+// signedAmount is the balance change of a transaction row, following the ledger's rules (see pkg/ledger):
+// income adds, expense subtracts, transfer rows carry their sign.
+func signedAmount(transactionType enum.TransactionType, amount int64) int64 {
+	if transactionType == enum.TransactionExpense {
+		return -amount
+	}
+
+	return amount
+}
+
+// apply returns balance after event of walletID's stream.
+func apply(balance int64, walletID string, event Event) (int64, error) {
+	switch event.Type {
+	case EventStreamOpened:
+		var data StreamOpened
+		if err := json.Unmarshal(event.Payload, &data); err != nil {
+			return 0, err
+		}
+		return data.Balance, nil
+	case EventTransactionRecorded:
+		var data TransactionRecorded
+		if err := json.Unmarshal(event.Payload, &data); err != nil {
+			return 0, err
+		}
+		return balance + signedAmount(data.Type, data.Amount), nil
+	case EventTransactionAmended:
+		var data TransactionAmended
+		if err := json.Unmarshal(event.Payload, &data); err != nil {
+			return 0, err
+		}
+		return balance + signedAmount(data.Type, data.Amount) - signedAmount(data.Type, data.PreviousAmount), nil
+	case EventTransferCompleted:
+		var data TransferCompleted
+		if err := json.Unmarshal(event.Payload, &data); err != nil {
+			return 0, err
+		}
+		if data.FromWalletID == walletID {
+			return balance - data.Amount, nil
+		}
+		return balance + data.Amount, nil
+	}
+
+	return balance, nil
+}
@@ -0,0 +1,155 @@
+package walletevents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mystaline/clefinport-be/pkg/db"
+	"github.com/mystaline/clefinport-be/pkg/enum"
+	"github.com/mystaline/clefinport-be/pkg/sql_query"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// The projector keeps the current state tables in sync with the appended events, inside the append transaction.
+// Balances are stored on every user_wallets row of the wallet (see pkg/ledger), each one moves by the event's delta.
+
+type insertTransaction struct {
+	WalletID string               `json:"walletId" column:"wallet_id"`
+	UserID   string               `json:"userId"   column:"user_id"`
+	Type     enum.TransactionType `json:"type"     column:"type"`
+	Amount   int64                `json:"amount"   column:"amount"`
+	Note     string               `json:"note"     column:"note"`
+	Date     time.Time            `json:"date"     column:"date"`
+}
+
+type storedTransaction struct {
+	Type   enum.TransactionType `json:"type"   column:"type"`
+	Amount int64                `json:"amount" column:"amount"`
+}
+
+type storedBalance struct {
+	Balance int64 `json:"balance" column:"balance"`
+}
+
+func (s *Store) insertTransaction(
+	ctx context.Context,
+	walletID string,
+	userID string,
+	transactionType enum.TransactionType,
+	amount int64,
+	note string,
+	date time.Time,
+) (string, error) {
+	if date.IsZero() {
+		date = time.Now()
+	}
+
+	id, err := s.Service.InsertOneWithData(ctx, db.TransactionTableName, insertTransaction{
+		WalletID: walletID,
+		UserID:   userID,
+		Type:     transactionType,
+		Amount:   amount,
+		Note:     note,
+		Date:     date,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprint(id), nil
+}
+
+func (s *Store) transaction(ctx context.Context, walletID, transactionID string) (storedTransaction, error) {
+	query, args, err := sql_query.NewSQLSelectBuilder[storedTransaction](db.TransactionTableName).
+		Where(map[string]sql_query.SQLCondition{
+			"id":         {Operator: sql_query.SQLOperatorEqual, Value: transactionID},
+			"wallet_id":  {Operator: sql_query.SQLOperatorEqual, Value: walletID},
+			"deleted_at": {Operator: sql_query.SQLOperatorIsNull},
+		}).
+		SetLimit(1).
+		Build()
+	if err != nil {
+		return storedTransaction{}, err
+	}
+
+	var transaction storedTransaction
+	err = s.Service.SelectOne(&transaction, ctx, query, args...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return storedTransaction{}, ErrTransactionNotFound
+	}
+
+	return transaction, err
+}
+
+func (s *Store) updateTransaction(ctx context.Context, data TransactionAmended) error {
+	values := map[string]any{
+		"amount": data.Amount,
+		"note":   data.Note,
+	}
+	if !data.Date.IsZero() {
+		values["date"] = data.Date
+	}
+
+	query, args, err := sql_query.NewSQLUpdateBuilder(db.TransactionTableName).
+		Update(values).
+		Where(map[string]sql_query.SQLCondition{
+			"id": {Operator: sql_query.SQLOperatorEqual, Value: data.TransactionID},
+		}).
+		Build()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.Service.UpdateOne(ctx, query, args...)
+	return err
+}
+
+// balance returns the stored balance of walletID, the same on each of its user_wallets rows.
+func (s *Store) balance(ctx context.Context, walletID string) (int64, error) {
+	query, args, err := sql_query.NewSQLSelectBuilder[storedBalance](db.UserWalletTableName).
+		Where(map[string]sql_query.SQLCondition{
+			"wallet_id": {Operator: sql_query.SQLOperatorEqual, Value: walletID},
+		}).
+		SetLimit(1).
+		Build()
+	if err != nil {
+		return 0, err
+	}
+
+	var stored storedBalance
+	err = s.Service.SelectOne(&stored, ctx, query, args...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrWalletNotFound
+	}
+
+	return stored.Balance, err
+}
+
+func (s *Store) addToBalance(ctx context.Context, walletID string, delta int64) error {
+	if delta == 0 {
+		return nil
+	}
+
+	query, args, err := sql_query.NewSQLUpdateBuilder(db.UserWalletTableName).
+		Increment(map[string]any{"balance": delta}).
+		Where(map[string]sql_query.SQLCondition{
+			"wallet_id": {Operator: sql_query.SQLOperatorEqual, Value: walletID},
+		}).
+		Build()
+	if err != nil {
+		return err
+	}
+
+	updated, err := s.Service.UpdateMany(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return ErrWalletNotFound
+	}
+
+	return nil
+}
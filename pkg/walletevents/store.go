@@ -0,0 +1,314 @@
+package walletevents
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/mystaline/clefinport-be/pkg/db"
+	"github.com/mystaline/clefinport-be/pkg/enum"
+	"github.com/mystaline/clefinport-be/pkg/service"
+	"github.com/mystaline/clefinport-be/pkg/sql_query"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrNotEventSourced     = errors.New("wallet is not event sourced")
+	ErrStreamExists        = errors.New("wallet is already event sourced")
+	ErrBeforeStream        = errors.New("the wallet's history starts later")
+	ErrWalletNotFound      = errors.New("wallet not found")
+	ErrTransactionNotFound = errors.New("transaction not found")
+	ErrInvalidEvent        = errors.New("invalid event")
+)
+
+// Store appends the events of wallet streams and projects them, each write is one transaction.
+// Appends to a stream are serialized by an advisory lock on the wallet, Sequence has no gap.
+type Store struct {
+	Service service.PostgreSqlService
+}
+
+func MakeStore(svc service.PostgreSqlService) *Store {
+	return &Store{Service: svc}
+}
+
+// Open makes walletID event sourced, starting its stream with the current balance.
+func (s *Store) Open(ctx context.Context, walletID string) (Event, error) {
+	return s.inTransaction(ctx, func() (Event, error) {
+		if err := s.lock(ctx, walletID); err != nil {
+			return Event{}, err
+		}
+
+		sequence, err := s.lastSequence(ctx, walletID)
+		if err != nil {
+			return Event{}, err
+		}
+		if sequence > 0 {
+			return Event{}, ErrStreamExists
+		}
+
+		balance, err := s.balance(ctx, walletID)
+		if err != nil {
+			return Event{}, err
+		}
+
+		return s.append(ctx, walletID, 1, EventStreamOpened, StreamOpened{Balance: balance})
+	})
+}
+
+// IsEventSourced reports whether the stream of walletID was opened.
+func (s *Store) IsEventSourced(ctx context.Context, walletID string) (bool, error) {
+	sequence, err := s.lastSequence(ctx, walletID)
+	return sequence > 0, err
+}
+
+// RecordTransaction records an income or an expense of an event-sourced wallet.
+func (s *Store) RecordTransaction(ctx context.Context, walletID string, data TransactionRecorded) (Event, error) {
+	if data.Type != enum.TransactionIncome && data.Type != enum.TransactionExpense {
+		return Event{}, fmt.Errorf("%w: transaction type must be %s or %s", ErrInvalidEvent, enum.TransactionIncome, enum.TransactionExpense)
+	}
+	if data.Amount <= 0 {
+		return Event{}, fmt.Errorf("%w: amount must be positive", ErrInvalidEvent)
+	}
+
+	return s.inTransaction(ctx, func() (Event, error) {
+		sequence, err := s.lockStream(ctx, walletID)
+		if err != nil {
+			return Event{}, err
+		}
+
+		data.TransactionID, err = s.insertTransaction(ctx, walletID, data.UserID, data.Type, data.Amount, data.Note, data.Date)
+		if err != nil {
+			return Event{}, err
+		}
+		if err := s.addToBalance(ctx, walletID, signedAmount(data.Type, data.Amount)); err != nil {
+			return Event{}, err
+		}
+
+		return s.append(ctx, walletID, sequence+1, EventTransactionRecorded, data)
+	})
+}
+
+// AmendTransaction corrects the amount, note and date of a transaction of an event-sourced wallet.
+// Transfers can't be amended, each side would drift from the other.
+func (s *Store) AmendTransaction(ctx context.Context, walletID string, data TransactionAmended) (Event, error) {
+	if data.Amount <= 0 {
+		return Event{}, fmt.Errorf("%w: amount must be positive", ErrInvalidEvent)
+	}
+
+	return s.inTransaction(ctx, func() (Event, error) {
+		sequence, err := s.lockStream(ctx, walletID)
+		if err != nil {
+			return Event{}, err
+		}
+
+		current, err := s.transaction(ctx, walletID, data.TransactionID)
+		if err != nil {
+			return Event{}, err
+		}
+		if current.Type == enum.TransactionTransfer {
+			return Event{}, fmt.Errorf("%w: transfers can't be amended", ErrInvalidEvent)
+		}
+
+		data.Type = current.Type
+		data.PreviousAmount = current.Amount
+		if err := s.updateTransaction(ctx, data); err != nil {
+			return Event{}, err
+		}
+		delta := signedAmount(data.Type, data.Amount) - signedAmount(data.Type, data.PreviousAmount)
+		if err := s.addToBalance(ctx, walletID, delta); err != nil {
+			return Event{}, err
+		}
+
+		return s.append(ctx, walletID, sequence+1, EventTransactionAmended, data)
+	})
+}
+
+// CompleteTransfer moves the amount between two wallets, at least one of them must be event sourced.
+// The event is appended to the stream of each event-sourced one, the returned events follow.
+func (s *Store) CompleteTransfer(ctx context.Context, data TransferCompleted) ([]Event, error) {
+	if data.FromWalletID == data.ToWalletID {
+		return nil, fmt.Errorf("%w: transfer to the same wallet", ErrInvalidEvent)
+	}
+	if data.Amount <= 0 {
+		return nil, fmt.Errorf("%w: amount must be positive", ErrInvalidEvent)
+	}
+
+	return inTransaction(ctx, s.Service, func() ([]Event, error) {
+		// Always locked in the same order, two opposite transfers would deadlock otherwise
+		walletIDs := []string{data.FromWalletID, data.ToWalletID}
+		slices.Sort(walletIDs)
+		sequences := map[string]int64{}
+		for _, walletID := range walletIDs {
+			if err := s.lock(ctx, walletID); err != nil {
+				return nil, err
+			}
+
+			sequence, err := s.lastSequence(ctx, walletID)
+			if err != nil {
+				return nil, err
+			}
+			if sequence > 0 {
+				sequences[walletID] = sequence
+			}
+		}
+		if len(sequences) == 0 {
+			return nil, ErrNotEventSourced
+		}
+
+		var err error
+		data.FromTransactionID, err = s.insertTransaction(ctx, data.FromWalletID, data.UserID, enum.TransactionTransfer, -data.Amount, data.Note, data.Date)
+		if err != nil {
+			return nil, err
+		}
+		data.ToTransactionID, err = s.insertTransaction(ctx, data.ToWalletID, data.UserID, enum.TransactionTransfer, data.Amount, data.Note, data.Date)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.addToBalance(ctx, data.FromWalletID, -data.Amount); err != nil {
+			return nil, err
+		}
+		if err := s.addToBalance(ctx, data.ToWalletID, data.Amount); err != nil {
+			return nil, err
+		}
+
+		events := []Event{}
+		for _, walletID := range []string{data.FromWalletID, data.ToWalletID} {
+			sequence, ok := sequences[walletID]
+			if !ok {
+				continue
+			}
+
+			event, err := s.append(ctx, walletID, sequence+1, EventTransferCompleted, data)
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, event)
+		}
+
+		return events, nil
+	})
+}
+
+// BalanceAsOf replays the stream of walletID up to asOf, the time the events were recorded.
+// ErrBeforeStream is returned for a time before the stream was opened.
+func (s *Store) BalanceAsOf(ctx context.Context, walletID string, asOf time.Time) (int64, error) {
+	var balance int64
+	var replayed int
+	err := s.EachEvent(ctx, walletID, asOf, func(event Event) error {
+		var err error
+		balance, err = apply(balance, walletID, event)
+		replayed++
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if replayed == 0 {
+		sourced, err := s.IsEventSourced(ctx, walletID)
+		if err != nil {
+			return 0, err
+		}
+		if sourced {
+			return 0, ErrBeforeStream
+		}
+		return 0, ErrNotEventSourced
+	}
+
+	return balance, nil
+}
+
+// EachEvent calls fn with the events of walletID recorded until the given time, in stream order.
+func (s *Store) EachEvent(ctx context.Context, walletID string, until time.Time, fn func(event Event) error) error {
+	query, args, err := sql_query.NewSQLSelectBuilder[Event](db.WalletEventTableName).
+		Where(map[string]sql_query.SQLCondition{
+			"wallet_id":  {Operator: sql_query.SQLOperatorEqual, Value: walletID},
+			"created_at": {Operator: sql_query.SQLOperatorLTE, Value: until},
+		}).
+		OrderBy([]string{"sequence"}, true).
+		Build()
+	if err != nil {
+		return err
+	}
+
+	return service.SelectEach(ctx, s.Service, query, args, fn)
+}
+
+func (s *Store) inTransaction(ctx context.Context, fn func() (Event, error)) (Event, error) {
+	return inTransaction(ctx, s.Service, fn)
+}
+
+func inTransaction[T any](ctx context.Context, svc service.PostgreSqlService, fn func() (T, error)) (T, error) {
+	return service.UseTransactions(ctx, svc.GetPool(), func(tx pgx.Tx) (T, error) {
+		svc.SetTransaction(tx)
+		defer svc.SetTransaction(nil)
+
+		return fn()
+	})
+}
+
+// lock serializes the appends to the stream of walletID until the transaction ends.
+func (s *Store) lock(ctx context.Context, walletID string) error {
+	_, err := s.Service.GetTransaction().Exec(ctx, "SELECT pg_advisory_xact_lock(hashtextextended($1, 0))", "wallet-events:"+walletID)
+	return err
+}
+
+// lockStream locks the stream of walletID and returns its last sequence, ErrNotEventSourced when it isn't opened.
+func (s *Store) lockStream(ctx context.Context, walletID string) (int64, error) {
+	if err := s.lock(ctx, walletID); err != nil {
+		return 0, err
+	}
+
+	sequence, err := s.lastSequence(ctx, walletID)
+	if err != nil {
+		return 0, err
+	}
+	if sequence == 0 {
+		return 0, ErrNotEventSourced
+	}
+
+	return sequence, nil
+}
+
+func (s *Store) lastSequence(ctx context.Context, walletID string) (int64, error) {
+	query, args, err := sql_query.NewSQLSelectBuilder[any](db.WalletEventTableName).
+		Select("COALESCE(MAX(sequence), 0)").
+		Where(map[string]sql_query.SQLCondition{
+			"wallet_id": {Operator: sql_query.SQLOperatorEqual, Value: walletID},
+		}).
+		Build()
+	if err != nil {
+		return 0, err
+	}
+
+	sequence, err := s.Service.Count(ctx, query, args...)
+	return int64(sequence), err
+}
+
+func (s *Store) append(ctx context.Context, walletID string, sequence int64, eventType EventType, data any) (Event, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return Event{}, err
+	}
+
+	query, args, err := sql_query.NewSQLInsertBuilder(db.WalletEventTableName).
+		Insert(
+			insertEvent{WalletID: walletID, Sequence: sequence, Type: eventType, Payload: payload},
+			`id`, `wallet_id::text AS "walletId"`, `sequence`, `event_type AS "type"`, `payload`, `created_at AS "occurredAt"`,
+		).
+		Build()
+	if err != nil {
+		return Event{}, err
+	}
+
+	var event Event
+	if err := s.Service.SelectOne(&event, ctx, query, args...); err != nil {
+		return Event{}, err
+	}
+
+	return event, nil
+}
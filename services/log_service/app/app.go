@@ -1,19 +1,31 @@
 package app
 
 import (
+	"context"
 	"os"
+	"time"
 
+	"github.com/mystaline/clefinport-be/pkg/db"
+	"github.com/mystaline/clefinport-be/pkg/delivery"
+	"github.com/mystaline/clefinport-be/pkg/jobqueue"
+	"github.com/mystaline/clefinport-be/pkg/leader"
 	"github.com/mystaline/clefinport-be/pkg/provider"
+	"github.com/mystaline/clefinport-be/pkg/tablestats"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/swagger"
-	// user_route "github.com/mystaline/clefinport-be/services/log_service/internal/route"
+
+	log_route "github.com/mystaline/clefinport-be/services/log_service/internal/route"
 )
 
 type App struct {
 	app *fiber.App
+
+	readiness   *delivery.Readiness
+	tableStats  *tablestats.Monitor
+	maintenance *leader.Runner
 }
 
 func MakeApp() *App {
@@ -25,7 +37,27 @@ func MakeApp() *App {
 func (a *App) Run(
 	serviceProvider provider.IServiceProvider,
 ) {
+	a.Setup(serviceProvider)
+
+	go warmup(a.readiness)
+	a.tableStats.Start(context.Background())
+	a.maintenance.Start(context.Background())
+
+	port := os.Getenv("SERVICE_PORT")
+	if port == "" {
+		port = "8080"
+	}
+
+	a.app.Listen(":" + port)
+}
+
+// Setup registers the middleware chain and the routes without starting anything,
+// tests drive the returned app with app.Test (see pkg/snapshot).
+func (a *App) Setup(
+	serviceProvider provider.IServiceProvider,
+) *fiber.App {
 	a.app.Use(cors.New())
+	a.app.Use(delivery.Compression())
 
 	swaggerURL := "doc.json"
 	env := os.Getenv("ENV")
@@ -34,14 +66,20 @@ func (a *App) Run(
 	}
 	a.app.Get("/docs/*", swagger.New(swagger.Config{URL: swaggerURL}))
 
-	setupRoute(a.app, serviceProvider)
+	a.readiness = &delivery.Readiness{}
+	a.readiness.Register(a.app)
 
-	port := os.Getenv("SERVICE_PORT")
-	if port == "" {
-		port = "8080"
-	}
+	// Registered before the request logger and authentication, Prometheus scrapes it every few seconds
+	a.tableStats = tablestats.MakeMonitor(db.LogServiceDBName, serviceProvider.MakeService(db.LogServiceDBName), tablestats.Config{})
+	a.app.Get("/metrics", tablestats.Handler(a.tableStats))
 
-	a.app.Listen(":" + port)
+	// Run by a single replica at a time
+	a.maintenance = maintenanceRunner(serviceProvider)
+	a.app.Get("/metrics/maintenance", leader.Handler(a.maintenance))
+
+	setupRoute(a.app, serviceProvider)
+
+	return a.app
 }
 
 func setupRoute(
@@ -51,5 +89,19 @@ func setupRoute(
 	// app.Use(util_middleware.ValidateJWTSQL())
 	app.Use(logger.New())
 
-	// user_route.SetupDashboardController(app, serviceProvider)
+	log_route.SetupAdminController(context.Background(), app, serviceProvider)
+}
+
+func maintenanceRunner(serviceProvider provider.IServiceProvider) *leader.Runner {
+	svc := serviceProvider.MakeService(db.LogServiceDBName)
+
+	return leader.MakeRunner(leader.MakeElector("log-maintenance", svc, leader.Config{}), leader.Task{
+		Name:     "job-queue-retention",
+		Interval: time.Hour,
+		Timeout:  5 * time.Minute,
+		Run: func(ctx context.Context) error {
+			_, err := jobqueue.Prune(ctx, svc, 30*24*time.Hour)
+			return err
+		},
+	})
 }
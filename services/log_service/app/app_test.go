@@ -0,0 +1,71 @@
+package app
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/mystaline/clefinport-be/pkg/audit"
+	"github.com/mystaline/clefinport-be/pkg/dto"
+	"github.com/mystaline/clefinport-be/pkg/privacy"
+	"github.com/mystaline/clefinport-be/pkg/provider"
+	"github.com/mystaline/clefinport-be/pkg/service"
+	"github.com/mystaline/clefinport-be/pkg/snapshot"
+)
+
+func TestListAuditLogsSnapshot(t *testing.T) {
+	t.Setenv("SUPPORT_ACCESS_TOKEN", "support-token")
+
+	svc := &service.MockBasePostgreSqlService{}
+	svc.On("Debug", mock.Anything).Maybe()
+	svc.On("SelectMany", mock.MatchedBy(func(*[]dto.PaginationResult[audit.StoredRecord]) bool { return true }), mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			*args.Get(0).(*[]dto.PaginationResult[audit.StoredRecord]) = []dto.PaginationResult[audit.StoredRecord]{{
+				TotalRecords: 1,
+				Data: []audit.StoredRecord{{
+					ID:         "1844674407370955",
+					Actor:      "support@clefinport.test",
+					ActorType:  "support",
+					Action:     "admin.sql_console",
+					Resource:   "/v1/admin/sql",
+					Result:     "allowed",
+					Channel:    "http",
+					OccurredAt: "2026-10-17T08:30:00Z",
+				}},
+			}}
+		}).
+		Return(nil)
+	// The export worker polls its queue in the background
+	svc.On("SelectMany", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	serviceProvider := new(provider.MockServiceProvider)
+	serviceProvider.On("MakeService", mock.Anything).Return(svc)
+
+	server := MakeApp().Setup(serviceProvider)
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+	}{
+		{
+			name: "list_audit_logs",
+			headers: map[string]string{
+				privacy.SupportTokenHeader:     "support-token",
+				privacy.SupportRequesterHeader: "support@clefinport.test",
+			},
+		},
+		{name: "list_audit_logs_without_support_access"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/v1/admin/audit-logs?page=1&limit=20", nil)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+
+			snapshot.MatchResponse(t, server, req, tt.name)
+		})
+	}
+}
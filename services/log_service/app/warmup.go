@@ -0,0 +1,39 @@
+package app
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"github.com/mystaline/clefinport-be/pkg/audit"
+	"github.com/mystaline/clefinport-be/pkg/db"
+	"github.com/mystaline/clefinport-be/pkg/delivery"
+	"github.com/mystaline/clefinport-be/pkg/schemacheck"
+	"github.com/mystaline/clefinport-be/pkg/service"
+	"github.com/mystaline/clefinport-be/pkg/sql_query"
+)
+
+// warmup pre-establishes pool connections and primes builder caches, then marks the service ready.
+// Set DB_WARMUP_CANARY=true to also run a canary query on each warmed connection.
+// Set SCHEMA_CHECK=true to verify DTO column tags against the schema, exiting on mismatch.
+func warmup(readiness *delivery.Readiness) {
+	config := db.WarmupConfig{Canary: os.Getenv("DB_WARMUP_CANARY") == "true"}
+
+	if err := db.WarmupPool(context.Background(), db.LogServiceDBName, config); err != nil {
+		log.Printf("warmup failed, service stays unready: %v", err)
+		return
+	}
+
+	sql_query.Prime[audit.StoredRecord]()
+
+	if os.Getenv("SCHEMA_CHECK") == "true" {
+		err := schemacheck.Verify(context.Background(), service.MakeService(db.LogServiceDBName),
+			schemacheck.For[audit.StoredRecord](db.EventLogTableName),
+		)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	readiness.MarkReady()
+}
@@ -0,0 +1,213 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/mystaline/clefinport-be/services/log_service/internal/usecase"
+
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/mystaline/clefinport-be/pkg/audit"
+	"github.com/mystaline/clefinport-be/pkg/delivery"
+	"github.com/mystaline/clefinport-be/pkg/dto"
+	"github.com/mystaline/clefinport-be/pkg/entity"
+	"github.com/mystaline/clefinport-be/pkg/exportjob"
+	"github.com/mystaline/clefinport-be/pkg/parser"
+	"github.com/mystaline/clefinport-be/pkg/privacy"
+)
+
+type AdminController struct {
+	Timeout time.Duration
+
+	ListAuditLogsUsecase   entity.UseCase[usecase.ListAuditLogsParam, *dto.PaginationResult[audit.StoredRecord]]
+	ExportAuditLogsUsecase entity.UseCase[usecase.ExportAuditLogsParam, int]
+
+	CreateAuditLogExportUsecase entity.UseCase[usecase.CreateAuditLogExportParam, *exportjob.Status]
+	GetExportJobUsecase         entity.UseCase[usecase.GetExportJobParam, *exportjob.Status]
+	DownloadExportJobUsecase    entity.UseCase[usecase.DownloadExportJobParam, *usecase.ExportFile]
+}
+
+func MakeAdminController(
+	timeout time.Duration,
+
+	listAuditLogsUseCase entity.UseCase[usecase.ListAuditLogsParam, *dto.PaginationResult[audit.StoredRecord]],
+	exportAuditLogsUseCase entity.UseCase[usecase.ExportAuditLogsParam, int],
+	createAuditLogExportUseCase entity.UseCase[usecase.CreateAuditLogExportParam, *exportjob.Status],
+	getExportJobUseCase entity.UseCase[usecase.GetExportJobParam, *exportjob.Status],
+	downloadExportJobUseCase entity.UseCase[usecase.DownloadExportJobParam, *usecase.ExportFile],
+) *AdminController {
+	return &AdminController{
+		Timeout:                     timeout,
+		ListAuditLogsUsecase:        listAuditLogsUseCase,
+		ExportAuditLogsUsecase:      exportAuditLogsUseCase,
+		CreateAuditLogExportUsecase: createAuditLogExportUseCase,
+		GetExportJobUsecase:         getExportJobUseCase,
+		DownloadExportJobUsecase:    downloadExportJobUseCase,
+	}
+}
+
+// @Summary      List Audit Logs
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Success      200 {object} "Successfully get audit logs"
+// @Router       /api/v1/admin/audit-logs [get]
+func (c *AdminController) ListAuditLogs(ctx *fiber.Ctx) error {
+	filter, err := parser.ParseQuery[audit.ListFilter](ctx.Queries())
+	if err != nil {
+		return entity.BadRequest("invalid query").SendResponse(ctx)
+	}
+
+	return delivery.RunHTTPWithTimeout(
+		ctx,
+		c.Timeout,
+		func(ctxWithTimeout context.Context) (*dto.PaginationResult[audit.StoredRecord], *entity.HttpError) {
+			c.ListAuditLogsUsecase.InitService()
+
+			param := usecase.ListAuditLogsParam{
+				Ctx:    ctxWithTimeout,
+				Filter: *filter,
+			}
+
+			res, err := c.ListAuditLogsUsecase.Invoke(param)
+			if err != nil {
+				e := entity.ToHttpError(err)
+				return nil, e
+			}
+
+			return res, nil
+		}, "Successfully get audit logs", fiber.StatusOK,
+	)
+}
+
+// @Summary      Export Audit Logs
+// @Description  Streams every matching audit record, newest first, without pagination.
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Success      200 {object} "Successfully export audit logs"
+// @Router       /api/v1/admin/audit-logs/export [get]
+func (c *AdminController) ExportAuditLogs(ctx *fiber.Ctx) error {
+	filter, err := parser.ParseQuery[audit.ListFilter](ctx.Queries())
+	if err != nil {
+		return entity.BadRequest("invalid query").SendResponse(ctx)
+	}
+
+	c.ExportAuditLogsUsecase.InitService()
+
+	// Exports run far longer than regular reads
+	return delivery.StreamJSONArray(
+		ctx,
+		10*c.Timeout,
+		func(streamCtx context.Context, emit delivery.EmitFunc[audit.StoredRecord]) error {
+			param := usecase.ExportAuditLogsParam{
+				Ctx:    streamCtx,
+				Filter: *filter,
+				Emit:   emit,
+			}
+
+			_, err := c.ExportAuditLogsUsecase.Invoke(param)
+			return err
+		}, "Successfully export audit logs",
+	)
+}
+
+// @Summary      Create Audit Log Export Job
+// @Description  Exports every matching audit record to a file in the background, poll the returned job for its download URL.
+// @Description  The same request, or the same Idempotency-Key header, returns the existing job.
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Success      202 {object} "Successfully create audit log export"
+// @Router       /api/v1/admin/audit-logs/export-jobs [post]
+func (c *AdminController) CreateAuditLogExport(ctx *fiber.Ctx) error {
+	filter, err := parser.ParseQuery[audit.ListFilter](ctx.Queries())
+	if err != nil {
+		return entity.BadRequest("invalid query").SendResponse(ctx)
+	}
+
+	requester := ctx.Get(privacy.SupportRequesterHeader)
+	idempotencyKey := ctx.Get("Idempotency-Key")
+
+	return delivery.RunHTTPWithTimeout(
+		ctx,
+		c.Timeout,
+		func(ctxWithTimeout context.Context) (*exportjob.Status, *entity.HttpError) {
+			c.CreateAuditLogExportUsecase.InitService()
+
+			param := usecase.CreateAuditLogExportParam{
+				Ctx:            ctxWithTimeout,
+				Filter:         *filter,
+				Requester:      requester,
+				IdempotencyKey: idempotencyKey,
+			}
+
+			res, err := c.CreateAuditLogExportUsecase.Invoke(param)
+			if err != nil {
+				e := entity.ToHttpError(err)
+				return nil, e
+			}
+
+			return res, nil
+		}, "Successfully create audit log export", fiber.StatusAccepted,
+	)
+}
+
+// @Summary      Get Export Job
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Success      200 {object} "Successfully get export job"
+// @Router       /api/v1/admin/export-jobs/:id [get]
+func (c *AdminController) GetExportJob(ctx *fiber.Ctx) error {
+	jobId := ctx.Params("id")
+	requester := ctx.Get(privacy.SupportRequesterHeader)
+
+	return delivery.RunHTTPWithTimeout(
+		ctx,
+		c.Timeout,
+		func(ctxWithTimeout context.Context) (*exportjob.Status, *entity.HttpError) {
+			c.GetExportJobUsecase.InitService()
+
+			param := usecase.GetExportJobParam{
+				Ctx:       ctxWithTimeout,
+				JobID:     jobId,
+				Requester: requester,
+			}
+
+			res, err := c.GetExportJobUsecase.Invoke(param)
+			if err != nil {
+				e := entity.ToHttpError(err)
+				return nil, e
+			}
+
+			return res, nil
+		}, "Successfully get export job", fiber.StatusOK,
+	)
+}
+
+// @Summary      Download Export File
+// @Tags         Admin
+// @Produce      octet-stream
+// @Success      200 {file} file "Export file"
+// @Router       /api/v1/admin/export-jobs/:id/download [get]
+func (c *AdminController) DownloadExportJob(ctx *fiber.Ctx) error {
+	c.DownloadExportJobUsecase.InitService()
+
+	param := usecase.DownloadExportJobParam{
+		Ctx:       ctx.UserContext(),
+		JobID:     ctx.Params("id"),
+		Requester: ctx.Get(privacy.SupportRequesterHeader),
+	}
+
+	file, err := c.DownloadExportJobUsecase.Invoke(param)
+	if err != nil {
+		return entity.ToHttpError(err).SendResponse(ctx)
+	}
+
+	// The body is streamed after the handler returns, fasthttp closes it once sent
+	ctx.Attachment(file.Name)
+	return ctx.SendStream(file.Body)
+}
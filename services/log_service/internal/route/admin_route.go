@@ -0,0 +1,86 @@
+package route
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/mystaline/clefinport-be/services/log_service/internal/controller"
+	"github.com/mystaline/clefinport-be/services/log_service/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/mystaline/clefinport-be/pkg/db"
+	"github.com/mystaline/clefinport-be/pkg/exportjob"
+	"github.com/mystaline/clefinport-be/pkg/jobqueue"
+	"github.com/mystaline/clefinport-be/pkg/privacy"
+	"github.com/mystaline/clefinport-be/pkg/provider"
+	"github.com/mystaline/clefinport-be/pkg/storage"
+)
+
+const exportJobQueue = "log_exports"
+
+func SetupAdminRoute(
+	app *fiber.App,
+	adminController controller.AdminController,
+) {
+	admin := app.Group("/v1/admin", privacy.RequireSupportAccess())
+
+	// Review audit records of sensitive operations
+	admin.Get("/audit-logs", adminController.ListAuditLogs)
+	// Download every matching audit record as one streamed list
+	admin.Get("/audit-logs/export", adminController.ExportAuditLogs)
+	// Export too large for a request: create a job, poll it, download its file
+	admin.Post("/audit-logs/export-jobs", adminController.CreateAuditLogExport)
+	admin.Get("/export-jobs/:id", adminController.GetExportJob)
+	admin.Get("/export-jobs/:id/download", adminController.DownloadExportJob)
+}
+
+// SetupAdminController also starts the export worker, it stops with ctx.
+func SetupAdminController(
+	ctx context.Context,
+	app *fiber.App,
+	serviceProvider provider.IServiceProvider,
+) {
+	// A failed export is retried from the start, the file is only stored once complete
+	queue, err := jobqueue.MakeQueue(serviceProvider.MakeService(db.LogServiceDBName), exportJobQueue, jobqueue.Config{
+		MaxAttempts: 3,
+		LockTimeout: 30 * time.Minute,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	exports := exportjob.MakeManager(queue, storage.FromEnv(), exportjob.Config{
+		DownloadPath: "/api/v1/admin/export-jobs/%s/download",
+	})
+	// The pseudonyms live in the vault of user_service, where support detokenizes them
+	// Without PII_TOKEN_SECRET export jobs fail rather than write raw user ids
+	var tokenizer privacy.Tokenizer
+	if hmacTokenizer, err := privacy.MakeTokenizer(serviceProvider.MakeService(db.UserServiceDBName)); err != nil {
+		log.Printf("audit log exports disabled: %v", err)
+	} else {
+		tokenizer = hmacTokenizer
+	}
+	exports.Register(usecase.AuditLogsExportKind, usecase.ProduceAuditLogExport(serviceProvider, tokenizer))
+
+	go queue.Work(ctx, 1, 10*time.Second, exports.Handle)
+
+	listAuditLogsUsecase := usecase.MakeListAuditLogsUseCase(serviceProvider)
+	exportAuditLogsUsecase := usecase.MakeExportAuditLogsUseCase(serviceProvider)
+	createAuditLogExportUsecase := usecase.MakeCreateAuditLogExportUseCase(exports)
+	getExportJobUsecase := usecase.MakeGetExportJobUseCase(exports)
+	downloadExportJobUsecase := usecase.MakeDownloadExportJobUseCase(exports)
+
+	adminController := controller.MakeAdminController(
+		60*time.Second,
+
+		listAuditLogsUsecase,
+		exportAuditLogsUsecase,
+		createAuditLogExportUsecase,
+		getExportJobUsecase,
+		downloadExportJobUsecase,
+	)
+
+	SetupAdminRoute(app, *adminController)
+}
@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/mystaline/clefinport-be/pkg/audit"
+	db "github.com/mystaline/clefinport-be/pkg/db"
+	"github.com/mystaline/clefinport-be/pkg/entity"
+	"github.com/mystaline/clefinport-be/pkg/exportjob"
+	"github.com/mystaline/clefinport-be/pkg/privacy"
+	provider "github.com/mystaline/clefinport-be/pkg/provider"
+)
+
+// AuditLogsExportKind is the export job kind of CreateAuditLogExport
+const AuditLogsExportKind = "audit_logs"
+
+type CreateAuditLogExportParam struct {
+	Ctx            context.Context
+	Filter         audit.ListFilter
+	Requester      string
+	IdempotencyKey string
+}
+
+type CreateAuditLogExportUseCase struct {
+	Exports *exportjob.Manager
+}
+
+func MakeCreateAuditLogExportUseCase(
+	exports *exportjob.Manager,
+) *CreateAuditLogExportUseCase {
+	return &CreateAuditLogExportUseCase{
+		Exports: exports,
+	}
+}
+
+// The manager carries its own service
+func (u *CreateAuditLogExportUseCase) InitService() {}
+
+// Invoke creates the export job of the matching records, or returns the one of the same request.
+func (u *CreateAuditLogExportUseCase) Invoke(
+	param CreateAuditLogExportParam,
+) (*exportjob.Status, error) {
+	// Exports hold every record, pagination is meaningless
+	param.Filter.Page, param.Filter.Limit = 0, 0
+
+	status, _, err := u.Exports.Create(param.Ctx, AuditLogsExportKind, param.Requester, param.IdempotencyKey, param.Filter)
+	if errors.Is(err, exportjob.ErrTooManyJobs) {
+		return nil, entity.TooManyRequests("too many audit log exports in progress, wait for one to finish")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return status, nil
+}
+
+// exportedUserActor pseudonymizes the actor of a record written by a user.
+type exportedUserActor struct {
+	Actor string `json:"actor" pii:"usr"`
+}
+
+// ProduceAuditLogExport writes the records of an audit_logs job, newest first.
+// The file leaves the service, so user actors are replaced with their pseudonyms, support resolves them by detokenizing.
+func ProduceAuditLogExport(serviceProvider provider.IServiceProvider, tokenizer privacy.Tokenizer) exportjob.Producer {
+	return func(ctx context.Context, params json.RawMessage, w io.Writer) error {
+		if tokenizer == nil {
+			return errors.New("audit log exports need PII_TOKEN_SECRET to pseudonymize user actors")
+		}
+
+		var filter audit.ListFilter
+		if err := json.Unmarshal(params, &filter); err != nil {
+			return err
+		}
+
+		logService := serviceProvider.MakeService(db.LogServiceDBName)
+		return exportjob.JSONArray(w, func(emit func(record audit.StoredRecord) error) error {
+			return audit.EachRecord(ctx, logService, filter, func(record audit.StoredRecord) error {
+				if record.ActorType == "user" {
+					actor := exportedUserActor{Actor: record.Actor}
+					if err := privacy.PseudonymizeRows(ctx, tokenizer, &actor); err != nil {
+						return err
+					}
+					record.Actor = actor.Actor
+				}
+
+				return emit(record)
+			})
+		})
+	}
+}
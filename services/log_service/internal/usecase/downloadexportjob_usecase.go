@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/mystaline/clefinport-be/pkg/entity"
+	"github.com/mystaline/clefinport-be/pkg/exportjob"
+	"github.com/mystaline/clefinport-be/pkg/storage"
+)
+
+type DownloadExportJobParam struct {
+	Ctx       context.Context
+	JobID     string
+	Requester string
+}
+
+// ExportFile is the file of a done export job, the caller closes Body.
+type ExportFile struct {
+	Name string
+	Body io.ReadCloser
+}
+
+type DownloadExportJobUseCase struct {
+	Exports *exportjob.Manager
+}
+
+func MakeDownloadExportJobUseCase(
+	exports *exportjob.Manager,
+) *DownloadExportJobUseCase {
+	return &DownloadExportJobUseCase{
+		Exports: exports,
+	}
+}
+
+// The manager carries its own service
+func (u *DownloadExportJobUseCase) InitService() {}
+
+// Invoke opens the file of a done export job of the requester.
+func (u *DownloadExportJobUseCase) Invoke(
+	param DownloadExportJobParam,
+) (*ExportFile, error) {
+	body, status, err := u.Exports.Open(param.Ctx, param.JobID, param.Requester)
+	switch {
+	case errors.Is(err, exportjob.ErrJobNotFound):
+		return nil, entity.NotFound("export job not found")
+	case errors.Is(err, exportjob.ErrFileNotReady):
+		return nil, entity.Conflict("export is not done yet")
+	case errors.Is(err, storage.ErrNotFound):
+		return nil, entity.NotFound("export file expired")
+	case err != nil:
+		return nil, err
+	}
+
+	return &ExportFile{
+		Name: fmt.Sprintf("%s-%s.json", status.Kind, status.ID),
+		Body: body,
+	}, nil
+}
@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/mystaline/clefinport-be/pkg/audit"
+	db "github.com/mystaline/clefinport-be/pkg/db"
+	provider "github.com/mystaline/clefinport-be/pkg/provider"
+	service "github.com/mystaline/clefinport-be/pkg/service"
+)
+
+type ExportAuditLogsParam struct {
+	Ctx    context.Context
+	Filter audit.ListFilter
+	// Emit writes one record to the streamed response
+	Emit func(record audit.StoredRecord) error
+}
+
+type ExportAuditLogsUseCase struct {
+	LogService service.PostgreSqlService
+
+	ServiceProvider provider.IServiceProvider
+}
+
+func MakeExportAuditLogsUseCase(
+	serviceProvider provider.IServiceProvider,
+) *ExportAuditLogsUseCase {
+	return &ExportAuditLogsUseCase{
+		ServiceProvider: serviceProvider,
+	}
+}
+
+func (u *ExportAuditLogsUseCase) InitService() {
+	dbName := db.LogServiceDBName
+
+	u.LogService = u.ServiceProvider.MakeService(dbName)
+	u.LogService.Debug(2)
+}
+
+// Invoke emits every matching record as it is read and returns how many were sent.
+func (u *ExportAuditLogsUseCase) Invoke(
+	param ExportAuditLogsParam,
+) (int, error) {
+	sent := 0
+	err := audit.EachRecord(param.Ctx, u.LogService, param.Filter, func(record audit.StoredRecord) error {
+		sent++
+		return param.Emit(record)
+	})
+
+	return sent, err
+}
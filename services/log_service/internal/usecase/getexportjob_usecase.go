@@ -0,0 +1,45 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mystaline/clefinport-be/pkg/entity"
+	"github.com/mystaline/clefinport-be/pkg/exportjob"
+)
+
+type GetExportJobParam struct {
+	Ctx       context.Context
+	JobID     string
+	Requester string
+}
+
+type GetExportJobUseCase struct {
+	Exports *exportjob.Manager
+}
+
+func MakeGetExportJobUseCase(
+	exports *exportjob.Manager,
+) *GetExportJobUseCase {
+	return &GetExportJobUseCase{
+		Exports: exports,
+	}
+}
+
+// The manager carries its own service
+func (u *GetExportJobUseCase) InitService() {}
+
+// Invoke returns the status of an export job of the requester, with its download URL once done.
+func (u *GetExportJobUseCase) Invoke(
+	param GetExportJobParam,
+) (*exportjob.Status, error) {
+	status, err := u.Exports.Get(param.Ctx, param.JobID, param.Requester)
+	if errors.Is(err, exportjob.ErrJobNotFound) {
+		return nil, entity.NotFound("export job not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return status, nil
+}
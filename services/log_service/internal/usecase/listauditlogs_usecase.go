@@ -0,0 +1,48 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/mystaline/clefinport-be/pkg/audit"
+	db "github.com/mystaline/clefinport-be/pkg/db"
+	"github.com/mystaline/clefinport-be/pkg/dto"
+	provider "github.com/mystaline/clefinport-be/pkg/provider"
+	service "github.com/mystaline/clefinport-be/pkg/service"
+)
+
+type ListAuditLogsParam struct {
+	Ctx    context.Context
+	Filter audit.ListFilter
+}
+
+type ListAuditLogsUseCase struct {
+	LogService service.PostgreSqlService
+
+	ServiceProvider provider.IServiceProvider
+}
+
+func MakeListAuditLogsUseCase(
+	serviceProvider provider.IServiceProvider,
+) *ListAuditLogsUseCase {
+	return &ListAuditLogsUseCase{
+		ServiceProvider: serviceProvider,
+	}
+}
+
+func (u *ListAuditLogsUseCase) InitService() {
+	dbName := db.LogServiceDBName
+
+	u.LogService = u.ServiceProvider.MakeService(dbName)
+	u.LogService.Debug(2)
+}
+
+func (u *ListAuditLogsUseCase) Invoke(
+	param ListAuditLogsParam,
+) (*dto.PaginationResult[audit.StoredRecord], error) {
+	result, err := audit.ListRecords(param.Ctx, u.LogService, param.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
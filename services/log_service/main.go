@@ -1,10 +1,14 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
+	"time"
 
+	"github.com/mystaline/clefinport-be/pkg/db"
 	"github.com/mystaline/clefinport-be/pkg/provider"
+	"github.com/mystaline/clefinport-be/pkg/service"
 
 	"github.com/joho/godotenv"
 
@@ -18,6 +22,17 @@ func main() {
 		}
 	}
 
+	db.SetSessionTimeouts(db.LogServiceDBName, db.SessionTimeouts{
+		// Audit searches and exports scan large ranges
+		Statement:         2 * time.Minute,
+		Lock:              5 * time.Second,
+		IdleInTransaction: time.Minute,
+	})
+
+	service.StartTxWatchdog(context.Background(), service.TxWatchdogConfig{
+		ForceRollback: os.Getenv("TX_LEAK_FORCE_ROLLBACK") == "true",
+	})
+
 	serviceProvider := provider.ServiceProvider{}
 
 	app := app.MakeApp()
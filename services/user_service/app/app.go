@@ -1,14 +1,25 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
 	"time"
 
+	"github.com/mystaline/clefinport-be/pkg/apikey"
+	"github.com/mystaline/clefinport-be/pkg/audit"
+	"github.com/mystaline/clefinport-be/pkg/chaos"
+	"github.com/mystaline/clefinport-be/pkg/config"
+	"github.com/mystaline/clefinport-be/pkg/db"
+	"github.com/mystaline/clefinport-be/pkg/delivery"
+	"github.com/mystaline/clefinport-be/pkg/grpcauth"
 	"github.com/mystaline/clefinport-be/pkg/provider"
+	"github.com/mystaline/clefinport-be/pkg/quota"
+	"github.com/mystaline/clefinport-be/pkg/replay"
+	"github.com/mystaline/clefinport-be/pkg/tablestats"
+	"github.com/mystaline/clefinport-be/pkg/walleterror"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 
 	user_route "github.com/mystaline/clefinport-be/services/user_service/internal/route"
 
@@ -22,6 +33,10 @@ import (
 
 type App struct {
 	app *fiber.App
+
+	readiness   *delivery.Readiness
+	tableStats  *tablestats.Monitor
+	auditWriter *audit.Writer
 }
 
 func MakeApp() *App {
@@ -33,15 +48,6 @@ func MakeApp() *App {
 func (a *App) Run(
 	serviceProvider provider.IServiceProvider,
 ) {
-	a.app.Use(cors.New())
-
-	swaggerURL := "doc.json"
-	env := os.Getenv("ENV")
-	if env != "" {
-		swaggerURL = "/TEMPLATE/docs/doc.json"
-	}
-	a.app.Get("/docs/*", swagger.New(swagger.Config{URL: swaggerURL}))
-
 	grpcHost := os.Getenv("WALLET_GRPC_HOST")
 	grpcAddr := os.Getenv("WALLET_GRPC_ADDRESS")
 	target := fmt.Sprintf("%s:%s", grpcHost, grpcAddr)
@@ -51,22 +57,63 @@ func (a *App) Run(
 	walletClient := pb_wallet.NewWalletServiceClient(conn)
 	log.Println("Dial done in", time.Since(startDial))
 
-	setupRoute(a.app, serviceProvider, walletClient)
+	a.Setup(serviceProvider, walletClient)
+
+	go warmup(a.readiness)
+	a.tableStats.Start(context.Background())
 
 	port := os.Getenv("SERVICE_PORT")
 	if port == "" {
 		port = "8080"
 	}
 
-	a.app.Listen(":" + port)
+	// Buffered audit records are flushed once the in flight requests are done
+	err := delivery.Serve(func() error { return a.app.Listen(":" + port) }, a.app.ShutdownWithContext, a.auditWriter.Close)
+	if err != nil {
+		log.Println("user service stopped:", err)
+	}
+}
+
+// Setup registers the middleware chain and the routes without starting anything,
+// tests drive the returned app with app.Test (see pkg/snapshot).
+func (a *App) Setup(
+	serviceProvider provider.IServiceProvider,
+	walletClient pb_wallet.WalletServiceClient,
+) *fiber.App {
+	a.app.Use(cors.New())
+	a.app.Use(delivery.Compression())
+
+	swaggerURL := "doc.json"
+	env := os.Getenv("ENV")
+	if env != "" {
+		swaggerURL = "/TEMPLATE/docs/doc.json"
+	}
+	a.app.Get("/docs/*", swagger.New(swagger.Config{URL: swaggerURL}))
+
+	a.readiness = &delivery.Readiness{}
+	a.readiness.Register(a.app)
+
+	// Registered before the request logger and authentication, Prometheus scrapes it every few seconds
+	a.tableStats = tablestats.MakeMonitor(db.UserServiceDBName, serviceProvider.MakeService(db.UserServiceDBName), tablestats.Config{})
+	a.app.Get("/metrics", tablestats.Handler(a.tableStats))
+
+	a.auditWriter = setupRoute(a.app, serviceProvider, walletClient)
+
+	return a.app
 }
 
 func mustConnectGRPC(target string, retries int) *grpc.ClientConn {
 	var conn *grpc.ClientConn
-	var err error
+
+	options, err := grpcauth.DialOptions(grpcauth.UserServiceName)
+	if err != nil {
+		panic("❌ Failed to configure gRPC auth: " + err.Error())
+	}
+	options = append(options, chaos.DialOptions()...)
+	options = append(options, grpc.WithChainUnaryInterceptor(walleterror.UnaryClientInterceptor()))
 
 	for i := 1; i <= retries; i++ {
-		conn, err = grpc.NewClient(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		conn, err = grpc.NewClient(target, options...)
 		if err == nil {
 			fmt.Println("✅ Connected to", target)
 			return conn
@@ -78,13 +125,37 @@ func mustConnectGRPC(target string, retries int) *grpc.ClientConn {
 	panic("❌ Failed to connect to gRPC service after retries: " + err.Error())
 }
 
+// setupRoute returns the audit writer of the routes, it is closed on shutdown.
 func setupRoute(
 	app *fiber.App,
 	serviceProvider provider.IServiceProvider,
 	walletClient pb_wallet.WalletServiceClient,
-) {
+) *audit.Writer {
 	// app.Use(util_middleware.ValidateJWTSQL())
 	app.Use(logger.New())
 
+	settings := config.MakeSettings(serviceProvider.MakeService(db.UserServiceDBName))
+	settings.Watch(context.Background(), time.Minute)
+
+	app.Use(
+		apikey.Authenticate(apikey.MakeManager(serviceProvider.MakeService(db.UserServiceDBName))),
+		apikey.DynamicRateLimit(func() int {
+			return settings.Int("apikey.rate_limit_per_minute", 120)
+		}, time.Minute),
+		replay.CaptureFromEnv(serviceProvider, "user_service"),
+	)
+
 	user_route.SetupUserController(app, serviceProvider, walletClient)
+	auditWriter := audit.MakeWriter(serviceProvider.MakeService(db.LogServiceDBName), audit.WriterConfig{})
+
+	user_route.SetupSupportController(app, serviceProvider, auditWriter, settings)
+	user_route.SetupAPIKeyController(app, serviceProvider, auditWriter)
+
+	// Plans aren't stored yet, everyone gets the free plan limits
+	quotas := quota.MakeManager(quota.Config{})
+	quotas.StartReconciler(context.Background(), 10*time.Minute)
+
+	user_route.SetupImportProfileController(app, serviceProvider, quotas)
+
+	return auditWriter
 }
@@ -0,0 +1,56 @@
+package app
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mystaline/clefinport-be/services/user_service/internal/dto"
+
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+
+	"github.com/mystaline/clefinport-be/pkg/provider"
+	"github.com/mystaline/clefinport-be/pkg/service"
+	"github.com/mystaline/clefinport-be/pkg/snapshot"
+
+	pb_wallet "github.com/mystaline/clefinport-be/pkg/pb/wallet"
+)
+
+// walletClient answers every user with the same total balance, for the user it was asked about.
+type walletClient struct {
+	totalBalance float64
+}
+
+func (c walletClient) GetTotalBalanceByUserId(ctx context.Context, in *pb_wallet.GetTotalBalanceByUserIdRequest, opts ...grpc.CallOption) (*pb_wallet.GetTotalBalanceByUserIdResponse, error) {
+	return &pb_wallet.GetTotalBalanceByUserIdResponse{UserId: in.UserId, TotalBalance: c.totalBalance}, nil
+}
+
+func TestGetUserInfoSnapshot(t *testing.T) {
+	svc := &service.MockBasePostgreSqlService{}
+	svc.On("Debug", mock.Anything).Maybe()
+	// Not a pgx pool, the settings are polled instead of listened to
+	svc.On("GetPool").Return(&service.MockPgxPool{}).Maybe()
+	svc.On("SelectMany", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	svc.On("SelectOne", mock.AnythingOfType("*dto.GetUserInfoResult"), mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			createdAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+			*args.Get(0).(*dto.GetUserInfoResult) = dto.GetUserInfoResult{
+				ID:        "1844674407370955",
+				FullName:  "Ayu Lestari",
+				Timezone:  "Asia/Jakarta",
+				Currency:  dto.EmbeddedCurrency{CurrencySymbol: "Rp", CurrencyName: "IDR"},
+				CreatedAt: createdAt,
+				UpdatedAt: createdAt,
+			}
+		}).
+		Return(nil)
+
+	serviceProvider := new(provider.MockServiceProvider)
+	serviceProvider.On("MakeService", mock.Anything).Return(svc)
+
+	server := MakeApp().Setup(serviceProvider, walletClient{totalBalance: 1250000})
+
+	snapshot.MatchResponse(t, server, httptest.NewRequest("GET", "/v1/user/1844674407370955", nil), "get_user_info")
+}
@@ -0,0 +1,150 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/mystaline/clefinport-be/services/user_service/internal/dto"
+	"github.com/mystaline/clefinport-be/services/user_service/internal/usecase"
+
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/mystaline/clefinport-be/pkg/apikey"
+	"github.com/mystaline/clefinport-be/pkg/delivery"
+	"github.com/mystaline/clefinport-be/pkg/entity"
+)
+
+type APIKeyController struct {
+	Timeout time.Duration
+
+	CreateAPIKeyUsecase entity.UseCase[usecase.CreateAPIKeyParam, *apikey.CreatedKey]
+	RotateAPIKeyUsecase entity.UseCase[usecase.RotateAPIKeyParam, *apikey.CreatedKey]
+	RevokeAPIKeyUsecase entity.UseCase[usecase.RevokeAPIKeyParam, *dto.RevokeAPIKeyResult]
+}
+
+func MakeAPIKeyController(
+	timeout time.Duration,
+
+	createAPIKeyUseCase entity.UseCase[usecase.CreateAPIKeyParam, *apikey.CreatedKey],
+	rotateAPIKeyUseCase entity.UseCase[usecase.RotateAPIKeyParam, *apikey.CreatedKey],
+	revokeAPIKeyUseCase entity.UseCase[usecase.RevokeAPIKeyParam, *dto.RevokeAPIKeyResult],
+) *APIKeyController {
+	return &APIKeyController{
+		Timeout:             timeout,
+		CreateAPIKeyUsecase: createAPIKeyUseCase,
+		RotateAPIKeyUsecase: rotateAPIKeyUseCase,
+		RevokeAPIKeyUsecase: revokeAPIKeyUseCase,
+	}
+}
+
+// @Summary      Create API Key
+// @Tags         API Keys
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Success      201 {object} "Successfully create api key"
+// @Router       /api/v1/user/:id/api-keys [post]
+func (c *APIKeyController) CreateAPIKey(ctx *fiber.Ctx) error {
+	userId := ctx.Params("id")
+
+	caller := apikey.FromContext(ctx)
+
+	var body dto.CreateAPIKeyBody
+	if err := ctx.BodyParser(&body); err != nil {
+		return entity.BadRequest("invalid request body").SendResponse(ctx)
+	}
+
+	return delivery.RunHTTPWithTimeout(
+		ctx,
+		c.Timeout,
+		func(ctxWithTimeout context.Context) (*apikey.CreatedKey, *entity.HttpError) {
+			c.CreateAPIKeyUsecase.InitService()
+
+			param := usecase.CreateAPIKeyParam{
+				Ctx:    ctxWithTimeout,
+				UserID: userId,
+				Body:   body,
+				Caller: caller,
+			}
+
+			res, err := c.CreateAPIKeyUsecase.Invoke(param)
+			if err != nil {
+				e := entity.ToHttpError(err)
+				return nil, e
+			}
+
+			return res, nil
+		}, "Successfully create api key", fiber.StatusCreated,
+	)
+}
+
+// @Summary      Rotate API Key
+// @Tags         API Keys
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Success      200 {object} "Successfully rotate api key"
+// @Router       /api/v1/user/:id/api-keys/:keyId/rotate [post]
+func (c *APIKeyController) RotateAPIKey(ctx *fiber.Ctx) error {
+	userId := ctx.Params("id")
+	keyId := ctx.Params("keyId")
+	caller := apikey.FromContext(ctx)
+
+	return delivery.RunHTTPWithTimeout(
+		ctx,
+		c.Timeout,
+		func(ctxWithTimeout context.Context) (*apikey.CreatedKey, *entity.HttpError) {
+			c.RotateAPIKeyUsecase.InitService()
+
+			param := usecase.RotateAPIKeyParam{
+				Ctx:    ctxWithTimeout,
+				UserID: userId,
+				KeyID:  keyId,
+				Caller: caller,
+			}
+
+			res, err := c.RotateAPIKeyUsecase.Invoke(param)
+			if err != nil {
+				e := entity.ToHttpError(err)
+				return nil, e
+			}
+
+			return res, nil
+		}, "Successfully rotate api key", fiber.StatusOK,
+	)
+}
+
+// @Summary      Revoke API Key
+// @Tags         API Keys
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Success      200 {object} "Successfully revoke api key"
+// @Router       /api/v1/user/:id/api-keys/:keyId [delete]
+func (c *APIKeyController) RevokeAPIKey(ctx *fiber.Ctx) error {
+	userId := ctx.Params("id")
+	keyId := ctx.Params("keyId")
+
+	return delivery.RunHTTPWithTimeout(
+		ctx,
+		c.Timeout,
+		func(ctxWithTimeout context.Context) (*dto.RevokeAPIKeyResult, *entity.HttpError) {
+			c.RevokeAPIKeyUsecase.InitService()
+
+			param := usecase.RevokeAPIKeyParam{
+				Ctx:    ctxWithTimeout,
+				UserID: userId,
+				KeyID:  keyId,
+			}
+
+			res, err := c.RevokeAPIKeyUsecase.Invoke(param)
+			if err != nil {
+				e := entity.ToHttpError(err)
+				return nil, e
+			}
+
+			return res, nil
+		}, "Successfully revoke api key", fiber.StatusOK,
+	)
+}
@@ -0,0 +1,150 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/mystaline/clefinport-be/services/user_service/internal/dto"
+	"github.com/mystaline/clefinport-be/services/user_service/internal/usecase"
+
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/mystaline/clefinport-be/pkg/delivery"
+	"github.com/mystaline/clefinport-be/pkg/entity"
+	"github.com/mystaline/clefinport-be/pkg/parser"
+)
+
+type ImportProfileController struct {
+	Timeout time.Duration
+
+	CreateImportProfileUsecase entity.UseCase[usecase.CreateImportProfileParam, *parser.MappingProfile]
+	ListImportProfilesUsecase  entity.UseCase[usecase.ListImportProfilesParam, []parser.MappingProfile]
+	PreviewImportUsecase       entity.UseCase[usecase.PreviewImportParam, *dto.ImportPreviewResult]
+}
+
+func MakeImportProfileController(
+	timeout time.Duration,
+
+	createImportProfileUseCase entity.UseCase[usecase.CreateImportProfileParam, *parser.MappingProfile],
+	listImportProfilesUseCase entity.UseCase[usecase.ListImportProfilesParam, []parser.MappingProfile],
+	previewImportUseCase entity.UseCase[usecase.PreviewImportParam, *dto.ImportPreviewResult],
+) *ImportProfileController {
+	return &ImportProfileController{
+		Timeout:                    timeout,
+		CreateImportProfileUsecase: createImportProfileUseCase,
+		ListImportProfilesUsecase:  listImportProfilesUseCase,
+		PreviewImportUsecase:       previewImportUseCase,
+	}
+}
+
+// @Summary      Create Import Mapping Profile
+// @Tags         Import Profiles
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Success      201 {object} "Successfully create import profile"
+// @Router       /api/v1/user/:id/import-profiles [post]
+func (c *ImportProfileController) CreateImportProfile(ctx *fiber.Ctx) error {
+	userId := ctx.Params("id")
+
+	var body dto.CreateImportProfileBody
+	if err := ctx.BodyParser(&body); err != nil {
+		return entity.BadRequest("invalid request body").SendResponse(ctx)
+	}
+
+	return delivery.RunHTTPWithTimeout(
+		ctx,
+		c.Timeout,
+		func(ctxWithTimeout context.Context) (*parser.MappingProfile, *entity.HttpError) {
+			c.CreateImportProfileUsecase.InitService()
+
+			param := usecase.CreateImportProfileParam{
+				Ctx:    ctxWithTimeout,
+				UserID: userId,
+				Body:   body,
+			}
+
+			res, err := c.CreateImportProfileUsecase.Invoke(param)
+			if err != nil {
+				e := entity.ToHttpError(err)
+				return nil, e
+			}
+
+			return res, nil
+		}, "Successfully create import profile", fiber.StatusCreated,
+	)
+}
+
+// @Summary      List Import Mapping Profiles
+// @Tags         Import Profiles
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200 {object} "Successfully get import profiles"
+// @Router       /api/v1/user/:id/import-profiles [get]
+func (c *ImportProfileController) ListImportProfiles(ctx *fiber.Ctx) error {
+	userId := ctx.Params("id")
+
+	return delivery.RunHTTPWithTimeout(
+		ctx,
+		c.Timeout,
+		func(ctxWithTimeout context.Context) ([]parser.MappingProfile, *entity.HttpError) {
+			c.ListImportProfilesUsecase.InitService()
+
+			param := usecase.ListImportProfilesParam{
+				Ctx:    ctxWithTimeout,
+				UserID: userId,
+			}
+
+			res, err := c.ListImportProfilesUsecase.Invoke(param)
+			if err != nil {
+				e := entity.ToHttpError(err)
+				return nil, e
+			}
+
+			return res, nil
+		}, "Successfully get import profiles", fiber.StatusOK,
+	)
+}
+
+// @Summary      Preview Import
+// @Description  Parses the first rows of an uploaded csv/xlsx file with a saved mapping profile, nothing is stored.
+// @Tags         Import Profiles
+// @Security     BearerAuth
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        file formData file true "csv or xlsx file"
+// @Success      200 {object} "Successfully preview import"
+// @Router       /api/v1/user/:id/import-profiles/:profileId/preview [post]
+func (c *ImportProfileController) PreviewImport(ctx *fiber.Ctx) error {
+	userId := ctx.Params("id")
+	profileId := ctx.Params("profileId")
+
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		return entity.BadRequest("file is required").SendResponse(ctx)
+	}
+
+	return delivery.RunHTTPWithTimeout(
+		ctx,
+		c.Timeout,
+		func(ctxWithTimeout context.Context) (*dto.ImportPreviewResult, *entity.HttpError) {
+			c.PreviewImportUsecase.InitService()
+
+			param := usecase.PreviewImportParam{
+				Ctx:       ctxWithTimeout,
+				UserID:    userId,
+				ProfileID: profileId,
+				File:      file,
+			}
+
+			res, err := c.PreviewImportUsecase.Invoke(param)
+			if err != nil {
+				e := entity.ToHttpError(err)
+				return nil, e
+			}
+
+			return res, nil
+		}, "Successfully preview import", fiber.StatusOK,
+	)
+}
@@ -0,0 +1,252 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mystaline/clefinport-be/services/user_service/internal/dto"
+	"github.com/mystaline/clefinport-be/services/user_service/internal/usecase"
+
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/mystaline/clefinport-be/pkg/config"
+	"github.com/mystaline/clefinport-be/pkg/delivery"
+	"github.com/mystaline/clefinport-be/pkg/entity"
+	"github.com/mystaline/clefinport-be/pkg/privacy"
+	"github.com/mystaline/clefinport-be/pkg/userarchive"
+)
+
+type SupportController struct {
+	Timeout time.Duration
+
+	DetokenizeUsecase entity.UseCase[usecase.DetokenizeParam, *dto.DetokenizeResult]
+	ExportUserUsecase entity.UseCase[usecase.ExportUserParam, *dto.UserArchiveFile]
+	ImportUserUsecase entity.UseCase[usecase.ImportUserParam, *userarchive.ImportResult]
+
+	ListSettingsUsecase  entity.UseCase[usecase.ListSettingsParam, []config.Setting]
+	UpdateSettingUsecase entity.UseCase[usecase.UpdateSettingParam, *config.Setting]
+	DeleteSettingUsecase entity.UseCase[usecase.DeleteSettingParam, *string]
+}
+
+func MakeSupportController(
+	timeout time.Duration,
+
+	detokenizeUseCase entity.UseCase[usecase.DetokenizeParam, *dto.DetokenizeResult],
+	exportUserUseCase entity.UseCase[usecase.ExportUserParam, *dto.UserArchiveFile],
+	importUserUseCase entity.UseCase[usecase.ImportUserParam, *userarchive.ImportResult],
+	listSettingsUseCase entity.UseCase[usecase.ListSettingsParam, []config.Setting],
+	updateSettingUseCase entity.UseCase[usecase.UpdateSettingParam, *config.Setting],
+	deleteSettingUseCase entity.UseCase[usecase.DeleteSettingParam, *string],
+) *SupportController {
+	return &SupportController{
+		Timeout:              timeout,
+		DetokenizeUsecase:    detokenizeUseCase,
+		ExportUserUsecase:    exportUserUseCase,
+		ImportUserUsecase:    importUserUseCase,
+		ListSettingsUsecase:  listSettingsUseCase,
+		UpdateSettingUsecase: updateSettingUseCase,
+		DeleteSettingUsecase: deleteSettingUseCase,
+	}
+}
+
+// @Summary      Detokenize Pseudonym
+// @Tags         Support
+// @Accept       json
+// @Produce      json
+// @Success      200 {object} "Successfully detokenize pseudonym"
+// @Router       /api/v1/support/detokenize [post]
+func (c *SupportController) Detokenize(ctx *fiber.Ctx) error {
+	var body dto.DetokenizeBody
+	if err := ctx.BodyParser(&body); err != nil {
+		return entity.BadRequest("invalid request body").SendResponse(ctx)
+	}
+	requesterId := ctx.Get(privacy.SupportRequesterHeader)
+
+	return delivery.RunHTTPWithTimeout(
+		ctx,
+		c.Timeout,
+		func(ctxWithTimeout context.Context) (*dto.DetokenizeResult, *entity.HttpError) {
+			c.DetokenizeUsecase.InitService()
+
+			param := usecase.DetokenizeParam{
+				Ctx:         ctxWithTimeout,
+				RequesterID: requesterId,
+				Body:        body,
+			}
+
+			res, err := c.DetokenizeUsecase.Invoke(param)
+			if err != nil {
+				e := entity.ToHttpError(err)
+				return nil, e
+			}
+
+			return res, nil
+		}, "Successfully detokenize pseudonym", fiber.StatusOK,
+	)
+}
+
+// @Summary      Export User Archive
+// @Description  Downloads the user's account (user, settings, wallets, members, categories, transactions) as a gzip archive.
+// @Tags         Support
+// @Produce      application/gzip
+// @Success      200 {file} file "User archive"
+// @Router       /api/v1/support/users/:id/export [get]
+func (c *SupportController) ExportUser(ctx *fiber.Ctx) error {
+	userId := ctx.Params("id")
+
+	// The archive is sent as a file, not wrapped in the JSON response
+	ctxWithTimeout, cancel := context.WithTimeout(ctx.UserContext(), c.Timeout)
+	defer cancel()
+
+	c.ExportUserUsecase.InitService()
+
+	res, err := c.ExportUserUsecase.Invoke(usecase.ExportUserParam{
+		Ctx:    ctxWithTimeout,
+		UserID: userId,
+	})
+	if err != nil {
+		return entity.ToHttpError(err).SendResponse(ctx)
+	}
+
+	ctx.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, res.FileName))
+	ctx.Set(fiber.HeaderContentType, "application/gzip")
+
+	return ctx.Send(res.Content)
+}
+
+// @Summary      Import User Archive
+// @Description  Imports an archive from the export endpoint of another environment, every row gets a new id.
+// @Tags         Support
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        file formData file true "archive from the export endpoint"
+// @Success      201 {object} "Successfully import user archive"
+// @Router       /api/v1/support/users/import [post]
+func (c *SupportController) ImportUser(ctx *fiber.Ctx) error {
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		return entity.BadRequest("file is required").SendResponse(ctx)
+	}
+
+	return delivery.RunHTTPWithTimeout(
+		ctx,
+		c.Timeout,
+		func(ctxWithTimeout context.Context) (*userarchive.ImportResult, *entity.HttpError) {
+			c.ImportUserUsecase.InitService()
+
+			param := usecase.ImportUserParam{
+				Ctx:  ctxWithTimeout,
+				File: file,
+			}
+
+			res, err := c.ImportUserUsecase.Invoke(param)
+			if err != nil {
+				e := entity.ToHttpError(err)
+				return nil, e
+			}
+
+			return res, nil
+		}, "Successfully import user archive", fiber.StatusCreated,
+	)
+}
+
+// @Summary      List Runtime Settings
+// @Tags         Support
+// @Accept       json
+// @Produce      json
+// @Success      200 {object} "Successfully list settings"
+// @Router       /api/v1/support/settings [get]
+func (c *SupportController) ListSettings(ctx *fiber.Ctx) error {
+	return delivery.RunHTTPWithTimeout(
+		ctx,
+		c.Timeout,
+		func(ctxWithTimeout context.Context) ([]config.Setting, *entity.HttpError) {
+			c.ListSettingsUsecase.InitService()
+
+			param := usecase.ListSettingsParam{
+				Ctx: ctxWithTimeout,
+			}
+
+			res, err := c.ListSettingsUsecase.Invoke(param)
+			if err != nil {
+				e := entity.ToHttpError(err)
+				return nil, e
+			}
+
+			return res, nil
+		}, "Successfully list settings", fiber.StatusOK,
+	)
+}
+
+// @Summary      Update Runtime Setting
+// @Description  Stores a setting, every instance of the service applies it without a redeploy.
+// @Tags         Support
+// @Accept       json
+// @Produce      json
+// @Success      200 {object} "Successfully update setting"
+// @Router       /api/v1/support/settings/:key [put]
+func (c *SupportController) UpdateSetting(ctx *fiber.Ctx) error {
+	var body dto.UpdateSettingBody
+	if err := ctx.BodyParser(&body); err != nil {
+		return entity.BadRequest("invalid request body").SendResponse(ctx)
+	}
+	key := ctx.Params("key")
+	requesterId := ctx.Get(privacy.SupportRequesterHeader)
+
+	return delivery.RunHTTPWithTimeout(
+		ctx,
+		c.Timeout,
+		func(ctxWithTimeout context.Context) (*config.Setting, *entity.HttpError) {
+			c.UpdateSettingUsecase.InitService()
+
+			param := usecase.UpdateSettingParam{
+				Ctx:         ctxWithTimeout,
+				Key:         key,
+				RequesterID: requesterId,
+				Body:        body,
+			}
+
+			res, err := c.UpdateSettingUsecase.Invoke(param)
+			if err != nil {
+				e := entity.ToHttpError(err)
+				return nil, e
+			}
+
+			return res, nil
+		}, "Successfully update setting", fiber.StatusOK,
+	)
+}
+
+// @Summary      Delete Runtime Setting
+// @Description  Removes a setting, the service falls back to its default.
+// @Tags         Support
+// @Accept       json
+// @Produce      json
+// @Success      200 {object} "Successfully delete setting"
+// @Router       /api/v1/support/settings/:key [delete]
+func (c *SupportController) DeleteSetting(ctx *fiber.Ctx) error {
+	key := ctx.Params("key")
+
+	return delivery.RunHTTPWithTimeout(
+		ctx,
+		c.Timeout,
+		func(ctxWithTimeout context.Context) (*string, *entity.HttpError) {
+			c.DeleteSettingUsecase.InitService()
+
+			param := usecase.DeleteSettingParam{
+				Ctx: ctxWithTimeout,
+				Key: key,
+			}
+
+			res, err := c.DeleteSettingUsecase.Invoke(param)
+			if err != nil {
+				e := entity.ToHttpError(err)
+				return nil, e
+			}
+
+			return res, nil
+		}, "Successfully delete setting", fiber.StatusOK,
+	)
+}
@@ -1,6 +1,9 @@
 package dto
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type EmbeddedCurrency struct {
 	CurrencySymbol string `json:"currencySymbol" column:"profile_settings.currency_symbol"`
@@ -27,3 +30,48 @@ type GetUserInfoData struct {
 	CreatedAt      time.Time `json:"createdAt"      column:"users.created_at"`
 	UpdatedAt      time.Time `json:"updatedAt"      column:"users.updated_at"`
 }
+
+type DetokenizeBody struct {
+	Token  string `json:"token"`
+	Reason string `json:"reason"`
+}
+
+type DetokenizeResult struct {
+	Token     string `json:"token"`
+	SubjectID string `json:"subjectId"`
+}
+
+type CreateAPIKeyBody struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+}
+
+type RevokeAPIKeyResult struct {
+	ID string `json:"id"`
+}
+
+type CreateImportProfileBody struct {
+	Name             string            `json:"name"`
+	HeaderRow        int               `json:"headerRow"`
+	Columns          map[string]string `json:"columns"`
+	DecimalSeparator string            `json:"decimalSeparator"`
+	AmountFields     []string          `json:"amountFields"`
+	DebitField       string            `json:"debitField"`
+	CreditField      string            `json:"creditField"`
+}
+
+type ImportPreviewResult struct {
+	ProfileID string                   `json:"profileId"`
+	Rows      []map[string]interface{} `json:"rows"`
+}
+
+// UserArchiveFile is the gzip archive of a user's account, sent as a download.
+type UserArchiveFile struct {
+	FileName string
+	Content  []byte
+}
+
+type UpdateSettingBody struct {
+	// Any JSON value, readers decode it into the type they expect
+	Value json.RawMessage `json:"value"`
+}
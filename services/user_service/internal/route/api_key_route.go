@@ -0,0 +1,53 @@
+package route
+
+import (
+	"time"
+
+	"github.com/mystaline/clefinport-be/services/user_service/internal/controller"
+	"github.com/mystaline/clefinport-be/services/user_service/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/mystaline/clefinport-be/pkg/apikey"
+	"github.com/mystaline/clefinport-be/pkg/audit"
+	"github.com/mystaline/clefinport-be/pkg/provider"
+)
+
+func SetupAPIKeyRoute(
+	app *fiber.App,
+	apiKeyController controller.APIKeyController,
+	auditWriter *audit.Writer,
+) {
+	// Only the user themselves manages their keys, and a key only issues scopes it holds
+	apiKeys := app.Group("/v1/user/:id/api-keys",
+		apikey.RequireOwner("id"),
+		audit.Middleware(auditWriter, "api_key.manage"),
+	)
+
+	// Issue a new scoped key, the plaintext key is only returned here
+	apiKeys.Post("/", apiKeyController.CreateAPIKey)
+	// Revoke key and issue a replacement with the same scopes
+	apiKeys.Post("/:keyId/rotate", apiKeyController.RotateAPIKey)
+	// Revoke key
+	apiKeys.Delete("/:keyId", apiKeyController.RevokeAPIKey)
+}
+
+func SetupAPIKeyController(
+	app *fiber.App,
+	serviceProvider provider.IServiceProvider,
+	auditWriter *audit.Writer,
+) {
+	createAPIKeyUsecase := usecase.MakeCreateAPIKeyUseCase(serviceProvider)
+	rotateAPIKeyUsecase := usecase.MakeRotateAPIKeyUseCase(serviceProvider)
+	revokeAPIKeyUsecase := usecase.MakeRevokeAPIKeyUseCase(serviceProvider)
+
+	apiKeyController := controller.MakeAPIKeyController(
+		60*time.Second,
+
+		createAPIKeyUsecase,
+		rotateAPIKeyUsecase,
+		revokeAPIKeyUsecase,
+	)
+
+	SetupAPIKeyRoute(app, *apiKeyController, auditWriter)
+}
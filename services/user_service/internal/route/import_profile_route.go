@@ -0,0 +1,54 @@
+package route
+
+import (
+	"time"
+
+	"github.com/mystaline/clefinport-be/services/user_service/internal/controller"
+	"github.com/mystaline/clefinport-be/services/user_service/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/mystaline/clefinport-be/pkg/apikey"
+	"github.com/mystaline/clefinport-be/pkg/db"
+	"github.com/mystaline/clefinport-be/pkg/introspect"
+	"github.com/mystaline/clefinport-be/pkg/provider"
+	"github.com/mystaline/clefinport-be/pkg/quota"
+)
+
+func SetupImportProfileRoute(
+	app *fiber.App,
+	importProfileController controller.ImportProfileController,
+) {
+	importProfiles := app.Group("/v1/user/:id/import-profiles")
+	readOnly, write := apikey.RequireScope(apikey.ScopeReadOnly), apikey.RequireScope(apikey.ScopeTransactionsWrite)
+
+	// Save a column mapping, reused on later uploads
+	importProfiles.Post("/", write, importProfileController.CreateImportProfile)
+	// List saved mappings
+	importProfiles.Get("/", readOnly, importProfileController.ListImportProfiles)
+	// Parse the first rows of an upload with a saved mapping, nothing is stored
+	importProfiles.Post("/:profileId/preview", readOnly, importProfileController.PreviewImport)
+}
+
+func SetupImportProfileController(
+	app *fiber.App,
+	serviceProvider provider.IServiceProvider,
+	quotas *quota.Manager,
+) {
+	// Mapped fields are checked against the transactions table of the wallet database
+	inspector := introspect.MakeInspector(serviceProvider.MakeService(db.WalletServiceDBName), 0)
+
+	createImportProfileUsecase := usecase.MakeCreateImportProfileUseCase(serviceProvider, inspector)
+	listImportProfilesUsecase := usecase.MakeListImportProfilesUseCase(serviceProvider)
+	previewImportUsecase := usecase.MakePreviewImportUseCase(serviceProvider, quotas)
+
+	importProfileController := controller.MakeImportProfileController(
+		60*time.Second,
+
+		createImportProfileUsecase,
+		listImportProfilesUsecase,
+		previewImportUsecase,
+	)
+
+	SetupImportProfileRoute(app, *importProfileController)
+}
@@ -0,0 +1,72 @@
+package route
+
+import (
+	"log"
+	"time"
+
+	"github.com/mystaline/clefinport-be/services/user_service/internal/controller"
+	"github.com/mystaline/clefinport-be/services/user_service/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/mystaline/clefinport-be/pkg/audit"
+	"github.com/mystaline/clefinport-be/pkg/config"
+	"github.com/mystaline/clefinport-be/pkg/db"
+	"github.com/mystaline/clefinport-be/pkg/privacy"
+	"github.com/mystaline/clefinport-be/pkg/provider"
+)
+
+func SetupSupportRoute(
+	app *fiber.App,
+	supportController controller.SupportController,
+	auditWriter *audit.Writer,
+) {
+	support := app.Group("/v1/support", audit.Middleware(auditWriter, "support.access"), privacy.RequireSupportAccess())
+
+	// Resolve analytics pseudonym back to raw identifier
+	support.Post("/detokenize", supportController.Detokenize)
+	// Move an account between environments, export here and import on the target
+	support.Get("/users/:id/export", supportController.ExportUser)
+	support.Post("/users/import", supportController.ImportUser)
+
+	// Runtime settings of the service (rate limits, caps, toggles), applied without a redeploy
+	support.Get("/settings", supportController.ListSettings)
+	support.Put("/settings/:key", supportController.UpdateSetting)
+	support.Delete("/settings/:key", supportController.DeleteSetting)
+}
+
+func SetupSupportController(
+	app *fiber.App,
+	serviceProvider provider.IServiceProvider,
+	auditWriter *audit.Writer,
+	settings *config.Settings,
+) {
+	// Built once so the tokens it already persisted stay cached across requests
+	// Without PII_TOKEN_SECRET detokenization answers 500, the other support endpoints keep working
+	var tokenizer privacy.Tokenizer
+	if hmacTokenizer, err := privacy.MakeTokenizer(serviceProvider.MakeService(db.UserServiceDBName)); err != nil {
+		log.Printf("detokenization disabled: %v", err)
+	} else {
+		tokenizer = hmacTokenizer
+	}
+
+	detokenizeUsecase := usecase.MakeDetokenizeUseCase(tokenizer)
+	exportUserUsecase := usecase.MakeExportUserUseCase(serviceProvider)
+	importUserUsecase := usecase.MakeImportUserUseCase(serviceProvider)
+	listSettingsUsecase := usecase.MakeListSettingsUseCase(settings)
+	updateSettingUsecase := usecase.MakeUpdateSettingUseCase(settings)
+	deleteSettingUsecase := usecase.MakeDeleteSettingUseCase(settings)
+
+	supportController := controller.MakeSupportController(
+		60*time.Second,
+
+		detokenizeUsecase,
+		exportUserUsecase,
+		importUserUsecase,
+		listSettingsUsecase,
+		updateSettingUsecase,
+		deleteSettingUsecase,
+	)
+
+	SetupSupportRoute(app, *supportController, auditWriter)
+}
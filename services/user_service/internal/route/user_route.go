@@ -8,6 +8,7 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 
+	"github.com/mystaline/clefinport-be/pkg/apikey"
 	"github.com/mystaline/clefinport-be/pkg/provider"
 
 	pb_wallet "github.com/mystaline/clefinport-be/pkg/pb/wallet"
@@ -22,7 +23,7 @@ func SetupUserRoute(
 	// // Get user's wallet list
 	// user.Get("/:id/wallets", userController.GetUserWalletList)
 	// Get user info
-	user.Get("/:id", userController.GetUserInfo)
+	user.Get("/:id", apikey.RequireScope(apikey.ScopeReadOnly), userController.GetUserInfo)
 	// // Change password
 	// user.Put("/:id/password", userController.ChangePassword)
 	// // Update profile
@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/mystaline/clefinport-be/services/user_service/internal/dto"
+
+	"github.com/mystaline/clefinport-be/pkg/apikey"
+	db "github.com/mystaline/clefinport-be/pkg/db"
+	"github.com/mystaline/clefinport-be/pkg/entity"
+	provider "github.com/mystaline/clefinport-be/pkg/provider"
+	service "github.com/mystaline/clefinport-be/pkg/service"
+)
+
+type CreateAPIKeyParam struct {
+	Ctx    context.Context
+	UserID string
+	Body   dto.CreateAPIKeyBody
+	// Key the request was authenticated with, nil for other callers
+	Caller *apikey.APIKey
+}
+
+type CreateAPIKeyUseCase struct {
+	UserService service.PostgreSqlService
+	Manager     *apikey.Manager
+
+	ServiceProvider provider.IServiceProvider
+}
+
+func MakeCreateAPIKeyUseCase(
+	serviceProvider provider.IServiceProvider,
+) *CreateAPIKeyUseCase {
+	return &CreateAPIKeyUseCase{
+		ServiceProvider: serviceProvider,
+	}
+}
+
+func (u *CreateAPIKeyUseCase) InitService() {
+	dbName := db.UserServiceDBName
+
+	u.UserService = u.ServiceProvider.MakeService(dbName)
+	u.UserService.Debug(2)
+
+	u.Manager = apikey.MakeManager(u.UserService)
+}
+
+func (u *CreateAPIKeyUseCase) Invoke(
+	param CreateAPIKeyParam,
+) (*apikey.CreatedKey, error) {
+	if strings.TrimSpace(param.Body.Name) == "" {
+		return nil, entity.BadRequest("name is required")
+	}
+
+	scopes := make([]apikey.Scope, 0, len(param.Body.Scopes))
+	for _, each := range param.Body.Scopes {
+		scopes = append(scopes, apikey.Scope(each))
+	}
+	if err := param.Caller.CanGrant(scopes); err != nil {
+		return nil, entity.Forbidden(err.Error())
+	}
+
+	created, err := u.Manager.Create(param.Ctx, param.UserID, param.Body.Name, scopes)
+	if errors.Is(err, apikey.ErrInvalidScope) {
+		return nil, entity.BadRequest(err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
@@ -0,0 +1,147 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/mystaline/clefinport-be/services/user_service/internal/dto"
+
+	db "github.com/mystaline/clefinport-be/pkg/db"
+	"github.com/mystaline/clefinport-be/pkg/entity"
+	"github.com/mystaline/clefinport-be/pkg/introspect"
+	"github.com/mystaline/clefinport-be/pkg/parser"
+	provider "github.com/mystaline/clefinport-be/pkg/provider"
+	service "github.com/mystaline/clefinport-be/pkg/service"
+	"github.com/mystaline/clefinport-be/pkg/sql_query"
+)
+
+type CreateImportProfileParam struct {
+	Ctx    context.Context
+	UserID string
+	Body   dto.CreateImportProfileBody
+}
+
+type CreateImportProfileUseCase struct {
+	UserService service.PostgreSqlService
+	// Inspector reads the wallet database, where imported rows land in the transactions table
+	Inspector *introspect.Inspector
+
+	ServiceProvider provider.IServiceProvider
+}
+
+type insertImportProfile struct {
+	UserID           string            `json:"userId"           column:"user_id"`
+	Name             string            `json:"name"             column:"name"`
+	HeaderRow        int               `json:"headerRow"        column:"header_row"`
+	Columns          map[string]string `json:"columns"          column:"columns"`
+	DecimalSeparator string            `json:"decimalSeparator" column:"decimal_separator"`
+	AmountFields     []string          `json:"amountFields"     column:"amount_fields"`
+	DebitField       string            `json:"debitField"       column:"debit_field"`
+	CreditField      string            `json:"creditField"      column:"credit_field"`
+}
+
+func MakeCreateImportProfileUseCase(
+	serviceProvider provider.IServiceProvider,
+	inspector *introspect.Inspector,
+) *CreateImportProfileUseCase {
+	return &CreateImportProfileUseCase{
+		ServiceProvider: serviceProvider,
+		Inspector:       inspector,
+	}
+}
+
+func (u *CreateImportProfileUseCase) InitService() {
+	dbName := db.UserServiceDBName
+
+	u.UserService = u.ServiceProvider.MakeService(dbName)
+	u.UserService.Debug(2)
+}
+
+func (u *CreateImportProfileUseCase) Invoke(
+	param CreateImportProfileParam,
+) (*parser.MappingProfile, error) {
+	if strings.TrimSpace(param.Body.Name) == "" {
+		return nil, entity.BadRequest("name is required")
+	}
+	if len(param.Body.Columns) == 0 {
+		return nil, entity.BadRequest("columns is required")
+	}
+	if param.Body.HeaderRow < 0 {
+		return nil, entity.BadRequest("headerRow must not be negative")
+	}
+	if param.Body.DecimalSeparator == "" {
+		param.Body.DecimalSeparator = "."
+	}
+	if param.Body.DecimalSeparator != "." && param.Body.DecimalSeparator != "," {
+		return nil, entity.BadRequest(`decimalSeparator must be "." or ","`)
+	}
+	for _, field := range []string{param.Body.DebitField, param.Body.CreditField} {
+		if _, ok := param.Body.Columns[field]; field != "" && !ok {
+			return nil, entity.BadRequest(field + " is not mapped in columns")
+		}
+	}
+	if param.Body.AmountFields == nil {
+		param.Body.AmountFields = []string{}
+	}
+	if err := u.validateTargets(param.Ctx, param.Body); err != nil {
+		return nil, err
+	}
+
+	var created parser.MappingProfile
+	_, err := u.UserService.InsertOneWithData(param.Ctx, db.ImportProfileTableName, insertImportProfile{
+		UserID:    param.UserID,
+		Name:      strings.TrimSpace(param.Body.Name),
+		HeaderRow: param.Body.HeaderRow,
+		Columns:   param.Body.Columns,
+
+		DecimalSeparator: param.Body.DecimalSeparator,
+		AmountFields:     param.Body.AmountFields,
+		DebitField:       param.Body.DebitField,
+		CreditField:      param.Body.CreditField,
+	}, service.ReturningConfig{
+		Column:      sql_query.ExtractJSONTags[parser.MappingProfile](),
+		Destination: &created,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// validateTargets rejects mapped fields that are not columns of the transactions table, so a typo in a mapping
+// fails when it is saved instead of on every import. Debit and credit fields are merged into amount, they are not columns.
+func (u *CreateImportProfileUseCase) validateTargets(ctx context.Context, body dto.CreateImportProfileBody) error {
+	if u.Inspector == nil {
+		return nil
+	}
+
+	table, err := u.Inspector.Table(ctx, db.TransactionTableName)
+	if errors.Is(err, introspect.ErrTableNotFound) {
+		// Nothing to validate against, e.g. a user service running without the wallet database migrated
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var unknown []string
+	for field := range body.Columns {
+		if field == body.DebitField || field == body.CreditField {
+			continue
+		}
+
+		column, ok := table.Column(sql_query.CamelToSnake(field))
+		if !ok || column.PrimaryKey {
+			unknown = append(unknown, field)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return entity.BadRequest("unknown import fields: " + strings.Join(unknown, ", "))
+	}
+
+	return nil
+}
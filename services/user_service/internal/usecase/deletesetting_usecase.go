@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mystaline/clefinport-be/pkg/config"
+	"github.com/mystaline/clefinport-be/pkg/entity"
+)
+
+type DeleteSettingParam struct {
+	Ctx context.Context
+	Key string
+}
+
+type DeleteSettingUseCase struct {
+	Settings *config.Settings
+}
+
+func MakeDeleteSettingUseCase(
+	settings *config.Settings,
+) *DeleteSettingUseCase {
+	return &DeleteSettingUseCase{
+		Settings: settings,
+	}
+}
+
+// The settings carry their own service
+func (u *DeleteSettingUseCase) InitService() {}
+
+// Invoke removes a runtime setting, its readers fall back to their default.
+func (u *DeleteSettingUseCase) Invoke(
+	param DeleteSettingParam,
+) (*string, error) {
+	err := u.Settings.Delete(param.Ctx, param.Key)
+	if errors.Is(err, config.ErrSettingNotFound) {
+		return nil, entity.NotFound("setting not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &param.Key, nil
+}
@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mystaline/clefinport-be/services/user_service/internal/dto"
+
+	"github.com/mystaline/clefinport-be/pkg/entity"
+	"github.com/mystaline/clefinport-be/pkg/privacy"
+)
+
+type DetokenizeParam struct {
+	Ctx         context.Context
+	RequesterID string
+	Body        dto.DetokenizeBody
+}
+
+type DetokenizeUseCase struct {
+	Tokenizer privacy.Tokenizer
+}
+
+// MakeDetokenizeUseCase takes the tokenizer of the service, nil when it isn't configured.
+func MakeDetokenizeUseCase(
+	tokenizer privacy.Tokenizer,
+) *DetokenizeUseCase {
+	return &DetokenizeUseCase{
+		Tokenizer: tokenizer,
+	}
+}
+
+// The tokenizer carries its own service
+func (u *DetokenizeUseCase) InitService() {}
+
+func (u *DetokenizeUseCase) Invoke(
+	param DetokenizeParam,
+) (*dto.DetokenizeResult, error) {
+	if u.Tokenizer == nil {
+		return nil, entity.InternalServerError("tokenizer is not configured")
+	}
+
+	subjectID, err := u.Tokenizer.Detokenize(param.Ctx, privacy.DetokenizeRequest{
+		Token:       param.Body.Token,
+		RequesterID: param.RequesterID,
+		Reason:      param.Body.Reason,
+	})
+	if errors.Is(err, privacy.ErrTokenNotFound) {
+		return nil, entity.NotFound(err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.DetokenizeResult{Token: param.Body.Token, SubjectID: subjectID}, nil
+}
@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mystaline/clefinport-be/services/user_service/internal/dto"
+
+	"github.com/mystaline/clefinport-be/pkg/entity"
+	provider "github.com/mystaline/clefinport-be/pkg/provider"
+	"github.com/mystaline/clefinport-be/pkg/userarchive"
+)
+
+type ExportUserParam struct {
+	Ctx    context.Context
+	UserID string
+}
+
+type ExportUserUseCase struct {
+	Graph []userarchive.Partition
+
+	ServiceProvider provider.IServiceProvider
+}
+
+func MakeExportUserUseCase(
+	serviceProvider provider.IServiceProvider,
+) *ExportUserUseCase {
+	return &ExportUserUseCase{
+		ServiceProvider: serviceProvider,
+	}
+}
+
+// InitService only picks the graph, userarchive opens a service per database of the graph.
+func (u *ExportUserUseCase) InitService() {
+	u.Graph = userarchive.DefaultGraph
+}
+
+func (u *ExportUserUseCase) Invoke(
+	param ExportUserParam,
+) (*dto.UserArchiveFile, error) {
+	archive, err := userarchive.Export(param.Ctx, u.ServiceProvider, param.UserID, u.Graph)
+	if errors.Is(err, userarchive.ErrUserNotFound) {
+		return nil, entity.NotFound(err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := userarchive.Write(&buf, archive); err != nil {
+		return nil, err
+	}
+
+	return &dto.UserArchiveFile{
+		FileName: fmt.Sprintf("user-%s-%s.json.gz", param.UserID, archive.ExportedAt.Format("20060102150405")),
+		Content:  buf.Bytes(),
+	}, nil
+}
@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"context"
+	"mime/multipart"
+
+	"github.com/mystaline/clefinport-be/pkg/entity"
+	provider "github.com/mystaline/clefinport-be/pkg/provider"
+	"github.com/mystaline/clefinport-be/pkg/userarchive"
+)
+
+type ImportUserParam struct {
+	Ctx  context.Context
+	File *multipart.FileHeader
+}
+
+type ImportUserUseCase struct {
+	Graph []userarchive.Partition
+
+	ServiceProvider provider.IServiceProvider
+}
+
+func MakeImportUserUseCase(
+	serviceProvider provider.IServiceProvider,
+) *ImportUserUseCase {
+	return &ImportUserUseCase{
+		ServiceProvider: serviceProvider,
+	}
+}
+
+// InitService only picks the graph, userarchive opens a service per database of the graph.
+func (u *ImportUserUseCase) InitService() {
+	u.Graph = userarchive.DefaultGraph
+}
+
+func (u *ImportUserUseCase) Invoke(
+	param ImportUserParam,
+) (*userarchive.ImportResult, error) {
+	file, err := param.File.Open()
+	if err != nil {
+		return nil, entity.BadRequest("failed to open archive")
+	}
+	defer file.Close()
+
+	archive, err := userarchive.Read(file)
+	if err != nil {
+		return nil, entity.BadRequest(err.Error())
+	}
+
+	// Unique violations (e.g. the email already has an account here) roll everything back
+	return userarchive.Import(param.Ctx, u.ServiceProvider, archive, u.Graph)
+}
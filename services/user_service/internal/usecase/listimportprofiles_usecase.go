@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"context"
+
+	db "github.com/mystaline/clefinport-be/pkg/db"
+	"github.com/mystaline/clefinport-be/pkg/parser"
+	provider "github.com/mystaline/clefinport-be/pkg/provider"
+	service "github.com/mystaline/clefinport-be/pkg/service"
+	"github.com/mystaline/clefinport-be/pkg/sql_query"
+)
+
+type ListImportProfilesParam struct {
+	Ctx    context.Context
+	UserID string
+}
+
+type ListImportProfilesUseCase struct {
+	UserService service.PostgreSqlService
+
+	ServiceProvider provider.IServiceProvider
+}
+
+func MakeListImportProfilesUseCase(
+	serviceProvider provider.IServiceProvider,
+) *ListImportProfilesUseCase {
+	return &ListImportProfilesUseCase{
+		ServiceProvider: serviceProvider,
+	}
+}
+
+func (u *ListImportProfilesUseCase) InitService() {
+	dbName := db.UserServiceDBName
+
+	u.UserService = u.ServiceProvider.MakeService(dbName)
+	u.UserService.Debug(2)
+}
+
+func (u *ListImportProfilesUseCase) Invoke(
+	param ListImportProfilesParam,
+) ([]parser.MappingProfile, error) {
+	query, args, err := sql_query.NewSQLSelectBuilder[parser.MappingProfile](db.ImportProfileTableName).
+		Where(map[string]sql_query.SQLCondition{
+			"user_id": {Operator: sql_query.SQLOperatorEqual, Value: param.UserID},
+		}).
+		OrderBy([]string{"name"}, true).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := []parser.MappingProfile{}
+	if err := u.UserService.SelectMany(&profiles, param.Ctx, query, args...); err != nil {
+		return nil, err
+	}
+
+	return profiles, nil
+}
@@ -0,0 +1,33 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/mystaline/clefinport-be/pkg/config"
+)
+
+type ListSettingsParam struct {
+	Ctx context.Context
+}
+
+type ListSettingsUseCase struct {
+	Settings *config.Settings
+}
+
+func MakeListSettingsUseCase(
+	settings *config.Settings,
+) *ListSettingsUseCase {
+	return &ListSettingsUseCase{
+		Settings: settings,
+	}
+}
+
+// The settings carry their own service
+func (u *ListSettingsUseCase) InitService() {}
+
+// Invoke lists the stored runtime settings, the ones not stored use the default of their readers.
+func (u *ListSettingsUseCase) Invoke(
+	param ListSettingsParam,
+) ([]config.Setting, error) {
+	return u.Settings.List(param.Ctx)
+}
@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"context"
+	"mime/multipart"
+
+	"github.com/mystaline/clefinport-be/services/user_service/internal/dto"
+
+	db "github.com/mystaline/clefinport-be/pkg/db"
+	"github.com/mystaline/clefinport-be/pkg/entity"
+	"github.com/mystaline/clefinport-be/pkg/parser"
+	provider "github.com/mystaline/clefinport-be/pkg/provider"
+	"github.com/mystaline/clefinport-be/pkg/quota"
+	service "github.com/mystaline/clefinport-be/pkg/service"
+	"github.com/mystaline/clefinport-be/pkg/sql_query"
+)
+
+// ImportPreviewRows is how many parsed rows are shown before the user commits an import.
+const ImportPreviewRows = 20
+
+type PreviewImportParam struct {
+	Ctx       context.Context
+	UserID    string
+	ProfileID string
+	File      *multipart.FileHeader
+}
+
+type PreviewImportUseCase struct {
+	UserService service.PostgreSqlService
+	Parser      parser.Parser
+	Quotas      *quota.Manager
+
+	ServiceProvider provider.IServiceProvider
+}
+
+func MakePreviewImportUseCase(
+	serviceProvider provider.IServiceProvider,
+	quotas *quota.Manager,
+) *PreviewImportUseCase {
+	return &PreviewImportUseCase{
+		ServiceProvider: serviceProvider,
+		Parser:          &parser.DefaultParser{},
+		Quotas:          quotas,
+	}
+}
+
+func (u *PreviewImportUseCase) InitService() {
+	dbName := db.UserServiceDBName
+
+	u.UserService = u.ServiceProvider.MakeService(dbName)
+	u.UserService.Debug(2)
+}
+
+func (u *PreviewImportUseCase) Invoke(
+	param PreviewImportParam,
+) (*dto.ImportPreviewResult, error) {
+	if param.File == nil {
+		return nil, entity.BadRequest("file is required")
+	}
+
+	query, args, err := sql_query.NewSQLSelectBuilder[parser.MappingProfile](db.ImportProfileTableName).
+		Where(map[string]sql_query.SQLCondition{
+			"id":      {Operator: sql_query.SQLOperatorEqual, Value: param.ProfileID},
+			"user_id": {Operator: sql_query.SQLOperatorEqual, Value: param.UserID},
+		}).
+		SetLimit(1).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+
+	var profile parser.MappingProfile
+	if err := u.UserService.SelectOne(&profile, param.Ctx, query, args...); err != nil {
+		return nil, entity.NotFound("import profile not found")
+	}
+
+	if u.Quotas != nil {
+		if err := u.Quotas.Check(param.Ctx, param.UserID, quota.ResourceImportBytes, param.File.Size); err != nil {
+			return nil, err
+		}
+		if err := u.Quotas.Consume(param.Ctx, param.UserID, quota.ResourceImportsPerDay, 1); err != nil {
+			return nil, err
+		}
+	}
+
+	rows, err := u.Parser.ParseWithProfile(param.File, profile, ImportPreviewRows)
+	if err != nil {
+		// Only previews that parse count towards the daily limit
+		if u.Quotas != nil {
+			u.Quotas.Add(param.UserID, quota.ResourceImportsPerDay, -1)
+		}
+		// Unreadable files and unknown columns are caused by the upload, not the server
+		return nil, entity.BadRequest(err.Error())
+	}
+
+	return &dto.ImportPreviewResult{ProfileID: profile.ID, Rows: rows}, nil
+}
@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/mystaline/clefinport-be/services/user_service/internal/dto"
+
+	"github.com/mystaline/clefinport-be/pkg/apikey"
+	db "github.com/mystaline/clefinport-be/pkg/db"
+	provider "github.com/mystaline/clefinport-be/pkg/provider"
+	service "github.com/mystaline/clefinport-be/pkg/service"
+)
+
+type RevokeAPIKeyParam struct {
+	Ctx    context.Context
+	UserID string
+	KeyID  string
+}
+
+type RevokeAPIKeyUseCase struct {
+	UserService service.PostgreSqlService
+	Manager     *apikey.Manager
+
+	ServiceProvider provider.IServiceProvider
+}
+
+func MakeRevokeAPIKeyUseCase(
+	serviceProvider provider.IServiceProvider,
+) *RevokeAPIKeyUseCase {
+	return &RevokeAPIKeyUseCase{
+		ServiceProvider: serviceProvider,
+	}
+}
+
+func (u *RevokeAPIKeyUseCase) InitService() {
+	dbName := db.UserServiceDBName
+
+	u.UserService = u.ServiceProvider.MakeService(dbName)
+	u.UserService.Debug(2)
+
+	u.Manager = apikey.MakeManager(u.UserService)
+}
+
+func (u *RevokeAPIKeyUseCase) Invoke(
+	param RevokeAPIKeyParam,
+) (*dto.RevokeAPIKeyResult, error) {
+	if err := u.Manager.Revoke(param.Ctx, param.UserID, param.KeyID); err != nil {
+		return nil, err
+	}
+
+	return &dto.RevokeAPIKeyResult{ID: param.KeyID}, nil
+}
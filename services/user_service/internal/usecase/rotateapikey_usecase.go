@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mystaline/clefinport-be/pkg/apikey"
+	db "github.com/mystaline/clefinport-be/pkg/db"
+	"github.com/mystaline/clefinport-be/pkg/entity"
+	provider "github.com/mystaline/clefinport-be/pkg/provider"
+	service "github.com/mystaline/clefinport-be/pkg/service"
+)
+
+type RotateAPIKeyParam struct {
+	Ctx    context.Context
+	UserID string
+	KeyID  string
+	// Key the request was authenticated with, nil for other callers
+	Caller *apikey.APIKey
+}
+
+type RotateAPIKeyUseCase struct {
+	UserService service.PostgreSqlService
+	Manager     *apikey.Manager
+
+	ServiceProvider provider.IServiceProvider
+}
+
+func MakeRotateAPIKeyUseCase(
+	serviceProvider provider.IServiceProvider,
+) *RotateAPIKeyUseCase {
+	return &RotateAPIKeyUseCase{
+		ServiceProvider: serviceProvider,
+	}
+}
+
+func (u *RotateAPIKeyUseCase) InitService() {
+	dbName := db.UserServiceDBName
+
+	u.UserService = u.ServiceProvider.MakeService(dbName)
+	u.UserService.Debug(2)
+
+	u.Manager = apikey.MakeManager(u.UserService)
+}
+
+func (u *RotateAPIKeyUseCase) Invoke(
+	param RotateAPIKeyParam,
+) (*apikey.CreatedKey, error) {
+	created, err := u.Manager.Rotate(param.Ctx, param.UserID, param.KeyID, param.Caller)
+	if errors.Is(err, apikey.ErrScopeNotHeld) {
+		return nil, entity.Forbidden(err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
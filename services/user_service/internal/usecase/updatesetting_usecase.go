@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/mystaline/clefinport-be/services/user_service/internal/dto"
+
+	"github.com/mystaline/clefinport-be/pkg/config"
+	"github.com/mystaline/clefinport-be/pkg/entity"
+)
+
+type UpdateSettingParam struct {
+	Ctx         context.Context
+	Key         string
+	RequesterID string
+	Body        dto.UpdateSettingBody
+}
+
+type UpdateSettingUseCase struct {
+	Settings *config.Settings
+}
+
+func MakeUpdateSettingUseCase(
+	settings *config.Settings,
+) *UpdateSettingUseCase {
+	return &UpdateSettingUseCase{
+		Settings: settings,
+	}
+}
+
+// The settings carry their own service
+func (u *UpdateSettingUseCase) InitService() {}
+
+// Invoke stores a runtime setting, every instance applies it within seconds.
+func (u *UpdateSettingUseCase) Invoke(
+	param UpdateSettingParam,
+) (*config.Setting, error) {
+	if len(param.Body.Value) == 0 || !json.Valid(param.Body.Value) {
+		return nil, entity.BadRequest("value must be a JSON value")
+	}
+
+	setting, err := u.Settings.Set(param.Ctx, param.Key, param.Body.Value, param.RequesterID)
+	if errors.Is(err, config.ErrInvalidKey) {
+		return nil, entity.BadRequest(err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return setting, nil
+}
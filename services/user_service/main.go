@@ -1,10 +1,13 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 
+	"github.com/mystaline/clefinport-be/pkg/db"
 	"github.com/mystaline/clefinport-be/pkg/provider"
+	"github.com/mystaline/clefinport-be/pkg/service"
 
 	"github.com/joho/godotenv"
 
@@ -18,6 +21,12 @@ func main() {
 		}
 	}
 
+	db.SetSessionTimeouts(db.UserServiceDBName, db.DefaultSessionTimeouts)
+
+	service.StartTxWatchdog(context.Background(), service.TxWatchdogConfig{
+		ForceRollback: os.Getenv("TX_LEAK_FORCE_ROLLBACK") == "true",
+	})
+
 	serviceProvider := provider.ServiceProvider{}
 
 	app := app.MakeApp()
@@ -1,10 +1,15 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"os"
 
+	"github.com/mystaline/clefinport-be/pkg/audit"
+	"github.com/mystaline/clefinport-be/pkg/db"
+	"github.com/mystaline/clefinport-be/pkg/delivery"
+	"github.com/mystaline/clefinport-be/pkg/grpcauth"
 	pb_wallet "github.com/mystaline/clefinport-be/pkg/pb/wallet"
 	"github.com/mystaline/clefinport-be/pkg/provider"
 	"github.com/mystaline/clefinport-be/services/wallet_service/internal/route"
@@ -25,11 +30,38 @@ func RunGRPCServer(
 		return fmt.Errorf("failed to listen: %v", err)
 	}
 
-	s := grpc.NewServer()
+	options, err := grpcauth.ServerOptions(grpcauth.MethodPolicy{
+		pb_wallet.WalletService_GetTotalBalanceByUserId_FullMethodName: {grpcauth.UserServiceName},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to configure grpc auth: %v", err)
+	}
+
+	auditWriter := audit.MakeWriter(serviceProvider.MakeService(db.LogServiceDBName), audit.WriterConfig{})
+	options = append(options, grpc.ChainUnaryInterceptor(audit.UnaryServerInterceptor(auditWriter, map[string]string{
+		pb_wallet.WalletService_GetTotalBalanceByUserId_FullMethodName: "balance.read",
+	})))
+
+	s := grpc.NewServer(options...)
 	pb_wallet.RegisterWalletServiceServer(s, route.SetupWalletGRPC(serviceProvider))
 
 	reflection.Register(s)
 
 	fmt.Println("🚀 gRPC Wallet server running on port", grpcPort)
-	return s.Serve(lis)
+	// Buffered audit records are flushed once the in flight calls are done
+	return delivery.Serve(func() error { return s.Serve(lis) }, func(ctx context.Context) error {
+		stopped := make(chan struct{})
+		go func() {
+			s.GracefulStop()
+			close(stopped)
+		}()
+
+		select {
+		case <-stopped:
+			return nil
+		case <-ctx.Done():
+			s.Stop()
+			return ctx.Err()
+		}
+	}, auditWriter.Close)
 }
@@ -1,9 +1,17 @@
 package app
 
 import (
+	"context"
+	"log"
 	"os"
 
+	"github.com/mystaline/clefinport-be/pkg/apikey"
+	"github.com/mystaline/clefinport-be/pkg/audit"
+	"github.com/mystaline/clefinport-be/pkg/db"
+	"github.com/mystaline/clefinport-be/pkg/delivery"
 	"github.com/mystaline/clefinport-be/pkg/provider"
+	"github.com/mystaline/clefinport-be/pkg/replay"
+	"github.com/mystaline/clefinport-be/pkg/tablestats"
 
 	wallet_route "github.com/mystaline/clefinport-be/services/wallet_service/internal/route"
 
@@ -15,6 +23,10 @@ import (
 
 type App struct {
 	app *fiber.App
+
+	readiness   *delivery.Readiness
+	tableStats  *tablestats.Monitor
+	auditWriter *audit.Writer
 }
 
 func MakeApp() *App {
@@ -26,7 +38,30 @@ func MakeApp() *App {
 func (a *App) Run(
 	serviceProvider provider.IServiceProvider,
 ) {
+	a.Setup(serviceProvider)
+
+	go warmup(a.readiness)
+	a.tableStats.Start(context.Background())
+
+	port := os.Getenv("SERVICE_PORT")
+	if port == "" {
+		port = "8080"
+	}
+
+	// Buffered audit records are flushed once the in flight requests are done
+	err := delivery.Serve(func() error { return a.app.Listen(":" + port) }, a.app.ShutdownWithContext, a.auditWriter.Close)
+	if err != nil {
+		log.Println("wallet http server stopped:", err)
+	}
+}
+
+// Setup registers the middleware chain and the routes without starting anything,
+// tests drive the returned app with app.Test (see pkg/snapshot).
+func (a *App) Setup(
+	serviceProvider provider.IServiceProvider,
+) *fiber.App {
 	a.app.Use(cors.New())
+	a.app.Use(delivery.Compression())
 
 	swaggerURL := "doc.json"
 	env := os.Getenv("ENV")
@@ -35,22 +70,29 @@ func (a *App) Run(
 	}
 	a.app.Get("/docs/*", swagger.New(swagger.Config{URL: swaggerURL}))
 
-	setupRoute(a.app, serviceProvider)
+	a.readiness = &delivery.Readiness{}
+	a.readiness.Register(a.app)
 
-	port := os.Getenv("SERVICE_PORT")
-	if port == "" {
-		port = "8080"
-	}
+	// Registered before the request logger and authentication, Prometheus scrapes it every few seconds
+	a.tableStats = tablestats.MakeMonitor(db.WalletServiceDBName, serviceProvider.MakeService(db.WalletServiceDBName), tablestats.Config{})
+	a.app.Get("/metrics", tablestats.Handler(a.tableStats))
 
-	a.app.Listen(":" + port)
+	a.auditWriter = setupRoute(a.app, serviceProvider)
+
+	return a.app
 }
 
+// setupRoute returns the audit writer of the routes, it is closed on shutdown.
 func setupRoute(
 	app *fiber.App,
 	serviceProvider provider.IServiceProvider,
-) {
+) *audit.Writer {
 	// app.Use(util_middleware.ValidateJWTSQL())
 	app.Use(logger.New())
+	// Keys live in the user service database, the scopes of each route are checked by the wallet routes
+	app.Use(apikey.Authenticate(apikey.MakeManager(serviceProvider.MakeService(db.UserServiceDBName))))
+	app.Use(replay.CaptureFromEnv(serviceProvider, "wallet_service"))
 
 	wallet_route.SetupWalletController(app, serviceProvider)
+	return wallet_route.SetupAdminController(context.Background(), app, serviceProvider)
 }
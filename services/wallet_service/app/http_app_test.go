@@ -0,0 +1,54 @@
+package app
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mystaline/clefinport-be/services/wallet_service/internal/dto"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/mystaline/clefinport-be/pkg/enum"
+	"github.com/mystaline/clefinport-be/pkg/provider"
+	"github.com/mystaline/clefinport-be/pkg/service"
+	"github.com/mystaline/clefinport-be/pkg/snapshot"
+)
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func TestListCategoriesSnapshot(t *testing.T) {
+	svc := &service.MockBasePostgreSqlService{}
+	svc.On("Debug", mock.Anything).Maybe()
+	svc.On("SelectMany", mock.AnythingOfType("*[]dto.CategoryData"), mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			*args.Get(0).(*[]dto.CategoryData) = []dto.CategoryData{
+				{ID: "1001", SystemKey: stringPtr("food"), Name: "Food", Type: enum.TransactionExpense},
+				{ID: "1002", ParentID: stringPtr("1001"), Name: "Groceries", Type: enum.TransactionExpense},
+			}
+		}).
+		Return(nil)
+	// The balance recalculation worker polls its queue in the background
+	svc.On("SelectMany", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	serviceProvider := new(provider.MockServiceProvider)
+	serviceProvider.On("MakeService", mock.Anything).Return(svc)
+
+	server := MakeApp().Setup(serviceProvider)
+
+	tests := []struct {
+		name string
+		url  string
+	}{
+		{name: "list_categories", url: "/v1/wallet/categories?userId=42&locale=en"},
+		{name: "list_categories_missing_user", url: "/v1/wallet/categories"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snapshot.MatchResponse(t, server, httptest.NewRequest("GET", tt.url, nil), tt.name)
+		})
+	}
+
+}
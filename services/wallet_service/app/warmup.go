@@ -0,0 +1,54 @@
+package app
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"github.com/mystaline/clefinport-be/services/wallet_service/internal/dto"
+
+	"github.com/mystaline/clefinport-be/pkg/categoryseed"
+	"github.com/mystaline/clefinport-be/pkg/db"
+	"github.com/mystaline/clefinport-be/pkg/delivery"
+	"github.com/mystaline/clefinport-be/pkg/schemacheck"
+	"github.com/mystaline/clefinport-be/pkg/service"
+	"github.com/mystaline/clefinport-be/pkg/sql_query"
+	"github.com/mystaline/clefinport-be/pkg/walletevents"
+)
+
+// warmup pre-establishes pool connections and primes builder caches, then marks the service ready.
+// Set DB_WARMUP_CANARY=true to also run a canary query on each warmed connection.
+// Set SCHEMA_CHECK=true to verify DTO column tags against the schema, exiting on mismatch.
+func warmup(readiness *delivery.Readiness) {
+	config := db.WarmupConfig{Canary: os.Getenv("DB_WARMUP_CANARY") == "true"}
+
+	for _, dbName := range []db.DBName{db.WalletServiceDBName, db.LogServiceDBName} {
+		if err := db.WarmupPool(context.Background(), dbName, config); err != nil {
+			log.Printf("warmup failed, service stays unready: %v", err)
+			return
+		}
+	}
+
+	sql_query.Prime[dto.GetWalletInfoData]()
+	sql_query.Prime[dto.WalletMemberData]()
+	sql_query.Prime[dto.CategoryData]()
+
+	// Keeps the system category names in sync with the packs shipped in this build,
+	// listing falls back to the stored names when it fails so the service still gets ready
+	if err := categoryseed.SyncNames(context.Background(), service.MakeService(db.WalletServiceDBName)); err != nil {
+		log.Printf("sync category names failed: %v", err)
+	}
+
+	if os.Getenv("SCHEMA_CHECK") == "true" {
+		err := schemacheck.Verify(context.Background(), service.MakeService(db.WalletServiceDBName),
+			schemacheck.For[dto.GetWalletInfoData](db.WalletTableName),
+			schemacheck.For[dto.WalletMemberData](db.WalletMemberTableName),
+			schemacheck.For[walletevents.Event](db.WalletEventTableName),
+		)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	readiness.MarkReady()
+}
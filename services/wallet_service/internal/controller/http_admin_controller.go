@@ -0,0 +1,242 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/mystaline/clefinport-be/services/wallet_service/internal/dto"
+	"github.com/mystaline/clefinport-be/services/wallet_service/internal/usecase"
+
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/mystaline/clefinport-be/pkg/audit"
+	"github.com/mystaline/clefinport-be/pkg/delivery"
+	"github.com/mystaline/clefinport-be/pkg/entity"
+	"github.com/mystaline/clefinport-be/pkg/introspect"
+	"github.com/mystaline/clefinport-be/pkg/sqlconsole"
+)
+
+type AdminController struct {
+	Timeout time.Duration
+
+	RecalculateBalancesUsecase     entity.UseCase[usecase.RecalculateBalancesParam, *dto.RecalculateBalancesResult]
+	GetBalanceRecalculationUsecase entity.UseCase[usecase.GetBalanceRecalculationParam, *dto.BalanceRecalculationStatus]
+	ListAdminTablesUsecase         entity.UseCase[usecase.ListAdminTablesParam, []string]
+	GetAdminTableUsecase           entity.UseCase[usecase.GetAdminTableParam, *introspect.Table]
+	EnableEventSourcingUsecase     entity.UseCase[usecase.EnableEventSourcingParam, *dto.EnableEventSourcingResult]
+	RunAdminSQLUsecase             entity.UseCase[usecase.RunAdminSQLParam, *sqlconsole.Result]
+}
+
+func MakeAdminController(
+	timeout time.Duration,
+
+	recalculateBalancesUseCase entity.UseCase[usecase.RecalculateBalancesParam, *dto.RecalculateBalancesResult],
+	getBalanceRecalculationUseCase entity.UseCase[usecase.GetBalanceRecalculationParam, *dto.BalanceRecalculationStatus],
+	listAdminTablesUseCase entity.UseCase[usecase.ListAdminTablesParam, []string],
+	getAdminTableUseCase entity.UseCase[usecase.GetAdminTableParam, *introspect.Table],
+	enableEventSourcingUseCase entity.UseCase[usecase.EnableEventSourcingParam, *dto.EnableEventSourcingResult],
+	runAdminSQLUseCase entity.UseCase[usecase.RunAdminSQLParam, *sqlconsole.Result],
+) *AdminController {
+	return &AdminController{
+		Timeout:                        timeout,
+		RecalculateBalancesUsecase:     recalculateBalancesUseCase,
+		GetBalanceRecalculationUsecase: getBalanceRecalculationUseCase,
+		ListAdminTablesUsecase:         listAdminTablesUseCase,
+		GetAdminTableUsecase:           getAdminTableUseCase,
+		EnableEventSourcingUsecase:     enableEventSourcingUseCase,
+		RunAdminSQLUsecase:             runAdminSQLUseCase,
+	}
+}
+
+// @Summary      Recalculate Wallet Balances
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Success      202 {object} "Successfully queue balance recalculation"
+// @Router       /api/v1/admin/balance-recalculations [post]
+func (c *AdminController) RecalculateBalances(ctx *fiber.Ctx) error {
+	var body dto.RecalculateBalancesBody
+	if err := ctx.BodyParser(&body); err != nil {
+		return entity.BadRequest("invalid request body").SendResponse(ctx)
+	}
+
+	return delivery.RunHTTPWithTimeout(
+		ctx,
+		c.Timeout,
+		func(ctxWithTimeout context.Context) (*dto.RecalculateBalancesResult, *entity.HttpError) {
+			c.RecalculateBalancesUsecase.InitService()
+
+			param := usecase.RecalculateBalancesParam{
+				Ctx:  ctxWithTimeout,
+				Body: body,
+			}
+
+			res, err := c.RecalculateBalancesUsecase.Invoke(param)
+			if err != nil {
+				e := entity.ToHttpError(err)
+				return nil, e
+			}
+
+			return res, nil
+		}, "Successfully queue balance recalculation", fiber.StatusAccepted,
+	)
+}
+
+// @Summary      Get Balance Recalculation Status
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Success      200 {object} "Successfully get balance recalculation status"
+// @Router       /api/v1/admin/balance-recalculations/:jobId [get]
+func (c *AdminController) GetBalanceRecalculation(ctx *fiber.Ctx) error {
+	jobId := ctx.Params("jobId")
+
+	return delivery.RunHTTPWithTimeout(
+		ctx,
+		c.Timeout,
+		func(ctxWithTimeout context.Context) (*dto.BalanceRecalculationStatus, *entity.HttpError) {
+			c.GetBalanceRecalculationUsecase.InitService()
+
+			param := usecase.GetBalanceRecalculationParam{
+				Ctx:   ctxWithTimeout,
+				JobID: jobId,
+			}
+
+			res, err := c.GetBalanceRecalculationUsecase.Invoke(param)
+			if err != nil {
+				e := entity.ToHttpError(err)
+				return nil, e
+			}
+
+			return res, nil
+		}, "Successfully get balance recalculation status", fiber.StatusOK,
+	)
+}
+
+// @Summary      List Tables
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Success      200 {object} "Successfully list tables"
+// @Router       /api/v1/admin/tables [get]
+func (c *AdminController) ListTables(ctx *fiber.Ctx) error {
+	return delivery.RunHTTPWithTimeout(
+		ctx,
+		c.Timeout,
+		func(ctxWithTimeout context.Context) ([]string, *entity.HttpError) {
+			c.ListAdminTablesUsecase.InitService()
+
+			param := usecase.ListAdminTablesParam{
+				Ctx: ctxWithTimeout,
+			}
+
+			res, err := c.ListAdminTablesUsecase.Invoke(param)
+			if err != nil {
+				e := entity.ToHttpError(err)
+				return nil, e
+			}
+
+			return res, nil
+		}, "Successfully list tables", fiber.StatusOK,
+	)
+}
+
+// @Summary      Get Table Schema
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Success      200 {object} "Successfully get table schema"
+// @Router       /api/v1/admin/tables/:table [get]
+func (c *AdminController) GetTable(ctx *fiber.Ctx) error {
+	table := ctx.Params("table")
+
+	return delivery.RunHTTPWithTimeout(
+		ctx,
+		c.Timeout,
+		func(ctxWithTimeout context.Context) (*introspect.Table, *entity.HttpError) {
+			c.GetAdminTableUsecase.InitService()
+
+			param := usecase.GetAdminTableParam{
+				Ctx:   ctxWithTimeout,
+				Table: table,
+			}
+
+			res, err := c.GetAdminTableUsecase.Invoke(param)
+			if err != nil {
+				e := entity.ToHttpError(err)
+				return nil, e
+			}
+
+			return res, nil
+		}, "Successfully get table schema", fiber.StatusOK,
+	)
+}
+
+// @Summary      Enable Wallet Event Sourcing
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Success      201 {object} "Successfully enable wallet event sourcing"
+// @Router       /api/v1/admin/wallets/:id/event-sourcing [post]
+func (c *AdminController) EnableEventSourcing(ctx *fiber.Ctx) error {
+	walletId := ctx.Params("id")
+
+	return delivery.RunHTTPWithTimeout(
+		ctx,
+		c.Timeout,
+		func(ctxWithTimeout context.Context) (*dto.EnableEventSourcingResult, *entity.HttpError) {
+			c.EnableEventSourcingUsecase.InitService()
+
+			param := usecase.EnableEventSourcingParam{
+				Ctx:      ctxWithTimeout,
+				WalletID: walletId,
+			}
+
+			res, err := c.EnableEventSourcingUsecase.Invoke(param)
+			if err != nil {
+				e := entity.ToHttpError(err)
+				return nil, e
+			}
+
+			return res, nil
+		}, "Successfully enable wallet event sourcing", fiber.StatusCreated,
+	)
+}
+
+// @Summary      Run Read-Only SQL
+// @Description  Runs one parameterized SELECT on the tables of the data browser, in a read-only transaction. The query is audited.
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Success      200 {object} "Successfully run query"
+// @Router       /api/v1/admin/sql [post]
+func (c *AdminController) RunSQL(ctx *fiber.Ctx) error {
+	var body sqlconsole.Query
+	if err := ctx.BodyParser(&body); err != nil {
+		return entity.BadRequest("invalid request body").SendResponse(ctx)
+	}
+
+	audit.SetDetail(ctx, body.SQL)
+
+	return delivery.RunHTTPWithTimeout(
+		ctx,
+		c.Timeout,
+		func(ctxWithTimeout context.Context) (*sqlconsole.Result, *entity.HttpError) {
+			c.RunAdminSQLUsecase.InitService()
+
+			param := usecase.RunAdminSQLParam{
+				Ctx:   ctxWithTimeout,
+				Query: body,
+			}
+
+			res, err := c.RunAdminSQLUsecase.Invoke(param)
+			if err != nil {
+				e := entity.ToHttpError(err)
+				return nil, e
+			}
+
+			return res, nil
+		}, "Successfully run query", fiber.StatusOK,
+	)
+}
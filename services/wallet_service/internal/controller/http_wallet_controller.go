@@ -17,17 +17,32 @@ import (
 type WalletController struct {
 	Timeout time.Duration
 
-	GetWalletInfoUsecase entity.UseCase[usecase.GetWalletInfoParam, *dto.GetWalletInfoResult]
+	GetWalletInfoUsecase    entity.UseCase[usecase.GetWalletInfoParam, *dto.GetWalletInfoResult]
+	InviteMemberUsecase     entity.UseCase[usecase.InviteMemberParam, *dto.InviteMemberResult]
+	VerifyInvitationUsecase entity.UseCase[usecase.VerifyInvitationParam, *dto.VerifyInvitationResult]
+	SeedCategoriesUsecase   entity.UseCase[usecase.SeedCategoriesParam, *dto.SeedCategoriesResult]
+	ListCategoriesUsecase   entity.UseCase[usecase.ListCategoriesParam, []dto.CategoryData]
+	GetBalanceAsOfUsecase   entity.UseCase[usecase.GetBalanceAsOfParam, *dto.BalanceAsOfResult]
 }
 
 func MakeWalletController(
 	timeout time.Duration,
 
 	getWalletInfoUseCase entity.UseCase[usecase.GetWalletInfoParam, *dto.GetWalletInfoResult],
+	inviteMemberUseCase entity.UseCase[usecase.InviteMemberParam, *dto.InviteMemberResult],
+	verifyInvitationUseCase entity.UseCase[usecase.VerifyInvitationParam, *dto.VerifyInvitationResult],
+	seedCategoriesUseCase entity.UseCase[usecase.SeedCategoriesParam, *dto.SeedCategoriesResult],
+	listCategoriesUseCase entity.UseCase[usecase.ListCategoriesParam, []dto.CategoryData],
+	getBalanceAsOfUseCase entity.UseCase[usecase.GetBalanceAsOfParam, *dto.BalanceAsOfResult],
 ) *WalletController {
 	return &WalletController{
-		Timeout:              timeout,
-		GetWalletInfoUsecase: getWalletInfoUseCase,
+		Timeout:                 timeout,
+		GetWalletInfoUsecase:    getWalletInfoUseCase,
+		InviteMemberUsecase:     inviteMemberUseCase,
+		VerifyInvitationUsecase: verifyInvitationUseCase,
+		SeedCategoriesUsecase:   seedCategoriesUseCase,
+		ListCategoriesUsecase:   listCategoriesUseCase,
+		GetBalanceAsOfUsecase:   getBalanceAsOfUseCase,
 	}
 }
 
@@ -62,3 +77,182 @@ func (c *WalletController) GetWalletInfo(ctx *fiber.Ctx) error {
 		}, "Successfully retrieve wallet info", fiber.StatusOK,
 	)
 }
+
+// @Summary      Invite Wallet Member
+// @Tags         Wallets
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Success      201 {object} "Successfully invite wallet member"
+// @Router       /api/v1/wallet/:id/invite-member [post]
+func (c *WalletController) InviteMember(ctx *fiber.Ctx) error {
+	walletId := ctx.Params("id")
+
+	var body dto.InviteMemberBody
+	if err := ctx.BodyParser(&body); err != nil {
+		return entity.BadRequest("invalid request body").SendResponse(ctx)
+	}
+
+	return delivery.RunHTTPWithTimeout(
+		ctx,
+		c.Timeout,
+		func(ctxWithTimeout context.Context) (*dto.InviteMemberResult, *entity.HttpError) {
+			c.InviteMemberUsecase.InitService()
+
+			param := usecase.InviteMemberParam{
+				Ctx:      ctxWithTimeout,
+				WalletID: walletId,
+				Body:     body,
+			}
+
+			res, err := c.InviteMemberUsecase.Invoke(param)
+			if err != nil {
+				e := entity.ToHttpError(err)
+				return nil, e
+			}
+
+			return res, nil
+		}, "Successfully invite wallet member", fiber.StatusCreated,
+	)
+}
+
+// @Summary      Verify Wallet Invitation
+// @Tags         Wallets
+// @Accept       json
+// @Produce      json
+// @Param        token query string true "Invitation token from the link"
+// @Success      200 {object} "Successfully verify wallet invitation"
+// @Router       /api/v1/wallet/invitations/verify [get]
+func (c *WalletController) VerifyInvitation(ctx *fiber.Ctx) error {
+	token := ctx.Query("token")
+	if token == "" {
+		return entity.BadRequest("token is required").SendResponse(ctx)
+	}
+
+	return delivery.RunHTTPWithTimeout(
+		ctx,
+		c.Timeout,
+		func(ctxWithTimeout context.Context) (*dto.VerifyInvitationResult, *entity.HttpError) {
+			c.VerifyInvitationUsecase.InitService()
+
+			param := usecase.VerifyInvitationParam{
+				Ctx:   ctxWithTimeout,
+				Token: token,
+			}
+
+			res, err := c.VerifyInvitationUsecase.Invoke(param)
+			if err != nil {
+				e := entity.ToHttpError(err)
+				return nil, e
+			}
+
+			return res, nil
+		}, "Successfully verify wallet invitation", fiber.StatusOK,
+	)
+}
+
+// @Summary      Seed Default Categories
+// @Tags         Categories
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Success      200 {object} "Successfully seed default categories"
+// @Router       /api/v1/wallet/categories/seed [post]
+func (c *WalletController) SeedCategories(ctx *fiber.Ctx) error {
+	var body dto.SeedCategoriesBody
+	if err := ctx.BodyParser(&body); err != nil {
+		return entity.BadRequest("invalid request body").SendResponse(ctx)
+	}
+
+	return delivery.RunHTTPWithTimeout(
+		ctx,
+		c.Timeout,
+		func(ctxWithTimeout context.Context) (*dto.SeedCategoriesResult, *entity.HttpError) {
+			c.SeedCategoriesUsecase.InitService()
+
+			param := usecase.SeedCategoriesParam{
+				Ctx:  ctxWithTimeout,
+				Body: body,
+			}
+
+			res, err := c.SeedCategoriesUsecase.Invoke(param)
+			if err != nil {
+				e := entity.ToHttpError(err)
+				return nil, e
+			}
+
+			return res, nil
+		}, "Successfully seed default categories", fiber.StatusOK,
+	)
+}
+
+// @Summary      List Categories
+// @Tags         Categories
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        userId query string true "Owner of the categories"
+// @Param        locale query string false "Display language of system categories, e.g. id or en-US"
+// @Success      200 {object} "Successfully retrieve categories"
+// @Router       /api/v1/wallet/categories [get]
+func (c *WalletController) ListCategories(ctx *fiber.Ctx) error {
+	userId := ctx.Query("userId")
+	locale := ctx.Query("locale", ctx.Get(fiber.HeaderAcceptLanguage))
+
+	return delivery.RunHTTPWithTimeout(
+		ctx,
+		c.Timeout,
+		func(ctxWithTimeout context.Context) ([]dto.CategoryData, *entity.HttpError) {
+			c.ListCategoriesUsecase.InitService()
+
+			param := usecase.ListCategoriesParam{
+				Ctx:    ctxWithTimeout,
+				UserID: userId,
+				Locale: locale,
+			}
+
+			res, err := c.ListCategoriesUsecase.Invoke(param)
+			if err != nil {
+				e := entity.ToHttpError(err)
+				return nil, e
+			}
+
+			return res, nil
+		}, "Successfully retrieve categories", fiber.StatusOK,
+	)
+}
+
+// @Summary      Get Wallet Balance As Of
+// @Tags         Wallets
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        asOf query string false "RFC 3339 date, now by default"
+// @Success      200 {object} "Successfully get wallet balance"
+// @Router       /api/v1/wallet/:id/balance [get]
+func (c *WalletController) GetBalanceAsOf(ctx *fiber.Ctx) error {
+	walletId := ctx.Params("id")
+	asOf := ctx.Query("asOf")
+
+	return delivery.RunHTTPWithTimeout(
+		ctx,
+		c.Timeout,
+		func(ctxWithTimeout context.Context) (*dto.BalanceAsOfResult, *entity.HttpError) {
+			c.GetBalanceAsOfUsecase.InitService()
+
+			param := usecase.GetBalanceAsOfParam{
+				Ctx:      ctxWithTimeout,
+				WalletID: walletId,
+				AsOf:     asOf,
+			}
+
+			res, err := c.GetBalanceAsOfUsecase.Invoke(param)
+			if err != nil {
+				e := entity.ToHttpError(err)
+				return nil, e
+			}
+
+			return res, nil
+		}, "Successfully get wallet balance", fiber.StatusOK,
+	)
+}
@@ -1,6 +1,12 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	"github.com/mystaline/clefinport-be/pkg/enum"
+)
+
+//go:generate go run github.com/mystaline/clefinport-be/pkg/cmd/sqlgen -type=GetWalletInfoData -mode=scan,columns
 
 type GetWalletInfoResult struct {
 	ID             string    `json:"id"`
@@ -17,3 +23,145 @@ type GetWalletInfoData struct {
 	CreatedAt      time.Time `json:"createdAt"      column:"created_at"`
 	UpdatedAt      time.Time `json:"updatedAt"      column:"updated_at"`
 }
+
+// Invitation rows of wallet_members start pending and become accepted once the invitee joins.
+const (
+	WalletMemberPending  = "pending"
+	WalletMemberAccepted = "accepted"
+)
+
+// What the client should do with a verified invitation link.
+const (
+	// The email already has an account, log in and accept
+	InvitationActionAccept = "accept"
+	// No account yet, sign up with the invited email first
+	InvitationActionRegister = "register"
+)
+
+type InviteMemberBody struct {
+	Email string          `json:"email"`
+	Role  enum.WalletRole `json:"role"`
+}
+
+type InviteMemberResult struct {
+	MemberID  string    `json:"memberId"`
+	Token     string    `json:"token"`
+	Link      string    `json:"link"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+type VerifyInvitationResult struct {
+	MemberID   string          `json:"memberId"`
+	WalletID   string          `json:"walletId"`
+	Email      string          `json:"email"`
+	Role       enum.WalletRole `json:"role"`
+	Registered bool            `json:"registered"`
+	UserID     *string         `json:"userId"`
+	Action     string          `json:"action"`
+}
+
+type WalletMemberData struct {
+	ID       string          `json:"id"       column:"id::text"`
+	WalletID string          `json:"walletId" column:"wallet_id::text"`
+	UserID   *string         `json:"userId"   column:"user_id::text"`
+	Email    string          `json:"email"    column:"email"`
+	Role     enum.WalletRole `json:"role"     column:"role"`
+	Status   string          `json:"status"   column:"status"`
+}
+
+type InsertWalletMember struct {
+	WalletID string          `json:"walletId" column:"wallet_id"`
+	Email    string          `json:"email"    column:"email"`
+	Role     enum.WalletRole `json:"role"     column:"role"`
+	Status   string          `json:"status"   column:"status"`
+}
+
+type InvitedUserData struct {
+	ID string `json:"id" column:"id::text"`
+}
+
+// CategoriesCacheTag scopes cached category lists, tagged per user with httpcache.Tag.
+const CategoriesCacheTag = "categories"
+
+// SeedCategoriesBody is sent by the login flow on a user's first login, locale is the user's display language.
+type SeedCategoriesBody struct {
+	UserID string `json:"userId"`
+	Locale string `json:"locale"`
+}
+
+type SeedCategoriesResult struct {
+	Locale  string `json:"locale"`
+	Created int    `json:"created"`
+	// True when the user already had the default categories, nothing was created
+	AlreadySeeded bool `json:"alreadySeeded"`
+}
+
+type InsertCategory struct {
+	UserID    string               `json:"userId"    column:"user_id"`
+	ParentID  *int64               `json:"parentId"  column:"parent_id"`
+	SystemKey string               `json:"systemKey" column:"system_key"`
+	Name      string               `json:"name"      column:"name"`
+	Type      enum.TransactionType `json:"type"      column:"type"`
+}
+
+// CategoryData names system categories in the requested language, custom categories keep their own name.
+type CategoryData struct {
+	ID        string               `json:"id"        column:"categories.id::text"`
+	ParentID  *string              `json:"parentId"  column:"categories.parent_id::text"`
+	SystemKey *string              `json:"systemKey" column:"categories.system_key"`
+	Name      string               `json:"name"      column:"COALESCE(system_category_names.name, categories.name)"`
+	Type      enum.TransactionType `json:"type"      column:"categories.type"`
+}
+
+// BalanceRecalculationQueue is the job_queue queue of admin triggered balance recalculations.
+const BalanceRecalculationQueue = "balance_recalculation"
+
+// RecalculateBalancesBody is also the job payload.
+type RecalculateBalancesBody struct {
+	UserIDs []string `json:"userIds"`
+	// Wallets repaired per statement, 100 by default
+	BatchSize int `json:"batchSize"`
+}
+
+type RecalculateBalancesResult struct {
+	JobID string `json:"jobId"`
+}
+
+type BalanceRecalculationError struct {
+	WalletIDs []string `json:"walletIds"`
+	Error     string   `json:"error"`
+}
+
+// BalanceRecalculationProgress is reported after every batch, failed batches are listed in Errors and skipped.
+type BalanceRecalculationProgress struct {
+	Total     int                         `json:"total"`
+	Processed int                         `json:"processed"`
+	Repaired  int64                       `json:"repaired"`
+	Errors    []BalanceRecalculationError `json:"errors"`
+}
+
+type BalanceRecalculationStatus struct {
+	JobID     string                        `json:"jobId"`
+	Status    string                        `json:"status"`
+	Attempts  int                           `json:"attempts"`
+	Progress  *BalanceRecalculationProgress `json:"progress"`
+	LastError *string                       `json:"lastError"`
+	CreatedAt time.Time                     `json:"createdAt"`
+	UpdatedAt time.Time                     `json:"updatedAt"`
+}
+
+type RecalculationWalletData struct {
+	WalletID string `json:"walletId" column:"wallet_id::text"`
+}
+
+type BalanceAsOfResult struct {
+	WalletID string    `json:"walletId"`
+	AsOf     time.Time `json:"asOf"`
+	Balance  int64     `json:"balance"`
+}
+
+type EnableEventSourcingResult struct {
+	WalletID string    `json:"walletId"`
+	Balance  int64     `json:"balance"`
+	OpenedAt time.Time `json:"openedAt"`
+}
@@ -0,0 +1,81 @@
+// Code generated by sqlgen. DO NOT EDIT.
+
+package dto
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ScanRow implements sql_query.RowScanner for GetWalletInfoData.
+func (d *GetWalletInfoData) ScanRow(rows pgx.Rows) error {
+	var (
+		f0 *string
+		f1 *string
+		f2 *string
+		f3 *time.Time
+		f4 *time.Time
+	)
+
+	fields := rows.FieldDescriptions()
+	dest := make([]any, len(fields))
+	for i, field := range fields {
+		switch field.Name {
+		case "id":
+			dest[i] = &f0
+		case "fullName":
+			dest[i] = &f1
+		case "profilePicture":
+			dest[i] = &f2
+		case "createdAt":
+			dest[i] = &f3
+		case "updatedAt":
+			dest[i] = &f4
+		default:
+			dest[i] = new(any)
+		}
+	}
+
+	if err := rows.Scan(dest...); err != nil {
+		return err
+	}
+
+	if f0 != nil {
+		d.ID = *f0
+	}
+	if f1 != nil {
+		d.FullName = *f1
+	}
+	if f2 != nil {
+		d.ProfilePicture = *f2
+	}
+	if f3 != nil {
+		d.CreatedAt = *f3
+	}
+	if f4 != nil {
+		d.UpdatedAt = *f4
+	}
+
+	return nil
+}
+
+// GetWalletInfoDataColumns holds the column of every selected field of GetWalletInfoData.
+type GetWalletInfoDataColumns struct {
+	ID             string
+	FullName       string
+	ProfilePicture string
+	CreatedAt      string
+	UpdatedAt      string
+}
+
+// Columns implements sql_query.ColumnSet for GetWalletInfoData.
+func (d GetWalletInfoData) Columns() GetWalletInfoDataColumns {
+	return GetWalletInfoDataColumns{
+		ID:             "id",
+		FullName:       "full_name",
+		ProfilePicture: "profile_picture",
+		CreatedAt:      "created_at",
+		UpdatedAt:      "updated_at",
+	}
+}
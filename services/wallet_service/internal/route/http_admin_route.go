@@ -0,0 +1,94 @@
+package route
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/mystaline/clefinport-be/services/wallet_service/internal/controller"
+	"github.com/mystaline/clefinport-be/services/wallet_service/internal/dto"
+	"github.com/mystaline/clefinport-be/services/wallet_service/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/mystaline/clefinport-be/pkg/audit"
+	"github.com/mystaline/clefinport-be/pkg/db"
+	"github.com/mystaline/clefinport-be/pkg/introspect"
+	"github.com/mystaline/clefinport-be/pkg/jobqueue"
+	"github.com/mystaline/clefinport-be/pkg/privacy"
+	"github.com/mystaline/clefinport-be/pkg/provider"
+	"github.com/mystaline/clefinport-be/pkg/sqlconsole"
+)
+
+func SetupAdminRoute(
+	app *fiber.App,
+	adminController controller.AdminController,
+	auditWriter *audit.Writer,
+) {
+	// Mounted before the access guard of the group so denied attempts are audited too
+	app.Use("/v1/admin/sql", audit.Middleware(auditWriter, "admin.sql_console"))
+
+	admin := app.Group("/v1/admin", privacy.RequireSupportAccess())
+
+	// Recompute wallet balances of selected users from their transactions in the background
+	admin.Post("/balance-recalculations", adminController.RecalculateBalances)
+	// Progress of a recalculation job
+	admin.Get("/balance-recalculations/:jobId", adminController.GetBalanceRecalculation)
+
+	// Tables of the data browser
+	admin.Get("/tables", adminController.ListTables)
+	// Columns of a table, with types, nullability and keys
+	admin.Get("/tables/:table", adminController.GetTable)
+	// Read-only SQL console over the same tables, instead of direct psql access
+	admin.Post("/sql", adminController.RunSQL)
+
+	// Switch a wallet to event-sourced storage, its history starts now
+	admin.Post("/wallets/:id/event-sourcing", adminController.EnableEventSourcing)
+}
+
+// SetupAdminController also starts the recalculation worker, it stops with ctx.
+// It returns the audit writer of the routes, close it on shutdown.
+func SetupAdminController(
+	ctx context.Context,
+	app *fiber.App,
+	serviceProvider provider.IServiceProvider,
+) *audit.Writer {
+	// One attempt per job, a retry would restart from the first wallet and hide the reported errors
+	queue, err := jobqueue.MakeQueue(serviceProvider.MakeService(db.WalletServiceDBName), dto.BalanceRecalculationQueue, jobqueue.Config{
+		MaxAttempts: 1,
+		LockTimeout: 30 * time.Minute,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	recalculateBalancesUsecase := usecase.MakeRecalculateBalancesUseCase(queue)
+	getBalanceRecalculationUsecase := usecase.MakeGetBalanceRecalculationUseCase(queue)
+	handleBalanceRecalculationUsecase := usecase.MakeHandleBalanceRecalculationUseCase(serviceProvider, queue)
+
+	go queue.Work(ctx, 1, 10*time.Second, handleBalanceRecalculationUsecase.Handle)
+
+	inspector := introspect.MakeInspector(serviceProvider.MakeService(db.WalletServiceDBName), 0)
+	listAdminTablesUsecase := usecase.MakeListAdminTablesUseCase(inspector)
+	getAdminTableUsecase := usecase.MakeGetAdminTableUseCase(inspector)
+	enableEventSourcingUsecase := usecase.MakeEnableEventSourcingUseCase(serviceProvider)
+
+	console := sqlconsole.MakeConsole(serviceProvider.MakeService(db.WalletServiceDBName), inspector, sqlconsole.Config{})
+	runAdminSQLUsecase := usecase.MakeRunAdminSQLUseCase(console)
+	auditWriter := audit.MakeWriter(serviceProvider.MakeService(db.LogServiceDBName), audit.WriterConfig{})
+
+	adminController := controller.MakeAdminController(
+		60*time.Second,
+
+		recalculateBalancesUsecase,
+		getBalanceRecalculationUsecase,
+		listAdminTablesUsecase,
+		getAdminTableUsecase,
+		enableEventSourcingUsecase,
+		runAdminSQLUsecase,
+	)
+
+	SetupAdminRoute(app, *adminController, auditWriter)
+
+	return auditWriter
+}
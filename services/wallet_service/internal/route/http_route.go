@@ -1,21 +1,28 @@
 package route
 
 import (
+	"log"
 	"time"
 
 	"github.com/mystaline/clefinport-be/services/wallet_service/internal/controller"
+	"github.com/mystaline/clefinport-be/services/wallet_service/internal/dto"
 	"github.com/mystaline/clefinport-be/services/wallet_service/internal/usecase"
 
 	"github.com/gofiber/fiber/v2"
 
+	"github.com/mystaline/clefinport-be/pkg/apikey"
+	"github.com/mystaline/clefinport-be/pkg/httpcache"
+	"github.com/mystaline/clefinport-be/pkg/invitation"
 	"github.com/mystaline/clefinport-be/pkg/provider"
 )
 
 func SetupWalletRoute(
 	app *fiber.App,
 	walletController controller.WalletController,
+	cache *httpcache.Cache,
 ) {
 	wallet := app.Group("/v1/wallet")
+	readOnly, write := apikey.RequireScope(apikey.ScopeReadOnly), apikey.RequireScope(apikey.ScopeTransactionsWrite)
 
 	// // Get wallet member list
 	// wallet.Get("/:id/members", walletController.GetWalletMemberList)
@@ -23,14 +30,27 @@ func SetupWalletRoute(
 	// wallet.Get("/:id/latest-transactions", walletController.GetWalletLatestTransactionList)
 	// // Get all wallet transactions
 	// wallet.Get("/:id/detail-transactions", walletController.GetWalletTransactions)
+	// Resolve an invitation link to its pending membership
+	wallet.Get("/invitations/verify", readOnly, walletController.VerifyInvitation)
+	// List user's categories, system categories named in the requested language
+	wallet.Get("/categories", readOnly, cache.Middleware(httpcache.RouteOptions{
+		Tags: func(ctx *fiber.Ctx) []string {
+			return []string{httpcache.Tag(dto.CategoriesCacheTag, ctx.Query("userId"))}
+		},
+		Vary: []string{fiber.HeaderAcceptLanguage},
+	}), walletController.ListCategories)
+	// Create the default categories of the user's language, called on every login and only seeds once
+	wallet.Post("/categories/seed", write, walletController.SeedCategories)
 	// Get wallet detail
-	wallet.Get("/:id", walletController.GetWalletInfo)
+	wallet.Get("/:id", readOnly, walletController.GetWalletInfo)
+	// Balance of an event-sourced wallet at a past date (?asOf=RFC 3339)
+	wallet.Get("/:id/balance", readOnly, walletController.GetBalanceAsOf)
 	// // Create new wallet
 	// wallet.Post("", walletController.CreateWallet)
 	// // Transfer between wallet
 	// wallet.Post("/:id/transfer", walletController.TransferBalance)
-	// // Invite member to shared wallet
-	// wallet.Post("/:id/invite-member", walletController.InviteCollabMember)
+	// Invite member to shared wallet, returns the signed invitation link
+	wallet.Post("/:id/invite-member", write, walletController.InviteMember)
 	// // Accept invitation to shared wallet
 	// wallet.Post("/:id/accept-invitation", walletController.AcceptCollabInvitation)
 	// // Delete member from shared wallet
@@ -41,13 +61,32 @@ func SetupWalletController(
 	app *fiber.App,
 	serviceProvider provider.IServiceProvider,
 ) {
+	// Without WALLET_INVITE_SECRET the invitation endpoints answer 500, the rest of the service keeps working
+	signer, err := invitation.MakeSigner()
+	if err != nil {
+		log.Printf("wallet invitations disabled: %v", err)
+	}
+
+	// Per instance, category writes of this service invalidate it
+	cache := httpcache.MakeCache(httpcache.MakeMemoryStore(0), httpcache.Config{})
+
 	getWalletInfoUsecase := usecase.MakeGetWalletInfoUseCase(serviceProvider)
+	inviteMemberUsecase := usecase.MakeInviteMemberUseCase(serviceProvider, signer)
+	verifyInvitationUsecase := usecase.MakeVerifyInvitationUseCase(serviceProvider, signer)
+	seedCategoriesUsecase := usecase.MakeSeedCategoriesUseCase(serviceProvider, cache)
+	listCategoriesUsecase := usecase.MakeListCategoriesUseCase(serviceProvider)
+	getBalanceAsOfUsecase := usecase.MakeGetBalanceAsOfUseCase(serviceProvider)
 
 	walletController := controller.MakeWalletController(
 		60*time.Second,
 
 		getWalletInfoUsecase,
+		inviteMemberUsecase,
+		verifyInvitationUsecase,
+		seedCategoriesUsecase,
+		listCategoriesUsecase,
+		getBalanceAsOfUsecase,
 	)
 
-	SetupWalletRoute(app, *walletController)
+	SetupWalletRoute(app, *walletController, cache)
 }
@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/mystaline/clefinport-be/services/wallet_service/internal/dto"
+
+	"github.com/mystaline/clefinport-be/pkg/db"
+	"github.com/mystaline/clefinport-be/pkg/entity"
+	"github.com/mystaline/clefinport-be/pkg/provider"
+	"github.com/mystaline/clefinport-be/pkg/service"
+	"github.com/mystaline/clefinport-be/pkg/walleterror"
+	"github.com/mystaline/clefinport-be/pkg/walletevents"
+)
+
+type EnableEventSourcingParam struct {
+	Ctx      context.Context
+	WalletID string
+}
+
+type EnableEventSourcingUseCase struct {
+	Service service.PostgreSqlService
+
+	ServiceProvider provider.IServiceProvider
+}
+
+func MakeEnableEventSourcingUseCase(
+	serviceProvider provider.IServiceProvider,
+) *EnableEventSourcingUseCase {
+	return &EnableEventSourcingUseCase{
+		ServiceProvider: serviceProvider,
+	}
+}
+
+func (u *EnableEventSourcingUseCase) InitService() {
+	u.Service = u.ServiceProvider.MakeService(db.WalletServiceDBName)
+}
+
+// Invoke opens the wallet's event stream with its current balance, it can't be closed again.
+func (u *EnableEventSourcingUseCase) Invoke(
+	param EnableEventSourcingParam,
+) (*dto.EnableEventSourcingResult, error) {
+	event, err := walletevents.MakeStore(u.Service).Open(param.Ctx, param.WalletID)
+	switch {
+	case errors.Is(err, walletevents.ErrStreamExists):
+		return nil, entity.Conflict("wallet is already event sourced")
+	case errors.Is(err, walletevents.ErrWalletNotFound):
+		return nil, walleterror.WalletNotFound(param.WalletID)
+	case err != nil:
+		return nil, err
+	}
+
+	var opened walletevents.StreamOpened
+	if err := json.Unmarshal(event.Payload, &opened); err != nil {
+		return nil, err
+	}
+
+	return &dto.EnableEventSourcingResult{
+		WalletID: param.WalletID,
+		Balance:  opened.Balance,
+		OpenedAt: event.OccurredAt,
+	}, nil
+}
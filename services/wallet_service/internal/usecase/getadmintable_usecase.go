@@ -0,0 +1,45 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mystaline/clefinport-be/pkg/entity"
+	"github.com/mystaline/clefinport-be/pkg/introspect"
+)
+
+type GetAdminTableParam struct {
+	Ctx   context.Context
+	Table string
+}
+
+type GetAdminTableUseCase struct {
+	Inspector *introspect.Inspector
+}
+
+func MakeGetAdminTableUseCase(
+	inspector *introspect.Inspector,
+) *GetAdminTableUseCase {
+	return &GetAdminTableUseCase{
+		Inspector: inspector,
+	}
+}
+
+// The inspector carries its own service
+func (u *GetAdminTableUseCase) InitService() {}
+
+// Invoke returns the columns of a table with their types, nullability and keys,
+// the admin data browser renders its generic views from them.
+func (u *GetAdminTableUseCase) Invoke(
+	param GetAdminTableParam,
+) (*introspect.Table, error) {
+	table, err := u.Inspector.Table(param.Ctx, param.Table)
+	if errors.Is(err, introspect.ErrTableNotFound) {
+		return nil, entity.NotFound("table not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &table, nil
+}
@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mystaline/clefinport-be/services/wallet_service/internal/dto"
+
+	"github.com/mystaline/clefinport-be/pkg/db"
+	"github.com/mystaline/clefinport-be/pkg/entity"
+	"github.com/mystaline/clefinport-be/pkg/provider"
+	"github.com/mystaline/clefinport-be/pkg/service"
+	"github.com/mystaline/clefinport-be/pkg/walletevents"
+)
+
+type GetBalanceAsOfParam struct {
+	Ctx      context.Context
+	WalletID string
+	// RFC 3339, now when empty
+	AsOf string
+}
+
+type GetBalanceAsOfUseCase struct {
+	Service service.PostgreSqlService
+
+	ServiceProvider provider.IServiceProvider
+}
+
+func MakeGetBalanceAsOfUseCase(
+	serviceProvider provider.IServiceProvider,
+) *GetBalanceAsOfUseCase {
+	return &GetBalanceAsOfUseCase{
+		ServiceProvider: serviceProvider,
+	}
+}
+
+func (u *GetBalanceAsOfUseCase) InitService() {
+	u.Service = u.ServiceProvider.MakeService(db.WalletServiceDBName)
+}
+
+// Invoke replays the wallet's event stream, only event-sourced wallets have a history.
+func (u *GetBalanceAsOfUseCase) Invoke(
+	param GetBalanceAsOfParam,
+) (*dto.BalanceAsOfResult, error) {
+	asOf := time.Now()
+	if param.AsOf != "" {
+		parsed, err := time.Parse(time.RFC3339, param.AsOf)
+		if err != nil {
+			return nil, entity.BadRequest("asOf must be an RFC 3339 date")
+		}
+		asOf = parsed
+	}
+
+	balance, err := walletevents.MakeStore(u.Service).BalanceAsOf(param.Ctx, param.WalletID, asOf)
+	switch {
+	case errors.Is(err, walletevents.ErrNotEventSourced):
+		return nil, entity.NotFound("wallet has no event history")
+	case errors.Is(err, walletevents.ErrBeforeStream):
+		return nil, entity.BadRequest("the wallet's history starts after asOf")
+	case err != nil:
+		return nil, err
+	}
+
+	return &dto.BalanceAsOfResult{
+		WalletID: param.WalletID,
+		AsOf:     asOf,
+		Balance:  balance,
+	}, nil
+}
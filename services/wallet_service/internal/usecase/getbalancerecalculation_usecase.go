@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/mystaline/clefinport-be/services/wallet_service/internal/dto"
+
+	"github.com/mystaline/clefinport-be/pkg/entity"
+	"github.com/mystaline/clefinport-be/pkg/jobqueue"
+)
+
+type GetBalanceRecalculationParam struct {
+	Ctx   context.Context
+	JobID string
+}
+
+type GetBalanceRecalculationUseCase struct {
+	Queue *jobqueue.Queue
+}
+
+func MakeGetBalanceRecalculationUseCase(
+	queue *jobqueue.Queue,
+) *GetBalanceRecalculationUseCase {
+	return &GetBalanceRecalculationUseCase{
+		Queue: queue,
+	}
+}
+
+// The queue carries its own service
+func (u *GetBalanceRecalculationUseCase) InitService() {}
+
+func (u *GetBalanceRecalculationUseCase) Invoke(
+	param GetBalanceRecalculationParam,
+) (*dto.BalanceRecalculationStatus, error) {
+	job, err := u.Queue.Get(param.Ctx, param.JobID)
+	if errors.Is(err, jobqueue.ErrJobNotFound) {
+		return nil, entity.NotFound("balance recalculation job not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.BalanceRecalculationStatus{
+		JobID:     job.ID,
+		Status:    string(job.Status),
+		Attempts:  job.Attempts,
+		LastError: job.LastError,
+		CreatedAt: job.CreatedAt,
+		UpdatedAt: job.UpdatedAt,
+	}
+
+	// Pending jobs haven't reported anything yet
+	if len(job.Progress) > 0 && string(job.Progress) != "null" {
+		var progress dto.BalanceRecalculationProgress
+		if err := json.Unmarshal(job.Progress, &progress); err != nil {
+			return nil, err
+		}
+		result.Progress = &progress
+	}
+
+	return result, nil
+}
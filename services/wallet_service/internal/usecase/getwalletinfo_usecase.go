@@ -40,16 +40,22 @@ func (u *GetWalletInfoUseCase) InitService() {
 func (u *GetWalletInfoUseCase) Invoke(
 	param GetWalletInfoParam,
 ) (*dto.GetWalletInfoResult, error) {
-	query, args, _ := sql_query.
+	cols := sql_query.Columns[dto.GetWalletInfoData]()
+
+	// Called on every wallet screen, the statement is parsed and planned once per connection
+	statement, err := sql_query.
 		NewSQLSelectBuilder[dto.GetWalletInfoData](db.WalletTableName).
 		Where(map[string]sql_query.SQLCondition{
-			"id": {Operator: sql_query.SQLOperatorEqual, Value: param.WalletID},
+			cols.ID: {Operator: sql_query.SQLOperatorEqual, Value: param.WalletID},
 		}).
 		SetLimit(1).
-		Build()
+		Prepare()
+	if err != nil {
+		return nil, err
+	}
 
 	var wallet dto.GetWalletInfoResult
-	if err := u.Service.SelectOne(&wallet, param.Ctx, query, args...); err != nil {
+	if _, err := u.Service.ExecPrepared(&wallet, param.Ctx, statement); err != nil {
 		return nil, err
 	}
 
@@ -40,8 +40,11 @@ func (u *GetUserTotalBalanceUseCase) InitService() {
 func (u *GetUserTotalBalanceUseCase) Invoke(
 	param GetUserTotalBalanceParam,
 ) (*pb_wallet.GetTotalBalanceByUserIdResponse, error) {
-	query, args, _ := sql_query.
-		NewSQLSelectBuilder[any](db.UserWalletTableName).
+	// Hot path for every user info request, reuse pooled builder
+	builder := sql_query.AcquireSelectBuilder[any](db.UserWalletTableName)
+	defer sql_query.Release(builder)
+
+	query, args, _ := builder.
 		Select(`sum(balance) as balance`).
 		Where(map[string]sql_query.SQLCondition{
 			"user_id": {Operator: sql_query.SQLOperatorEqual, Value: param.UserID},
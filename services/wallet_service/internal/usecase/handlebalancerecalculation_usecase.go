@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/mystaline/clefinport-be/services/wallet_service/internal/dto"
+
+	db "github.com/mystaline/clefinport-be/pkg/db"
+	"github.com/mystaline/clefinport-be/pkg/jobqueue"
+	"github.com/mystaline/clefinport-be/pkg/ledger"
+	provider "github.com/mystaline/clefinport-be/pkg/provider"
+	service "github.com/mystaline/clefinport-be/pkg/service"
+	"github.com/mystaline/clefinport-be/pkg/sql_query"
+)
+
+const defaultRecalculationBatchSize = 100
+
+// HandleBalanceRecalculationUseCase is the worker side of RecalculateBalancesUseCase.
+type HandleBalanceRecalculationUseCase struct {
+	Service service.PostgreSqlService
+	Queue   *jobqueue.Queue
+
+	ServiceProvider provider.IServiceProvider
+}
+
+func MakeHandleBalanceRecalculationUseCase(
+	serviceProvider provider.IServiceProvider,
+	queue *jobqueue.Queue,
+) *HandleBalanceRecalculationUseCase {
+	return &HandleBalanceRecalculationUseCase{
+		ServiceProvider: serviceProvider,
+		Queue:           queue,
+	}
+}
+
+func (u *HandleBalanceRecalculationUseCase) InitService() {
+	dbName := db.WalletServiceDBName
+
+	u.Service = u.ServiceProvider.MakeService(dbName)
+	u.Service.Debug(2)
+}
+
+// Handle repairs the balances of every wallet of the job's users batch by batch, reporting progress after each one.
+// A failing batch is recorded and skipped so one broken wallet doesn't block the rest,
+// only failing to list the wallets fails the job.
+func (u *HandleBalanceRecalculationUseCase) Handle(ctx context.Context, job jobqueue.Job) error {
+	u.InitService()
+
+	var body dto.RecalculateBalancesBody
+	if err := job.Decode(&body); err != nil {
+		return err
+	}
+
+	batchSize := body.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultRecalculationBatchSize
+	}
+
+	query, args, err := sql_query.NewSQLSelectBuilder[dto.RecalculationWalletData](db.UserWalletTableName).
+		Where(map[string]sql_query.SQLCondition{
+			"user_id": {Operator: sql_query.SQLOperatorIn, Value: body.UserIDs},
+		}).
+		GroupBy("wallet_id").
+		OrderBy([]string{"wallet_id"}, true).
+		Build()
+	if err != nil {
+		return err
+	}
+
+	wallets := []dto.RecalculationWalletData{}
+	if err := u.Service.SelectMany(&wallets, ctx, query, args...); err != nil {
+		return err
+	}
+
+	progress := dto.BalanceRecalculationProgress{
+		Total:  len(wallets),
+		Errors: []dto.BalanceRecalculationError{},
+	}
+	if err := u.Queue.SetProgress(ctx, job, progress); err != nil {
+		return err
+	}
+
+	for start := 0; start < len(wallets); start += batchSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		end := min(start+batchSize, len(wallets))
+		walletIDs := make([]string, 0, end-start)
+		for _, wallet := range wallets[start:end] {
+			walletIDs = append(walletIDs, wallet.WalletID)
+		}
+
+		repaired, err := ledger.Repair(ctx, u.Service, walletIDs)
+		if err != nil {
+			progress.Errors = append(progress.Errors, dto.BalanceRecalculationError{WalletIDs: walletIDs, Error: err.Error()})
+		}
+		progress.Processed = end
+		progress.Repaired += repaired
+
+		// Progress is informative, losing an update must not abort the repair unless another worker took the job over
+		if err := u.Queue.SetProgress(ctx, job, progress); err != nil {
+			if errors.Is(err, jobqueue.ErrClaimLost) {
+				return err
+			}
+			log.Printf("balance recalculation %s: failed to report progress: %v", job.ID, err)
+		}
+	}
+
+	return nil
+}
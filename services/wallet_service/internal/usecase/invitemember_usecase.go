@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mystaline/clefinport-be/services/wallet_service/internal/dto"
+
+	db "github.com/mystaline/clefinport-be/pkg/db"
+	"github.com/mystaline/clefinport-be/pkg/entity"
+	"github.com/mystaline/clefinport-be/pkg/enum"
+	"github.com/mystaline/clefinport-be/pkg/invitation"
+	provider "github.com/mystaline/clefinport-be/pkg/provider"
+	service "github.com/mystaline/clefinport-be/pkg/service"
+	"github.com/mystaline/clefinport-be/pkg/sql_query"
+	"github.com/mystaline/clefinport-be/pkg/walleterror"
+)
+
+type InviteMemberParam struct {
+	Ctx      context.Context
+	WalletID string
+	Body     dto.InviteMemberBody
+}
+
+type InviteMemberUseCase struct {
+	Service service.PostgreSqlService
+	Signer  *invitation.Signer
+
+	ServiceProvider provider.IServiceProvider
+}
+
+func MakeInviteMemberUseCase(
+	serviceProvider provider.IServiceProvider,
+	signer *invitation.Signer,
+) *InviteMemberUseCase {
+	return &InviteMemberUseCase{
+		ServiceProvider: serviceProvider,
+		Signer:          signer,
+	}
+}
+
+func (u *InviteMemberUseCase) InitService() {
+	dbName := db.WalletServiceDBName
+
+	u.Service = u.ServiceProvider.MakeService(dbName)
+	u.Service.Debug(2)
+}
+
+func (u *InviteMemberUseCase) Invoke(
+	param InviteMemberParam,
+) (*dto.InviteMemberResult, error) {
+	if u.Signer == nil {
+		return nil, entity.InternalServerError("wallet invitations are not configured")
+	}
+
+	email := strings.ToLower(strings.TrimSpace(param.Body.Email))
+	if !strings.Contains(email, "@") {
+		return nil, entity.BadRequest("a valid email is required")
+	}
+
+	role := param.Body.Role
+	if role == "" {
+		role = enum.WalletRoleViewer
+	}
+	// A wallet has exactly one owner, ownership isn't handed out through links
+	if role == enum.WalletRoleOwner {
+		return nil, entity.BadRequest("members can't be invited as owner")
+	}
+
+	walletCount, err := u.Service.CountWithFilter(param.Ctx, db.WalletTableName, map[string]sql_query.SQLCondition{
+		"id": {Operator: sql_query.SQLOperatorEqual, Value: param.WalletID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if walletCount == 0 {
+		return nil, walleterror.WalletNotFound(param.WalletID)
+	}
+
+	memberCount, err := u.Service.CountWithFilter(param.Ctx, db.WalletMemberTableName, map[string]sql_query.SQLCondition{
+		"wallet_id": {Operator: sql_query.SQLOperatorEqual, Value: param.WalletID},
+		"email":     {Operator: sql_query.SQLOperatorEqual, Value: email},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if memberCount > 0 {
+		return nil, entity.Conflict("email is already a member or invited to this wallet")
+	}
+
+	id, err := u.Service.InsertOneWithData(param.Ctx, db.WalletMemberTableName, dto.InsertWalletMember{
+		WalletID: param.WalletID,
+		Email:    email,
+		Role:     role,
+		Status:   dto.WalletMemberPending,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	memberID := fmt.Sprint(id)
+	token, expiresAt := u.Signer.Sign(memberID, param.WalletID, email)
+
+	return &dto.InviteMemberResult{
+		MemberID:  memberID,
+		Token:     token,
+		Link:      invitation.Link(token),
+		ExpiresAt: expiresAt,
+	}, nil
+}
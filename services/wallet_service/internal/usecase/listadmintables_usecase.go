@@ -0,0 +1,33 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/mystaline/clefinport-be/pkg/introspect"
+)
+
+type ListAdminTablesParam struct {
+	Ctx context.Context
+}
+
+type ListAdminTablesUseCase struct {
+	Inspector *introspect.Inspector
+}
+
+func MakeListAdminTablesUseCase(
+	inspector *introspect.Inspector,
+) *ListAdminTablesUseCase {
+	return &ListAdminTablesUseCase{
+		Inspector: inspector,
+	}
+}
+
+// The inspector carries its own service
+func (u *ListAdminTablesUseCase) InitService() {}
+
+// Invoke lists the tables the admin data browser can show.
+func (u *ListAdminTablesUseCase) Invoke(
+	param ListAdminTablesParam,
+) ([]string, error) {
+	return u.Inspector.TableNames(param.Ctx)
+}
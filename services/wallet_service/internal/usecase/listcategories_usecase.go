@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/mystaline/clefinport-be/services/wallet_service/internal/dto"
+
+	"github.com/mystaline/clefinport-be/pkg/categoryseed"
+	db "github.com/mystaline/clefinport-be/pkg/db"
+	"github.com/mystaline/clefinport-be/pkg/entity"
+	provider "github.com/mystaline/clefinport-be/pkg/provider"
+	service "github.com/mystaline/clefinport-be/pkg/service"
+	"github.com/mystaline/clefinport-be/pkg/sql_query"
+)
+
+type ListCategoriesParam struct {
+	Ctx    context.Context
+	UserID string
+	Locale string
+}
+
+type ListCategoriesUseCase struct {
+	Service service.PostgreSqlService
+
+	ServiceProvider provider.IServiceProvider
+}
+
+func MakeListCategoriesUseCase(
+	serviceProvider provider.IServiceProvider,
+) *ListCategoriesUseCase {
+	return &ListCategoriesUseCase{
+		ServiceProvider: serviceProvider,
+	}
+}
+
+func (u *ListCategoriesUseCase) InitService() {
+	dbName := db.WalletServiceDBName
+
+	u.Service = u.ServiceProvider.MakeService(dbName)
+	u.Service.Debug(2)
+}
+
+// Invoke lists the user's categories with system categories named in the requested language,
+// so switching language renames them without touching the rows reports group by.
+func (u *ListCategoriesUseCase) Invoke(
+	param ListCategoriesParam,
+) ([]dto.CategoryData, error) {
+	if param.UserID == "" {
+		return nil, entity.BadRequest("userId is required")
+	}
+
+	query, args, err := listCategoriesQuery(param).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	categories := []dto.CategoryData{}
+	if err := u.Service.SelectMany(&categories, param.Ctx, query, args...); err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+// listCategoriesQuery selects the user's categories, system ones named in the resolved locale.
+func listCategoriesQuery(param ListCategoriesParam) sql_query.SQLSelectChainBuilder {
+	return sql_query.
+		NewSQLSelectBuilder[dto.CategoryData](db.CategoryTableName).
+		LeftJoin(db.SystemCategoryNameTableName, "system_category_names.system_key = categories.system_key",
+			map[string]sql_query.SQLCondition{
+				"system_category_names.locale": {Operator: sql_query.SQLOperatorEqual, Value: categoryseed.ResolveLocale(param.Locale)},
+			},
+		).
+		Where(map[string]sql_query.SQLCondition{
+			"categories.user_id": {Operator: sql_query.SQLOperatorEqual, Value: param.UserID},
+		}).
+		OrderBy([]string{"categories.name"}, true)
+}
@@ -0,0 +1,25 @@
+package usecase
+
+import (
+	"testing"
+
+	sqltesting "github.com/mystaline/clefinport-be/pkg/sql_query/testing"
+)
+
+func TestListCategoriesQuery(t *testing.T) {
+	sqltesting.AssertSQL(t, listCategoriesQuery(ListCategoriesParam{UserID: "42", Locale: "id-ID"}), `
+		SELECT
+			categories.id::text as "id",
+			categories.parent_id::text as "parentId",
+			categories.system_key as "systemKey",
+			COALESCE(system_category_names.name, categories.name) as "name",
+			categories.type as "type"
+		FROM categories
+		LEFT JOIN system_category_names
+			ON system_category_names.system_key = categories.system_key
+			AND "system_category_names"."locale" = $1
+		WHERE "categories"."user_id" = $2
+		ORDER BY categories.name ASC NULLS FIRST`,
+		[]any{"id", "42"},
+	)
+}
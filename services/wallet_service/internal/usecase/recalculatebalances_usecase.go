@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/mystaline/clefinport-be/services/wallet_service/internal/dto"
+
+	"github.com/mystaline/clefinport-be/pkg/entity"
+	"github.com/mystaline/clefinport-be/pkg/jobqueue"
+)
+
+// Balance recalculations are limited per job so a single request can't queue a full table scan.
+const maxRecalculationUsers = 1000
+
+type RecalculateBalancesParam struct {
+	Ctx  context.Context
+	Body dto.RecalculateBalancesBody
+}
+
+type RecalculateBalancesUseCase struct {
+	Queue *jobqueue.Queue
+}
+
+func MakeRecalculateBalancesUseCase(
+	queue *jobqueue.Queue,
+) *RecalculateBalancesUseCase {
+	return &RecalculateBalancesUseCase{
+		Queue: queue,
+	}
+}
+
+// The queue carries its own service
+func (u *RecalculateBalancesUseCase) InitService() {}
+
+// Invoke queues the recalculation, the worker picks it up and reports progress on the job.
+func (u *RecalculateBalancesUseCase) Invoke(
+	param RecalculateBalancesParam,
+) (*dto.RecalculateBalancesResult, error) {
+	if len(param.Body.UserIDs) == 0 {
+		return nil, entity.BadRequest("userIds is required")
+	}
+	if len(param.Body.UserIDs) > maxRecalculationUsers {
+		return nil, entity.BadRequest("too many userIds, split them into several jobs")
+	}
+	if param.Body.BatchSize < 0 {
+		return nil, entity.BadRequest("batchSize can't be negative")
+	}
+
+	jobID, err := u.Queue.Enqueue(param.Ctx, param.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.RecalculateBalancesResult{JobID: jobID}, nil
+}
@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mystaline/clefinport-be/pkg/entity"
+	"github.com/mystaline/clefinport-be/pkg/sqlconsole"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type RunAdminSQLParam struct {
+	Ctx   context.Context
+	Query sqlconsole.Query
+}
+
+type RunAdminSQLUseCase struct {
+	Console *sqlconsole.Console
+}
+
+func MakeRunAdminSQLUseCase(
+	console *sqlconsole.Console,
+) *RunAdminSQLUseCase {
+	return &RunAdminSQLUseCase{
+		Console: console,
+	}
+}
+
+// The console carries its own service
+func (u *RunAdminSQLUseCase) InitService() {}
+
+// Invoke runs a read-only query of support staff, rejected queries and Postgres errors are reported as bad requests
+// so the console can show why.
+func (u *RunAdminSQLUseCase) Invoke(
+	param RunAdminSQLParam,
+) (*sqlconsole.Result, error) {
+	result, err := u.Console.Run(param.Ctx, param.Query)
+	if errors.Is(err, sqlconsole.ErrRejected) {
+		return nil, entity.BadRequest(err.Error())
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		// query_canceled, raised by statement_timeout
+		if pgErr.Code == "57014" {
+			return nil, entity.BadRequest("query exceeded the statement timeout")
+		}
+		return nil, entity.BadRequest(pgErr.Message)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
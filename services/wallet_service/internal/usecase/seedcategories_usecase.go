@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/mystaline/clefinport-be/services/wallet_service/internal/dto"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/mystaline/clefinport-be/pkg/categoryseed"
+	db "github.com/mystaline/clefinport-be/pkg/db"
+	"github.com/mystaline/clefinport-be/pkg/entity"
+	"github.com/mystaline/clefinport-be/pkg/httpcache"
+	provider "github.com/mystaline/clefinport-be/pkg/provider"
+	service "github.com/mystaline/clefinport-be/pkg/service"
+	"github.com/mystaline/clefinport-be/pkg/sql_query"
+)
+
+type SeedCategoriesParam struct {
+	Ctx  context.Context
+	Body dto.SeedCategoriesBody
+}
+
+type SeedCategoriesUseCase struct {
+	Service service.PostgreSqlService
+	Cache   *httpcache.Cache
+
+	ServiceProvider provider.IServiceProvider
+}
+
+func MakeSeedCategoriesUseCase(
+	serviceProvider provider.IServiceProvider,
+	cache *httpcache.Cache,
+) *SeedCategoriesUseCase {
+	return &SeedCategoriesUseCase{
+		ServiceProvider: serviceProvider,
+		Cache:           cache,
+	}
+}
+
+func (u *SeedCategoriesUseCase) InitService() {
+	dbName := db.WalletServiceDBName
+
+	u.Service = u.ServiceProvider.MakeService(dbName)
+	u.Service.Debug(2)
+}
+
+// Invoke creates the default category tree of the user's language, it is safe to call on every login:
+// users who already have system categories are left alone, whatever language they were seeded in.
+func (u *SeedCategoriesUseCase) Invoke(
+	param SeedCategoriesParam,
+) (*dto.SeedCategoriesResult, error) {
+	if param.Body.UserID == "" {
+		return nil, entity.BadRequest("userId is required")
+	}
+
+	pack := categoryseed.Load(param.Body.Locale)
+
+	result, err := service.UseTransactions(param.Ctx, u.Service.GetPool(), func(tx pgx.Tx) (*dto.SeedCategoriesResult, error) {
+		u.Service.SetTransaction(tx)
+		defer u.Service.SetTransaction(nil)
+
+		// Two first logins racing (e.g. web and mobile) would seed twice, the second waits and sees the first's rows
+		if _, err := tx.Exec(param.Ctx, "SELECT pg_advisory_xact_lock(hashtext($1))", "category-seed:"+param.Body.UserID); err != nil {
+			return nil, err
+		}
+
+		seeded, err := u.Service.CountWithFilter(param.Ctx, db.CategoryTableName, map[string]sql_query.SQLCondition{
+			"user_id":    {Operator: sql_query.SQLOperatorEqual, Value: param.Body.UserID},
+			"system_key": {Operator: sql_query.SQLOperatorIsNotNull},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if seeded > 0 {
+			return &dto.SeedCategoriesResult{Locale: pack.Locale, AlreadySeeded: true}, nil
+		}
+
+		parentIDs := map[string]int64{}
+		var children []dto.InsertCategory
+
+		for _, category := range pack.Categories {
+			row := dto.InsertCategory{
+				UserID:    param.Body.UserID,
+				SystemKey: category.Key,
+				Name:      category.Name,
+				Type:      category.Type,
+			}
+
+			if category.ParentKey != "" {
+				parentID, ok := parentIDs[category.ParentKey]
+				if !ok {
+					return nil, fmt.Errorf("category %s: parent %s not seeded", category.Key, category.ParentKey)
+				}
+				row.ParentID = &parentID
+				children = append(children, row)
+				continue
+			}
+
+			// Parents one by one, their ids are needed by the children
+			id, err := u.Service.InsertOneWithData(param.Ctx, db.CategoryTableName, row)
+			if err != nil {
+				return nil, err
+			}
+			parentID, err := strconv.ParseInt(fmt.Sprint(id), 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			parentIDs[category.Key] = parentID
+		}
+
+		created := len(parentIDs)
+		if len(children) > 0 {
+			inserted, err := u.Service.InsertManyWithData(param.Ctx, db.CategoryTableName, children)
+			if err != nil {
+				return nil, err
+			}
+			created += int(inserted.(int64))
+		}
+
+		return &dto.SeedCategoriesResult{Locale: pack.Locale, Created: created}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Created > 0 {
+		u.Cache.Invalidate(param.Ctx, httpcache.Tag(dto.CategoriesCacheTag, param.Body.UserID))
+	}
+
+	return result, nil
+}
@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mystaline/clefinport-be/services/wallet_service/internal/dto"
+
+	"github.com/jackc/pgx/v5"
+
+	db "github.com/mystaline/clefinport-be/pkg/db"
+	"github.com/mystaline/clefinport-be/pkg/entity"
+	"github.com/mystaline/clefinport-be/pkg/invitation"
+	provider "github.com/mystaline/clefinport-be/pkg/provider"
+	service "github.com/mystaline/clefinport-be/pkg/service"
+	"github.com/mystaline/clefinport-be/pkg/sql_query"
+)
+
+type VerifyInvitationParam struct {
+	Ctx   context.Context
+	Token string
+}
+
+type VerifyInvitationUseCase struct {
+	Service     service.PostgreSqlService
+	UserService service.PostgreSqlService
+	Signer      *invitation.Signer
+
+	ServiceProvider provider.IServiceProvider
+}
+
+func MakeVerifyInvitationUseCase(
+	serviceProvider provider.IServiceProvider,
+	signer *invitation.Signer,
+) *VerifyInvitationUseCase {
+	return &VerifyInvitationUseCase{
+		ServiceProvider: serviceProvider,
+		Signer:          signer,
+	}
+}
+
+func (u *VerifyInvitationUseCase) InitService() {
+	u.Service = u.ServiceProvider.MakeService(db.WalletServiceDBName)
+	u.Service.Debug(2)
+
+	// Invitees are looked up by email to tell existing accounts from new ones
+	u.UserService = u.ServiceProvider.MakeService(db.UserServiceDBName)
+	u.UserService.Debug(2)
+}
+
+// Invoke maps a token to its pending wallet_members row.
+// Registered invitees get the row linked to their user id and are told to accept,
+// new invitees are told to register with the invited email first.
+func (u *VerifyInvitationUseCase) Invoke(
+	param VerifyInvitationParam,
+) (*dto.VerifyInvitationResult, error) {
+	if u.Signer == nil {
+		return nil, entity.InternalServerError("wallet invitations are not configured")
+	}
+
+	claims, err := u.Signer.Verify(param.Token)
+	if errors.Is(err, invitation.ErrExpiredToken) {
+		return nil, entity.BadRequest("invitation link has expired, ask for a new one")
+	}
+	if err != nil {
+		return nil, entity.BadRequest(err.Error())
+	}
+
+	query, args, err := sql_query.NewSQLSelectBuilder[dto.WalletMemberData](db.WalletMemberTableName).
+		Where(map[string]sql_query.SQLCondition{
+			"id":        {Operator: sql_query.SQLOperatorEqual, Value: claims.MemberID},
+			"wallet_id": {Operator: sql_query.SQLOperatorEqual, Value: claims.WalletID},
+		}).
+		SetLimit(1).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+
+	var member dto.WalletMemberData
+	if err := u.Service.SelectOne(&member, param.Ctx, query, args...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.NotFound("invitation not found")
+		}
+		return nil, err
+	}
+
+	// The row was re-invited with another email or already used
+	if member.Email != claims.Email {
+		return nil, entity.BadRequest(invitation.ErrInvalidToken.Error())
+	}
+	if member.Status != dto.WalletMemberPending {
+		return nil, entity.Conflict("invitation has already been accepted")
+	}
+
+	result := &dto.VerifyInvitationResult{
+		MemberID: member.ID,
+		WalletID: member.WalletID,
+		Email:    member.Email,
+		Role:     member.Role,
+		Action:   dto.InvitationActionRegister,
+	}
+
+	userQuery, userArgs, err := sql_query.NewSQLSelectBuilder[dto.InvitedUserData](db.UserTableName).
+		Where(map[string]sql_query.SQLCondition{
+			"lower(email)": {Operator: sql_query.SQLOperatorEqual, Value: member.Email},
+		}).
+		SetLimit(1).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+
+	var user dto.InvitedUserData
+	err = u.UserService.SelectOne(&user, param.Ctx, userQuery, userArgs...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return result, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if member.UserID == nil {
+		_, err := u.Service.UpdateOneWithData(param.Ctx, db.WalletMemberTableName,
+			map[string]sql_query.SQLCondition{
+				"id":     {Operator: sql_query.SQLOperatorEqual, Value: member.ID},
+				"status": {Operator: sql_query.SQLOperatorEqual, Value: dto.WalletMemberPending},
+			},
+			map[string]any{"user_id": user.ID},
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result.Registered = true
+	result.UserID = &user.ID
+	result.Action = dto.InvitationActionAccept
+
+	return result, nil
+}
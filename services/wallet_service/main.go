@@ -1,11 +1,15 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 	"sync"
+	"time"
 
+	"github.com/mystaline/clefinport-be/pkg/db"
 	"github.com/mystaline/clefinport-be/pkg/provider"
+	"github.com/mystaline/clefinport-be/pkg/service"
 
 	"github.com/joho/godotenv"
 
@@ -19,6 +23,17 @@ func main() {
 		}
 	}
 
+	db.SetSessionTimeouts(db.WalletServiceDBName, db.SessionTimeouts{
+		// Transfers lock wallet rows, fail fast instead of queueing behind a stuck lock
+		Lock:              2 * time.Second,
+		Statement:         15 * time.Second,
+		IdleInTransaction: 30 * time.Second,
+	})
+
+	service.StartTxWatchdog(context.Background(), service.TxWatchdogConfig{
+		ForceRollback: os.Getenv("TX_LEAK_FORCE_ROLLBACK") == "true",
+	})
+
 	serviceProvider := provider.ServiceProvider{}
 
 	var wg sync.WaitGroup